@@ -12,6 +12,7 @@ if [ ! -f "${SQLE_CONF}" ];then
       debug_log: ${DEBUG}
       log_path: '${SQLE_BASE}/logs'
       plugin_path: '${SQLE_BASE}/plugins'
+      auto_reload_plugins: false
     db_config:
       mysql_cnf:
         mysql_host: '${MYSQL_HOST}'
@@ -115,6 +115,15 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 		v1Router.PATCH("/rule_templates/:rule_template_name/", v1.UpdateRuleTemplate, AdminUserAllowed())
 		v1Router.DELETE("/rule_templates/:rule_template_name/", v1.DeleteRuleTemplate, AdminUserAllowed())
 
+		// custom rule
+		v1Router.POST("/custom_rules", v1.CreateCustomRule, AdminUserAllowed())
+		v1Router.PATCH("/custom_rules/:rule_name/", v1.UpdateCustomRule, AdminUserAllowed())
+		v1Router.DELETE("/custom_rules/:rule_name/", v1.DeleteCustomRule, AdminUserAllowed())
+
+		// dashboard
+		v1Router.GET("/dashboard/rule_hits", v1.GetRuleHitStatistics, AdminUserAllowed())
+		v1Router.GET("/dashboard/meta_cache", v1.GetMetaCacheStatistics, AdminUserAllowed())
+
 		// workflow template
 		v1Router.GET("/workflow_templates", v1.GetWorkflowTemplates, AdminUserAllowed())
 		v1Router.POST("/workflow_templates", v1.CreateWorkflowTemplate, AdminUserAllowed())
@@ -149,6 +158,9 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 		v1Router.POST("/configurations/license/check", v1.CheckLicense, AdminUserAllowed())
 		v1Router.GET("/configurations/oauth2", v1.GetOauth2Configuration, AdminUserAllowed())
 		v1Router.PATCH("/configurations/oauth2", v1.UpdateOauth2Configuration, AdminUserAllowed())
+		v1Router.GET("/configurations/plugins", v1.GetPlugins, AdminUserAllowed())
+		v1Router.GET("/configurations/drivers/status", v1.GetDriversStatus, AdminUserAllowed())
+		v1Router.POST("/configurations/plugins/reload", v1.ReloadPlugins, AdminUserAllowed())
 
 	}
 
@@ -172,6 +184,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 	v1Router.GET("/instances/:instance_name/workflow_template", v1.GetInstanceWorkflowTemplate)
 	v1Router.GET("/instances/:instance_name/schemas/:schema_name/tables", v1.ListTableBySchema)
 	v1Router.GET("/instances/:instance_name/schemas/:schema_name/tables/:table_name/metadata", v1.GetTableMetadata)
+	v1Router.GET("/instances/:instance_name/schemas/:schema_name/design_report", v1.GetSchemaDesignReport)
 
 	// rule template
 	v1Router.GET("/rule_templates", v1.GetRuleTemplates)
@@ -180,6 +193,9 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 
 	//rule
 	v1Router.GET("/rules", v1.GetRules)
+	v1Router.POST("/rules/sandbox", v1.AuditRuleSandbox)
+	v1Router.GET("/custom_rules", v1.GetCustomRules)
+	v1Router.GET("/custom_rules/:rule_name/", v1.GetCustomRule)
 
 	// workflow
 	v1Router.POST("/workflows", v1.CreateWorkflow)
@@ -191,6 +207,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 	v1Router.PATCH("/workflows/:workflow_id/", v1.UpdateWorkflow)
 	v1Router.PUT("/workflows/:workflow_id/schedule", v1.UpdateWorkflowSchedule)
 	v1Router.POST("/workflows/:workflow_id/task/execute", v1.ExecuteTaskOnWorkflow)
+	v1Router.POST("/workflows/:workflow_id/task/rollback", v1.RollbackTaskOnWorkflow)
 
 	// task
 	v1Router.POST("/tasks/audits", v1.CreateAndAuditTask)
@@ -199,7 +216,11 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 	v1Router.GET("/tasks/audits/:task_id/sql_report", v1.DownloadTaskSQLReportFile)
 	v1Router.GET("/tasks/audits/:task_id/sql_file", v1.DownloadTaskSQLFile)
 	v1Router.GET("/tasks/audits/:task_id/sql_content", v1.GetAuditTaskSQLContent)
+	v1Router.GET("/tasks/audits/:task_id/rollback_sqls", v1.GetAuditTaskRollbackSQLs)
+	v1Router.GET("/tasks/audits/:task_id/rollback_preview", v1.GetAuditTaskRollbackPreview)
+	v1Router.POST("/tasks/audits/:task_id/dry_run", v1.DryRunAuditTask)
 	v1Router.PATCH("/tasks/audits/:task_id/sqls/:number", v1.UpdateAuditTaskSQLs)
+	v1Router.POST("/tasks/audits/:task_id/fixes", v1.ApplyAuditTaskFixes)
 	v1Router.GET("/tasks/audits/:task_id/sqls/:number/analysis", v1.GetTaskAnalysisData)
 
 	// dashboard
@@ -233,6 +254,11 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 	v1Router.GET("/audit_plans/:audit_plan_name/notify_config/test", v1.TestAuditPlanNotifyConfig)
 	v1Router.GET("/audit_plans/reports/:audit_plan_report_id/sqls/:number/analysis", v1.GetAuditPlanAnalysisData)
 
+	// schema baseline
+	v1Router.POST("/schema_baselines", v1.CreateSchemaBaseline)
+	v1Router.DELETE("/schema_baselines/:schema_baseline_name/", v1.DeleteSchemaBaseline, AdminUserAllowed())
+	v1Router.GET("/schema_baseline_tips", v1.GetSchemaBaselineTips)
+
 	// sql query
 	v1Router.POST("/sql_query/prepare/:instance_name/", v1.PrepareSQLQuery)
 	v1Router.GET("/sql_query/history/:instance_name/", v1.GetSQLQueryHistory)
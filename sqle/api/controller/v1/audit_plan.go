@@ -28,6 +28,7 @@ var (
 	errAuditPlanNotExist         = errors.New(errors.DataNotExist, fmt.Errorf("audit plan is not exist"))
 	errAuditPlanExisted          = errors.New(errors.DataNotExist, fmt.Errorf("audit plan existed"))
 	errAuditPlanInstanceConflict = errors.New(errors.DataConflict, fmt.Errorf("instance_name can not be empty while instance_database is not empty"))
+	errAuditPlanBaselineConflict = errors.New(errors.DataConflict, fmt.Errorf("instance_name must be empty while schema_baseline_name is not empty"))
 )
 
 type GetAuditPlanMetasReqV1 struct {
@@ -104,13 +105,14 @@ func GetAuditPlanMetas(c echo.Context) error {
 }
 
 type CreateAuditPlanReqV1 struct {
-	Name             string                `json:"audit_plan_name" form:"audit_plan_name" example:"audit_plan_for_java_repo_1" valid:"required,name"`
-	Cron             string                `json:"audit_plan_cron" form:"audit_plan_cron" example:"0 */2 * * *" valid:"required,cron"`
-	InstanceType     string                `json:"audit_plan_instance_type" form:"audit_plan_instance_type" example:"mysql" valid:"required"`
-	InstanceName     string                `json:"audit_plan_instance_name" form:"audit_plan_instance_name" example:"test_mysql"`
-	InstanceDatabase string                `json:"audit_plan_instance_database" form:"audit_plan_instance_database" example:"app1"`
-	Type             string                `json:"audit_plan_type" form:"audit_plan_type" example:"slow log"`
-	Params           []AuditPlanParamReqV1 `json:"audit_plan_params" valid:"dive,required"`
+	Name               string                `json:"audit_plan_name" form:"audit_plan_name" example:"audit_plan_for_java_repo_1" valid:"required,name"`
+	Cron               string                `json:"audit_plan_cron" form:"audit_plan_cron" example:"0 */2 * * *" valid:"required,cron"`
+	InstanceType       string                `json:"audit_plan_instance_type" form:"audit_plan_instance_type" example:"mysql" valid:"required"`
+	InstanceName       string                `json:"audit_plan_instance_name" form:"audit_plan_instance_name" example:"test_mysql"`
+	InstanceDatabase   string                `json:"audit_plan_instance_database" form:"audit_plan_instance_database" example:"app1"`
+	Type               string                `json:"audit_plan_type" form:"audit_plan_type" example:"slow log"`
+	Params             []AuditPlanParamReqV1 `json:"audit_plan_params" valid:"dive,required"`
+	SchemaBaselineName string                `json:"audit_plan_schema_baseline_name" form:"audit_plan_schema_baseline_name" example:"app1_baseline"`
 }
 
 type AuditPlanParamReqV1 struct {
@@ -174,6 +176,15 @@ func CreateAuditPlan(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, errAuditPlanInstanceConflict)
 	}
 
+	if req.SchemaBaselineName != "" {
+		if req.InstanceName != "" {
+			return controller.JSONBaseErrorReq(c, errAuditPlanBaselineConflict)
+		}
+		if err := checkSchemaBaseline(req.SchemaBaselineName, req.InstanceType); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
+
 	// check user
 	currentUserName := controller.GetUserName(c)
 	user, exist, err := s.GetUserByName(currentUserName)
@@ -250,15 +261,16 @@ func CreateAuditPlan(c echo.Context) error {
 	}
 
 	ap := &model.AuditPlan{
-		Name:             req.Name,
-		CronExpression:   req.Cron,
-		Type:             req.Type,
-		Params:           ps,
-		CreateUserID:     user.ID,
-		Token:            t,
-		DBType:           instanceType,
-		InstanceName:     req.InstanceName,
-		InstanceDatabase: req.InstanceDatabase,
+		Name:               req.Name,
+		CronExpression:     req.Cron,
+		Type:               req.Type,
+		Params:             ps,
+		CreateUserID:       user.ID,
+		Token:              t,
+		DBType:             instanceType,
+		InstanceName:       req.InstanceName,
+		InstanceDatabase:   req.InstanceDatabase,
+		SchemaBaselineName: req.SchemaBaselineName,
 	}
 	err = s.Save(ap)
 	if err != nil {
@@ -301,10 +313,11 @@ func DeleteAuditPlan(c echo.Context) error {
 }
 
 type UpdateAuditPlanReqV1 struct {
-	Cron             *string               `json:"audit_plan_cron" form:"audit_plan_cron" example:"0 */2 * * *" valid:"omitempty,cron"`
-	InstanceName     *string               `json:"audit_plan_instance_name" form:"audit_plan_instance_name" example:"test_mysql"`
-	InstanceDatabase *string               `json:"audit_plan_instance_database" form:"audit_plan_instance_database" example:"app1"`
-	Params           []AuditPlanParamReqV1 `json:"audit_plan_params" valid:"dive,required"`
+	Cron               *string               `json:"audit_plan_cron" form:"audit_plan_cron" example:"0 */2 * * *" valid:"omitempty,cron"`
+	InstanceName       *string               `json:"audit_plan_instance_name" form:"audit_plan_instance_name" example:"test_mysql"`
+	InstanceDatabase   *string               `json:"audit_plan_instance_database" form:"audit_plan_instance_database" example:"app1"`
+	Params             []AuditPlanParamReqV1 `json:"audit_plan_params" valid:"dive,required"`
+	SchemaBaselineName *string               `json:"audit_plan_schema_baseline_name" form:"audit_plan_schema_baseline_name" example:"app1_baseline"`
 }
 
 // @Summary 更新审核计划
@@ -348,6 +361,21 @@ func UpdateAuditPlan(c echo.Context) error {
 	if req.InstanceDatabase != nil {
 		updateAttr["instance_database"] = *req.InstanceDatabase
 	}
+	if req.SchemaBaselineName != nil {
+		instanceName := ap.InstanceName
+		if req.InstanceName != nil {
+			instanceName = *req.InstanceName
+		}
+		if *req.SchemaBaselineName != "" {
+			if instanceName != "" {
+				return controller.JSONBaseErrorReq(c, errAuditPlanBaselineConflict)
+			}
+			if err := checkSchemaBaseline(*req.SchemaBaselineName, ap.DBType); err != nil {
+				return controller.JSONBaseErrorReq(c, err)
+			}
+		}
+		updateAttr["schema_baseline_name"] = *req.SchemaBaselineName
+	}
 	if req.Params != nil {
 		ps, err := checkAndGenerateAuditPlanParams(ap.Type, ap.DBType, req.Params)
 		if err != nil {
@@ -377,13 +405,14 @@ type GetAuditPlansResV1 struct {
 }
 
 type AuditPlanResV1 struct {
-	Name             string          `json:"audit_plan_name" example:"audit_for_java_app1"`
-	Cron             string          `json:"audit_plan_cron" example:"0 */2 * * *"`
-	DBType           string          `json:"audit_plan_db_type" example:"mysql"`
-	Token            string          `json:"audit_plan_token" example:"it's a JWT Token for scanner"`
-	InstanceName     string          `json:"audit_plan_instance_name" example:"test_mysql"`
-	InstanceDatabase string          `json:"audit_plan_instance_database" example:"app1"`
-	Meta             AuditPlanMetaV1 `json:"audit_plan_meta"`
+	Name               string          `json:"audit_plan_name" example:"audit_for_java_app1"`
+	Cron               string          `json:"audit_plan_cron" example:"0 */2 * * *"`
+	DBType             string          `json:"audit_plan_db_type" example:"mysql"`
+	Token              string          `json:"audit_plan_token" example:"it's a JWT Token for scanner"`
+	InstanceName       string          `json:"audit_plan_instance_name" example:"test_mysql"`
+	InstanceDatabase   string          `json:"audit_plan_instance_database" example:"app1"`
+	Meta               AuditPlanMetaV1 `json:"audit_plan_meta"`
+	SchemaBaselineName string          `json:"audit_plan_schema_baseline_name" example:"app1_baseline"`
 }
 
 // @Summary 获取审核计划信息列表
@@ -444,13 +473,14 @@ func GetAuditPlans(c echo.Context) error {
 		}
 		meta.Params = ap.Params
 		auditPlansResV1[i] = AuditPlanResV1{
-			Name:             ap.Name,
-			Cron:             ap.Cron,
-			DBType:           ap.DBType,
-			InstanceName:     ap.InstanceName,
-			InstanceDatabase: ap.InstanceDatabase,
-			Token:            ap.Token,
-			Meta:             convertAuditPlanMetaToRes(meta),
+			Name:               ap.Name,
+			Cron:               ap.Cron,
+			DBType:             ap.DBType,
+			InstanceName:       ap.InstanceName,
+			InstanceDatabase:   ap.InstanceDatabase,
+			Token:              ap.Token,
+			Meta:               convertAuditPlanMetaToRes(meta),
+			SchemaBaselineName: ap.SchemaBaseline.String,
 		}
 	}
 	return c.JSON(http.StatusOK, &GetAuditPlansResV1{
@@ -498,13 +528,14 @@ func GetAuditPlan(c echo.Context) error {
 	return c.JSON(http.StatusOK, &GetAuditPlanResV1{
 		BaseRes: controller.NewBaseReq(nil),
 		Data: AuditPlanResV1{
-			Name:             ap.Name,
-			Cron:             ap.CronExpression,
-			DBType:           ap.DBType,
-			InstanceName:     ap.InstanceName,
-			InstanceDatabase: ap.InstanceDatabase,
-			Token:            ap.Token,
-			Meta:             convertAuditPlanMetaToRes(meta),
+			Name:               ap.Name,
+			Cron:               ap.CronExpression,
+			DBType:             ap.DBType,
+			InstanceName:       ap.InstanceName,
+			InstanceDatabase:   ap.InstanceDatabase,
+			Token:              ap.Token,
+			Meta:               convertAuditPlanMetaToRes(meta),
+			SchemaBaselineName: ap.SchemaBaselineName,
 		},
 	})
 }
@@ -3,12 +3,16 @@ package v1
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/config"
 	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/actiontech/sqle/sqle/notification"
+	"github.com/actiontech/sqle/sqle/server"
 
 	"github.com/labstack/echo/v4"
 )
@@ -419,24 +423,179 @@ type GetDriversResV1 struct {
 }
 
 type DriversResV1 struct {
-	Drivers []string `json:"driver_name_list"`
+	Drivers []string       `json:"driver_name_list"`
+	Details []*DriverResV1 `json:"driver_list"`
 }
 
-// GetDrivers get support Driver list.
-// @Summary 获取当前 server 支持的审核类型
-// @Description get drivers
+type DriverResV1 struct {
+	Name         string   `json:"driver_name"`
+	Capabilities []string `json:"capabilities" enums:"rollback,query,analysis,online_ddl,offline_audit,sandbox,schema_baseline"`
+}
+
+// GetDrivers get support Driver list and the capabilities of each driver.
+// @Summary 获取当前 server 支持的审核类型及其支持的功能
+// @Description get drivers and capabilities
 // @Id getDriversV1
 // @Tags configuration
 // @Security ApiKeyAuth
 // @Success 200 {object} v1.GetDriversResV1
 // @router /v1/configurations/drivers [get]
 func GetDrivers(c echo.Context) error {
+	names := driver.AllDrivers()
+	details := make([]*DriverResV1, 0, len(names))
+	for _, name := range names {
+		caps := driver.DriverCapabilities(name)
+		d := &DriverResV1{
+			Name:         name,
+			Capabilities: make([]string, 0, len(caps)),
+		}
+		for _, dc := range caps {
+			d.Capabilities = append(d.Capabilities, string(dc))
+		}
+		details = append(details, d)
+	}
 	return c.JSON(http.StatusOK, &GetDriversResV1{
 		BaseRes: controller.NewBaseReq(nil),
-		Data:    DriversResV1{Drivers: driver.AllDrivers()},
+		Data: DriversResV1{
+			Drivers: names,
+			Details: details,
+		},
+	})
+}
+
+type GetDriversStatusResV1 struct {
+	controller.BaseRes
+	Data []*DriverStatusResV1 `json:"data"`
+}
+
+type DriverStatusResV1 struct {
+	DriverName string              `json:"driver_name"`
+	IsPlugin   bool                `json:"is_plugin"`
+	Process    *PluginProcessResV1 `json:"process,omitempty"`
+}
+
+type PluginProcessResV1 struct {
+	PluginPath           string     `json:"plugin_path"`
+	State                string     `json:"state" enums:"not_started,running,stopped,crashed,start_failed,unresponsive"`
+	Pid                  int        `json:"pid"`
+	StartedAt            *time.Time `json:"started_at,omitempty"`
+	LastError            string     `json:"last_error"`
+	LastErrorAt          *time.Time `json:"last_error_at,omitempty"`
+	RestartCount         int        `json:"restart_count"`
+	RPCCount             int64      `json:"rpc_count"`
+	LastRPCLatencyMs     int64      `json:"last_rpc_latency_ms"`
+	AvgRPCLatencyMs      int64      `json:"avg_rpc_latency_ms"`
+	InflightRPCCount     int        `json:"inflight_rpc_count"`
+	LongestInflightRPCMs int64      `json:"longest_inflight_rpc_ms"`
+}
+
+// GetDriversStatus get status of drivers.
+// @Summary 获取审核插件运行状态
+// @Description get status of drivers, including plugin process state, last error, restart count and RPC latency
+// @Id getDriversStatusV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetDriversStatusResV1
+// @router /v1/configurations/drivers/status [get]
+func GetDriversStatus(c echo.Context) error {
+	status := driver.AllDriverStatus()
+	data := make([]*DriverStatusResV1, 0, len(status))
+	for _, s := range status {
+		ds := &DriverStatusResV1{
+			DriverName: s.Name,
+			IsPlugin:   s.Process != nil,
+		}
+		if p := s.Process; p != nil {
+			ds.Process = &PluginProcessResV1{
+				PluginPath:           p.Path,
+				State:                string(p.State),
+				Pid:                  p.Pid,
+				StartedAt:            timeOrNil(p.StartedAt),
+				LastError:            p.LastError,
+				LastErrorAt:          timeOrNil(p.LastErrorAt),
+				RestartCount:         p.RestartCount,
+				RPCCount:             p.RPCCount,
+				LastRPCLatencyMs:     p.LastRPCLatency.Milliseconds(),
+				AvgRPCLatencyMs:      p.AvgRPCLatency.Milliseconds(),
+				InflightRPCCount:     p.InflightRPCCount,
+				LongestInflightRPCMs: p.LongestInflightRPC.Milliseconds(),
+			}
+		}
+		data = append(data, ds)
+	}
+	return c.JSON(http.StatusOK, &GetDriversStatusResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+type GetPluginsResV1 struct {
+	controller.BaseRes
+	Data []*PluginResV1 `json:"data"`
+}
+
+type PluginResV1 struct {
+	Path        string     `json:"plugin_path"`
+	Name        string     `json:"plugin_name"`
+	ModTime     time.Time  `json:"mod_time"`
+	LoadedAt    *time.Time `json:"loaded_at,omitempty"`
+	LastError   string     `json:"last_error"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// GetPlugins get load status of plugins.
+// @Summary 获取插件加载状态
+// @Description get load status of plugins, including reload failures
+// @Id getPluginsV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetPluginsResV1
+// @router /v1/configurations/plugins [get]
+func GetPlugins(c echo.Context) error {
+	status := driver.AllPluginStatus()
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Path < status[j].Path
+	})
+
+	data := make([]*PluginResV1, 0, len(status))
+	for _, s := range status {
+		data = append(data, &PluginResV1{
+			Path:        s.Path,
+			Name:        s.Name,
+			ModTime:     s.ModTime,
+			LoadedAt:    timeOrNil(s.LoadedAt),
+			LastError:   s.LastError,
+			LastErrorAt: timeOrNil(s.LastErrorAt),
+		})
+	}
+	return c.JSON(http.StatusOK, &GetPluginsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
 	})
 }
 
+// ReloadPlugins reload new or modified plugins in plugin directory.
+// @Summary 重新加载插件
+// @Description reload new or modified plugins in plugin directory
+// @Id reloadPluginsV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} controller.BaseRes
+// @router /v1/configurations/plugins/reload [post]
+func ReloadPlugins(c echo.Context) error {
+	if err := server.ReloadPlugins(); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.LoadDriverFail, err))
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+}
+
 type GetSQLEInfoResV1 struct {
 	controller.BaseRes
 	Version string `json:"version"`
@@ -0,0 +1,268 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// custom rule is only evaluated by MySQL driver now.
+var customRuleSupportedDBTypes = []string{driver.DriverTypeMySQL}
+
+func checkCustomRuleDBType(dbType string) error {
+	for _, t := range customRuleSupportedDBTypes {
+		if t == dbType {
+			return nil
+		}
+	}
+	return errors.New(errors.DataInvalid, fmt.Errorf("custom rule is not supported by db type %s", dbType))
+}
+
+func checkCustomRuleMatcher(r *model.CustomRule) error {
+	if err := r.DriverMatcher().Validate(); err != nil {
+		return errors.New(errors.DataInvalid, err)
+	}
+	return nil
+}
+
+type CreateCustomRuleReqV1 struct {
+	RuleName  string   `json:"rule_name" valid:"required,name"`
+	DBType    string   `json:"db_type" valid:"required" example:"mysql"`
+	Desc      string   `json:"desc" valid:"required"`
+	Level     string   `json:"level" valid:"required,oneof=normal notice warn error" enums:"normal,notice,warn,error" example:"warn"`
+	Typ       string   `json:"type" valid:"required" example:"自定义规则"`
+	StmtTypes []string `json:"stmt_types" example:"select,update"`
+	MatchType string   `json:"match_type" valid:"required,oneof=sql_regex fingerprint_regex ast_predicate" enums:"sql_regex,fingerprint_regex,ast_predicate"`
+	// Matcher is a regexp, or a JSON predicate if match type is ast_predicate, e.g.
+	// {"table_name_pattern": "^tmp_", "missing_clauses": ["where"]}
+	Matcher string `json:"matcher" valid:"required"`
+}
+
+// @Summary 添加自定义规则
+// @Description create a custom rule
+// @Id createCustomRuleV1
+// @Tags rule_template
+// @Security ApiKeyAuth
+// @Accept json
+// @Param instance body v1.CreateCustomRuleReqV1 true "create custom rule request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/custom_rules [post]
+func CreateCustomRule(c echo.Context) error {
+	req := new(CreateCustomRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	if err := checkCustomRuleDBType(req.DBType); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	s := model.GetStorage()
+	_, exist, err := s.GetRule(req.RuleName, req.DBType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataExist, fmt.Errorf("rule is exist")))
+	}
+	_, exist, err = s.GetCustomRuleByName(req.RuleName)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataExist, fmt.Errorf("custom rule is exist")))
+	}
+
+	rule := &model.CustomRule{
+		RuleName:  req.RuleName,
+		DBType:    req.DBType,
+		Desc:      req.Desc,
+		Level:     req.Level,
+		Typ:       req.Typ,
+		StmtTypes: req.StmtTypes,
+		MatchType: req.MatchType,
+		Matcher:   req.Matcher,
+	}
+	if err := checkCustomRuleMatcher(rule); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := s.SaveCustomRule(rule); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+}
+
+type UpdateCustomRuleReqV1 struct {
+	Desc      *string  `json:"desc"`
+	Level     *string  `json:"level" valid:"omitempty,oneof=normal notice warn error" enums:"normal,notice,warn,error"`
+	Typ       *string  `json:"type"`
+	StmtTypes []string `json:"stmt_types"`
+	MatchType *string  `json:"match_type" valid:"omitempty,oneof=sql_regex fingerprint_regex ast_predicate" enums:"sql_regex,fingerprint_regex,ast_predicate"`
+	Matcher   *string  `json:"matcher"`
+}
+
+// @Summary 更新自定义规则
+// @Description update custom rule
+// @Id updateCustomRuleV1
+// @Tags rule_template
+// @Security ApiKeyAuth
+// @Param rule_name path string true "custom rule name"
+// @Param instance body v1.UpdateCustomRuleReqV1 true "update custom rule request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/custom_rules/{rule_name}/ [patch]
+func UpdateCustomRule(c echo.Context) error {
+	req := new(UpdateCustomRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	rule, exist, err := s.GetCustomRuleByName(c.Param("rule_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("custom rule is not exist")))
+	}
+
+	if req.Desc != nil {
+		rule.Desc = *req.Desc
+	}
+	if req.Level != nil {
+		rule.Level = *req.Level
+	}
+	if req.Typ != nil {
+		rule.Typ = *req.Typ
+	}
+	if req.StmtTypes != nil {
+		rule.StmtTypes = req.StmtTypes
+	}
+	if req.MatchType != nil {
+		rule.MatchType = *req.MatchType
+	}
+	if req.Matcher != nil {
+		rule.Matcher = *req.Matcher
+	}
+	if err := checkCustomRuleMatcher(rule); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := s.SaveCustomRule(rule); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+}
+
+// @Summary 删除自定义规则
+// @Description delete custom rule, the rule is removed from all rule templates
+// @Id deleteCustomRuleV1
+// @Tags rule_template
+// @Security ApiKeyAuth
+// @Param rule_name path string true "custom rule name"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/custom_rules/{rule_name}/ [delete]
+func DeleteCustomRule(c echo.Context) error {
+	s := model.GetStorage()
+	rule, exist, err := s.GetCustomRuleByName(c.Param("rule_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("custom rule is not exist")))
+	}
+	if err := s.DeleteCustomRule(rule); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+}
+
+type CustomRuleResV1 struct {
+	RuleName  string   `json:"rule_name"`
+	DBType    string   `json:"db_type" example:"mysql"`
+	Desc      string   `json:"desc"`
+	Level     string   `json:"level" enums:"normal,notice,warn,error"`
+	Typ       string   `json:"type"`
+	StmtTypes []string `json:"stmt_types"`
+	MatchType string   `json:"match_type" enums:"sql_regex,fingerprint_regex,ast_predicate"`
+	Matcher   string   `json:"matcher"`
+}
+
+func convertCustomRuleToRes(rule *model.CustomRule) CustomRuleResV1 {
+	return CustomRuleResV1{
+		RuleName:  rule.RuleName,
+		DBType:    rule.DBType,
+		Desc:      rule.Desc,
+		Level:     rule.Level,
+		Typ:       rule.Typ,
+		StmtTypes: rule.StmtTypes,
+		MatchType: rule.MatchType,
+		Matcher:   rule.Matcher,
+	}
+}
+
+type GetCustomRuleResV1 struct {
+	controller.BaseRes
+	Data CustomRuleResV1 `json:"data"`
+}
+
+// @Summary 获取自定义规则
+// @Description get custom rule
+// @Id getCustomRuleV1
+// @Tags rule_template
+// @Security ApiKeyAuth
+// @Param rule_name path string true "custom rule name"
+// @Success 200 {object} v1.GetCustomRuleResV1
+// @router /v1/custom_rules/{rule_name}/ [get]
+func GetCustomRule(c echo.Context) error {
+	s := model.GetStorage()
+	rule, exist, err := s.GetCustomRuleByName(c.Param("rule_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("custom rule is not exist")))
+	}
+	return c.JSON(http.StatusOK, &GetCustomRuleResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertCustomRuleToRes(rule),
+	})
+}
+
+type GetCustomRulesReqV1 struct {
+	FilterDBType string `json:"filter_db_type" query:"filter_db_type"`
+}
+
+type GetCustomRulesResV1 struct {
+	controller.BaseRes
+	Data []CustomRuleResV1 `json:"data"`
+}
+
+// @Summary 自定义规则列表
+// @Description get custom rules
+// @Id getCustomRuleListV1
+// @Tags rule_template
+// @Security ApiKeyAuth
+// @Param filter_db_type query string false "filter db type"
+// @Success 200 {object} v1.GetCustomRulesResV1
+// @router /v1/custom_rules [get]
+func GetCustomRules(c echo.Context) error {
+	req := new(GetCustomRulesReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	rules, err := model.GetStorage().GetCustomRules(req.FilterDBType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]CustomRuleResV1, 0, len(rules))
+	for _, rule := range rules {
+		data = append(data, convertCustomRuleToRes(rule))
+	}
+	return c.JSON(http.StatusOK, &GetCustomRulesResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/labstack/echo/v4"
 )
@@ -89,3 +90,127 @@ func Dashboard(c echo.Context) error {
 		},
 	})
 }
+
+type GetRuleHitStatisticsReqV1 struct {
+	FilterCreateTimeFrom   string `json:"filter_create_time_from" query:"filter_create_time_from"`
+	FilterCreateTimeTo     string `json:"filter_create_time_to" query:"filter_create_time_to"`
+	FilterDBType           string `json:"filter_db_type" query:"filter_db_type"`
+	FilterInstanceName     string `json:"filter_instance_name" query:"filter_instance_name"`
+	FilterRuleTemplateName string `json:"filter_rule_template_name" query:"filter_rule_template_name"`
+	FilterLevel            string `json:"filter_level" query:"filter_level" valid:"omitempty,oneof=normal notice warn error"`
+	FilterSource           string `json:"filter_source" query:"filter_source" valid:"omitempty,oneof=task audit_plan"`
+}
+
+type GetRuleHitStatisticsResV1 struct {
+	controller.BaseRes
+	Data []*RuleHitStatisticsResV1 `json:"data"`
+}
+
+type RuleHitStatisticsResV1 struct {
+	RuleName         string `json:"rule_name"`
+	DBType           string `json:"db_type"`
+	InstanceName     string `json:"instance_name"`
+	RuleTemplateName string `json:"rule_template_name"`
+	Level            string `json:"level" enums:"normal,notice,warn,error"`
+	HitCount         uint64 `json:"hit_count"`
+	SuppressedCount  uint64 `json:"suppressed_count"`
+	BlockedCount     uint64 `json:"blocked_count"`
+	AuditCount       uint64 `json:"audit_count"`
+}
+
+// @Summary 获取规则触发统计
+// @Description get the hit statistics of rules in tasks and audit plan reports, grouped by rule, instance, rule template and level. The SQLs which match the whitelist are counted as rule "sql_whitelist", and blocked_count is the number of hits which block the task from being submitted as workflow
+// @Id getRuleHitStatisticsV1
+// @Tags dashboard
+// @Security ApiKeyAuth
+// @Param filter_create_time_from query string false "filter create time from"
+// @Param filter_create_time_to query string false "filter create time to"
+// @Param filter_db_type query string false "filter db type"
+// @Param filter_instance_name query string false "filter instance name"
+// @Param filter_rule_template_name query string false "filter rule template name"
+// @Param filter_level query string false "filter level" Enums(normal,notice,warn,error)
+// @Param filter_source query string false "filter source" Enums(task,audit_plan)
+// @Produce json
+// @Success 200 {object} v1.GetRuleHitStatisticsResV1
+// @router /v1/dashboard/rule_hits [get]
+func GetRuleHitStatistics(c echo.Context) error {
+	req := new(GetRuleHitStatisticsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	statistics, err := s.GetRuleHitStatisticsByReq(map[string]interface{}{
+		"filter_create_time_from":   req.FilterCreateTimeFrom,
+		"filter_create_time_to":     req.FilterCreateTimeTo,
+		"filter_db_type":            req.FilterDBType,
+		"filter_instance_name":      req.FilterInstanceName,
+		"filter_rule_template_name": req.FilterRuleTemplateName,
+		"filter_level":              req.FilterLevel,
+		"filter_source":             req.FilterSource,
+	})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]*RuleHitStatisticsResV1, 0, len(statistics))
+	for _, s := range statistics {
+		data = append(data, &RuleHitStatisticsResV1{
+			RuleName:         s.RuleName,
+			DBType:           s.DBType,
+			InstanceName:     s.InstanceName,
+			RuleTemplateName: s.RuleTemplateName,
+			Level:            s.Level,
+			HitCount:         s.HitCount,
+			SuppressedCount:  s.SuppressedCount,
+			BlockedCount:     s.BlockedCount,
+			AuditCount:       s.AuditCount,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetRuleHitStatisticsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type GetMetaCacheStatisticsResV1 struct {
+	controller.BaseRes
+	Data []*MetaCacheStatisticsResV1 `json:"data"`
+}
+
+type MetaCacheStatisticsResV1 struct {
+	Instance     string  `json:"instance"`
+	Hits         uint64  `json:"hits"`
+	Misses       uint64  `json:"misses"`
+	HitRate      float64 `json:"hit_rate"`
+	CachedTables int     `json:"cached_tables"`
+}
+
+// @Summary 获取 MySQL 元数据缓存统计
+// @Description get the hit statistics of MySQL metadata cache, grouped by instance address and user
+// @Id getMetaCacheStatisticsV1
+// @Tags dashboard
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} v1.GetMetaCacheStatisticsResV1
+// @router /v1/dashboard/meta_cache [get]
+func GetMetaCacheStatistics(c echo.Context) error {
+	stats := session.DefaultMetaCache.Stats()
+	data := make([]*MetaCacheStatisticsResV1, 0, len(stats))
+	for _, s := range stats {
+		var hitRate float64
+		if total := s.Hits + s.Misses; total > 0 {
+			hitRate = float64(s.Hits) / float64(total)
+		}
+		data = append(data, &MetaCacheStatisticsResV1{
+			Instance:     s.Instance,
+			Hits:         s.Hits,
+			Misses:       s.Misses,
+			HitRate:      hitRate,
+			CachedTables: s.CachedTables,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetMetaCacheStatisticsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
 	"github.com/actiontech/sqle/sqle/utils"
 
 	"github.com/labstack/echo/v4"
@@ -90,6 +91,56 @@ type CreateInstanceReqV1 struct {
 	RuleTemplates        []string                        `json:"rule_template_name_list" form:"rule_template_name_list"`
 	Roles                []string                        `json:"role_name_list" form:"role_name_list"`
 	AdditionalParams     []*InstanceAdditionalParamReqV1 `json:"additional_params" from:"additional_params"`
+	RuleOverrides        []*InstanceRuleOverrideReqV1    `json:"rule_override_list" from:"rule_override_list" valid:"dive,required"`
+}
+
+type InstanceRuleOverrideReqV1 struct {
+	RuleName string           `json:"rule_name" valid:"required" example:"ddl_check_object_name_length"`
+	Level    string           `json:"level" valid:"omitempty,oneof=normal notice warn error" enums:"normal,notice,warn,error"`
+	Params   []RuleParamReqV1 `json:"params" valid:"dive,required"`
+}
+
+// checkAndGenerateInstanceRuleOverrides checks the rule and the params of overrides, only
+// the params in request are kept in the override, the others are inherited from rule template.
+func checkAndGenerateInstanceRuleOverrides(reqs []*InstanceRuleOverrideReqV1, dbType string) ([]*model.InstanceRuleOverride, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	ruleNames := make([]string, 0, len(reqs))
+	for _, r := range reqs {
+		for _, name := range ruleNames {
+			if name == r.RuleName {
+				return nil, errors.New(errors.DataInvalid, fmt.Errorf("rule %s is overridden repeatedly", r.RuleName))
+			}
+		}
+		ruleNames = append(ruleNames, r.RuleName)
+	}
+	rules, err := model.GetStorage().GetAndCheckRuleExist(ruleNames, dbType)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make([]*model.InstanceRuleOverride, 0, len(reqs))
+	for _, r := range reqs {
+		rule := rules[r.RuleName]
+		ruleParams := rule.Params.Copy()
+		overrideParams := make(params.Params, 0, len(r.Params))
+		for _, p := range r.Params {
+			if err := ruleParams.SetParamValue(p.Key, p.Value); err != nil {
+				return nil, errors.New(errors.DataInvalid, fmt.Errorf("set rule %s param error: %s", r.RuleName, err))
+			}
+			overrideParams = append(overrideParams, ruleParams.GetParam(p.Key))
+		}
+		if r.Level == "" && len(overrideParams) == 0 {
+			return nil, errors.New(errors.DataInvalid, fmt.Errorf("rule %s overrides nothing", r.RuleName))
+		}
+		overrides = append(overrides, &model.InstanceRuleOverride{
+			RuleName:   r.RuleName,
+			RuleLevel:  r.Level,
+			RuleParams: overrideParams,
+		})
+	}
+	return overrides, nil
 }
 
 type SQLQueryConfigReqV1 struct {
@@ -241,6 +292,11 @@ func CreateInstance(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
+	ruleOverrides, err := checkAndGenerateInstanceRuleOverrides(req.RuleOverrides, instance.DbType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
 	err = s.Save(instance)
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
@@ -255,6 +311,11 @@ func CreateInstance(c echo.Context) error {
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
+
+	err = s.UpdateInstanceRuleOverrides(instance, ruleOverrides...)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
 	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
@@ -292,6 +353,33 @@ type InstanceResV1 struct {
 	Roles                []string                        `json:"role_name_list,omitempty"`
 	AdditionalParams     []*InstanceAdditionalParamResV1 `json:"additional_params"`
 	SQLQueryConfig       *SQLQueryConfigResV1            `json:"sql_query_config"`
+	RuleOverrides        []*InstanceRuleOverrideResV1    `json:"rule_override_list,omitempty"`
+}
+
+type InstanceRuleOverrideResV1 struct {
+	RuleName string           `json:"rule_name"`
+	Level    string           `json:"level,omitempty" enums:"normal,notice,warn,error"`
+	Params   []RuleParamResV1 `json:"params,omitempty"`
+}
+
+func convertInstanceRuleOverridesToRes(overrides []*model.InstanceRuleOverride) []*InstanceRuleOverrideResV1 {
+	overridesRes := make([]*InstanceRuleOverrideResV1, 0, len(overrides))
+	for _, o := range overrides {
+		overrideRes := &InstanceRuleOverrideResV1{
+			RuleName: o.RuleName,
+			Level:    o.RuleLevel,
+		}
+		for _, p := range o.RuleParams {
+			overrideRes.Params = append(overrideRes.Params, RuleParamResV1{
+				Key:   p.Key,
+				Value: p.Value,
+				Desc:  p.Desc,
+				Type:  string(p.Type),
+			})
+		}
+		overridesRes = append(overridesRes, overrideRes)
+	}
+	return overridesRes
 }
 
 type SQLQueryConfigResV1 struct {
@@ -406,9 +494,17 @@ func GetInstance(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
 	}
 
+	overrides, err := s.GetInstanceRuleOverrides(instance.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	instanceRes := convertInstanceToRes(instance)
+	if len(overrides) > 0 {
+		instanceRes.RuleOverrides = convertInstanceRuleOverridesToRes(overrides)
+	}
 	return c.JSON(http.StatusOK, &GetInstanceResV1{
 		BaseRes: controller.NewBaseReq(nil),
-		Data:    convertInstanceToRes(instance),
+		Data:    instanceRes,
 	})
 }
 
@@ -469,6 +565,7 @@ type UpdateInstanceReqV1 struct {
 	Roles                []string                        `json:"role_name_list" form:"role_name_list"`
 	SQLQueryConfig       *SQLQueryConfigReqV1            `json:"sql_query_config" from:"sql_query_config"`
 	AdditionalParams     []*InstanceAdditionalParamReqV1 `json:"additional_params" from:"additional_params"`
+	RuleOverrides        []*InstanceRuleOverrideReqV1    `json:"rule_override_list" from:"rule_override_list" valid:"dive,required"`
 }
 
 // UpdateInstance update instance
@@ -579,6 +676,17 @@ func UpdateInstance(c echo.Context) error {
 		}
 	}
 
+	if req.RuleOverrides != nil {
+		ruleOverrides, err := checkAndGenerateInstanceRuleOverrides(req.RuleOverrides, instance.DbType)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		err = s.UpdateInstanceRuleOverrides(instance, ruleOverrides...)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
+
 	if req.AdditionalParams != nil {
 		additionalParams := driver.AllAdditionalParams()[instance.DbType]
 		for _, additionalParam := range req.AdditionalParams {
@@ -959,12 +1067,40 @@ func GetInstanceRules(c echo.Context) error {
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
+	overrides, err := s.GetInstanceRuleOverrides(instance.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
 	return c.JSON(http.StatusOK, &GetRulesResV1{
 		BaseRes: controller.NewBaseReq(nil),
-		Data:    convertRulesToRes(rules),
+		Data:    convertInstanceRulesToRes(rules, overrides),
 	})
 }
 
+// convertInstanceRulesToRes converts the rules of instance, the origin of level and params
+// is the instance if they are overridden by instance, otherwise it is the rule template.
+func convertInstanceRulesToRes(rules []*model.Rule, overrides []*model.InstanceRuleOverride) []RuleResV1 {
+	overrideMap := make(map[string]*model.InstanceRuleOverride, len(overrides))
+	for _, o := range overrides {
+		overrideMap[o.RuleName] = o
+	}
+	rulesRes := convertRulesToRes(rules)
+	for i := range rulesRes {
+		o, isOverridden := overrideMap[rulesRes[i].Name]
+		rulesRes[i].LevelOrigin = model.RuleValueOriginRuleTemplate
+		if isOverridden && o.RuleLevel != "" {
+			rulesRes[i].LevelOrigin = model.RuleValueOriginInstance
+		}
+		for j := range rulesRes[i].Params {
+			rulesRes[i].Params[j].Origin = model.RuleValueOriginRuleTemplate
+			if isOverridden && o.IsParamOverridden(rulesRes[i].Params[j].Key) {
+				rulesRes[i].Params[j].Origin = model.RuleValueOriginInstance
+			}
+		}
+	}
+	return rulesRes
+}
+
 func CheckInstanceCanBindOneRuleTemplate(ruleTemplates []string) bool {
 	return len(ruleTemplates) <= 1
 }
@@ -1106,3 +1242,75 @@ type GetTableMetadataResV1 struct {
 func GetTableMetadata(c echo.Context) error {
 	return getTableMetadata(c)
 }
+
+type SchemaDesignReportTableResV1 struct {
+	Number         uint   `json:"number" example:"1"`
+	CreateTableSQL string `json:"create_table_sql"`
+	AuditResult    string `json:"audit_result" example:"same format as task audit result"`
+}
+
+type SchemaDesignReportResV1 struct {
+	AuditLevel string                          `json:"audit_level" enums:"normal,notice,warn,error,"`
+	Score      int32                           `json:"score"`
+	PassRate   float64                         `json:"pass_rate"`
+	Tables     []*SchemaDesignReportTableResV1 `json:"tables"`
+}
+
+type GetSchemaDesignReportResV1 struct {
+	controller.BaseRes
+	Data SchemaDesignReportResV1 `json:"data"`
+}
+
+// GetSchemaDesignReport audit the design of schema on demand
+// @Summary 审核数据库的库表设计
+// @Description audit the design of all tables in the schema as a unit by the schema design rules in the rule template of instance, the report is not saved
+// @Id getSchemaDesignReportV1
+// @Tags instance
+// @Param instance_name path string true "instance name"
+// @Param schema_name path string true "schema name"
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetSchemaDesignReportResV1
+// @router /v1/instances/{instance_name}/schemas/{schema_name}/design_report [get]
+func GetSchemaDesignReport(c echo.Context) error {
+	s := model.GetStorage()
+	instance, exist, err := s.GetInstanceByName(c.Param("instance_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
+	}
+	can, err := checkCurrentUserCanAccessInstance(c, instance)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !can {
+		return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
+	}
+	if instance.DbType != driver.DriverTypeMySQL {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("schema design audit is not supported by db type %s", instance.DbType)))
+	}
+
+	report, err := auditplan.AuditSchemaDesign(log.NewEntry(), instance, c.Param("schema_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	tables := make([]*SchemaDesignReportTableResV1, 0, len(report.AuditPlanReportSQLs))
+	for _, sql := range report.AuditPlanReportSQLs {
+		tables = append(tables, &SchemaDesignReportTableResV1{
+			Number:         sql.Number,
+			CreateTableSQL: sql.SQL,
+			AuditResult:    sql.AuditResult,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSchemaDesignReportResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: SchemaDesignReportResV1{
+			AuditLevel: report.AuditLevel,
+			Score:      report.Score,
+			PassRate:   report.PassRate,
+			Tables:     tables,
+		},
+	})
+}
@@ -4,18 +4,16 @@
 package v1
 
 import (
-	e "errors"
-	"github.com/actiontech/sqle/sqle/errors"
+	"context"
 	"net/http"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/labstack/echo/v4"
 )
 
-var errCommunityEditionDoesNotSupportListTables = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support list tables"))
-var errCommunityEditionDoesNotSupportGetTablesMetadata = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support get table metadata"))
-
 func getInstanceTips(c echo.Context) error {
 	req := new(InstanceTipReqV1)
 	if err := controller.BindAndValidateReq(c, req); err != nil {
@@ -52,10 +50,100 @@ func getInstanceTips(c echo.Context) error {
 	})
 }
 
+// newAnalysisDriver returns the AnalysisDriver of instance which current user can access.
+func newAnalysisDriver(c echo.Context, instanceName, schema string) (driver.AnalysisDriver, error) {
+	s := model.GetStorage()
+	instance, exist, err := s.GetInstanceByName(instanceName)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errInstanceNoAccess
+	}
+	can, err := checkCurrentUserCanAccessInstance(c, instance)
+	if err != nil {
+		return nil, err
+	}
+	if !can {
+		return nil, errInstanceNoAccess
+	}
+	dsn, err := newDSN(instance, schema)
+	if err != nil {
+		return nil, err
+	}
+	return driver.NewAnalysisDriver(log.NewEntry(), instance.DbType, dsn)
+}
+
 func listTableBySchema(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportListTables)
+	schema := c.Param("schema_name")
+	d, err := newAnalysisDriver(c, c.Param("instance_name"), schema)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer d.Close(context.TODO())
+	res, err := d.ListTablesInSchema(context.TODO(), &driver.ListTablesInSchemaConf{Schema: schema})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	tables := make([]Table, 0, len(res.Tables))
+	for _, table := range res.Tables {
+		tables = append(tables, Table{Name: table.Name})
+	}
+	return c.JSON(http.StatusOK, &ListTableBySchemaResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    tables,
+	})
 }
 
 func getTableMetadata(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportGetTablesMetadata)
+	schema := c.Param("schema_name")
+	d, err := newAnalysisDriver(c, c.Param("instance_name"), schema)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer d.Close(context.TODO())
+	res, err := d.GetTableMetaByTableName(context.TODO(), &driver.GetTableMetaByTableNameConf{
+		Schema: schema,
+		Table:  c.Param("table_name"),
+	})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetTableMetadataResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertTableMetaItemToRes(res.TableMeta),
+	})
+}
+
+func convertAnalysisInfoToRes(info driver.AnalysisInfoInTableFormat) ([]TableMetaItemHeadResV1, []map[string]string) {
+	head := make([]TableMetaItemHeadResV1, 0, len(info.Column))
+	for _, column := range info.Column {
+		head = append(head, TableMetaItemHeadResV1{
+			FieldName: column.Name,
+			Desc:      column.Desc,
+		})
+	}
+	rows := make([]map[string]string, 0, len(info.Rows))
+	for _, row := range info.Rows {
+		r := make(map[string]string, len(row))
+		for i, value := range row {
+			if i < len(info.Column) {
+				r[info.Column[i].Name] = value
+			}
+		}
+		rows = append(rows, r)
+	}
+	return head, rows
+}
+
+func convertTableMetaItemToRes(meta driver.TableMetaItem) TableMeta {
+	res := TableMeta{
+		Name:           meta.Name,
+		Schema:         meta.Schema,
+		CreateTableSQL: meta.CreateTableSQL,
+	}
+	res.Columns.Head, res.Columns.Rows = convertAnalysisInfoToRes(meta.ColumnsInfo.AnalysisInfoInTableFormat)
+	res.Indexes.Head, res.Indexes.Rows = convertAnalysisInfoToRes(meta.IndexesInfo.AnalysisInfoInTableFormat)
+	return res
 }
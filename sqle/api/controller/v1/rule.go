@@ -5,8 +5,11 @@ import (
 	"net/http"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
 
 	"github.com/labstack/echo/v4"
 )
@@ -17,6 +20,9 @@ type CreateRuleTemplateReqV1 struct {
 	DBType    string      `json:"db_type" valid:"required"`
 	Instances []string    `json:"instance_name_list"`
 	RuleList  []RuleReqV1 `json:"rule_list" form:"rule_list" valid:"required,dive,required"`
+
+	AllowSuppression   bool     `json:"allow_suppression"`
+	SuppressibleLevels []string `json:"suppressible_levels" valid:"dive,oneof=normal notice warn error" enums:"normal,notice,warn,error"`
 }
 
 type RuleReqV1 struct {
@@ -103,9 +109,11 @@ func CreateRuleTemplate(c echo.Context) error {
 	}
 
 	ruleTemplate := &model.RuleTemplate{
-		Name:   req.Name,
-		Desc:   req.Desc,
-		DBType: req.DBType,
+		Name:               req.Name,
+		Desc:               req.Desc,
+		DBType:             req.DBType,
+		AllowSuppression:   req.AllowSuppression,
+		SuppressibleLevels: req.SuppressibleLevels,
 	}
 	templateRules := make([]model.RuleTemplateRule, 0, len(req.RuleList))
 	if req.RuleList != nil || len(req.RuleList) > 0 {
@@ -154,6 +162,9 @@ type UpdateRuleTemplateReqV1 struct {
 	Desc      *string     `json:"desc"`
 	Instances []string    `json:"instance_name_list" example:"mysql-xxx"`
 	RuleList  []RuleReqV1 `json:"rule_list" form:"rule_list" valid:"dive,required"`
+
+	AllowSuppression   *bool    `json:"allow_suppression"`
+	SuppressibleLevels []string `json:"suppressible_levels" valid:"dive,oneof=normal notice warn error" enums:"normal,notice,warn,error"`
 }
 
 // @Summary 更新规则模板
@@ -206,8 +217,16 @@ func UpdateRuleTemplate(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
-	if req.Desc != nil {
-		template.Desc = *req.Desc
+	if req.Desc != nil || req.AllowSuppression != nil || req.SuppressibleLevels != nil {
+		if req.Desc != nil {
+			template.Desc = *req.Desc
+		}
+		if req.AllowSuppression != nil {
+			template.AllowSuppression = *req.AllowSuppression
+		}
+		if req.SuppressibleLevels != nil {
+			template.SuppressibleLevels = req.SuppressibleLevels
+		}
 		err = s.Save(&template)
 		if err != nil {
 			return controller.JSONBaseErrorReq(c, err)
@@ -240,6 +259,9 @@ type RuleTemplateDetailResV1 struct {
 	DBType    string      `json:"db_type"`
 	Instances []string    `json:"instance_name_list,omitempty"`
 	RuleList  []RuleResV1 `json:"rule_list,omitempty"`
+
+	AllowSuppression   bool     `json:"allow_suppression"`
+	SuppressibleLevels []string `json:"suppressible_levels" enums:"normal,notice,warn,error"`
 }
 
 func convertRuleTemplateToRes(template *model.RuleTemplate) *RuleTemplateDetailResV1 {
@@ -257,6 +279,9 @@ func convertRuleTemplateToRes(template *model.RuleTemplate) *RuleTemplateDetailR
 		DBType:    template.DBType,
 		Instances: instanceNames,
 		RuleList:  ruleList,
+
+		AllowSuppression:   template.AllowSuppression,
+		SuppressibleLevels: template.SuppressibleLevels,
 	}
 }
 
@@ -394,6 +419,17 @@ type RuleResV1 struct {
 	Typ    string           `json:"type" example:"全局配置" `
 	DBType string           `json:"db_type" example:"mysql"`
 	Params []RuleParamResV1 `json:"params,omitempty"`
+
+	Knowledge *RuleKnowledgeResV1 `json:"knowledge,omitempty"`
+	// LevelOrigin is only returned by the rules of instance.
+	LevelOrigin string `json:"level_origin,omitempty" enums:"rule_template,instance"`
+}
+
+type RuleKnowledgeResV1 struct {
+	Rationale   string `json:"rationale"`
+	BadExample  string `json:"bad_example"`
+	GoodExample string `json:"good_example"`
+	Remediation string `json:"remediation"`
 }
 
 type RuleParamResV1 struct {
@@ -401,6 +437,8 @@ type RuleParamResV1 struct {
 	Value string `json:"value" form:"value"`
 	Desc  string `json:"desc" form:"desc"`
 	Type  string `json:"type" form:"type" enums:"string,int,bool"`
+	// Origin is only returned by the rules of instance.
+	Origin string `json:"origin,omitempty" enums:"rule_template,instance"`
 }
 
 func convertRuleToRes(rule *model.Rule) RuleResV1 {
@@ -424,6 +462,14 @@ func convertRuleToRes(rule *model.Rule) RuleResV1 {
 		}
 		ruleRes.Params = paramsRes
 	}
+	if rule.RuleKnowledge != (model.RuleKnowledge{}) {
+		ruleRes.Knowledge = &RuleKnowledgeResV1{
+			Rationale:   rule.Rationale,
+			BadExample:  rule.BadExample,
+			GoodExample: rule.GoodExample,
+			Remediation: rule.Remediation,
+		}
+	}
 	return ruleRes
 }
 
@@ -572,9 +618,11 @@ func CloneRuleTemplate(c echo.Context) error {
 	}
 
 	ruleTemplate := &model.RuleTemplate{
-		Name:   req.Name,
-		Desc:   req.Desc,
-		DBType: sourceTpl.DBType,
+		Name:               req.Name,
+		Desc:               req.Desc,
+		DBType:             sourceTpl.DBType,
+		AllowSuppression:   sourceTpl.AllowSuppression,
+		SuppressibleLevels: sourceTpl.SuppressibleLevels,
 	}
 	err = s.Save(ruleTemplate)
 	if err != nil {
@@ -607,3 +655,128 @@ func CheckRuleTemplateCanBeBindEachInstance(s *model.Storage, tplName string, in
 	}
 	return nil
 }
+
+type AuditRuleSandboxReqV1 struct {
+	DBType           string      `json:"db_type" valid:"required" example:"mysql"`
+	RuleTemplateName string      `json:"rule_template_name"`
+	RuleList         []RuleReqV1 `json:"rule_list" valid:"dive,required"`
+	SetupSQL         string      `json:"setup_sql" example:"create table t1(id int primary key, name varchar(32));"`
+	SQL              string      `json:"sql" valid:"required" example:"alter table t1 modify column name varchar(16);"`
+}
+
+type AuditRuleSandboxResV1 struct {
+	controller.BaseRes
+	Data []*RuleSandboxSQLResV1 `json:"data"`
+}
+
+type RuleSandboxSQLResV1 struct {
+	Number       uint                `json:"number"`
+	SQL          string              `json:"sql"`
+	AuditLevel   string              `json:"audit_level" enums:"normal,notice,warn,error,"`
+	AuditResults []*AuditResultResV1 `json:"audit_results"`
+}
+
+// @Summary 在沙箱中使用规则审核SQL
+// @Description audit SQL by the rules of rule template or rule list without instance, the setup SQL is audited before the SQL to create the tables which the SQL depends on, and only the results of the SQL are returned. The rule list overrides the level and params of the rule template.
+// @Id auditRuleSandboxV1
+// @Tags rule_template
+// @Security ApiKeyAuth
+// @Accept json
+// @Param instance body v1.AuditRuleSandboxReqV1 true "audit rule sandbox request"
+// @Success 200 {object} v1.AuditRuleSandboxResV1
+// @router /v1/rules/sandbox [post]
+func AuditRuleSandbox(c echo.Context) error {
+	req := new(AuditRuleSandboxReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	rules, err := getSandboxRules(req)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	driverRules := make([]*driver.Rule, 0, len(rules))
+	for _, rule := range rules {
+		driverRules = append(driverRules, model.ConvertRuleToDriverRule(rule))
+	}
+	sqls, err := server.AuditSandbox(log.NewEntry(), req.DBType, req.RuleTemplateName, driverRules, req.SetupSQL, req.SQL)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+	}
+
+	data := make([]*RuleSandboxSQLResV1, 0, len(sqls))
+	for i, sql := range sqls {
+		data = append(data, &RuleSandboxSQLResV1{
+			Number:       uint(i + 1),
+			SQL:          sql.SQL,
+			AuditLevel:   string(sql.Result.Level()),
+			AuditResults: convertAuditResultsToRes(model.GenerateAuditResultsByDriverResult(sql.Result)),
+		})
+	}
+	return c.JSON(http.StatusOK, &AuditRuleSandboxResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+// getSandboxRules returns the rules of the rule template, which are overridden by the
+// rule list of request.
+func getSandboxRules(req *AuditRuleSandboxReqV1) ([]*model.Rule, error) {
+	s := model.GetStorage()
+	rules := []*model.Rule{}
+	ruleIndex := map[string]int{}
+
+	if req.RuleTemplateName != "" {
+		tpl, exist, err := s.GetRuleTemplateDetailByName(req.RuleTemplateName)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			return nil, errors.New(errors.DataNotExist, fmt.Errorf("rule template %s is not exist", req.RuleTemplateName))
+		}
+		if tpl.DBType != req.DBType {
+			return nil, errors.New(errors.DataInvalid, fmt.Errorf("db type of rule template %s is %s", tpl.Name, tpl.DBType))
+		}
+		for _, r := range tpl.RuleList {
+			rule := *r.GetRule()
+			ruleIndex[rule.Name] = len(rules)
+			rules = append(rules, &rule)
+		}
+	}
+
+	if len(req.RuleList) > 0 {
+		ruleNames := make([]string, 0, len(req.RuleList))
+		for _, r := range req.RuleList {
+			ruleNames = append(ruleNames, r.Name)
+		}
+		existRules, err := s.GetAndCheckRuleExist(ruleNames, req.DBType)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range req.RuleList {
+			rule := existRules[r.Name]
+			if i, ok := ruleIndex[r.Name]; ok {
+				rule = *rules[i]
+			}
+			rule.Level = r.Level
+			// the params of rule may be shared, so the params are set on a copy.
+			rule.Params = rule.Params.Copy()
+			for _, p := range r.Params {
+				if err := rule.Params.SetParamValue(p.Key, p.Value); err != nil {
+					return nil, errors.New(errors.DataInvalid, fmt.Errorf("set rule %s param error: %s", r.Name, err))
+				}
+			}
+			if i, ok := ruleIndex[r.Name]; ok {
+				rules[i] = &rule
+				continue
+			}
+			ruleIndex[r.Name] = len(rules)
+			rules = append(rules, &rule)
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("rule template or rule list is required"))
+	}
+	return rules, nil
+}
@@ -0,0 +1,209 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
+
+	"github.com/labstack/echo/v4"
+)
+
+var errSchemaBaselineNotExist = errors.New(errors.DataNotExist, fmt.Errorf("schema baseline is not exist"))
+
+// checkSchemaBaseline checks the schema baseline can be used by the audit of dbType.
+func checkSchemaBaseline(name, dbType string) error {
+	baseline, exist, err := model.GetStorage().GetSchemaBaselineByName(name)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errSchemaBaselineNotExist
+	}
+	if baseline.DBType != dbType {
+		return errors.New(errors.DataConflict, fmt.Errorf("db type of schema baseline is %v, not %v", baseline.DBType, dbType))
+	}
+	return nil
+}
+
+type CreateSchemaBaselineReqV1 struct {
+	Name          string `json:"name" form:"name" example:"app1_baseline" valid:"required,name"`
+	DBType        string `json:"db_type" form:"db_type" example:"mysql" valid:"required"`
+	DefaultSchema string `json:"default_schema" form:"default_schema" example:"app1"`
+	Desc          string `json:"desc" form:"desc"`
+	Sql           string `json:"sql" form:"sql" example:"CREATE TABLE t1(id INT PRIMARY KEY)"`
+	AuditPlanName string `json:"audit_plan_name" form:"audit_plan_name" example:"app1_schema_meta"`
+}
+
+// @Summary 上传库表结构基线
+// @Description create a schema baseline, the offline audit plan attached to it is audited against the tables in it.
+// @Description The CREATE statements can be uploaded in three ways, any one can be used, but only one is effective.
+// @Description 1. formData[sql]: the CREATE statements;
+// @Description 2. file[input_sql_file]: a schema dump file, e.g. the output of "mysqldump --no-data";
+// @Description 3. formData[audit_plan_name]: the snapshot of a schema meta audit plan.
+// @Accept mpfd
+// @Produce json
+// @Id createSchemaBaselineV1
+// @Tags schema_baseline
+// @Security ApiKeyAuth
+// @Param name formData string true "schema baseline name"
+// @Param db_type formData string true "db type"
+// @Param default_schema formData string false "the schema of the tables which schema is not specified"
+// @Param desc formData string false "description"
+// @Param sql formData string false "CREATE statements"
+// @Param input_sql_file formData file false "schema dump file"
+// @Param audit_plan_name formData string false "schema meta audit plan name"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/schema_baselines [post]
+func CreateSchemaBaseline(c echo.Context) error {
+	req := new(CreateSchemaBaselineReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	if !driver.HasCapability(req.DBType, driver.CapabilitySchemaBaseline) {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("db type %v does not support schema baseline", req.DBType)))
+	}
+
+	s := model.GetStorage()
+	_, exist, err := s.GetSchemaBaselineByName(req.Name)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataExist, fmt.Errorf("schema baseline is exist")))
+	}
+
+	baseline := &model.SchemaBaseline{
+		Name:          req.Name,
+		DBType:        req.DBType,
+		DefaultSchema: req.DefaultSchema,
+		Desc:          req.Desc,
+	}
+	switch {
+	case req.Sql != "":
+		baseline.Content = req.Sql
+	case req.AuditPlanName != "":
+		if err := CheckCurrentUserCanAccessAuditPlan(c, req.AuditPlanName, model.OP_AUDIT_PLAN_VIEW_OTHERS); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		ap, exist, err := s.GetAuditPlanByName(req.AuditPlanName)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !exist {
+			return controller.JSONBaseErrorReq(c, errAuditPlanNotExist)
+		}
+		if ap.Type != auditplan.TypeMySQLSchemaMeta || ap.DBType != req.DBType {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+				fmt.Errorf("audit plan %v is not a %v schema meta audit plan", ap.Name, req.DBType)))
+		}
+		sqls, err := s.GetAuditPlanSQLs(ap.Name)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		contents := make([]string, 0, len(sqls))
+		for _, sql := range sqls {
+			contents = append(contents, sql.SQLContent)
+		}
+		baseline.Content = model.NewSchemaBaselineContent(contents)
+		if baseline.DefaultSchema == "" {
+			baseline.DefaultSchema = ap.InstanceDatabase
+		}
+	default:
+		sql, exist, err := controller.ReadFileContent(c, InputSQLFileName)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !exist {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("schema baseline is empty")))
+		}
+		baseline.Content = sql
+	}
+
+	return controller.JSONBaseErrorReq(c, s.Save(baseline))
+}
+
+type GetSchemaBaselineTipsReqV1 struct {
+	FilterDBType string `json:"filter_db_type" query:"filter_db_type"`
+}
+
+type GetSchemaBaselineTipsResV1 struct {
+	controller.BaseRes
+	Data []*SchemaBaselineTipResV1 `json:"data"`
+}
+
+type SchemaBaselineTipResV1 struct {
+	Name          string    `json:"name"`
+	DBType        string    `json:"db_type"`
+	DefaultSchema string    `json:"default_schema"`
+	Desc          string    `json:"desc"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// @Summary 获取库表结构基线提示信息
+// @Description get schema baseline tips
+// @Id getSchemaBaselineTipsV1
+// @Tags schema_baseline
+// @Security ApiKeyAuth
+// @Param filter_db_type query string false "filter db type"
+// @Success 200 {object} v1.GetSchemaBaselineTipsResV1
+// @router /v1/schema_baseline_tips [get]
+func GetSchemaBaselineTips(c echo.Context) error {
+	req := new(GetSchemaBaselineTipsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	baselines, err := model.GetStorage().GetSchemaBaselineTips(req.FilterDBType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*SchemaBaselineTipResV1, 0, len(baselines))
+	for _, b := range baselines {
+		data = append(data, &SchemaBaselineTipResV1{
+			Name:          b.Name,
+			DBType:        b.DBType,
+			DefaultSchema: b.DefaultSchema,
+			Desc:          b.Desc,
+			CreatedAt:     b.CreatedAt,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSchemaBaselineTipsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+// @Summary 删除库表结构基线
+// @Description delete schema baseline, the schema baseline can not be deleted if it is attached to any audit plan
+// @Id deleteSchemaBaselineV1
+// @Tags schema_baseline
+// @Security ApiKeyAuth
+// @Param schema_baseline_name path string true "schema baseline name"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/schema_baselines/{schema_baseline_name}/ [delete]
+func DeleteSchemaBaseline(c echo.Context) error {
+	s := model.GetStorage()
+	baseline, exist, err := s.GetSchemaBaselineByName(c.Param("schema_baseline_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errSchemaBaselineNotExist)
+	}
+	auditPlanNames, err := s.GetAuditPlanNamesBySchemaBaseline(baseline.Name)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if len(auditPlanNames) > 0 {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataExist,
+			fmt.Errorf("schema baseline is used by audit plans %v", strings.Join(auditPlanNames, ", "))))
+	}
+	return controller.JSONBaseErrorReq(c, s.Delete(baseline))
+}
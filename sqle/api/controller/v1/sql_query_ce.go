@@ -4,27 +4,375 @@
 package v1
 
 import (
+	"context"
 	e "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
-var errCommunityEditionDoesNotSupportSqlQuery = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support SQL Query"))
+var errSqlQueryNotExist = errors.New(errors.DataNotExist, e.New("sql query is not exist"))
+var errSqlQueryEmpty = errors.New(errors.DataInvalid, e.New("sql is empty"))
+
+const sqlQueryExecResultOk = "ok"
+
+// getSQLQueryInstance returns the instance which current user can access.
+func getSQLQueryInstance(c echo.Context, instance *model.Instance, exist bool, err error) (*model.Instance, error) {
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errInstanceNoAccess
+	}
+	can, err := checkCurrentUserCanAccessInstance(c, instance)
+	if err != nil {
+		return nil, err
+	}
+	if !can {
+		return nil, errInstanceNoAccess
+	}
+	return instance, nil
+}
+
+func newSQLQueryDriver(l *logrus.Entry, instance *model.Instance, schema string) (driver.SQLQueryDriver, error) {
+	dsn, err := newDSN(instance, schema)
+	if err != nil {
+		return nil, err
+	}
+	return driver.NewSQLQueryDriver(l, instance.DbType, dsn)
+}
+
+// prepareQuerySQL checks the SQL is a query and rewrites it to read the page [offset, offset+limit).
+func prepareQuerySQL(d driver.SQLQueryDriver, sql string, limit, offset uint32) (string, error) {
+	res, err := d.QueryPrepare(context.TODO(), sql, &driver.QueryPrepareConf{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return "", err
+	}
+	if res.ErrorType == driver.ErrorTypeNotQuery {
+		return "", errors.New(errors.DataInvalid, fmt.Errorf("%s: %s", sql, res.Error))
+	}
+	return res.NewSQL, nil
+}
+
+// auditQuerySQLs audits the SQLs by the rules of instance, the query is not allowed
+// if any SQL audit level is not less than AllowQueryWhenLessThanAuditLevel.
+func auditQuerySQLs(l *logrus.Entry, instance *model.Instance, schema string, sqls []string) error {
+	task := &model.Task{
+		Schema:      schema,
+		InstanceId:  instance.ID,
+		Instance:    instance,
+		DBType:      instance.DbType,
+		ExecuteSQLs: make([]*model.ExecuteSQL, 0, len(sqls)),
+	}
+	for i, sql := range sqls {
+		task.ExecuteSQLs = append(task.ExecuteSQLs, &model.ExecuteSQL{
+			BaseSQL: model.BaseSQL{
+				Number:  uint(i + 1),
+				Content: sql,
+			},
+		})
+	}
+	if err := server.Audit(l, task); err != nil {
+		return err
+	}
+
+	allowLevel := instance.SqlQueryConfig.AllowQueryWhenLessThanAuditLevel
+	if allowLevel == "" {
+		return nil
+	}
+	var results []string
+	for _, executeSQL := range task.ExecuteSQLs {
+		if driver.RuleLevel(executeSQL.AuditLevel).MoreOrEqual(driver.RuleLevel(allowLevel)) {
+			results = append(results, fmt.Sprintf("%s: %s", executeSQL.Content, executeSQL.AuditResult))
+		}
+	}
+	if len(results) > 0 {
+		return errors.New(errors.DataInvalid, fmt.Errorf("query is not allowed because audit level is not less than %s, %s",
+			allowLevel, strings.Join(results, "; ")))
+	}
+	return nil
+}
 
 func prepareSQLQuery(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSqlQuery)
+	req := new(PrepareSQLQueryReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	instance, exist, err := s.GetInstanceByName(c.Param("instance_name"))
+	instance, err = getSQLQueryInstance(c, instance, exist, err)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	user, err := controller.GetCurrentUser(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	l := log.NewEntry()
+	d, err := newDriverWithoutAudit(l, instance, req.InstanceSchema)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer d.Close(context.TODO())
+	nodes, err := d.Parse(context.TODO(), req.SQL)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if len(nodes) == 0 {
+		return controller.JSONBaseErrorReq(c, errSqlQueryEmpty)
+	}
+
+	qd, err := newSQLQueryDriver(l, instance, req.InstanceSchema)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer qd.Close(context.TODO())
+	sqls := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if _, err := prepareQuerySQL(qd, node.Text, uint32(instance.SqlQueryConfig.MaxPreQueryRows), 0); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		sqls = append(sqls, node.Text)
+	}
+
+	if instance.SqlQueryConfig.AuditEnabled {
+		if err := auditQuerySQLs(l, instance, req.InstanceSchema, sqls); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
+
+	history := &model.SqlQueryHistory{
+		CreateUserId: user.ID,
+		InstanceId:   instance.ID,
+		Schema:       req.InstanceSchema,
+		RawSql:       req.SQL,
+		ExecSQLs:     make([]*model.SqlQueryExecutionSql, 0, len(sqls)),
+	}
+	for _, sql := range sqls {
+		history.ExecSQLs = append(history.ExecSQLs, &model.SqlQueryExecutionSql{Sql: sql})
+	}
+	if err := s.Save(history); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	queryIds := make([]PrepareSQLQueryResSQLV1, 0, len(history.ExecSQLs))
+	for _, executionSql := range history.ExecSQLs {
+		queryIds = append(queryIds, PrepareSQLQueryResSQLV1{
+			SQL:     executionSql.Sql,
+			QueryId: strconv.FormatUint(uint64(executionSql.ID), 10),
+		})
+	}
+	return c.JSON(http.StatusOK, &PrepareSQLQueryResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: PrepareSQLQueryResDataV1{
+			QueryIds: queryIds,
+		},
+	})
 }
 
 func getSQLResult(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSqlQuery)
+	req := new(GetSQLResultReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	executionSql, exist, err := s.GetSqlQueryExecutionSqlById(c.Param("query_id"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errSqlQueryNotExist)
+	}
+	history, exist, err := s.GetSqlQueryHistoryById(executionSql.SqlQueryHistoryId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	user, err := controller.GetCurrentUser(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	// user can only get the result of the query created by the same user.
+	if !exist || history.CreateUserId != user.ID {
+		return controller.JSONBaseErrorReq(c, errSqlQueryNotExist)
+	}
+	instance, exist, err := s.GetInstanceById(strconv.FormatUint(uint64(history.InstanceId), 10))
+	instance, err = getSQLQueryInstance(c, instance, exist, err)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	// the rows after the first MaxPreQueryRows rows of the query are never read.
+	pageSize := req.PageSize
+	offset := (req.PageIndex - 1) * req.PageSize
+	if maxRows := uint32(instance.SqlQueryConfig.MaxPreQueryRows); maxRows > 0 {
+		if offset >= maxRows {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+				fmt.Errorf("only the first %d rows of the query can be read", maxRows)))
+		}
+		if offset+pageSize > maxRows {
+			pageSize = maxRows - offset
+		}
+	}
+
+	l := log.NewEntry()
+	d, err := newSQLQueryDriver(l, instance, history.Schema)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer d.Close(context.TODO())
+	querySQL, err := prepareQuerySQL(d, executionSql.Sql, pageSize, offset)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	startAt := time.Now()
+	result, queryErr := d.Query(context.TODO(), querySQL, &driver.QueryConf{
+		TimeOutSecond: uint32(instance.SqlQueryConfig.QueryTimeoutSecond),
+	})
+	endAt := time.Now()
+
+	executionSql.ExecStartAt = &startAt
+	executionSql.ExecEndAt = &endAt
+	executionSql.ExecResult = sqlQueryExecResultOk
+	if queryErr != nil {
+		executionSql.ExecResult = queryErr.Error()
+	}
+	if err := s.Save(executionSql); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if queryErr != nil {
+		return controller.JSONBaseErrorReq(c, queryErr)
+	}
+	// the driver may return more rows than the rewritten limit.
+	if len(result.Rows) > int(pageSize) {
+		result.Rows = result.Rows[:pageSize]
+	}
+
+	head := make([]SQLResultItemHeadResV1, 0, len(result.Column))
+	for _, column := range result.Column {
+		head = append(head, SQLResultItemHeadResV1{FieldName: column.Key})
+	}
+	rows := make([]map[string]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		r := make(map[string]string, len(row.Values))
+		for i, value := range row.Values {
+			if i < len(head) {
+				r[head[i].FieldName] = value.Value
+			}
+		}
+		rows = append(rows, r)
+	}
+
+	return c.JSON(http.StatusOK, &GetSQLResultResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: GetSQLResultResDataV1{
+			SQL:         querySQL,
+			StartLine:   int(offset) + 1,
+			EndLine:     int(offset) + len(rows),
+			CurrentPage: int(req.PageIndex),
+			ExecuteTime: int(endAt.Sub(startAt).Milliseconds()),
+			Rows:        rows,
+			Head:        head,
+		},
+	})
 }
 
 func getSQLQueryHistory(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSqlQuery)
+	req := new(GetSQLQueryHistoryReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	instance, exist, err := s.GetInstanceByName(c.Param("instance_name"))
+	instance, err = getSQLQueryInstance(c, instance, exist, err)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	user, err := controller.GetCurrentUser(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	histories, err := s.GetSqlQueryHistories(user.ID, instance.ID, req.FilterFuzzySearch, req.PageIndex, req.PageSize)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	res := make([]SQLHistoryItemResV1, 0, len(histories))
+	for _, history := range histories {
+		res = append(res, SQLHistoryItemResV1{SQL: history.RawSql})
+	}
+	return c.JSON(http.StatusOK, &GetSQLQueryHistoryResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: GetSQLQueryHistoryResDataV1{
+			SQLHistories: res,
+		},
+	})
 }
 
 func getSQLExplain(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSqlQuery)
+	req := new(GetSqlExplainReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	instance, exist, err := s.GetInstanceByName(c.Param("instance_name"))
+	instance, err = getSQLQueryInstance(c, instance, exist, err)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	l := log.NewEntry()
+	d, err := newDriverWithoutAudit(l, instance, req.InstanceSchema)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer d.Close(context.TODO())
+	nodes, err := d.Parse(context.TODO(), req.Sql)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	dsn, err := newDSN(instance, req.InstanceSchema)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	ad, err := driver.NewAnalysisDriver(l, instance.DbType, dsn)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer ad.Close(context.TODO())
+	explains := make([]SQLExplain, 0, len(nodes))
+	for _, node := range nodes {
+		res, err := ad.Explain(context.TODO(), &driver.ExplainConf{Sql: node.Text})
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		explains = append(explains, convertExplainResultToRes(node.Text, res))
+	}
+	return c.JSON(http.StatusOK, &GetSQLExplainResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    explains,
+	})
+}
+
+func convertExplainResultToRes(sql string, res *driver.ExplainResult) SQLExplain {
+	explain := SQLExplain{SQL: sql}
+	explain.ClassicResult.Head, explain.ClassicResult.Rows = convertAnalysisInfoToRes(res.ClassicResult.AnalysisInfoInTableFormat)
+	return explain
 }
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"mime"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
@@ -23,9 +25,12 @@ import (
 var ErrTaskNoAccess = errors.New(errors.DataNotExist, fmt.Errorf("task is not exist or you can't access it"))
 
 type CreateAuditTaskReqV1 struct {
-	InstanceName   string `json:"instance_name" form:"instance_name" example:"inst_1" valid:"required"`
+	InstanceName   string `json:"instance_name" form:"instance_name" example:"inst_1"`
 	InstanceSchema string `json:"instance_schema" form:"instance_schema" example:"db1"`
 	Sql            string `json:"sql" form:"sql" example:"alter table tb1 drop columns c1"`
+	// SchemaBaselineName is used to audit the SQLs offline against the schema baseline
+	// instead of an instance, it can not be used with InstanceName.
+	SchemaBaselineName string `json:"schema_baseline_name" form:"schema_baseline_name" example:"app1_baseline"`
 }
 
 type GetAuditTaskResV1 struct {
@@ -40,10 +45,11 @@ type AuditTaskResV1 struct {
 	AuditLevel     string     `json:"audit_level" enums:"normal,notice,warn,error,"`
 	Score          int32      `json:"score"`
 	PassRate       float64    `json:"pass_rate"`
-	Status         string     `json:"status" enums:"initialized,audited,executing,exec_success,exec_failed"`
+	Status         string     `json:"status" enums:"initialized,audited,executing,exec_success,exec_failed,rolling_back,rollback_succeeded,rollback_failed"`
 	SQLSource      string     `json:"sql_source" enums:"form_data,sql_file,mybatis_xml_file,audit_plan"`
 	ExecStartTime  *time.Time `json:"exec_start_time,omitempty"`
 	ExecEndTime    *time.Time `json:"exec_end_time,omitempty"`
+	SourceTaskId   uint       `json:"source_task_id,omitempty"`
 }
 
 func convertTaskToRes(task *model.Task) *AuditTaskResV1 {
@@ -58,6 +64,7 @@ func convertTaskToRes(task *model.Task) *AuditTaskResV1 {
 		SQLSource:      task.SQLSource,
 		ExecStartTime:  task.ExecStartAt,
 		ExecEndTime:    task.ExecEndAt,
+		SourceTaskId:   task.SourceTaskId,
 	}
 }
 
@@ -101,8 +108,9 @@ func getSQLFromFile(c echo.Context) (string, string, error) {
 // @Tags task
 // @Id createAndAuditTaskV1
 // @Security ApiKeyAuth
-// @Param instance_name formData string true "instance name"
+// @Param instance_name formData string false "instance name, one of instance name and schema baseline name is required"
 // @Param instance_schema formData string false "schema of instance"
+// @Param schema_baseline_name formData string false "schema baseline name, the SQLs are audited offline against it"
 // @Param sql formData string false "sqls for audit"
 // @Param input_sql_file formData file false "input SQL file"
 // @Param input_mybatis_xml_file formData file false "input mybatis XML file"
@@ -125,30 +133,55 @@ func CreateAndAuditTask(c echo.Context) error {
 			return controller.JSONBaseErrorReq(c, err)
 		}
 	}
-	s := model.GetStorage()
-	instance, exist, err := s.GetInstanceByName(req.InstanceName)
-	if err != nil {
-		return controller.JSONBaseErrorReq(c, err)
-	}
-	if !exist {
-		return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
+	if (req.InstanceName == "") == (req.SchemaBaselineName == "") {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("one of instance_name and schema_baseline_name is required")))
 	}
 
-	can, err := checkCurrentUserCanAccessInstance(c, instance)
-	if err != nil {
-		return controller.JSONBaseErrorReq(c, err)
-	}
-	if !can {
-		return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
-	}
+	s := model.GetStorage()
+	var instance *model.Instance
+	var baseline *model.SchemaBaseline
+	var d driver.Driver
+	if req.SchemaBaselineName != "" {
+		var exist bool
+		baseline, exist, err = s.GetSchemaBaselineByName(req.SchemaBaselineName)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !exist {
+			return controller.JSONBaseErrorReq(c, errSchemaBaselineNotExist)
+		}
+		d, err = newDriverWithoutCfg(log.NewEntry(), baseline.DBType)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		defer d.Close(context.TODO())
+	} else {
+		var exist bool
+		instance, exist, err = s.GetInstanceByName(req.InstanceName)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !exist {
+			return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
+		}
 
-	d, err := newDriverWithoutAudit(log.NewEntry(), instance, "")
-	if err != nil {
-		return err
-	}
-	defer d.Close(context.TODO())
-	if err := d.Ping(context.TODO()); err != nil {
-		return controller.JSONBaseErrorReq(c, err)
+		can, err := checkCurrentUserCanAccessInstance(c, instance)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !can {
+			return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
+		}
+
+		d, err = newDriverWithoutAudit(log.NewEntry(), instance, "")
+		if err != nil {
+			return err
+		}
+		defer d.Close(context.TODO())
+		if err := d.Ping(context.TODO()); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
 	}
 
 	user, err := controller.GetCurrentUser(c)
@@ -157,12 +190,16 @@ func CreateAndAuditTask(c echo.Context) error {
 	}
 	task := &model.Task{
 		Schema:       req.InstanceSchema,
-		InstanceId:   instance.ID,
-		Instance:     instance,
 		CreateUserId: user.ID,
 		ExecuteSQLs:  []*model.ExecuteSQL{},
 		SQLSource:    source,
-		DBType:       instance.DbType,
+	}
+	if instance != nil {
+		task.InstanceId = instance.ID
+		task.DBType = instance.DbType
+	} else {
+		task.SchemaBaselineId = baseline.ID
+		task.DBType = baseline.DBType
 	}
 	createAt := time.Now()
 	task.CreatedAt = createAt
@@ -179,13 +216,11 @@ func CreateAndAuditTask(c echo.Context) error {
 			},
 		})
 	}
-	// if task instance is not nil, gorm will update instance when save task.
-	task.Instance = nil
+	// the instance and schema baseline are not set to task, otherwise gorm will update them when save task.
 	err = s.Save(task)
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
-	task.Instance = instance
 	task, err = server.GetSqled().AddTaskWaitResult(fmt.Sprintf("%d", task.ID), server.ActionTypeAudit)
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
@@ -280,15 +315,66 @@ type GetAuditTaskSQLsResV1 struct {
 }
 
 type AuditTaskSQLResV1 struct {
-	Number      uint   `json:"number"`
-	ExecSQL     string `json:"exec_sql"`
-	AuditResult string `json:"audit_result"`
-	AuditLevel  string `json:"audit_level"`
-	AuditStatus string `json:"audit_status"`
-	ExecResult  string `json:"exec_result"`
-	ExecStatus  string `json:"exec_status"`
-	RollbackSQL string `json:"rollback_sql,omitempty"`
-	Description string `json:"description"`
+	Number       uint                `json:"number"`
+	ExecSQL      string              `json:"exec_sql"`
+	AuditResult  string              `json:"audit_result"`
+	AuditResults []*AuditResultResV1 `json:"audit_results"`
+	AuditLevel   string              `json:"audit_level"`
+	AuditStatus  string              `json:"audit_status"`
+	ExecResult   string              `json:"exec_result"`
+	ExecStatus   string              `json:"exec_status"`
+	RollbackSQL  string              `json:"rollback_sql,omitempty"`
+	Description  string              `json:"description"`
+	FixedSQL     string              `json:"fixed_sql,omitempty"`
+	// EstimatedAffectedRows is omitted if the affected rows is not estimated.
+	EstimatedAffectedRows *int64 `json:"estimated_affected_rows,omitempty"`
+	DryRunStatus          string `json:"dry_run_status,omitempty" enums:"succeeded,failed,skipped"`
+	DryRunResult          string `json:"dry_run_result,omitempty"`
+}
+
+type AuditResultResV1 struct {
+	Level    string            `json:"level" example:"warn"`
+	Message  string            `json:"message"`
+	RuleName string            `json:"rule_name" example:"dml_check_with_limit"`
+	Category string            `json:"category"`
+	Position *SQLPositionResV1 `json:"position,omitempty"`
+
+	Suppressed     bool   `json:"suppressed"`
+	SuppressReason string `json:"suppress_reason,omitempty"`
+	Fixable        bool   `json:"fixable"`
+}
+
+type SQLPositionResV1 struct {
+	StartLine   int `json:"start_line"`
+	StartColumn int `json:"start_column"`
+	EndLine     int `json:"end_line"`
+	EndColumn   int `json:"end_column"`
+}
+
+func convertAuditResultsToRes(results model.AuditResults) []*AuditResultResV1 {
+	res := make([]*AuditResultResV1, 0, len(results))
+	for _, result := range results {
+		r := &AuditResultResV1{
+			Level:    result.Level,
+			Message:  result.Message,
+			RuleName: result.RuleName,
+			Category: result.Category,
+
+			Suppressed:     result.Suppressed,
+			SuppressReason: result.SuppressReason,
+			Fixable:        result.Fixable,
+		}
+		if result.Position != nil {
+			r.Position = &SQLPositionResV1{
+				StartLine:   result.Position.StartLine,
+				StartColumn: result.Position.StartColumn,
+				EndLine:     result.Position.EndLine,
+				EndColumn:   result.Position.EndColumn,
+			}
+		}
+		res = append(res, r)
+	}
+	return res
 }
 
 // @Summary 获取指定审核任务的SQLs信息
@@ -346,15 +432,23 @@ func GetTaskSQLs(c echo.Context) error {
 	taskSQLsRes := make([]*AuditTaskSQLResV1, 0, len(taskSQLs))
 	for _, taskSQL := range taskSQLs {
 		taskSQLRes := &AuditTaskSQLResV1{
-			Number:      taskSQL.Number,
-			Description: taskSQL.Description,
-			ExecSQL:     taskSQL.ExecSQL,
-			AuditResult: taskSQL.AuditResult,
-			AuditLevel:  taskSQL.AuditLevel,
-			AuditStatus: taskSQL.AuditStatus,
-			ExecResult:  taskSQL.ExecResult,
-			ExecStatus:  taskSQL.ExecStatus,
-			RollbackSQL: taskSQL.RollbackSQL.String,
+			Number:       taskSQL.Number,
+			Description:  taskSQL.Description,
+			ExecSQL:      taskSQL.ExecSQL,
+			AuditResult:  taskSQL.AuditResult,
+			AuditResults: convertAuditResultsToRes(taskSQL.AuditResults),
+			AuditLevel:   taskSQL.AuditLevel,
+			AuditStatus:  taskSQL.AuditStatus,
+			ExecResult:   taskSQL.ExecResult,
+			ExecStatus:   taskSQL.ExecStatus,
+			RollbackSQL:  taskSQL.RollbackSQL.String,
+			FixedSQL:     taskSQL.FixedSQL.String,
+			DryRunStatus: taskSQL.DryRunStatus.String,
+			DryRunResult: taskSQL.DryRunResult.String,
+		}
+		if taskSQL.EstimatedAffectedRows.Valid {
+			rows := taskSQL.EstimatedAffectedRows.Int64
+			taskSQLRes.EstimatedAffectedRows = &rows
 		}
 		taskSQLsRes = append(taskSQLsRes, taskSQLRes)
 	}
@@ -567,6 +661,114 @@ func UpdateAuditTaskSQLs(c echo.Context) error {
 	return controller.JSONBaseErrorReq(c, err)
 }
 
+type ApplyAuditTaskFixesReqV1 struct {
+	SQLNumbers []uint `json:"sql_numbers" valid:"required"`
+}
+
+// @Summary 应用审核任务中的SQL修复建议
+// @Description apply the accepted fix suggestions of SQLs in the audit task, a new task revision is created and audited
+// @Tags task
+// @Id applyAuditTaskFixesV1
+// @Accept json
+// @Param task_id path string true "task id"
+// @Param req body v1.ApplyAuditTaskFixesReqV1 true "the numbers of SQLs which fix suggestions are accepted"
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetAuditTaskResV1
+// @router /v1/tasks/audits/{task_id}/fixes [post]
+func ApplyAuditTaskFixes(c echo.Context) error {
+	req := new(ApplyAuditTaskFixesReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	task, exist, err := s.GetTaskDetailById(c.Param("task_id"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrTaskNoAccess)
+	}
+	err = checkCurrentUserCanViewTask(c, task)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if task.Instance == nil {
+		return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
+	}
+	can, err := checkCurrentUserCanAccessInstance(c, task.Instance)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !can {
+		return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
+	}
+
+	accepted := make(map[uint]struct{}, len(req.SQLNumbers))
+	for _, number := range req.SQLNumbers {
+		accepted[number] = struct{}{}
+	}
+	executeSQLs := task.ExecuteSQLs
+	sort.Slice(executeSQLs, func(i, j int) bool {
+		return executeSQLs[i].Number < executeSQLs[j].Number
+	})
+
+	user, err := controller.GetCurrentUser(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	instance := task.Instance
+	newTask := &model.Task{
+		Schema:       task.Schema,
+		InstanceId:   task.InstanceId,
+		CreateUserId: user.ID,
+		ExecuteSQLs:  make([]*model.ExecuteSQL, 0, len(executeSQLs)),
+		SQLSource:    task.SQLSource,
+		DBType:       task.DBType,
+		SourceTaskId: task.ID,
+	}
+	for _, executeSQL := range executeSQLs {
+		content := executeSQL.Content
+		if _, ok := accepted[executeSQL.Number]; ok {
+			if executeSQL.FixedSQL == "" {
+				return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+					fmt.Errorf("sql number %d has no fix suggestion", executeSQL.Number)))
+			}
+			content = executeSQL.FixedSQL
+			delete(accepted, executeSQL.Number)
+		}
+		newTask.ExecuteSQLs = append(newTask.ExecuteSQLs, &model.ExecuteSQL{
+			BaseSQL: model.BaseSQL{
+				Number:      executeSQL.Number,
+				Content:     content,
+				Description: executeSQL.Description,
+			},
+		})
+	}
+	if len(accepted) > 0 {
+		notFound := make([]uint, 0, len(accepted))
+		for number := range accepted {
+			notFound = append(notFound, number)
+		}
+		sort.Slice(notFound, func(i, j int) bool { return notFound[i] < notFound[j] })
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist,
+			fmt.Errorf("sql numbers %v not found", notFound)))
+	}
+
+	err = s.Save(newTask)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	newTask.Instance = instance
+	newTask, err = server.GetSqled().AddTaskWaitResult(fmt.Sprintf("%d", newTask.ID), server.ActionTypeAudit)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetAuditTaskResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertTaskToRes(newTask),
+	})
+}
+
 func checkCurrentUserCanViewTask(c echo.Context, task *model.Task) (err error) {
 	return checkCurrentUserCanAccessTask(c, task, []uint{model.OP_WORKFLOW_VIEW_OTHERS})
 }
@@ -605,3 +807,232 @@ type GetTaskAnalysisDataResV1 struct {
 func GetTaskAnalysisData(c echo.Context) error {
 	return getTaskAnalysisData(c)
 }
+
+type GetAuditTaskRollbackSQLsResV1 struct {
+	controller.BaseRes
+	Data []*AuditTaskRollbackSQLResV1 `json:"data"`
+}
+
+type AuditTaskRollbackSQLResV1 struct {
+	ExecSQLNumber uint                                  `json:"exec_sql_number"`
+	RollbackSQL   string                                `json:"rollback_sql"`
+	ExecStatus    string                                `json:"exec_status"`
+	ExecResult    string                                `json:"exec_result"`
+	RowAffects    int64                                 `json:"row_affects"`
+	Statements    []*AuditTaskRollbackSQLStatementResV1 `json:"statements"`
+}
+
+type AuditTaskRollbackSQLStatementResV1 struct {
+	Number     uint   `json:"number"`
+	SQL        string `json:"sql"`
+	ExecStatus string `json:"exec_status"`
+	ExecResult string `json:"exec_result"`
+	RowAffects int64  `json:"row_affects"`
+}
+
+// @Summary 获取审核任务的回滚SQL及执行结果
+// @Description get rollback SQLs and the outcome of each statement for the audit task
+// @Tags task
+// @Id getAuditTaskRollbackSQLsV1
+// @Security ApiKeyAuth
+// @Param task_id path string true "task id"
+// @Success 200 {object} v1.GetAuditTaskRollbackSQLsResV1
+// @router /v1/tasks/audits/{task_id}/rollback_sqls [get]
+func GetAuditTaskRollbackSQLs(c echo.Context) error {
+	taskId := c.Param("task_id")
+	s := model.GetStorage()
+	task, exist, err := s.GetTaskDetailById(taskId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrTaskNoAccess)
+	}
+	err = checkCurrentUserCanViewTask(c, task)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	numbers := map[uint]uint{}
+	for _, executeSQL := range task.ExecuteSQLs {
+		numbers[executeSQL.ID] = executeSQL.Number
+	}
+	rollbackSQLsRes := []*AuditTaskRollbackSQLResV1{}
+	for _, rollbackSQL := range task.RollbackSQLs {
+		if rollbackSQL.Content == "" {
+			continue
+		}
+		rollbackSQLRes := &AuditTaskRollbackSQLResV1{
+			ExecSQLNumber: numbers[rollbackSQL.ExecuteSQLId],
+			RollbackSQL:   rollbackSQL.Content,
+			ExecStatus:    rollbackSQL.ExecStatus,
+			ExecResult:    rollbackSQL.ExecResult,
+			RowAffects:    rollbackSQL.RowAffects,
+			Statements:    []*AuditTaskRollbackSQLStatementResV1{},
+		}
+		for _, stmt := range rollbackSQL.Statements {
+			rollbackSQLRes.Statements = append(rollbackSQLRes.Statements, &AuditTaskRollbackSQLStatementResV1{
+				Number:     stmt.Number,
+				SQL:        stmt.Content,
+				ExecStatus: stmt.ExecStatus,
+				ExecResult: stmt.ExecResult,
+				RowAffects: stmt.RowAffects,
+			})
+		}
+		rollbackSQLsRes = append(rollbackSQLsRes, rollbackSQLRes)
+	}
+	return c.JSON(http.StatusOK, &GetAuditTaskRollbackSQLsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    rollbackSQLsRes,
+	})
+}
+
+type GetAuditTaskRollbackPreviewResV1 struct {
+	controller.BaseRes
+	Data []*AuditTaskRollbackPreviewResV1 `json:"data"`
+}
+
+type AuditTaskRollbackPreviewResV1 struct {
+	ExecSQLNumber uint   `json:"exec_sql_number"`
+	SQL           string `json:"sql"`
+	Group         int    `json:"group"`
+	InTransaction bool   `json:"in_transaction"`
+	// MatchedRows is omitted if the rows can not be counted.
+	MatchedRows *int64 `json:"matched_rows,omitempty"`
+}
+
+// @Summary 预览审核任务的回滚操作
+// @Description preview the statements to be executed in order and the rows they match when the audit task is rolled back, nothing is executed
+// @Tags task
+// @Id getAuditTaskRollbackPreviewV1
+// @Security ApiKeyAuth
+// @Param task_id path string true "task id"
+// @Success 200 {object} v1.GetAuditTaskRollbackPreviewResV1
+// @router /v1/tasks/audits/{task_id}/rollback_preview [get]
+func GetAuditTaskRollbackPreview(c echo.Context) error {
+	taskId := c.Param("task_id")
+	s := model.GetStorage()
+	task, exist, err := s.GetTaskDetailById(taskId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrTaskNoAccess)
+	}
+	err = checkCurrentUserCanViewTask(c, task)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	previews, err := server.PreviewRollback(c.Request().Context(), task)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	previewsRes := make([]*AuditTaskRollbackPreviewResV1, 0, len(previews))
+	for _, preview := range previews {
+		previewsRes = append(previewsRes, &AuditTaskRollbackPreviewResV1{
+			ExecSQLNumber: preview.ExecuteSQLNumber,
+			SQL:           preview.SQL,
+			Group:         preview.Group,
+			InTransaction: preview.InTransaction,
+			MatchedRows:   preview.MatchedRows,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetAuditTaskRollbackPreviewResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    previewsRes,
+	})
+}
+
+type DryRunAuditTaskReqV1 struct {
+	// StagingInstanceName is the instance where the shadow tables are created, they are
+	// created on the instance of task if it is empty.
+	StagingInstanceName string `json:"staging_instance_name" form:"staging_instance_name" example:"inst_staging"`
+	SampleRows          int64  `json:"sample_rows" form:"sample_rows" valid:"omitempty,min=0,max=10000" example:"100"`
+}
+
+type DryRunAuditTaskResV1 struct {
+	controller.BaseRes
+	Data []*AuditTaskDryRunSQLResV1 `json:"data"`
+}
+
+type AuditTaskDryRunSQLResV1 struct {
+	Number       uint   `json:"number"`
+	ExecSQL      string `json:"exec_sql"`
+	DryRunStatus string `json:"dry_run_status" enums:"succeeded,failed,skipped"`
+	DryRunResult string `json:"dry_run_result"`
+}
+
+// @Summary 在影子表上试运行审核任务
+// @Description dry run the audit task, the SQLs are executed against the throwaway copies of the tables they use, and the copies are dropped afterwards
+// @Tags task
+// @Id dryRunAuditTaskV1
+// @Security ApiKeyAuth
+// @Accept json
+// @Param task_id path string true "task id"
+// @Param req body v1.DryRunAuditTaskReqV1 true "dry run options"
+// @Success 200 {object} v1.DryRunAuditTaskResV1
+// @router /v1/tasks/audits/{task_id}/dry_run [post]
+func DryRunAuditTask(c echo.Context) error {
+	req := new(DryRunAuditTaskReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	taskId := c.Param("task_id")
+	s := model.GetStorage()
+	task, exist, err := s.GetTaskById(taskId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrTaskNoAccess)
+	}
+	err = checkCurrentUserCanAccessTask(c, task, []uint{})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if task.Instance == nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("the task without instance can not be dry run")))
+	}
+
+	var staging *model.Instance
+	if req.StagingInstanceName != "" {
+		staging, exist, err = s.GetInstanceByName(req.StagingInstanceName)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !exist {
+			return controller.JSONBaseErrorReq(c, errInstanceNotExist)
+		}
+		can, err := checkCurrentUserCanAccessInstance(c, staging)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !can {
+			return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
+		}
+		if staging.DbType != task.DBType {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+				fmt.Errorf("the db type of staging instance is %v, but the task is %v", staging.DbType, task.DBType)))
+		}
+	}
+
+	task, err = server.GetSqled().AddDryRunTaskWaitResult(taskId, staging, req.SampleRows)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	sqlsRes := make([]*AuditTaskDryRunSQLResV1, 0, len(task.ExecuteSQLs))
+	for _, executeSQL := range task.ExecuteSQLs {
+		sqlsRes = append(sqlsRes, &AuditTaskDryRunSQLResV1{
+			Number:       executeSQL.Number,
+			ExecSQL:      executeSQL.Content,
+			DryRunStatus: executeSQL.DryRunStatus,
+			DryRunResult: executeSQL.DryRunResult,
+		})
+	}
+	return c.JSON(http.StatusOK, &DryRunAuditTaskResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    sqlsRes,
+	})
+}
@@ -4,14 +4,74 @@
 package v1
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+
 	"github.com/labstack/echo/v4"
 )
 
-var errNotSupportGetTaskAnalysisData = errors.New(errors.EnterpriseEditionFeatures, fmt.Errorf("get task analysis data is enterprise version functions"))
-
 func getTaskAnalysisData(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errNotSupportGetTaskAnalysisData)
+	taskId := c.Param("task_id")
+	sqlNumber := c.Param("number")
+
+	s := model.GetStorage()
+	task, exist, err := s.GetTaskById(taskId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrTaskNoAccess)
+	}
+	if err := checkCurrentUserCanViewTask(c, task); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if task.Instance == nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("instance of task is not exist")))
+	}
+
+	taskSql, exist, err := s.GetTaskSQLByNumber(taskId, sqlNumber)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("sql number not found")))
+	}
+
+	dsn, err := newDSN(task.Instance, task.Schema)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	d, err := driver.NewAnalysisDriver(log.NewEntry(), task.Instance.DbType, dsn)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer d.Close(context.TODO())
+
+	explain, err := d.Explain(context.TODO(), &driver.ExplainConf{Sql: taskSql.Content})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	metas, err := d.GetTableMetaBySQL(context.TODO(), &driver.GetTableMetaBySQLConf{Sql: taskSql.Content})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	tableMetas := make([]TableMeta, 0, len(metas.TableMetas))
+	for _, meta := range metas.TableMetas {
+		tableMetas = append(tableMetas, convertTableMetaItemToRes(meta))
+	}
+	return c.JSON(http.StatusOK, &GetTaskAnalysisDataResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: GetTaskAnalysisDataResItemV1{
+			SQLExplain: convertExplainResultToRes(taskSql.Content, explain),
+			TableMetas: tableMetas,
+		},
+	})
 }
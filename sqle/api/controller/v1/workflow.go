@@ -616,7 +616,7 @@ type WorkflowResV1 struct {
 type WorkflowRecordResV1 struct {
 	TaskId            uint                 `json:"task_id"`
 	CurrentStepNumber uint                 `json:"current_step_number,omitempty"`
-	Status            string               `json:"status" enums:"on_process,rejected,canceled,exec_scheduled,executing,exec_failed,finished"`
+	Status            string               `json:"status" enums:"on_process,rejected,canceled,exec_scheduled,executing,exec_failed,finished,rolling_back,rollback_failed,rolled_back"`
 	ScheduleTime      *time.Time           `json:"schedule_time,omitempty"`
 	ScheduleUser      string               `json:"schedule_user,omitempty"`
 	Steps             []*WorkflowStepResV1 `json:"workflow_step_list,omitempty"`
@@ -777,6 +777,12 @@ func convertWorkflowStatusToRes(workflowStatus, taskStatus string, scheduleTime
 		status = model.WorkflowStatusFinish
 	case model.TaskStatusExecuteFailed:
 		status = model.WorkflowStatusExecFailed
+	case model.TaskStatusRollingBack:
+		status = model.WorkflowStatusRollingBack
+	case model.TaskStatusRollbackFailed:
+		status = model.WorkflowStatusRollbackFailed
+	case model.TaskStatusRollbackSucceeded:
+		status = model.WorkflowStatusRolledBack
 	}
 	if status == model.WorkflowStatusRunning && scheduleTime != nil {
 		status = model.WorkflowStatusExecScheduled
@@ -839,7 +845,7 @@ type GetWorkflowsReqV1 struct {
 	FilterCreateTimeTo                string `json:"filter_create_time_to" query:"filter_create_time_to"`
 	FilterCreateUserName              string `json:"filter_create_user_name" query:"filter_create_user_name"`
 	FilterCurrentStepType             string `json:"filter_current_step_type" query:"filter_current_step_type" valid:"omitempty,oneof=sql_review sql_execute"`
-	FilterStatus                      string `json:"filter_status" query:"filter_status" valid:"omitempty,oneof=on_process rejected canceled exec_scheduled executing exec_failed finished"`
+	FilterStatus                      string `json:"filter_status" query:"filter_status" valid:"omitempty,oneof=on_process rejected canceled exec_scheduled executing exec_failed finished rolling_back rollback_failed rolled_back"`
 	FilterCurrentStepAssigneeUserName string `json:"filter_current_step_assignee_user_name" query:"filter_current_step_assignee_user_name"`
 	FilterTaskInstanceName            string `json:"filter_task_instance_name" query:"filter_task_instance_name"`
 	PageIndex                         uint32 `json:"page_index" query:"page_index" valid:"required"`
@@ -864,7 +870,7 @@ type WorkflowDetailResV1 struct {
 	CreateTime              *time.Time `json:"create_time"`
 	CurrentStepType         string     `json:"current_step_type,omitempty" enums:"sql_review,sql_execute"`
 	CurrentStepAssigneeUser []string   `json:"current_step_assignee_user_name_list,omitempty"`
-	Status                  string     `json:"status" enums:"on_process,rejected,canceled,exec_scheduled,executing,exec_failed,finished"`
+	Status                  string     `json:"status" enums:"on_process,rejected,canceled,exec_scheduled,executing,exec_failed,finished,rolling_back,rollback_failed,rolled_back"`
 	ScheduleTime            *time.Time `json:"schedule_time,omitempty"`
 }
 
@@ -878,7 +884,7 @@ type WorkflowDetailResV1 struct {
 // @Param filter_create_time_to query string false "filter create time to"
 // @Param filter_create_user_name query string false "filter create user name"
 // @Param filter_current_step_type query string false "filter current step type" Enums(sql_review, sql_execute)
-// @Param filter_status query string false "filter workflow status" Enums(on_process, rejected, canceled, exec_scheduled, executing, exec_failed, finished)
+// @Param filter_status query string false "filter workflow status" Enums(on_process, rejected, canceled, exec_scheduled, executing, exec_failed, finished, rolling_back, rollback_failed, rolled_back)
 // @Param filter_current_step_assignee_user_name query string false "filter current step assignee user name"
 // @Param filter_task_instance_name query string false "filter instance name"
 // @Param page_index query uint32 false "page index"
@@ -912,6 +918,12 @@ func GetWorkflows(c echo.Context) error {
 		taskStatus = model.TaskStatusExecuteFailed
 	case model.WorkflowStatusFinish:
 		taskStatus = model.TaskStatusExecuteSucceeded
+	case model.WorkflowStatusRollingBack:
+		taskStatus = model.TaskStatusRollingBack
+	case model.WorkflowStatusRollbackFailed:
+		taskStatus = model.TaskStatusRollbackFailed
+	case model.WorkflowStatusRolledBack:
+		taskStatus = model.TaskStatusRollbackSucceeded
 	}
 	// filter workflow status
 	switch req.FilterStatus {
@@ -1523,6 +1535,57 @@ func ExecuteTaskOnWorkflow(c echo.Context) error {
 	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
+// @Summary 工单回滚 SQL 上线
+// @Description rollback task on workflow, the rollback is executed in background, and the workflow is rolled back only if all the rollback SQLs succeed
+// @Tags workflow
+// @Id rollbackTaskOnWorkflowV1
+// @Security ApiKeyAuth
+// @Param workflow_id path string true "workflow id"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/workflows/{workflow_id}/task/rollback [post]
+func RollbackTaskOnWorkflow(c echo.Context) error {
+	workflowId := c.Param("workflow_id")
+	id, err := FormatStringToInt(workflowId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	err = checkCurrentUserCanAccessWorkflow(c, &model.Workflow{
+		Model: model.Model{ID: uint(id)},
+	}, []uint{})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	user, err := controller.GetCurrentUser(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	s := model.GetStorage()
+	workflow, exist, err := s.GetWorkflowDetailById(workflowId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrWorkflowNoAccess)
+	}
+
+	if workflow.Record.Status != model.WorkflowStatusFinish {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("workflow status is %s, not allow to rollback it", workflow.Record.Status)))
+	}
+	if !model.IsDefaultAdminUser(user.Name) && !workflow.IsFinalStepUser(user) {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("you are not allow to rollback the workflow")))
+	}
+
+	err = server.GetSqled().AddTask(fmt.Sprintf("%d", workflow.Record.TaskId), server.ActionTypeRollback)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+}
+
 func checkCurrentUserCanCreateWorkflow(user *model.User, instance *model.Instance) error {
 
 	if model.IsDefaultAdminUser(user.Name) {
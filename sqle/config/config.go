@@ -10,15 +10,20 @@ type Server struct {
 }
 
 type SqleConfig struct {
-	SqleServerPort   int    `yaml:"server_port"`
-	EnableHttps      bool   `yaml:"enable_https"`
-	CertFilePath     string `yaml:"cert_file_path"`
-	KeyFilePath      string `yaml:"key_file_path"`
-	AutoMigrateTable bool   `yaml:"auto_migrate_table"`
-	DebugLog         bool   `yaml:"debug_log"`
-	LogPath          string `yaml:"log_path"`
-	PluginPath       string `yaml:"plugin_path"`
-	SecretKey        string `yaml:"secret_key"`
+	SqleServerPort    int    `yaml:"server_port"`
+	EnableHttps       bool   `yaml:"enable_https"`
+	CertFilePath      string `yaml:"cert_file_path"`
+	KeyFilePath       string `yaml:"key_file_path"`
+	AutoMigrateTable  bool   `yaml:"auto_migrate_table"`
+	DebugLog          bool   `yaml:"debug_log"`
+	LogPath           string `yaml:"log_path"`
+	PluginPath        string `yaml:"plugin_path"`
+	AutoReloadPlugins bool   `yaml:"auto_reload_plugins"`
+	SecretKey         string `yaml:"secret_key"`
+	// MysqlMetaCacheTTL is the seconds that the metadata of MySQL instance is cached, the
+	// cache is disabled if it is not positive. The tables changed outside SQLE are not seen
+	// until the cached metadata is expired, so a short TTL is recommended.
+	MysqlMetaCacheTTL int `yaml:"mysql_meta_cache_ttl"`
 }
 
 type DatabaseConfig struct {
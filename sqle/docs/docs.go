@@ -842,11 +842,11 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get drivers",
+                "description": "get drivers and capabilities",
                 "tags": [
                     "configuration"
                 ],
-                "summary": "获取当前 server 支持的审核类型",
+                "summary": "获取当前 server 支持的审核类型及其支持的功能",
                 "operationId": "getDriversV1",
                 "responses": {
                     "200": {
@@ -858,6 +858,29 @@ var doc = `{
                 }
             }
         },
+        "/v1/configurations/drivers/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get status of drivers, including plugin process state, last error, restart count and RPC latency",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取审核插件运行状态",
+                "operationId": "getDriversStatusV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetDriversStatusResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/configurations/ldap": {
             "get": {
                 "security": [
@@ -1106,6 +1129,52 @@ var doc = `{
                 }
             }
         },
+        "/v1/configurations/plugins": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get load status of plugins, including reload failures",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取插件加载状态",
+                "operationId": "getPluginsV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetPluginsResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/plugins/reload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "reload new or modified plugins in plugin directory",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "重新加载插件",
+                "operationId": "reloadPluginsV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/configurations/smtp": {
             "get": {
                 "security": [
@@ -1354,79 +1423,59 @@ var doc = `{
                 }
             }
         },
-        "/v1/dashboard": {
+        "/v1/custom_rules": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get dashboard info",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "get custom rules",
                 "tags": [
-                    "dashboard"
+                    "rule_template"
                 ],
-                "summary": "获取 dashboard 信息",
-                "operationId": "getDashboardV1",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/v1.GetDashboardResV1"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/instance_additional_metas": {
-            "get": {
-                "security": [
+                "summary": "自定义规则列表",
+                "operationId": "getCustomRuleListV1",
+                "parameters": [
                     {
-                        "ApiKeyAuth": []
+                        "type": "string",
+                        "description": "filter db type",
+                        "name": "filter_db_type",
+                        "in": "query"
                     }
                 ],
-                "description": "get instance additional metas",
-                "tags": [
-                    "instance"
-                ],
-                "summary": "获取实例的额外属性列表",
-                "operationId": "getInstanceAdditionalMetas",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetInstanceAdditionalMetasResV1"
+                            "$ref": "#/definitions/v1.GetCustomRulesResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/instance_connection": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "test instance db connection 注：可直接提交创建实例接口的body，该接口的json 内容是创建实例的 json 的子集",
+                "description": "create a custom rule",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "instance"
+                    "rule_template"
                 ],
-                "summary": "实例连通性测试（实例提交前）",
-                "operationId": "checkInstanceIsConnectableV1",
+                "summary": "添加自定义规则",
+                "operationId": "createCustomRuleV1",
                 "parameters": [
                     {
-                        "description": "instance info",
+                        "description": "create custom rule request",
                         "name": "instance",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.GetInstanceConnectableReqV1"
+                            "$ref": "#/definitions/v1.CreateCustomRuleReqV1"
                         }
                     }
                 ],
@@ -1434,160 +1483,100 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetInstanceConnectableResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/instance_tips": {
+        "/v1/custom_rules/{rule_name}/": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get instance tip list",
+                "description": "get custom rule",
                 "tags": [
-                    "instance"
+                    "rule_template"
                 ],
-                "summary": "获取实例提示列表",
-                "operationId": "getInstanceTipListV1",
+                "summary": "获取自定义规则",
+                "operationId": "getCustomRuleV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "filter db type",
-                        "name": "filter_db_type",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "create_audit_plan",
-                            "sql_query"
-                        ],
-                        "type": "string",
-                        "description": "functional module",
-                        "name": "functional_module",
-                        "in": "query"
+                        "description": "custom rule name",
+                        "name": "rule_name",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetInstanceTipsResV1"
+                            "$ref": "#/definitions/v1.GetCustomRuleResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/instances": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get instance info list",
+                "description": "delete custom rule, the rule is removed from all rule templates",
                 "tags": [
-                    "instance"
+                    "rule_template"
                 ],
-                "summary": "获取实例信息列表",
-                "operationId": "getInstanceListV1",
+                "summary": "删除自定义规则",
+                "operationId": "deleteCustomRuleV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "filter instance name",
-                        "name": "filter_instance_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter db type",
-                        "name": "filter_db_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter db host",
-                        "name": "filter_db_host",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter db port",
-                        "name": "filter_db_port",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter db user",
-                        "name": "filter_db_user",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter workflow rule template name",
-                        "name": "filter_workflow_template_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter rule template name",
-                        "name": "filter_rule_template_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter role name",
-                        "name": "filter_role_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page index",
-                        "name": "page_index",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "size of per page",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "custom rule name",
+                        "name": "rule_name",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetInstancesResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "create a instance",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "update custom rule",
                 "tags": [
-                    "instance"
+                    "rule_template"
                 ],
-                "summary": "添加实例",
-                "operationId": "createInstanceV1",
+                "summary": "更新自定义规则",
+                "operationId": "updateCustomRuleV1",
                 "parameters": [
                     {
-                        "description": "add instance",
+                        "type": "string",
+                        "description": "custom rule name",
+                        "name": "rule_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update custom rule request",
                         "name": "instance",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateInstanceReqV1"
+                            "$ref": "#/definitions/v1.UpdateCustomRuleReqV1"
                         }
                     }
                 ],
@@ -1601,54 +1590,407 @@ var doc = `{
                 }
             }
         },
-        "/v1/instances/{instance_name}/": {
+        "/v1/dashboard": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get instance db",
-                "tags": [
-                    "instance"
+                "description": "get dashboard info",
+                "produces": [
+                    "application/json"
                 ],
-                "summary": "获取实例信息",
-                "operationId": "getInstanceV1",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "instance name",
-                        "name": "instance_name",
-                        "in": "path",
-                        "required": true
-                    }
+                "tags": [
+                    "dashboard"
                 ],
+                "summary": "获取 dashboard 信息",
+                "operationId": "getDashboardV1",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetInstanceResV1"
+                            "$ref": "#/definitions/v1.GetDashboardResV1"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/dashboard/meta_cache": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "delete instance db",
+                "description": "get the hit statistics of MySQL metadata cache, grouped by instance address and user",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "instance"
+                    "dashboard"
                 ],
-                "summary": "删除实例",
-                "operationId": "deleteInstanceV1",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "instance name",
-                        "name": "instance_name",
+                "summary": "获取 MySQL 元数据缓存统计",
+                "operationId": "getMetaCacheStatisticsV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetMetaCacheStatisticsResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/dashboard/rule_hits": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the hit statistics of rules in tasks and audit plan reports, grouped by rule, instance, rule template and level. The SQLs which match the whitelist are counted as rule \"sql_whitelist\", and blocked_count is the number of hits which block the task from being submitted as workflow",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "获取规则触发统计",
+                "operationId": "getRuleHitStatisticsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter create time from",
+                        "name": "filter_create_time_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create time to",
+                        "name": "filter_create_time_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter db type",
+                        "name": "filter_db_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter instance name",
+                        "name": "filter_instance_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter rule template name",
+                        "name": "filter_rule_template_name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "normal",
+                            "notice",
+                            "warn",
+                            "error"
+                        ],
+                        "type": "string",
+                        "description": "filter level",
+                        "name": "filter_level",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "task",
+                            "audit_plan"
+                        ],
+                        "type": "string",
+                        "description": "filter source",
+                        "name": "filter_source",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetRuleHitStatisticsResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/instance_additional_metas": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get instance additional metas",
+                "tags": [
+                    "instance"
+                ],
+                "summary": "获取实例的额外属性列表",
+                "operationId": "getInstanceAdditionalMetas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetInstanceAdditionalMetasResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/instance_connection": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "test instance db connection 注：可直接提交创建实例接口的body，该接口的json 内容是创建实例的 json 的子集",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instance"
+                ],
+                "summary": "实例连通性测试（实例提交前）",
+                "operationId": "checkInstanceIsConnectableV1",
+                "parameters": [
+                    {
+                        "description": "instance info",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetInstanceConnectableReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetInstanceConnectableResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/instance_tips": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get instance tip list",
+                "tags": [
+                    "instance"
+                ],
+                "summary": "获取实例提示列表",
+                "operationId": "getInstanceTipListV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter db type",
+                        "name": "filter_db_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "create_audit_plan",
+                            "sql_query"
+                        ],
+                        "type": "string",
+                        "description": "functional module",
+                        "name": "functional_module",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetInstanceTipsResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/instances": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get instance info list",
+                "tags": [
+                    "instance"
+                ],
+                "summary": "获取实例信息列表",
+                "operationId": "getInstanceListV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter instance name",
+                        "name": "filter_instance_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter db type",
+                        "name": "filter_db_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter db host",
+                        "name": "filter_db_host",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter db port",
+                        "name": "filter_db_port",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter db user",
+                        "name": "filter_db_user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter workflow rule template name",
+                        "name": "filter_workflow_template_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter rule template name",
+                        "name": "filter_rule_template_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter role name",
+                        "name": "filter_role_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetInstancesResV1"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "create a instance",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instance"
+                ],
+                "summary": "添加实例",
+                "operationId": "createInstanceV1",
+                "parameters": [
+                    {
+                        "description": "add instance",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateInstanceReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/instances/{instance_name}/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get instance db",
+                "tags": [
+                    "instance"
+                ],
+                "summary": "获取实例信息",
+                "operationId": "getInstanceV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "instance name",
+                        "name": "instance_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetInstanceResV1"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete instance db",
+                "tags": [
+                    "instance"
+                ],
+                "summary": "删除实例",
+                "operationId": "deleteInstanceV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "instance name",
+                        "name": "instance_name",
                         "in": "path",
                         "required": true
                     }
@@ -1798,6 +2140,45 @@ var doc = `{
                 }
             }
         },
+        "/v1/instances/{instance_name}/schemas/{schema_name}/design_report": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "audit the design of all tables in the schema as a unit by the schema design rules in the rule template of instance, the report is not saved",
+                "tags": [
+                    "instance"
+                ],
+                "summary": "审核数据库的库表设计",
+                "operationId": "getSchemaDesignReportV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "instance name",
+                        "name": "instance_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "schema name",
+                        "name": "schema_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSchemaDesignReportResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/instances/{instance_name}/schemas/{schema_name}/tables": {
             "get": {
                 "security": [
@@ -2017,51 +2398,248 @@ var doc = `{
                 "tags": [
                     "role"
                 ],
-                "summary": "获取角色提示列表",
-                "operationId": "getRoleTipListV1",
+                "summary": "获取角色提示列表",
+                "operationId": "getRoleTipListV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetRoleTipsResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/roles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get role list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "获取角色列表",
+                "operationId": "getRoleListV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter role name",
+                        "name": "filter_role_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter user name",
+                        "name": "filter_user_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter instance name",
+                        "name": "filter_instance_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetRolesResV1"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "create role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "创建角色",
+                "operationId": "createRoleV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "create role",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateRoleReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/roles/{role_name}/": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "删除角色",
+                "operationId": "deleteRoleV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "role name",
+                        "name": "role_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "更新角色信息",
+                "operationId": "updateRoleV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "role name",
+                        "name": "role_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update role request",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateRoleReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/rule_template_tips": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get rule template tips",
+                "tags": [
+                    "rule_template"
+                ],
+                "summary": "获取规则模板提示",
+                "operationId": "getRuleTemplateTipsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter db type",
+                        "name": "filter_db_type",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetRoleTipsResV1"
+                            "$ref": "#/definitions/v1.GetRuleTemplateTipsResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/roles": {
+        "/v1/rule_templates": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get role list",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "get all rule template",
                 "tags": [
-                    "role"
+                    "rule_template"
                 ],
-                "summary": "获取角色列表",
-                "operationId": "getRoleListV1",
-                "deprecated": true,
+                "summary": "规则模板列表",
+                "operationId": "getRuleTemplateListV1",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "filter role name",
-                        "name": "filter_role_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter user name",
-                        "name": "filter_user_name",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "description": "filter instance name",
@@ -2085,7 +2663,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetRolesResV1"
+                            "$ref": "#/definitions/v1.GetRuleTemplatesResV1"
                         }
                     }
                 }
@@ -2096,27 +2674,23 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "create role",
+                "description": "create a rule template",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "role"
+                    "rule_template"
                 ],
-                "summary": "创建角色",
-                "operationId": "createRoleV1",
-                "deprecated": true,
+                "summary": "添加规则模板",
+                "operationId": "createRuleTemplateV1",
                 "parameters": [
                     {
-                        "description": "create role",
+                        "description": "add rule template request",
                         "name": "instance",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateRoleReqV1"
+                            "$ref": "#/definitions/v1.CreateRuleTemplateReqV1"
                         }
                     }
                 ],
@@ -2130,30 +2704,54 @@ var doc = `{
                 }
             }
         },
-        "/v1/roles/{role_name}/": {
-            "delete": {
+        "/v1/rule_templates/{rule_template_name}/": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "delete role",
-                "consumes": [
-                    "application/json"
+                "description": "get rule template",
+                "tags": [
+                    "rule_template"
                 ],
-                "produces": [
-                    "application/json"
+                "summary": "获取规则模板信息",
+                "operationId": "getRuleTemplateV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "rule template name",
+                        "name": "rule_template_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetRuleTemplateResV1"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "delete rule template",
                 "tags": [
-                    "role"
+                    "rule_template"
                 ],
-                "summary": "删除角色",
-                "operationId": "deleteRoleV1",
+                "summary": "删除规则模板",
+                "operationId": "deleteRuleTemplateV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "role name",
-                        "name": "role_name",
+                        "description": "rule template name",
+                        "name": "rule_template_name",
                         "in": "path",
                         "required": true
                     }
@@ -2173,34 +2771,71 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update role",
-                "consumes": [
-                    "application/json"
+                "description": "update rule template",
+                "tags": [
+                    "rule_template"
                 ],
-                "produces": [
+                "summary": "更新规则模板",
+                "operationId": "updateRuleTemplateV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "rule template name",
+                        "name": "rule_template_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update rule template request",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateRuleTemplateReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/rule_templates/{rule_template_name}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "clone a rule template",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "role"
+                    "rule_template"
                 ],
-                "summary": "更新角色信息",
-                "operationId": "updateRoleV1",
-                "deprecated": true,
+                "summary": "克隆规则模板",
+                "operationId": "CloneRuleTemplateV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "role name",
-                        "name": "role_name",
+                        "description": "rule template name",
+                        "name": "rule_template_name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "update role request",
+                        "description": "clone rule template request",
                         "name": "instance",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateRoleReqV1"
+                            "$ref": "#/definitions/v1.CloneRuleTemplateReqV1"
                         }
                     }
                 ],
@@ -2214,19 +2849,19 @@ var doc = `{
                 }
             }
         },
-        "/v1/rule_template_tips": {
+        "/v1/rules": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get rule template tips",
+                "description": "get all rule template",
                 "tags": [
                     "rule_template"
                 ],
-                "summary": "获取规则模板提示",
-                "operationId": "getRuleTemplateTipsV1",
+                "summary": "规则列表",
+                "operationId": "getRuleListV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -2239,42 +2874,67 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetRuleTemplateTipsResV1"
+                            "$ref": "#/definitions/v1.GetRulesResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/rule_templates": {
-            "get": {
+        "/v1/rules/sandbox": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get all rule template",
+                "description": "audit SQL by the rules of rule template or rule list without instance, the setup SQL is audited before the SQL to create the tables which the SQL depends on, and only the results of the SQL are returned. The rule list overrides the level and params of the rule template.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
                     "rule_template"
                 ],
-                "summary": "规则模板列表",
-                "operationId": "getRuleTemplateListV1",
+                "summary": "在沙箱中使用规则审核SQL",
+                "operationId": "auditRuleSandboxV1",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "filter instance name",
-                        "name": "filter_instance_name",
-                        "in": "query"
-                    },
+                        "description": "audit rule sandbox request",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AuditRuleSandboxReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.AuditRuleSandboxResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/schema_baseline_tips": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "page index",
-                        "name": "page_index",
-                        "in": "query"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get schema baseline tips",
+                "tags": [
+                    "schema_baseline"
+                ],
+                "summary": "获取库表结构基线提示信息",
+                "operationId": "getSchemaBaselineTipsV1",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "description": "size of per page",
-                        "name": "page_size",
+                        "type": "string",
+                        "description": "filter db type",
+                        "name": "filter_db_type",
                         "in": "query"
                     }
                 ],
@@ -2282,35 +2942,75 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetRuleTemplatesResV1"
+                            "$ref": "#/definitions/v1.GetSchemaBaselineTipsResV1"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/schema_baselines": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "create a rule template",
+                "description": "create a schema baseline, the offline audit plan attached to it is audited against the tables in it.\nThe CREATE statements can be uploaded in three ways, any one can be used, but only one is effective.\n1. formData[sql]: the CREATE statements;\n2. file[input_sql_file]: a schema dump file, e.g. the output of \"mysqldump --no-data\";\n3. formData[audit_plan_name]: the snapshot of a schema meta audit plan.",
                 "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "rule_template"
+                    "schema_baseline"
                 ],
-                "summary": "添加规则模板",
-                "operationId": "createRuleTemplateV1",
+                "summary": "上传库表结构基线",
+                "operationId": "createSchemaBaselineV1",
                 "parameters": [
                     {
-                        "description": "add rule template request",
-                        "name": "instance",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.CreateRuleTemplateReqV1"
-                        }
+                        "type": "string",
+                        "description": "schema baseline name",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "db type",
+                        "name": "db_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the schema of the tables which schema is not specified",
+                        "name": "default_schema",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "description",
+                        "name": "desc",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CREATE statements",
+                        "name": "sql",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "schema dump file",
+                        "name": "input_sql_file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "schema meta audit plan name",
+                        "name": "audit_plan_name",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
@@ -2323,24 +3023,24 @@ var doc = `{
                 }
             }
         },
-        "/v1/rule_templates/{rule_template_name}/": {
-            "get": {
+        "/v1/schema_baselines/{schema_baseline_name}/": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get rule template",
+                "description": "delete schema baseline, the schema baseline can not be deleted if it is attached to any audit plan",
                 "tags": [
-                    "rule_template"
+                    "schema_baseline"
                 ],
-                "summary": "获取规则模板信息",
-                "operationId": "getRuleTemplateV1",
+                "summary": "删除库表结构基线",
+                "operationId": "deleteSchemaBaselineV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
+                        "description": "schema baseline name",
+                        "name": "schema_baseline_name",
                         "in": "path",
                         "required": true
                     }
@@ -2349,112 +3049,137 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetRuleTemplateResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/sql_query/explain/{instance_name}/": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "delete rule template",
+                "description": "get SQL explain",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "rule_template"
+                    "sql_query"
                 ],
-                "summary": "删除规则模板",
-                "operationId": "deleteRuleTemplateV1",
+                "summary": "获取SQL执行计划",
+                "operationId": "getSQLExplain",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
+                        "description": "instance name",
+                        "name": "instance_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "sql and schema",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlExplainReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetSQLExplainResV1"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/v1/sql_query/history/{instance_name}/": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update rule template",
+                "description": "get sql query history",
                 "tags": [
-                    "rule_template"
+                    "sql_query"
                 ],
-                "summary": "更新规则模板",
-                "operationId": "updateRuleTemplateV1",
+                "summary": "获取当前用户历史查询SQL",
+                "operationId": "getSQLQueryHistory",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
+                        "description": "instance name",
+                        "name": "instance_name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "update rule template request",
-                        "name": "instance",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.UpdateRuleTemplateReqV1"
-                        }
+                        "type": "string",
+                        "description": "fuzzy search filter",
+                        "name": "filter_fuzzy_search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetSQLQueryHistoryResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/rule_templates/{rule_template_name}/clone": {
+        "/v1/sql_query/prepare/{instance_name}/": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "clone a rule template",
+                "description": "execute sql query",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "rule_template"
+                    "sql_query"
                 ],
-                "summary": "克隆规则模板",
-                "operationId": "CloneRuleTemplateV1",
+                "summary": "准备执行查询sql",
+                "operationId": "prepareSQLQuery",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
+                        "description": "instance name",
+                        "name": "instance_name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "clone rule template request",
-                        "name": "instance",
+                        "description": "exec sql",
+                        "name": "req",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CloneRuleTemplateReqV1"
+                            "$ref": "#/definitions/v1.PrepareSQLQueryReqV1"
                         }
                     }
                 ],
@@ -2462,168 +3187,188 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.PrepareSQLQueryResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/rules": {
+        "/v1/sql_query/results/{query_id}/": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get all rule template",
+                "description": "get sql query result",
                 "tags": [
-                    "rule_template"
+                    "sql_query"
                 ],
-                "summary": "规则列表",
-                "operationId": "getRuleListV1",
+                "summary": "获取SQL查询结果",
+                "operationId": "getSQLResult",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "filter db type",
-                        "name": "filter_db_type",
-                        "in": "query"
+                        "description": "query sql id",
+                        "name": "query_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetRulesResV1"
+                            "$ref": "#/definitions/v1.GetSQLResultResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/sql_query/explain/{instance_name}/": {
+        "/v1/tasks/audits": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get SQL explain",
+                "description": "create and audit a task, you can upload sql content in three ways, any one can be used, but only one is effective.\n1. formData[sql]: sql content;\n2. file[input_sql_file]: it is a sql file;\n3. file[input_mybatis_xml_file]: it is mybatis xml file, sql will be parsed from it.",
                 "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sql_query"
+                    "task"
                 ],
-                "summary": "获取SQL执行计划",
-                "operationId": "getSQLExplain",
+                "summary": "创建Sql审核任务并提交审核",
+                "operationId": "createAndAuditTaskV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "instance name",
+                        "description": "instance name, one of instance name and schema baseline name is required",
                         "name": "instance_name",
-                        "in": "path",
-                        "required": true
+                        "in": "formData"
                     },
                     {
-                        "description": "sql and schema",
-                        "name": "req",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.GetSqlExplainReqV1"
-                        }
+                        "type": "string",
+                        "description": "schema of instance",
+                        "name": "instance_schema",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "schema baseline name, the SQLs are audited offline against it",
+                        "name": "schema_baseline_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sqls for audit",
+                        "name": "sql",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "input SQL file",
+                        "name": "input_sql_file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "input mybatis XML file",
+                        "name": "input_mybatis_xml_file",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSQLExplainResV1"
+                            "$ref": "#/definitions/v1.GetAuditTaskResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/sql_query/history/{instance_name}/": {
+        "/v1/tasks/audits/{task_id}/": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql query history",
+                "description": "get task",
                 "tags": [
-                    "sql_query"
+                    "task"
                 ],
-                "summary": "获取当前用户历史查询SQL",
-                "operationId": "getSQLQueryHistory",
+                "summary": "获取Sql审核任务信息",
+                "operationId": "getAuditTaskV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "instance name",
-                        "name": "instance_name",
+                        "description": "task id",
+                        "name": "task_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "fuzzy search filter",
-                        "name": "filter_fuzzy_search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page index",
-                        "name": "page_index",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "size of per page",
-                        "name": "page_size",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSQLQueryHistoryResV1"
+                            "$ref": "#/definitions/v1.GetAuditTaskResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/sql_query/prepare/{instance_name}/": {
+        "/v1/tasks/audits/{task_id}/dry_run": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "execute sql query",
+                "description": "dry run the audit task, the SQLs are executed against the throwaway copies of the tables they use, and the copies are dropped afterwards",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "sql_query"
+                    "task"
                 ],
-                "summary": "准备执行查询sql",
-                "operationId": "prepareSQLQuery",
+                "summary": "在影子表上试运行审核任务",
+                "operationId": "dryRunAuditTaskV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "instance name",
-                        "name": "instance_name",
+                        "description": "task id",
+                        "name": "task_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "exec sql",
+                        "description": "dry run options",
                         "name": "req",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.PrepareSQLQueryReqV1"
+                            "$ref": "#/definitions/v1.DryRunAuditTaskReqV1"
                         }
                     }
                 ],
@@ -2631,133 +3376,101 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.PrepareSQLQueryResV1"
+                            "$ref": "#/definitions/v1.DryRunAuditTaskResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/sql_query/results/{query_id}/": {
-            "get": {
+        "/v1/tasks/audits/{task_id}/fixes": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql query result",
+                "description": "apply the accepted fix suggestions of SQLs in the audit task, a new task revision is created and audited",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "sql_query"
+                    "task"
                 ],
-                "summary": "获取SQL查询结果",
-                "operationId": "getSQLResult",
+                "summary": "应用审核任务中的SQL修复建议",
+                "operationId": "applyAuditTaskFixesV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "query sql id",
-                        "name": "query_id",
+                        "description": "task id",
+                        "name": "task_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "page index",
-                        "name": "page_index",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "size of per page",
-                        "name": "page_size",
-                        "in": "query",
-                        "required": true
+                        "description": "the numbers of SQLs which fix suggestions are accepted",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ApplyAuditTaskFixesReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSQLResultResV1"
+                            "$ref": "#/definitions/v1.GetAuditTaskResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/tasks/audits": {
-            "post": {
+        "/v1/tasks/audits/{task_id}/rollback_preview": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "create and audit a task, you can upload sql content in three ways, any one can be used, but only one is effective.\n1. formData[sql]: sql content;\n2. file[input_sql_file]: it is a sql file;\n3. file[input_mybatis_xml_file]: it is mybatis xml file, sql will be parsed from it.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "preview the statements to be executed in order and the rows they match when the audit task is rolled back, nothing is executed",
                 "tags": [
                     "task"
                 ],
-                "summary": "创建Sql审核任务并提交审核",
-                "operationId": "createAndAuditTaskV1",
+                "summary": "预览审核任务的回滚操作",
+                "operationId": "getAuditTaskRollbackPreviewV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "instance name",
-                        "name": "instance_name",
-                        "in": "formData",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "schema of instance",
-                        "name": "instance_schema",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "sqls for audit",
-                        "name": "sql",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "input SQL file",
-                        "name": "input_sql_file",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "input mybatis XML file",
-                        "name": "input_mybatis_xml_file",
-                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetAuditTaskResV1"
+                            "$ref": "#/definitions/v1.GetAuditTaskRollbackPreviewResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/tasks/audits/{task_id}/": {
+        "/v1/tasks/audits/{task_id}/rollback_sqls": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get task",
+                "description": "get rollback SQLs and the outcome of each statement for the audit task",
                 "tags": [
                     "task"
                 ],
-                "summary": "获取Sql审核任务信息",
-                "operationId": "getAuditTaskV1",
+                "summary": "获取审核任务的回滚SQL及执行结果",
+                "operationId": "getAuditTaskRollbackSQLsV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -2771,7 +3484,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetAuditTaskResV1"
+                            "$ref": "#/definitions/v1.GetAuditTaskRollbackSQLsResV1"
                         }
                     }
                 }
@@ -3864,7 +4577,10 @@ var doc = `{
                             "exec_scheduled",
                             "executing",
                             "exec_failed",
-                            "finished"
+                            "finished",
+                            "rolling_back",
+                            "rollback_failed",
+                            "rolled_back"
                         ],
                         "type": "string",
                         "description": "filter workflow status",
@@ -4253,6 +4969,38 @@ var doc = `{
                 }
             }
         },
+        "/v1/workflows/{workflow_id}/task/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "rollback task on workflow, the rollback is executed in background, and the workflow is rolled back only if all the rollback SQLs succeed",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "工单回滚 SQL 上线",
+                "operationId": "rollbackTaskOnWorkflowV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow id",
+                        "name": "workflow_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
         "/v2/audit_plans/{audit_plan_name}/report/{audit_plan_report_id}/": {
             "get": {
                 "security": [
@@ -4561,6 +5309,17 @@ var doc = `{
                 }
             }
         },
+        "v1.ApplyAuditTaskFixesReqV1": {
+            "type": "object",
+            "properties": {
+                "sql_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
         "v1.AuditPlanMetaV1": {
             "type": "object",
             "properties": {
@@ -4691,6 +5450,10 @@ var doc = `{
                     "type": "string",
                     "example": "audit_for_java_app1"
                 },
+                "audit_plan_schema_baseline_name": {
+                    "type": "string",
+                    "example": "app1_baseline"
+                },
                 "audit_plan_token": {
                     "type": "string",
                     "example": "it's a JWT Token for scanner"
@@ -4739,6 +5502,105 @@ var doc = `{
                 }
             }
         },
+        "v1.AuditResultResV1": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "fixable": {
+                    "type": "boolean"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "warn"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SQLPositionResV1"
+                },
+                "rule_name": {
+                    "type": "string",
+                    "example": "dml_check_with_limit"
+                },
+                "suppress_reason": {
+                    "type": "string"
+                },
+                "suppressed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.AuditRuleSandboxReqV1": {
+            "type": "object",
+            "properties": {
+                "db_type": {
+                    "type": "string",
+                    "example": "mysql"
+                },
+                "rule_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleReqV1"
+                    }
+                },
+                "rule_template_name": {
+                    "type": "string"
+                },
+                "setup_sql": {
+                    "type": "string",
+                    "example": "create table t1(id int primary key, name varchar(32));"
+                },
+                "sql": {
+                    "type": "string",
+                    "example": "alter table t1 modify column name varchar(16);"
+                }
+            }
+        },
+        "v1.AuditRuleSandboxResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleSandboxSQLResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.AuditTaskDryRunSQLResV1": {
+            "type": "object",
+            "properties": {
+                "dry_run_result": {
+                    "type": "string"
+                },
+                "dry_run_status": {
+                    "type": "string",
+                    "enum": [
+                        "succeeded",
+                        "failed",
+                        "skipped"
+                    ]
+                },
+                "exec_sql": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.AuditTaskResV1": {
             "type": "object",
             "properties": {
@@ -4771,6 +5633,9 @@ var doc = `{
                 "score": {
                     "type": "integer"
                 },
+                "source_task_id": {
+                    "type": "integer"
+                },
                 "sql_source": {
                     "type": "string",
                     "enum": [
@@ -4787,7 +5652,10 @@ var doc = `{
                         "audited",
                         "executing",
                         "exec_success",
-                        "exec_failed"
+                        "exec_failed",
+                        "rolling_back",
+                        "rollback_succeeded",
+                        "rollback_failed"
                     ]
                 },
                 "task_id": {
@@ -4795,6 +5663,73 @@ var doc = `{
                 }
             }
         },
+        "v1.AuditTaskRollbackPreviewResV1": {
+            "type": "object",
+            "properties": {
+                "exec_sql_number": {
+                    "type": "integer"
+                },
+                "group": {
+                    "type": "integer"
+                },
+                "in_transaction": {
+                    "type": "boolean"
+                },
+                "matched_rows": {
+                    "description": "MatchedRows is omitted if the rows can not be counted.",
+                    "type": "integer"
+                },
+                "sql": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.AuditTaskRollbackSQLResV1": {
+            "type": "object",
+            "properties": {
+                "exec_result": {
+                    "type": "string"
+                },
+                "exec_sql_number": {
+                    "type": "integer"
+                },
+                "exec_status": {
+                    "type": "string"
+                },
+                "rollback_sql": {
+                    "type": "string"
+                },
+                "row_affects": {
+                    "type": "integer"
+                },
+                "statements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditTaskRollbackSQLStatementResV1"
+                    }
+                }
+            }
+        },
+        "v1.AuditTaskRollbackSQLStatementResV1": {
+            "type": "object",
+            "properties": {
+                "exec_result": {
+                    "type": "string"
+                },
+                "exec_status": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "row_affects": {
+                    "type": "integer"
+                },
+                "sql": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.AuditTaskSQLContentResV1": {
             "type": "object",
             "properties": {
@@ -4813,12 +5748,33 @@ var doc = `{
                 "audit_result": {
                     "type": "string"
                 },
+                "audit_results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditResultResV1"
+                    }
+                },
                 "audit_status": {
                     "type": "string"
                 },
                 "description": {
                     "type": "string"
                 },
+                "dry_run_result": {
+                    "type": "string"
+                },
+                "dry_run_status": {
+                    "type": "string",
+                    "enum": [
+                        "succeeded",
+                        "failed",
+                        "skipped"
+                    ]
+                },
+                "estimated_affected_rows": {
+                    "description": "EstimatedAffectedRows is omitted if the affected rows is not estimated.",
+                    "type": "integer"
+                },
                 "exec_result": {
                     "type": "string"
                 },
@@ -4828,6 +5784,9 @@ var doc = `{
                 "exec_status": {
                     "type": "string"
                 },
+                "fixed_sql": {
+                    "type": "string"
+                },
                 "number": {
                     "type": "integer"
                 },
@@ -4971,6 +5930,10 @@ var doc = `{
                         "$ref": "#/definitions/v1.AuditPlanParamReqV1"
                     }
                 },
+                "audit_plan_schema_baseline_name": {
+                    "type": "string",
+                    "example": "app1_baseline"
+                },
                 "audit_plan_type": {
                     "type": "string",
                     "example": "slow log"
@@ -4998,6 +5961,57 @@ var doc = `{
                 }
             }
         },
+        "v1.CreateCustomRuleReqV1": {
+            "type": "object",
+            "properties": {
+                "db_type": {
+                    "type": "string",
+                    "example": "mysql"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ],
+                    "example": "warn"
+                },
+                "match_type": {
+                    "type": "string",
+                    "enum": [
+                        "sql_regex",
+                        "fingerprint_regex",
+                        "ast_predicate"
+                    ]
+                },
+                "matcher": {
+                    "description": "Matcher is a regexp, or a JSON predicate if match type is ast_predicate, e.g.\n{\"table_name_pattern\": \"^tmp_\", \"missing_clauses\": [\"where\"]}",
+                    "type": "string"
+                },
+                "rule_name": {
+                    "type": "string"
+                },
+                "stmt_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "select",
+                        "update"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "example": "自定义规则"
+                }
+            }
+        },
         "v1.CreateInstanceReqV1": {
             "type": "object",
             "properties": {
@@ -5047,6 +6061,12 @@ var doc = `{
                         "type": "string"
                     }
                 },
+                "rule_override_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.InstanceRuleOverrideReqV1"
+                    }
+                },
                 "rule_template_name_list": {
                     "type": "array",
                     "items": {
@@ -5088,6 +6108,9 @@ var doc = `{
         "v1.CreateRuleTemplateReqV1": {
             "type": "object",
             "properties": {
+                "allow_suppression": {
+                    "type": "boolean"
+                },
                 "db_type": {
                     "type": "string"
                 },
@@ -5108,6 +6131,18 @@ var doc = `{
                 },
                 "rule_template_name": {
                     "type": "string"
+                },
+                "suppressible_levels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "normal",
+                            "notice",
+                            "warn",
+                            "error"
+                        ]
+                    }
                 }
             }
         },
@@ -5215,6 +6250,50 @@ var doc = `{
                 }
             }
         },
+        "v1.CustomRuleResV1": {
+            "type": "object",
+            "properties": {
+                "db_type": {
+                    "type": "string",
+                    "example": "mysql"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "match_type": {
+                    "type": "string",
+                    "enum": [
+                        "sql_regex",
+                        "fingerprint_regex",
+                        "ast_predicate"
+                    ]
+                },
+                "matcher": {
+                    "type": "string"
+                },
+                "rule_name": {
+                    "type": "string"
+                },
+                "stmt_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.DashboardResV1": {
             "type": "object",
             "properties": {
@@ -5224,9 +6303,53 @@ var doc = `{
                 }
             }
         },
+        "v1.DriverResV1": {
+            "type": "object",
+            "properties": {
+                "capabilities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "rollback",
+                            "query",
+                            "analysis",
+                            "online_ddl",
+                            "offline_audit",
+                            "sandbox",
+                            "schema_baseline"
+                        ]
+                    }
+                },
+                "driver_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.DriverStatusResV1": {
+            "type": "object",
+            "properties": {
+                "driver_name": {
+                    "type": "string"
+                },
+                "is_plugin": {
+                    "type": "boolean"
+                },
+                "process": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.PluginProcessResV1"
+                }
+            }
+        },
         "v1.DriversResV1": {
             "type": "object",
             "properties": {
+                "driver_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.DriverResV1"
+                    }
+                },
                 "driver_name_list": {
                     "type": "array",
                     "items": {
@@ -5235,6 +6358,39 @@ var doc = `{
                 }
             }
         },
+        "v1.DryRunAuditTaskReqV1": {
+            "type": "object",
+            "properties": {
+                "sample_rows": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "staging_instance_name": {
+                    "description": "StagingInstanceName is the instance where the shadow tables are created, they are\ncreated on the instance of task if it is empty.",
+                    "type": "string",
+                    "example": "inst_staging"
+                }
+            }
+        },
+        "v1.DryRunAuditTaskResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditTaskDryRunSQLResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.ExplainClassicResult": {
             "type": "object",
             "properties": {
@@ -5420,7 +6576,106 @@ var doc = `{
                 }
             }
         },
-        "v1.GetAuditPlanSQLsResV1": {
+        "v1.GetAuditPlanSQLsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditPlanSQLResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.GetAuditPlansResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditPlanResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.GetAuditTaskResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditTaskResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetAuditTaskRollbackPreviewResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditTaskRollbackPreviewResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetAuditTaskRollbackSQLsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditTaskRollbackSQLResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetAuditTaskSQLContentResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -5428,21 +6683,16 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.AuditPlanSQLResV1"
-                    }
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditTaskSQLContentResV1"
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
-                },
-                "total_nums": {
-                    "type": "integer"
                 }
             }
         },
-        "v1.GetAuditPlansResV1": {
+        "v1.GetAuditTaskSQLsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -5452,7 +6702,7 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.AuditPlanResV1"
+                        "$ref": "#/definitions/v1.AuditTaskSQLResV1"
                     }
                 },
                 "message": {
@@ -5464,7 +6714,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetAuditTaskResV1": {
+        "v1.GetAuditWhitelistResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -5472,16 +6722,21 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.AuditTaskResV1"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditWhitelistResV1"
+                    }
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.GetAuditTaskSQLContentResV1": {
+        "v1.GetCustomRuleResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -5490,7 +6745,7 @@ var doc = `{
                 },
                 "data": {
                     "type": "object",
-                    "$ref": "#/definitions/v1.AuditTaskSQLContentResV1"
+                    "$ref": "#/definitions/v1.CustomRuleResV1"
                 },
                 "message": {
                     "type": "string",
@@ -5498,7 +6753,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetAuditTaskSQLsResV1": {
+        "v1.GetCustomRulesResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -5508,19 +6763,16 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.AuditTaskSQLResV1"
+                        "$ref": "#/definitions/v1.CustomRuleResV1"
                     }
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
-                },
-                "total_nums": {
-                    "type": "integer"
                 }
             }
         },
-        "v1.GetAuditWhitelistResV1": {
+        "v1.GetDashboardResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -5528,21 +6780,16 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.AuditWhitelistResV1"
-                    }
+                    "type": "object",
+                    "$ref": "#/definitions/v1.DashboardResV1"
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
-                },
-                "total_nums": {
-                    "type": "integer"
                 }
             }
         },
-        "v1.GetDashboardResV1": {
+        "v1.GetDriversResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -5551,7 +6798,7 @@ var doc = `{
                 },
                 "data": {
                     "type": "object",
-                    "$ref": "#/definitions/v1.DashboardResV1"
+                    "$ref": "#/definitions/v1.DriversResV1"
                 },
                 "message": {
                     "type": "string",
@@ -5559,7 +6806,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetDriversResV1": {
+        "v1.GetDriversStatusResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -5567,8 +6814,10 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.DriversResV1"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.DriverStatusResV1"
+                    }
                 },
                 "message": {
                     "type": "string",
@@ -5774,6 +7023,25 @@ var doc = `{
                 }
             }
         },
+        "v1.GetMetaCacheStatisticsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.MetaCacheStatisticsResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetOauth2ConfigurationResDataV1": {
             "type": "object",
             "properties": {
@@ -5876,6 +7144,25 @@ var doc = `{
                 }
             }
         },
+        "v1.GetPluginsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.PluginResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetRoleTipsResV1": {
             "type": "object",
             "properties": {
@@ -5917,6 +7204,25 @@ var doc = `{
                 }
             }
         },
+        "v1.GetRuleHitStatisticsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleHitStatisticsResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetRuleTemplateResV1": {
             "type": "object",
             "properties": {
@@ -6141,6 +7447,42 @@ var doc = `{
                 }
             }
         },
+        "v1.GetSchemaBaselineTipsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SchemaBaselineTipResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetSchemaDesignReportResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SchemaDesignReportResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetSqlExplainReqV1": {
             "type": "object",
             "properties": {
@@ -6551,6 +7893,12 @@ var doc = `{
                         "type": "string"
                     }
                 },
+                "rule_override_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.InstanceRuleOverrideResV1"
+                    }
+                },
                 "rule_template_name_list": {
                     "type": "array",
                     "items": {
@@ -6566,6 +7914,53 @@ var doc = `{
                 }
             }
         },
+        "v1.InstanceRuleOverrideReqV1": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleParamReqV1"
+                    }
+                },
+                "rule_name": {
+                    "type": "string",
+                    "example": "ddl_check_object_name_length"
+                }
+            }
+        },
+        "v1.InstanceRuleOverrideResV1": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleParamResV1"
+                    }
+                },
+                "rule_name": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.InstanceSchemaResV1": {
             "type": "object",
             "properties": {
@@ -6708,6 +8103,26 @@ var doc = `{
                 }
             }
         },
+        "v1.MetaCacheStatisticsResV1": {
+            "type": "object",
+            "properties": {
+                "cached_tables": {
+                    "type": "integer"
+                },
+                "hit_rate": {
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "misses": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.Oauth2ConfigurationReqV1": {
             "type": "object",
             "properties": {
@@ -6755,43 +8170,115 @@ var doc = `{
                 "op_code": {
                     "type": "integer"
                 },
-                "op_desc": {
+                "op_desc": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.PartialSyncAuditPlanSQLsReqV1": {
+            "type": "object",
+            "properties": {
+                "audit_plan_sql_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditPlanSQLReqV1"
+                    }
+                }
+            }
+        },
+        "v1.PatchUserGroupReqV1": {
+            "type": "object",
+            "properties": {
+                "is_disabled": {
+                    "type": "boolean"
+                },
+                "role_name_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_group_desc": {
+                    "type": "string",
+                    "example": "this is a group"
+                },
+                "user_name_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v1.PluginProcessResV1": {
+            "type": "object",
+            "properties": {
+                "avg_rpc_latency_ms": {
+                    "type": "integer"
+                },
+                "inflight_rpc_count": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_error_at": {
+                    "type": "string"
+                },
+                "last_rpc_latency_ms": {
+                    "type": "integer"
+                },
+                "longest_inflight_rpc_ms": {
+                    "type": "integer"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "plugin_path": {
+                    "type": "string"
+                },
+                "restart_count": {
+                    "type": "integer"
+                },
+                "rpc_count": {
+                    "type": "integer"
+                },
+                "started_at": {
                     "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "not_started",
+                        "running",
+                        "stopped",
+                        "crashed",
+                        "start_failed",
+                        "unresponsive"
+                    ]
                 }
             }
         },
-        "v1.PartialSyncAuditPlanSQLsReqV1": {
-            "type": "object",
-            "properties": {
-                "audit_plan_sql_list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.AuditPlanSQLReqV1"
-                    }
-                }
-            }
-        },
-        "v1.PatchUserGroupReqV1": {
+        "v1.PluginResV1": {
             "type": "object",
             "properties": {
-                "is_disabled": {
-                    "type": "boolean"
+                "last_error": {
+                    "type": "string"
                 },
-                "role_name_list": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "last_error_at": {
+                    "type": "string"
                 },
-                "user_group_desc": {
-                    "type": "string",
-                    "example": "this is a group"
+                "loaded_at": {
+                    "type": "string"
                 },
-                "user_name_list": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "mod_time": {
+                    "type": "string"
+                },
+                "plugin_name": {
+                    "type": "string"
+                },
+                "plugin_path": {
+                    "type": "string"
                 }
             }
         },
@@ -6884,6 +8371,61 @@ var doc = `{
                 }
             }
         },
+        "v1.RuleHitStatisticsResV1": {
+            "type": "object",
+            "properties": {
+                "audit_count": {
+                    "type": "integer"
+                },
+                "blocked_count": {
+                    "type": "integer"
+                },
+                "db_type": {
+                    "type": "string"
+                },
+                "hit_count": {
+                    "type": "integer"
+                },
+                "instance_name": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "rule_name": {
+                    "type": "string"
+                },
+                "rule_template_name": {
+                    "type": "string"
+                },
+                "suppressed_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.RuleKnowledgeResV1": {
+            "type": "object",
+            "properties": {
+                "bad_example": {
+                    "type": "string"
+                },
+                "good_example": {
+                    "type": "string"
+                },
+                "rationale": {
+                    "type": "string"
+                },
+                "remediation": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.RuleParamReqV1": {
             "type": "object",
             "properties": {
@@ -6904,6 +8446,14 @@ var doc = `{
                 "key": {
                     "type": "string"
                 },
+                "origin": {
+                    "description": "Origin is only returned by the rules of instance.",
+                    "type": "string",
+                    "enum": [
+                        "rule_template",
+                        "instance"
+                    ]
+                },
                 "type": {
                     "type": "string",
                     "enum": [
@@ -6946,6 +8496,10 @@ var doc = `{
                 "desc": {
                     "type": "string"
                 },
+                "knowledge": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.RuleKnowledgeResV1"
+                },
                 "level": {
                     "type": "string",
                     "enum": [
@@ -6956,6 +8510,14 @@ var doc = `{
                     ],
                     "example": "error"
                 },
+                "level_origin": {
+                    "description": "LevelOrigin is only returned by the rules of instance.",
+                    "type": "string",
+                    "enum": [
+                        "rule_template",
+                        "instance"
+                    ]
+                },
                 "params": {
                     "type": "array",
                     "items": {
@@ -6971,9 +8533,39 @@ var doc = `{
                 }
             }
         },
+        "v1.RuleSandboxSQLResV1": {
+            "type": "object",
+            "properties": {
+                "audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error",
+                        ""
+                    ]
+                },
+                "audit_results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditResultResV1"
+                    }
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "sql": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.RuleTemplateDetailResV1": {
             "type": "object",
             "properties": {
+                "allow_suppression": {
+                    "type": "boolean"
+                },
                 "db_type": {
                     "type": "string"
                 },
@@ -6994,6 +8586,18 @@ var doc = `{
                 },
                 "rule_template_name": {
                     "type": "string"
+                },
+                "suppressible_levels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "normal",
+                            "notice",
+                            "warn",
+                            "error"
+                        ]
+                    }
                 }
             }
         },
@@ -7066,6 +8670,23 @@ var doc = `{
                 }
             }
         },
+        "v1.SQLPositionResV1": {
+            "type": "object",
+            "properties": {
+                "end_column": {
+                    "type": "integer"
+                },
+                "end_line": {
+                    "type": "integer"
+                },
+                "start_column": {
+                    "type": "integer"
+                },
+                "start_line": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.SQLQueryConfigReqV1": {
             "type": "object",
             "properties": {
@@ -7124,6 +8745,69 @@ var doc = `{
                 }
             }
         },
+        "v1.SchemaBaselineTipResV1": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "db_type": {
+                    "type": "string"
+                },
+                "default_schema": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.SchemaDesignReportResV1": {
+            "type": "object",
+            "properties": {
+                "audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error",
+                        ""
+                    ]
+                },
+                "pass_rate": {
+                    "type": "number"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SchemaDesignReportTableResV1"
+                    }
+                }
+            }
+        },
+        "v1.SchemaDesignReportTableResV1": {
+            "type": "object",
+            "properties": {
+                "audit_result": {
+                    "type": "string",
+                    "example": "same format as task audit result"
+                },
+                "create_table_sql": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
         "v1.SystemVariablesResV1": {
             "type": "object",
             "properties": {
@@ -7403,6 +9087,10 @@ var doc = `{
                     "items": {
                         "$ref": "#/definitions/v1.AuditPlanParamReqV1"
                     }
+                },
+                "audit_plan_schema_baseline_name": {
+                    "type": "string",
+                    "example": "app1_baseline"
                 }
             }
         },
@@ -7458,6 +9146,43 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateCustomRuleReqV1": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "match_type": {
+                    "type": "string",
+                    "enum": [
+                        "sql_regex",
+                        "fingerprint_regex",
+                        "ast_predicate"
+                    ]
+                },
+                "matcher": {
+                    "type": "string"
+                },
+                "stmt_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.UpdateInstanceReqV1": {
             "type": "object",
             "properties": {
@@ -7503,6 +9228,12 @@ var doc = `{
                         "type": "string"
                     }
                 },
+                "rule_override_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.InstanceRuleOverrideReqV1"
+                    }
+                },
                 "rule_template_name_list": {
                     "type": "array",
                     "items": {
@@ -7549,6 +9280,9 @@ var doc = `{
         "v1.UpdateRuleTemplateReqV1": {
             "type": "object",
             "properties": {
+                "allow_suppression": {
+                    "type": "boolean"
+                },
                 "desc": {
                     "type": "string"
                 },
@@ -7566,6 +9300,18 @@ var doc = `{
                     "items": {
                         "$ref": "#/definitions/v1.RuleReqV1"
                     }
+                },
+                "suppressible_levels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "normal",
+                            "notice",
+                            "warn",
+                            "error"
+                        ]
+                    }
                 }
             }
         },
@@ -7932,7 +9678,10 @@ var doc = `{
                         "exec_scheduled",
                         "executing",
                         "exec_failed",
-                        "finished"
+                        "finished",
+                        "rolling_back",
+                        "rollback_failed",
+                        "rolled_back"
                     ]
                 },
                 "subject": {
@@ -7976,7 +9725,10 @@ var doc = `{
                         "exec_scheduled",
                         "executing",
                         "exec_failed",
-                        "finished"
+                        "finished",
+                        "rolling_back",
+                        "rollback_failed",
+                        "rolled_back"
                     ]
                 },
                 "task_id": {
@@ -0,0 +1,69 @@
+package driver
+
+// Capability is a feature supported by driver.
+type Capability string
+
+const (
+	// CapabilityRollback means the driver can generate rollback SQL by GenRollbackSQL.
+	CapabilityRollback Capability = "rollback"
+	// CapabilityQuery means the driver has a registered SQLQueryDriver.
+	CapabilityQuery Capability = "query"
+	// CapabilityAnalysis means the driver has a registered AnalysisDriver.
+	CapabilityAnalysis Capability = "analysis"
+	// CapabilityOnlineDDL means the driver can execute DDL by online schema change tools.
+	CapabilityOnlineDDL Capability = "online_ddl"
+	// CapabilityOfflineAudit means the driver can audit SQL without connecting to instance.
+	CapabilityOfflineAudit Capability = "offline_audit"
+	// CapabilitySandbox means the driver can audit SQL against a mock database built by
+	// the audited SQL, see Config.Sandbox.
+	CapabilitySandbox Capability = "sandbox"
+	// CapabilitySchemaBaseline means the driver can audit SQL offline against a schema
+	// dump, see Config.SchemaBaseline.
+	CapabilitySchemaBaseline Capability = "schema_baseline"
+)
+
+// DriverCapabilities returns the capabilities of driver, including the declared
+// capabilities and the capabilities detected from registered query and analysis drivers.
+func DriverCapabilities(name string) []Capability {
+	capabilitiesMu.RLock()
+	declared := capabilities[name]
+	capabilitiesMu.RUnlock()
+
+	caps := make([]Capability, 0, len(declared)+2)
+	exist := map[Capability]struct{}{}
+	add := func(c Capability) {
+		if _, ok := exist[c]; ok {
+			return
+		}
+		exist[c] = struct{}{}
+		caps = append(caps, c)
+	}
+	for _, c := range declared {
+		add(c)
+	}
+
+	queryDriverMu.RLock()
+	_, ok := queryDrivers[name]
+	queryDriverMu.RUnlock()
+	if ok {
+		add(CapabilityQuery)
+	}
+
+	analysisDriverMu.RLock()
+	_, ok = analysisDrivers[name]
+	analysisDriverMu.RUnlock()
+	if ok {
+		add(CapabilityAnalysis)
+	}
+	return caps
+}
+
+// HasCapability reports whether driver supports the capability.
+func HasCapability(name string, c Capability) bool {
+	for _, dc := range DriverCapabilities(name) {
+		if dc == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/pkg/params"
+)
+
+// Custom rules are defined by user and stored by sqle, they are passed to driver
+// as normal rules, the matcher of custom rule is carried by following params.
+const (
+	CustomRuleParamKeyMatchType = "custom_rule_match_type"
+	CustomRuleParamKeyMatcher   = "custom_rule_matcher"
+	CustomRuleParamKeyStmtTypes = "custom_rule_stmt_types"
+)
+
+const (
+	// CustomRuleMatchTypeSQLRegex matches the regexp with the raw SQL.
+	CustomRuleMatchTypeSQLRegex = "sql_regex"
+	// CustomRuleMatchTypeFingerprintRegex matches the regexp with the fingerprint of SQL.
+	CustomRuleMatchTypeFingerprintRegex = "fingerprint_regex"
+	// CustomRuleMatchTypeASTPredicate matches the declarative predicate with the parsed SQL.
+	CustomRuleMatchTypeASTPredicate = "ast_predicate"
+)
+
+// statement types which custom rule can apply to.
+const (
+	CustomRuleStmtTypeSelect         = "select"
+	CustomRuleStmtTypeInsert         = "insert"
+	CustomRuleStmtTypeReplace        = "replace"
+	CustomRuleStmtTypeUpdate         = "update"
+	CustomRuleStmtTypeDelete         = "delete"
+	CustomRuleStmtTypeCreateTable    = "create_table"
+	CustomRuleStmtTypeAlterTable     = "alter_table"
+	CustomRuleStmtTypeDropTable      = "drop_table"
+	CustomRuleStmtTypeTruncateTable  = "truncate_table"
+	CustomRuleStmtTypeCreateIndex    = "create_index"
+	CustomRuleStmtTypeDropIndex      = "drop_index"
+	CustomRuleStmtTypeCreateDatabase = "create_database"
+	CustomRuleStmtTypeDropDatabase   = "drop_database"
+	CustomRuleStmtTypeCreateView     = "create_view"
+	CustomRuleStmtTypeOther          = "other"
+)
+
+var CustomRuleStmtTypes = []string{
+	CustomRuleStmtTypeSelect, CustomRuleStmtTypeInsert, CustomRuleStmtTypeReplace,
+	CustomRuleStmtTypeUpdate, CustomRuleStmtTypeDelete, CustomRuleStmtTypeCreateTable,
+	CustomRuleStmtTypeAlterTable, CustomRuleStmtTypeDropTable, CustomRuleStmtTypeTruncateTable,
+	CustomRuleStmtTypeCreateIndex, CustomRuleStmtTypeDropIndex, CustomRuleStmtTypeCreateDatabase,
+	CustomRuleStmtTypeDropDatabase, CustomRuleStmtTypeCreateView, CustomRuleStmtTypeOther,
+}
+
+// clauses which can be used in CustomRuleASTPredicate.
+const (
+	CustomRuleClauseWhere    = "where"
+	CustomRuleClauseLimit    = "limit"
+	CustomRuleClauseOrderBy  = "order_by"
+	CustomRuleClauseGroupBy  = "group_by"
+	CustomRuleClauseHaving   = "having"
+	CustomRuleClauseJoin     = "join"
+	CustomRuleClauseSubquery = "subquery"
+)
+
+var CustomRuleClauses = []string{
+	CustomRuleClauseWhere, CustomRuleClauseLimit, CustomRuleClauseOrderBy, CustomRuleClauseGroupBy,
+	CustomRuleClauseHaving, CustomRuleClauseJoin, CustomRuleClauseSubquery,
+}
+
+// CustomRuleASTPredicate is the declarative predicate over the parsed SQL, it matches
+// the SQL if all the specified conditions are satisfied.
+type CustomRuleASTPredicate struct {
+	// TableNamePattern matches if any table name in SQL matches the regexp.
+	TableNamePattern string `json:"table_name_pattern,omitempty"`
+	// ColumnNamePattern matches if any column name in SQL matches the regexp.
+	ColumnNamePattern string `json:"column_name_pattern,omitempty"`
+	// HasClauses matches if SQL has all the clauses.
+	HasClauses []string `json:"has_clauses,omitempty"`
+	// MissingClauses matches if SQL has none of the clauses.
+	MissingClauses []string `json:"missing_clauses,omitempty"`
+}
+
+// CustomRuleMatcher is the matcher of custom rule.
+type CustomRuleMatcher struct {
+	MatchType string
+	// Matcher is a regexp if MatchType is regex, or a JSON predicate if MatchType is ast_predicate.
+	Matcher string
+	// StmtTypes is the statement types the rule applies to, empty means all statements.
+	StmtTypes []string
+}
+
+// ASTPredicate parses the predicate of matcher which match type is ast_predicate.
+func (m *CustomRuleMatcher) ASTPredicate() (*CustomRuleASTPredicate, error) {
+	p := &CustomRuleASTPredicate{}
+	if err := json.Unmarshal([]byte(m.Matcher), p); err != nil {
+		return nil, fmt.Errorf("invalid ast predicate: %v", err)
+	}
+	return p, nil
+}
+
+// Validate checks the matcher can be used to audit.
+func (m *CustomRuleMatcher) Validate() error {
+	for _, t := range m.StmtTypes {
+		if !containsString(CustomRuleStmtTypes, t) {
+			return fmt.Errorf("unknown statement type %s", t)
+		}
+	}
+	switch m.MatchType {
+	case CustomRuleMatchTypeSQLRegex, CustomRuleMatchTypeFingerprintRegex:
+		if m.Matcher == "" {
+			return fmt.Errorf("regexp is required")
+		}
+		if _, err := regexp.Compile(m.Matcher); err != nil {
+			return fmt.Errorf("invalid regexp: %v", err)
+		}
+	case CustomRuleMatchTypeASTPredicate:
+		p, err := m.ASTPredicate()
+		if err != nil {
+			return err
+		}
+		if p.TableNamePattern == "" && p.ColumnNamePattern == "" &&
+			len(p.HasClauses) == 0 && len(p.MissingClauses) == 0 {
+			return fmt.Errorf("ast predicate has no condition")
+		}
+		for _, pattern := range []string{p.TableNamePattern, p.ColumnNamePattern} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid regexp: %v", err)
+			}
+		}
+		for _, c := range append(append([]string{}, p.HasClauses...), p.MissingClauses...) {
+			if !containsString(CustomRuleClauses, c) {
+				return fmt.Errorf("unknown clause %s", c)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown match type %s", m.MatchType)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCustomRuleParams returns the params which carry the matcher to driver.
+func NewCustomRuleParams(m *CustomRuleMatcher) params.Params {
+	return params.Params{
+		{
+			Key:   CustomRuleParamKeyMatchType,
+			Value: m.MatchType,
+			Desc:  "匹配方式",
+			Type:  params.ParamTypeString,
+		},
+		{
+			Key:   CustomRuleParamKeyMatcher,
+			Value: m.Matcher,
+			Desc:  "匹配规则",
+			Type:  params.ParamTypeString,
+		},
+		{
+			Key:   CustomRuleParamKeyStmtTypes,
+			Value: strings.Join(m.StmtTypes, ","),
+			Desc:  "适用语句类型",
+			Type:  params.ParamTypeString,
+		},
+	}
+}
+
+// GetCustomRuleMatcher returns the matcher of rule, ok is false if rule is not a custom rule.
+func GetCustomRuleMatcher(rule *Rule) (m *CustomRuleMatcher, ok bool) {
+	matchType := rule.Params.GetParam(CustomRuleParamKeyMatchType).String()
+	if matchType == "" {
+		return nil, false
+	}
+	m = &CustomRuleMatcher{
+		MatchType: matchType,
+		Matcher:   rule.Params.GetParam(CustomRuleParamKeyMatcher).String(),
+	}
+	stmtTypes := rule.Params.GetParam(CustomRuleParamKeyStmtTypes).String()
+	for _, t := range strings.Split(stmtTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			m.StmtTypes = append(m.StmtTypes, t)
+		}
+	}
+	return m, true
+}
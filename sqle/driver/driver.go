@@ -2,6 +2,7 @@ package driver
 
 import (
 	"os/exec"
+	"sync"
 
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/pkg/params"
@@ -23,6 +24,8 @@ type DSN struct {
 }
 
 type PluginClient interface {
+	// Path returns the plugin binary path.
+	Path() string
 	Kill()
 	Client() (goPlugin.ClientProtocol, error)
 	RegisterPlugin(c PluginClient) error
@@ -30,22 +33,84 @@ type PluginClient interface {
 
 type pluginClientOld struct {
 	path string
-	c    *goPlugin.Client
+	sup  *pluginSupervisor
+
+	mu sync.Mutex
+	c  *goPlugin.Client
+	// standby is the process restarted after the previous one crashed, it is handed to the next call.
+	standby goPlugin.ClientProtocol
 }
 
 func newOldClientFromFile(path string) *pluginClientOld {
-	return &pluginClientOld{
+	p := &pluginClientOld{
 		path: path,
+		sup:  newPluginSupervisor(path),
 	}
+	p.sup.restarter = p.restart
+	return p
+}
+
+func (p *pluginClientOld) Path() string {
+	return p.path
 }
 
 func (p *pluginClientOld) Kill() {
-	p.c.Kill()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// the standby process is not used by anyone, it is only killed when the plugin is unloaded.
+	if p.standby != nil && !p.sup.isClosed() {
+		return
+	}
+	p.killLocked()
+}
+
+func (p *pluginClientOld) killLocked() {
+	if p.c != nil {
+		p.sup.stopping(p.c)
+		p.c.Kill()
+	}
+	p.standby = nil
 }
 
 func (p *pluginClientOld) Client() (goPlugin.ClientProtocol, error) {
+	p.mu.Lock()
+	if c := p.standby; c != nil && !p.c.Exited() {
+		p.standby = nil
+		p.mu.Unlock()
+		return c, nil
+	}
+	p.mu.Unlock()
+
+	p.sup.waitBackoff()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.startLocked()
+}
+
+// restart starts a standby process after the previous one crashed, it is called by the exit watcher.
+func (p *pluginClientOld) restart() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.sup.needRestart() {
+		return nil
+	}
+	c, err := p.startLocked()
+	if err != nil {
+		return err
+	}
+	p.standby = c
+	return nil
+}
+
+func (p *pluginClientOld) startLocked() (goPlugin.ClientProtocol, error) {
 	p.resetClient()
-	return p.c.Client()
+	c, err := p.c.Client()
+	p.sup.started(p.c, err)
+	return c, err
+}
+
+func (p *pluginClientOld) supervisor() *pluginSupervisor {
+	return p.sup
 }
 
 func (p *pluginClientOld) RegisterPlugin(c PluginClient) error {
@@ -54,9 +119,7 @@ func (p *pluginClientOld) RegisterPlugin(c PluginClient) error {
 }
 
 func (p *pluginClientOld) resetClient() {
-	if p.c != nil {
-		p.c.Kill()
-	}
+	p.killLocked()
 	p.c = goPlugin.NewClient(&goPlugin.ClientConfig{
 		HandshakeConfig: handshakeConfig,
 		Plugins: goPlugin.PluginSet{
@@ -64,27 +127,90 @@ func (p *pluginClientOld) resetClient() {
 		},
 		Cmd:              exec.Command(p.path),
 		AllowedProtocols: []goPlugin.Protocol{goPlugin.ProtocolGRPC},
+		GRPCDialOptions:  p.sup.dialOptions(),
 	})
 }
 
 type pluginClient struct {
 	path string
-	c    *goPlugin.Client
+	sup  *pluginSupervisor
+
+	mu sync.Mutex
+	c  *goPlugin.Client
+	// standby is the process restarted after the previous one crashed, it is handed to the next call.
+	standby goPlugin.ClientProtocol
 }
 
 func newClientFromFile(path string) *pluginClient {
-	return &pluginClient{
+	p := &pluginClient{
 		path: path,
+		sup:  newPluginSupervisor(path),
 	}
+	p.sup.restarter = p.restart
+	return p
+}
+
+func (p *pluginClient) Path() string {
+	return p.path
 }
 
 func (p *pluginClient) Kill() {
-	p.c.Kill()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// the standby process is not used by anyone, it is only killed when the plugin is unloaded.
+	if p.standby != nil && !p.sup.isClosed() {
+		return
+	}
+	p.killLocked()
+}
+
+func (p *pluginClient) killLocked() {
+	if p.c != nil {
+		p.sup.stopping(p.c)
+		p.c.Kill()
+	}
+	p.standby = nil
 }
 
 func (p *pluginClient) Client() (goPlugin.ClientProtocol, error) {
+	p.mu.Lock()
+	if c := p.standby; c != nil && !p.c.Exited() {
+		p.standby = nil
+		p.mu.Unlock()
+		return c, nil
+	}
+	p.mu.Unlock()
+
+	p.sup.waitBackoff()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.startLocked()
+}
+
+// restart starts a standby process after the previous one crashed, it is called by the exit watcher.
+func (p *pluginClient) restart() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.sup.needRestart() {
+		return nil
+	}
+	c, err := p.startLocked()
+	if err != nil {
+		return err
+	}
+	p.standby = c
+	return nil
+}
+
+func (p *pluginClient) startLocked() (goPlugin.ClientProtocol, error) {
 	p.resetClient()
-	return p.c.Client()
+	c, err := p.c.Client()
+	p.sup.started(p.c, err)
+	return c, err
+}
+
+func (p *pluginClient) supervisor() *pluginSupervisor {
+	return p.sup
 }
 
 func (p *pluginClient) RegisterPlugin(c PluginClient) error {
@@ -96,14 +222,13 @@ func (p *pluginClient) RegisterPlugin(c PluginClient) error {
 }
 
 func (p *pluginClient) resetClient() {
-	if p.c != nil {
-		p.c.Kill()
-	}
+	p.killLocked()
 	p.c = goPlugin.NewClient(&goPlugin.ClientConfig{
 		HandshakeConfig:  handshakeConfig,
 		VersionedPlugins: defaultPluginSet,
 		Cmd:              exec.Command(p.path),
 		AllowedProtocols: []goPlugin.Protocol{goPlugin.ProtocolGRPC},
+		GRPCDialOptions:  p.sup.dialOptions(),
 	})
 }
 
@@ -123,6 +248,8 @@ func RegisterDriverFromClient(client PluginClient) error {
 
 func registerPlugin(pluginName string, c PluginClient) error {
 	if err := registerQueryPlugin(pluginName, c); err != nil {
+		// the plugin may be reloaded by a new version which does not support query any more.
+		unregisterSQLQueryDriver(pluginName)
 		log.Logger().WithFields(logrus.Fields{
 			"plugin_name": pluginName,
 			"plugin_type": PluginNameQueryDriver,
@@ -14,6 +14,8 @@ type AnalysisDriver interface {
 	GetTableMetaByTableName(ctx context.Context, conf *GetTableMetaByTableNameConf) (*GetTableMetaByTableNameResult, error)
 	GetTableMetaBySQL(ctx context.Context, conf *GetTableMetaBySQLConf) (*GetTableMetaBySQLResult, error)
 	Explain(ctx context.Context, conf *ExplainConf) (*ExplainResult, error)
+	// Close releases the resources of the driver, the driver can not be used after closed.
+	Close(ctx context.Context)
 }
 
 type ListTablesInSchemaConf struct {
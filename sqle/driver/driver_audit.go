@@ -15,6 +15,8 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -29,6 +31,10 @@ var (
 	// additionalParams store driver additional params
 	additionalParams   map[string]params.Params
 	additionalParamsMu sync.RWMutex
+
+	// capabilities store the features declared by each driver.
+	capabilities   map[string][]Capability
+	capabilitiesMu sync.RWMutex
 )
 
 const (
@@ -85,6 +91,18 @@ type Rule struct {
 	Category string
 	Level    RuleLevel
 	Params   params.Params
+
+	// Knowledge explains the rule to the user whose SQL violates the rule.
+	Knowledge RuleKnowledge
+}
+
+// RuleKnowledge is the knowledge base of a rule, it tells why the rule is necessary
+// and how to fix the SQL which violates the rule.
+type RuleKnowledge struct {
+	Rationale   string
+	BadExample  string
+	GoodExample string
+	Remediation string
 }
 
 //func (r *Rule) GetValueInt(defaultRule *Rule) int64 {
@@ -124,6 +142,42 @@ type Rule struct {
 type Config struct {
 	DSN   *DSN
 	Rules []*Rule
+
+	// Sandbox indicates the driver audits SQL against a mock database which is only
+	// built by the audited SQL, it takes effect when DSN is nil and the driver has
+	// CapabilitySandbox.
+	Sandbox bool
+
+	// SchemaBaseline is the existing schema which offline audit is against, it takes
+	// effect when DSN is nil and the driver has CapabilitySchemaBaseline.
+	SchemaBaseline *SchemaBaseline
+
+	// TaskId is the id of the task which the SQLs belong to. The objects created on execution
+	// are named by it, e.g. the backup table of TRUNCATE, so the ones of other tasks are kept.
+	TaskId string
+}
+
+type executeSQLNumberKey struct{}
+
+// WithExecuteSQLNumber returns a context which carries the number of the SQL in task, it is passed
+// to GenRollbackSQL and Exec, so the objects created on execution are named by the SQL, e.g. the
+// backup table of TRUNCATE, see Config.TaskId.
+func WithExecuteSQLNumber(ctx context.Context, number uint) context.Context {
+	return context.WithValue(ctx, executeSQLNumberKey{}, number)
+}
+
+// GetExecuteSQLNumber returns the number of the SQL in task carried by ctx, it is 0 if not carried.
+func GetExecuteSQLNumber(ctx context.Context) uint {
+	number, _ := ctx.Value(executeSQLNumberKey{}).(uint)
+	return number
+}
+
+// SchemaBaseline is a schema dump of database, e.g. the output of "mysqldump --no-data".
+type SchemaBaseline struct {
+	// DefaultSchema is the schema of the tables which schema is not specified in SQL.
+	DefaultSchema string
+	// SQL is the CREATE statements of schemas and tables.
+	SQL string
 }
 
 // NewConfig return a config for driver.
@@ -148,30 +202,49 @@ type handler func(log *logrus.Entry, c *Config) (Driver, error)
 // RegisterAuditDriver like sql.RegisterAuditDriver.
 //
 // RegisterAuditDriver makes a database driver available by the provided driver name.
-// Driver's initialize handler and audit rules register by RegisterAuditDriver.
-func RegisterAuditDriver(name string, h handler, rs []*Rule, ap params.Params) {
-	_, exist := drivers[name]
-	if exist {
-		panic("duplicated driver name")
+// Driver's initialize handler, audit rules and capabilities register by RegisterAuditDriver.
+func RegisterAuditDriver(name string, h handler, rs []*Rule, ap params.Params, caps ...Capability) {
+	if err := setAuditDriver(name, h, rs, ap, caps, false); err != nil {
+		panic(err.Error())
 	}
+}
+
+var errDuplicatedDriverName = errors.New("duplicated driver name")
 
+// setAuditDriver set driver's handler, rules, additional params and capabilities at once,
+// the registered driver will be replaced if replace is true.
+func setAuditDriver(name string, h handler, rs []*Rule, ap params.Params, caps []Capability, replace bool) error {
 	driversMu.Lock()
+	defer driversMu.Unlock()
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	additionalParamsMu.Lock()
+	defer additionalParamsMu.Unlock()
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+
+	_, exist := drivers[name]
+	if exist && !replace {
+		return errDuplicatedDriverName
+	}
+
 	drivers[name] = h
-	driversMu.Unlock()
 
-	rulesMu.Lock()
 	if rules == nil {
 		rules = make(map[string][]*Rule)
 	}
 	rules[name] = rs
-	rulesMu.Unlock()
 
-	additionalParamsMu.Lock()
 	if additionalParams == nil {
 		additionalParams = make(map[string]params.Params)
 	}
 	additionalParams[name] = ap
-	additionalParamsMu.Unlock()
+
+	if capabilities == nil {
+		capabilities = make(map[string][]Capability)
+	}
+	capabilities[name] = caps
+	return nil
 }
 
 type DriverNotSupportedError struct {
@@ -198,12 +271,18 @@ func NewDriver(log *logrus.Entry, dbType string, cfg *Config) (Driver, error) {
 func AllRules() map[string][]*Rule {
 	rulesMu.RLock()
 	defer rulesMu.RUnlock()
-	return rules
+
+	// rules may be replaced by plugin reloading, so return a copy.
+	newRules := make(map[string][]*Rule, len(rules))
+	for k, v := range rules {
+		newRules[k] = v
+	}
+	return newRules
 }
 
 func AllDrivers() []string {
-	rulesMu.RLock()
-	defer rulesMu.RUnlock()
+	driversMu.RLock()
+	defer driversMu.RUnlock()
 
 	driverNames := make([]string, 0, len(drivers))
 	for n := range drivers {
@@ -225,6 +304,22 @@ func AllAdditionalParams() map[string] /*driver name*/ params.Params {
 
 var ErrNodesCountExceedOne = errors.New("after parse, nodes count exceed one")
 
+// TxError may be returned by Driver.Tx when one of the queries fails, the queries before
+// it are rolled back and the queries after it are not executed.
+type TxError struct {
+	// Index is the position of the failed query.
+	Index int
+	Err   error
+}
+
+func (e *TxError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TxError) Unwrap() error {
+	return e.Err
+}
+
 // Driver is a interface that must be implemented by a database.
 //
 // It's implementation maybe on the same process or over gRPC(by go-plugin).
@@ -263,6 +358,96 @@ type Driver interface {
 	GenRollbackSQL(ctx context.Context, sql string) (string, string, error)
 }
 
+// BatchAuditor is an optional interface that may be implemented by a Driver.
+//
+// AuditBatch audit sqls with rules in one call, each sql is single SQL text.
+// The SQL context is kept across the batch just like calling Audit one by one,
+// and the results are returned in the same order as sqls.
+type BatchAuditor interface {
+	AuditBatch(ctx context.Context, sqls []string) ([]*AuditResult, error)
+}
+
+// AuditSQLs audit sqls in order. It uses BatchAuditor if the driver implements it,
+// otherwise it falls back to call Driver.Audit for each sql.
+func AuditSQLs(ctx context.Context, d Driver, sqls []string) ([]*AuditResult, error) {
+	if ba, ok := d.(BatchAuditor); ok {
+		return ba.AuditBatch(ctx, sqls)
+	}
+	return auditOneByOne(ctx, d, sqls)
+}
+
+// Flashback is the rollback SQLs generated from the changes actually made by the executed SQLs,
+// e.g. the row events in MySQL binlog.
+type Flashback struct {
+	StartBinlogFile string
+	StartBinlogPos  int64
+	EndBinlogFile   string
+	EndBinlogPos    int64
+
+	// RollbackSQLs are in the same order as the executed SQLs, the empty one means there is
+	// no rollback SQL generated for the SQL. It is nil if the generation is failed.
+	RollbackSQLs []string
+	// FailedReason is the reason why RollbackSQLs is not generated.
+	FailedReason string
+}
+
+// FlashbackExecutor is an optional interface that may be implemented by a Driver.
+//
+// TxWithFlashback executes queries in a transaction just like Driver.Tx, and generates the
+// rollback SQLs from the changes made by the transaction. The flashback is nil if it is not
+// enabled, the failure of generation doesn't fail the execution but is recorded in FailedReason.
+type FlashbackExecutor interface {
+	TxWithFlashback(ctx context.Context, queries ...string) ([]driver.Result, *Flashback, error)
+}
+
+// RowsCounter is an optional interface that may be implemented by a Driver.
+//
+// CountMatchedRows returns the rows which will be affected by the DML without executing it,
+// ok is false if the SQL is not a DML or the rows can not be counted.
+type RowsCounter interface {
+	CountMatchedRows(ctx context.Context, sql string) (rows int64, ok bool, err error)
+}
+
+// DryRunOptions is the options of DryRunner.DryRun.
+type DryRunOptions struct {
+	// DSN is the instance where the shadow is created, it is created on the instance of
+	// driver if DSN is nil.
+	DSN *DSN
+	// SampleRows is the max rows copied from each table to its shadow, only the structure
+	// of table is copied if it is 0.
+	SampleRows int64
+}
+
+// DryRunResult is the outcome of a query executed in dry run.
+type DryRunResult struct {
+	// Skipped is true if the query is not executed in dry run, Reason tells why.
+	Skipped bool
+	Reason  string
+	// Err is the error of executing the query against the shadow.
+	Err error
+}
+
+// DryRunner is an optional interface that may be implemented by a Driver.
+//
+// DryRun executes queries in order against a shadow, which is a throwaway copy of the tables
+// used by the queries, and drops the shadow afterwards, so the tables are not changed. The
+// results are in the same order as queries.
+type DryRunner interface {
+	DryRun(ctx context.Context, opts *DryRunOptions, queries ...string) ([]*DryRunResult, error)
+}
+
+func auditOneByOne(ctx context.Context, d Driver, sqls []string) ([]*AuditResult, error) {
+	results := make([]*AuditResult, 0, len(sqls))
+	for _, sql := range sqls {
+		result, err := d.Audit(ctx, sql)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 // Registerer is the interface that all SQLe plugins must support.
 type Registerer interface {
 	// Name returns plugin name.
@@ -275,6 +460,13 @@ type Registerer interface {
 	AdditionalParams() params.Params
 }
 
+// CapabilityRegisterer is an optional interface that may be implemented by a Registerer
+// to declare the features supported by the plugin. Query and analysis are detected
+// from the registered plugin types, so they are not required here.
+type CapabilityRegisterer interface {
+	Capabilities() []Capability
+}
+
 // Node is a interface which unify SQL ast tree. It produce by Driver.Parse.
 type Node struct {
 	// Text is the raw SQL text of Node.
@@ -299,17 +491,82 @@ type Node struct {
 // }
 
 type AuditResult struct {
-	results []*auditResult
+	results []*AuditResultItem
+
+	// fixedSQL is the SQL rewritten by the rules to fix the problems, it is empty if no
+	// problem can be fixed mechanically.
+	fixedSQL string
+
+	// estimatedAffectedRows is the estimated rows affected by the DML, it is nil if
+	// the rows is not estimated.
+	estimatedAffectedRows *int64
 }
 
-type auditResult struct {
-	level   RuleLevel
-	message string
+// SQLPosition is the position of a SQL fragment in the audited SQL text.
+// Line and column start from 1, and the end position is inclusive.
+type SQLPosition struct {
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+}
+
+// NewSQLPosition return the position of sqlText[offset:offset+length].
+func NewSQLPosition(sqlText string, offset, length int) *SQLPosition {
+	if offset < 0 || length <= 0 || offset+length > len(sqlText) {
+		return nil
+	}
+	startLine, startColumn := lineAndColumn(sqlText, offset)
+	endLine, endColumn := lineAndColumn(sqlText, offset+length-1)
+	return &SQLPosition{
+		StartLine:   startLine,
+		StartColumn: startColumn,
+		EndLine:     endLine,
+		EndColumn:   endColumn,
+	}
+}
+
+func lineAndColumn(text string, offset int) (line, column int) {
+	line, column = 1, 1
+	for i, r := range text {
+		if i >= offset {
+			break
+		}
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
+
+// AuditResultItem is a single result of Audit.
+type AuditResultItem struct {
+	Level   RuleLevel
+	Message string
+
+	// RuleName and Category are the rule which produce this result. They are empty
+	// if the result is not produced by a rule, such as the result of syntax check.
+	RuleName string
+	Category string
+
+	// Position is the position of the offending SQL fragment, it is nil if unknown.
+	Position *SQLPosition
+
+	// Suppressed is true if the result is suppressed by the hint in SQL comment,
+	// the suppressed result is not counted by AuditResult.Level.
+	Suppressed     bool
+	SuppressReason string
+
+	// Fixable is true if the problem is fixed by the fixed SQL of AuditResult.
+	Fixable bool
 }
 
 func NewInspectResults() *AuditResult {
 	return &AuditResult{
-		results: []*auditResult{},
+		results: []*AuditResultItem{},
 	}
 }
 
@@ -317,8 +574,11 @@ func NewInspectResults() *AuditResult {
 func (rs *AuditResult) Level() RuleLevel {
 	level := RuleLevelNull
 	for _, curr := range rs.results {
-		if ruleLevelMap[curr.level] > ruleLevelMap[level] {
-			level = curr.level
+		if curr.Suppressed {
+			continue
+		}
+		if ruleLevelMap[curr.Level] > ruleLevelMap[level] {
+			level = curr.Level
 		}
 	}
 	return level
@@ -330,32 +590,154 @@ func (rs *AuditResult) Message() string {
 		var message string
 		match, _ := regexp.MatchString(fmt.Sprintf(`^\[%s|%s|%s|%s|%s\]`,
 			RuleLevelError, RuleLevelWarn, RuleLevelNotice, RuleLevelNormal, "osc"),
-			result.message)
+			result.Message)
 		if match {
-			message = result.message
+			message = result.Message
 		} else {
-			message = fmt.Sprintf("[%s]%s", result.level, result.message)
+			message = fmt.Sprintf("[%s]%s", result.Level, result.Message)
+		}
+		if result.Suppressed {
+			message = fmt.Sprintf("%s(已通过注释忽略", message)
+			if result.SuppressReason != "" {
+				message = fmt.Sprintf("%s, 原因: %s", message, result.SuppressReason)
+			}
+			message += ")"
 		}
 		messages[n] = message
 	}
 	return strings.Join(messages, "\n")
 }
 
+// Items return all the results which sorted by level.
+func (rs *AuditResult) Items() []*AuditResultItem {
+	items := make([]*AuditResultItem, 0, len(rs.results))
+	for _, result := range rs.results {
+		item := *result
+		items = append(items, &item)
+	}
+	return items
+}
+
 func (rs *AuditResult) Add(level RuleLevel, message string, args ...interface{}) {
 	if level == "" || message == "" {
 		return
 	}
 
-	rs.results = append(rs.results, &auditResult{
-		level:   level,
-		message: fmt.Sprintf(message, args...),
+	rs.AddItem(&AuditResultItem{
+		Level:   level,
+		Message: fmt.Sprintf(message, args...),
+	})
+}
+
+// AddWithRule add result which is produced by rule.
+func (rs *AuditResult) AddWithRule(rule Rule, message string, args ...interface{}) {
+	if rule.Level == "" || message == "" {
+		return
+	}
+
+	rs.AddItem(&AuditResultItem{
+		Level:    rule.Level,
+		Message:  fmt.Sprintf(message, args...),
+		RuleName: rule.Name,
+		Category: rule.Category,
+	})
+}
+
+// AddWithRuleAt add result which is produced by rule, p is the position of the offending
+// SQL fragment.
+func (rs *AuditResult) AddWithRuleAt(rule Rule, p *SQLPosition, message string, args ...interface{}) {
+	if rule.Level == "" || message == "" {
+		return
+	}
+
+	rs.AddItem(&AuditResultItem{
+		Level:    rule.Level,
+		Message:  fmt.Sprintf(message, args...),
+		RuleName: rule.Name,
+		Category: rule.Category,
+		Position: p,
 	})
+}
+
+func (rs *AuditResult) AddItem(item *AuditResultItem) {
+	if item == nil || item.Level == "" || item.Message == "" {
+		return
+	}
+
+	rs.results = append(rs.results, item)
 	rs.SortByLevel()
 }
 
+// SetFixedSQL sets the SQL which fixes the problems reported by the rules.
+func (rs *AuditResult) SetFixedSQL(sql string, ruleNames ...string) {
+	rs.fixedSQL = sql
+	for _, result := range rs.results {
+		if containsString(ruleNames, result.RuleName) {
+			result.Fixable = true
+		}
+	}
+}
+
+// FixedSQL returns the SQL which fixes the problems, it is empty if no problem can be fixed.
+func (rs *AuditResult) FixedSQL() string {
+	return rs.fixedSQL
+}
+
+// SetEstimatedAffectedRows sets the estimated rows affected by the DML.
+func (rs *AuditResult) SetEstimatedAffectedRows(rows int64) {
+	rs.estimatedAffectedRows = &rows
+}
+
+// EstimatedAffectedRows returns the estimated rows affected by the DML, the second
+// return value is false if the rows is not estimated.
+func (rs *AuditResult) EstimatedAffectedRows() (int64, bool) {
+	if rs.estimatedAffectedRows == nil {
+		return 0, false
+	}
+	return *rs.estimatedAffectedRows, true
+}
+
+// SetPosition set position for the results which position is unknown.
+func (rs *AuditResult) SetPosition(p *SQLPosition) {
+	if p == nil {
+		return
+	}
+	for _, result := range rs.results {
+		if result.Position == nil {
+			position := *p
+			result.Position = &position
+		}
+	}
+}
+
+// ShiftPosition moves the known positions of results behind prefix, it is used when the
+// positions are computed in the SQL text which follows prefix.
+func (rs *AuditResult) ShiftPosition(prefix string) {
+	if prefix == "" {
+		return
+	}
+	line, column := lineAndColumn(prefix+" ", len(prefix))
+	for _, result := range rs.results {
+		p := result.Position
+		if p == nil {
+			continue
+		}
+		if p.StartLine == 1 {
+			p.StartColumn += column - 1
+		}
+		if p.EndLine == 1 {
+			p.EndColumn += column - 1
+		}
+		p.StartLine += line - 1
+		p.EndLine += line - 1
+	}
+}
+
+// SortByLevel sorts the results by level, the results of the same level keep the order
+// they are added, so the order is the same after transferred from plugin.
 func (rs *AuditResult) SortByLevel() {
-	sort.Slice(rs.results, func(i, j int) bool {
-		return rs.results[i].level.More(rs.results[j].level)
+	sort.SliceStable(rs.results, func(i, j int) bool {
+		return rs.results[i].Level.More(rs.results[j].Level)
 	})
 }
 
@@ -369,11 +751,21 @@ type driverPluginClient struct {
 
 	// driverQuitCh produce a singal for telling caller that it's time to Client.Kill() plugin process.
 	driverQuitCh chan struct{}
+
+	// onClose is called after the driver is closed.
+	onClose func()
+
+	// auditBatchUnsupported is true if the plugin is built with an old SQLE version
+	// which does not implement AuditBatch RPC.
+	auditBatchUnsupported bool
 }
 
 func (s *driverPluginClient) Close(ctx context.Context) {
 	s.plugin.Close(ctx, &proto.Empty{})
 	close(s.driverQuitCh)
+	if s.onClose != nil {
+		s.onClose()
+	}
 }
 
 func (s *driverPluginClient) Ping(ctx context.Context) error {
@@ -463,13 +855,67 @@ func (s *driverPluginClient) Audit(ctx context.Context, sql string) (*AuditResul
 	if err != nil {
 		return nil, err
 	}
+	return convertAuditResultFromProtoToDriver(resp), nil
+}
 
-	ret := &AuditResult{}
-	for _, result := range resp.Results {
-		ret.results = append(ret.results, &auditResult{
-			level:   RuleLevel(result.Level),
-			message: result.Message,
-		})
+// The gRPC message is limited to 4MB by default, so the batch is sent in chunks. The
+// SQL context is kept by the plugin driver across the chunks.
+const (
+	auditBatchMaxSize = 1 << 20
+	// auditBatchMaxSQLs limits the size of response, which grows with the count of SQLs.
+	auditBatchMaxSQLs = 1000
+)
+
+// splitAuditBatch splits sqls into chunks in order, the SQLs in each chunk are at most
+// auditBatchMaxSQLs and auditBatchMaxSize bytes, unless a single SQL exceeds it.
+func splitAuditBatch(sqls []string) [][]string {
+	chunks := [][]string{}
+	start, size := 0, 0
+	for i, sql := range sqls {
+		if i > start && (i-start >= auditBatchMaxSQLs || size+len(sql) > auditBatchMaxSize) {
+			chunks = append(chunks, sqls[start:i])
+			start, size = i, 0
+		}
+		size += len(sql)
+	}
+	if start < len(sqls) {
+		chunks = append(chunks, sqls[start:])
+	}
+	return chunks
+}
+
+func (s *driverPluginClient) AuditBatch(ctx context.Context, sqls []string) ([]*AuditResult, error) {
+	ret := make([]*AuditResult, 0, len(sqls))
+	for _, chunk := range splitAuditBatch(sqls) {
+		results, err := s.auditBatch(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, results...)
+	}
+	return ret, nil
+}
+
+func (s *driverPluginClient) auditBatch(ctx context.Context, sqls []string) ([]*AuditResult, error) {
+	if s.auditBatchUnsupported {
+		return auditOneByOne(ctx, s, sqls)
+	}
+
+	resp, err := s.plugin.AuditBatch(ctx, &proto.AuditBatchRequest{Sqls: sqls})
+	if status.Code(err) == codes.Unimplemented {
+		s.auditBatchUnsupported = true
+		return auditOneByOne(ctx, s, sqls)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results) != len(sqls) {
+		return nil, fmt.Errorf("audit batch: expect %d results, but got %d", len(sqls), len(resp.Results))
+	}
+
+	ret := make([]*AuditResult, 0, len(resp.Results))
+	for _, auditResp := range resp.Results {
+		ret = append(ret, convertAuditResultFromProtoToDriver(auditResp))
 	}
 	return ret, nil
 }
@@ -499,6 +945,12 @@ func convertRuleFromProtoToDriver(rule *proto.Rule) *Rule {
 		Desc:     rule.Desc,
 		Level:    RuleLevel(rule.Level),
 		Params:   ps,
+		Knowledge: RuleKnowledge{
+			Rationale:   rule.GetKnowledge().GetRationale(),
+			BadExample:  rule.GetKnowledge().GetBadExample(),
+			GoodExample: rule.GetKnowledge().GetGoodExample(),
+			Remediation: rule.GetKnowledge().GetRemediation(),
+		},
 	}
 }
 
@@ -518,9 +970,76 @@ func convertRuleFromDriverToProto(rule *Rule) *proto.Rule {
 		Level:    string(rule.Level),
 		Category: rule.Category,
 		Params:   params,
+		Knowledge: &proto.RuleKnowledge{
+			Rationale:   rule.Knowledge.Rationale,
+			BadExample:  rule.Knowledge.BadExample,
+			GoodExample: rule.Knowledge.GoodExample,
+			Remediation: rule.Knowledge.Remediation,
+		},
 	}
 }
 
+func convertAuditResultFromProtoToDriver(resp *proto.AuditResponse) *AuditResult {
+	ret := NewInspectResults()
+	for _, result := range resp.GetResults() {
+		item := &AuditResultItem{
+			Level:    RuleLevel(result.GetLevel()),
+			Message:  result.GetMessage(),
+			RuleName: result.GetRuleName(),
+			Category: result.GetCategory(),
+
+			Suppressed:     result.GetSuppressed(),
+			SuppressReason: result.GetSuppressReason(),
+			Fixable:        result.GetFixable(),
+		}
+		if p := result.GetPosition(); p != nil {
+			item.Position = &SQLPosition{
+				StartLine:   int(p.GetStartLine()),
+				StartColumn: int(p.GetStartColumn()),
+				EndLine:     int(p.GetEndLine()),
+				EndColumn:   int(p.GetEndColumn()),
+			}
+		}
+		ret.results = append(ret.results, item)
+	}
+	ret.SortByLevel()
+	ret.fixedSQL = resp.GetFixedSQL()
+	if resp.GetAffectedRowsEstimated() {
+		ret.SetEstimatedAffectedRows(resp.GetEstimatedAffectedRows())
+	}
+	return ret
+}
+
+func convertAuditResultFromDriverToProto(result *AuditResult) *proto.AuditResponse {
+	resp := &proto.AuditResponse{FixedSQL: result.fixedSQL}
+	if rows, ok := result.EstimatedAffectedRows(); ok {
+		resp.AffectedRowsEstimated = true
+		resp.EstimatedAffectedRows = rows
+	}
+	for _, item := range result.results {
+		protoResult := &proto.AuditResult{
+			Level:    string(item.Level),
+			Message:  item.Message,
+			RuleName: item.RuleName,
+			Category: item.Category,
+
+			Suppressed:     item.Suppressed,
+			SuppressReason: item.SuppressReason,
+			Fixable:        item.Fixable,
+		}
+		if item.Position != nil {
+			protoResult.Position = &proto.SQLPosition{
+				StartLine:   int32(item.Position.StartLine),
+				StartColumn: int32(item.Position.StartColumn),
+				EndLine:     int32(item.Position.EndLine),
+				EndColumn:   int32(item.Position.EndColumn),
+			}
+		}
+		resp.Results = append(resp.Results, protoResult)
+	}
+	return resp
+}
+
 func getServerHandle(client PluginClient, closeCh <-chan struct{}) (proto.DriverClient, error) {
 	gRPCClient, err := client.Client()
 	if err != nil {
@@ -559,9 +1078,24 @@ func registerAuditDriver(client PluginClient) (pluginName string, err error) {
 		driverRules = append(driverRules, convertRuleFromProtoToDriver(rule))
 	}
 
-	handler := func(log *logrus.Entry, config *Config) (Driver, error) {
+	rp := &registeredPlugin{
+		name:   pluginMeta.Name,
+		path:   client.Path(),
+		client: client,
+	}
+	handler := func(log *logrus.Entry, config *Config) (d Driver, err error) {
+		rp, err := acquirePlugin(pluginMeta.Name)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				rp.release()
+			}
+		}()
+
 		pluginCloseCh := make(chan struct{})
-		srv, err := getServerHandle(client, pluginCloseCh)
+		srv, err := getServerHandle(rp.client, pluginCloseCh)
 		if err != nil {
 			return nil, err
 		}
@@ -593,11 +1127,19 @@ func registerAuditDriver(client PluginClient) (pluginName string, err error) {
 			return nil, err
 		}
 
-		return &driverPluginClient{srv, pluginCloseCh}, nil
+		return &driverPluginClient{plugin: srv, driverQuitCh: pluginCloseCh, onClose: rp.release}, nil
 
 	}
 
-	RegisterAuditDriver(pluginMeta.Name, handler, driverRules, proto.ConvertProtoParamToParam(pluginMeta.GetAdditionalParams()))
+	var driverCaps []Capability
+	for _, c := range pluginMeta.GetCapabilities() {
+		driverCaps = append(driverCaps, Capability(c))
+	}
+
+	err = registerPluginAuditDriver(rp, handler, driverRules, proto.ConvertProtoParamToParam(pluginMeta.GetAdditionalParams()), driverCaps)
+	if err != nil {
+		return "", err
+	}
 
 	log.Logger().WithFields(logrus.Fields{
 		"plugin_name": pluginMeta.Name,
@@ -0,0 +1,29 @@
+package driver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_splitAuditBatch(t *testing.T) {
+	assert.Empty(t, splitAuditBatch(nil))
+	assert.Equal(t, [][]string{{"select 1", "select 2"}}, splitAuditBatch([]string{"select 1", "select 2"}))
+
+	// the chunks are limited by the count of SQLs.
+	sqls := make([]string, auditBatchMaxSQLs+1)
+	for i := range sqls {
+		sqls[i] = "select 1"
+	}
+	chunks := splitAuditBatch(sqls)
+	assert.Len(t, chunks, 2)
+	assert.Len(t, chunks[0], auditBatchMaxSQLs)
+	assert.Len(t, chunks[1], 1)
+
+	// the chunks are limited by the size of SQLs, the large SQL is in a chunk alone.
+	half := "select '" + strings.Repeat("a", auditBatchMaxSize/2) + "'"
+	large := "select '" + strings.Repeat("a", auditBatchMaxSize) + "'"
+	chunks = splitAuditBatch([]string{"select 1", half, half, large, "select 2"})
+	assert.Equal(t, [][]string{{"select 1", half}, {half}, {large}, {"select 2"}}, chunks)
+}
@@ -16,6 +16,8 @@ import (
 type SQLQueryDriver interface {
 	QueryPrepare(ctx context.Context, sql string, conf *QueryPrepareConf) (*QueryPrepareResult, error)
 	Query(ctx context.Context, sql string, conf *QueryConf) (*QueryResult, error)
+	// Close releases the resources of the driver, the driver can not be used after closed.
+	Close(ctx context.Context)
 }
 
 type ErrorType string
@@ -68,14 +70,23 @@ type queryDriverPluginClient struct {
 
 	// driverQuitCh produce a singal for telling caller that it's time to Client.Kill() plugin process.
 	driverQuitCh chan struct{}
+
+	// onClose is called after the driver is closed.
+	onClose func()
+
+	closeOnce sync.Once
 }
 
-func (q *queryDriverPluginClient) Close() {
-	close(q.driverQuitCh)
+func (q *queryDriverPluginClient) Close(ctx context.Context) {
+	q.closeOnce.Do(func() {
+		close(q.driverQuitCh)
+		if q.onClose != nil {
+			q.onClose()
+		}
+	})
 }
 
 func (q *queryDriverPluginClient) QueryPrepare(ctx context.Context, sql string, conf *QueryPrepareConf) (*QueryPrepareResult, error) {
-	defer q.Close()
 	req := &proto.QueryPrepareRequest{
 		Sql: sql,
 		Conf: &proto.QueryPrepareConf{
@@ -95,7 +106,6 @@ func (q *queryDriverPluginClient) QueryPrepare(ctx context.Context, sql string,
 }
 
 func (q *queryDriverPluginClient) Query(ctx context.Context, sql string, conf *QueryConf) (*QueryResult, error) {
-	defer q.Close()
 	req := &proto.QueryRequest{
 		Sql: sql,
 		Conf: &proto.QueryConf{
@@ -165,15 +175,26 @@ func GetQueryDriverNames() []string {
 // RegisterSQLQueryDriver makes a database driver available by the provided driver name.
 // SQLQueryDriver's initialize handler and audit rules register by RegisterSQLQueryDriver.
 func RegisterSQLQueryDriver(name string, h queryHandler) {
-	queryDriverMu.RLock()
-	_, exist := queryDrivers[name]
-	queryDriverMu.RUnlock()
-	if exist {
-		panic("duplicated driver name")
+	if err := setSQLQueryDriver(name, h, false); err != nil {
+		panic(err.Error())
 	}
+}
 
+// setSQLQueryDriver set SQLQueryDriver's handler, the registered driver will be replaced if replace is true.
+func setSQLQueryDriver(name string, h queryHandler, replace bool) error {
 	queryDriverMu.Lock()
+	defer queryDriverMu.Unlock()
+	_, exist := queryDrivers[name]
+	if exist && !replace {
+		return errDuplicatedDriverName
+	}
 	queryDrivers[name] = h
+	return nil
+}
+
+func unregisterSQLQueryDriver(name string) {
+	queryDriverMu.Lock()
+	delete(queryDrivers, name)
 	queryDriverMu.Unlock()
 }
 
@@ -189,9 +210,22 @@ func registerQueryPlugin(pluginName string, c PluginClient) error {
 	if err != nil {
 		return err
 	}
-	handler := func(log *logrus.Entry, config *DSN) (SQLQueryDriver, error) {
+	if _, ok := getRegisteredPlugin(pluginName); !ok {
+		return fmt.Errorf("plugin %s is not registered as audit driver", pluginName)
+	}
+	handler := func(log *logrus.Entry, config *DSN) (d SQLQueryDriver, err error) {
+		rp, err := acquirePlugin(pluginName)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				rp.release()
+			}
+		}()
+
 		pluginCloseCh := make(chan struct{})
-		srv, err := getQueryServerHandle(c, pluginCloseCh)
+		srv, err := getQueryServerHandle(rp.client, pluginCloseCh)
 		if err != nil {
 			return nil, err
 		}
@@ -217,11 +251,14 @@ func registerQueryPlugin(pluginName string, c PluginClient) error {
 			return nil, err
 		}
 
-		return &queryDriverPluginClient{srv, pluginCloseCh}, nil
+		return &queryDriverPluginClient{plugin: srv, driverQuitCh: pluginCloseCh, onClose: rp.release}, nil
 
 	}
 
-	RegisterSQLQueryDriver(pluginName, handler)
+	// the query driver is owned by the registered plugin, so it can be replaced on plugin reloading.
+	if err := setSQLQueryDriver(pluginName, handler, true); err != nil {
+		return err
+	}
 	log.Logger().WithFields(logrus.Fields{
 		"plugin_name": pluginName,
 		"plugin_type": PluginNameQueryDriver,
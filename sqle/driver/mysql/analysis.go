@@ -0,0 +1,222 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/sirupsen/logrus"
+)
+
+// AnalysisDriver implements driver.AnalysisDriver interface, it provides table
+// metadata and SQL explain of MySQL.
+type AnalysisDriver struct {
+	log *logrus.Entry
+	dsn *driver.DSN
+}
+
+func NewAnalysisDriver(log *logrus.Entry, dsn *driver.DSN) (driver.AnalysisDriver, error) {
+	if dsn == nil {
+		return nil, fmt.Errorf("dsn is required for analysis")
+	}
+	return &AnalysisDriver{
+		log: log,
+		dsn: dsn,
+	}, nil
+}
+
+// withExecutor opens a connection for fn, the connection is closed after fn returns.
+func (a *AnalysisDriver) withExecutor(fn func(conn *executor.Executor) error) error {
+	conn, err := executor.NewExecutor(a.log, a.dsn, a.dsn.DatabaseName)
+	if err != nil {
+		return err
+	}
+	defer conn.Db.Close()
+	return fn(conn)
+}
+
+// Close does nothing, the connection is opened and closed in every call by withExecutor.
+func (a *AnalysisDriver) Close(ctx context.Context) {}
+
+func (a *AnalysisDriver) ListTablesInSchema(ctx context.Context, conf *driver.ListTablesInSchemaConf) (*driver.ListTablesInSchemaResult, error) {
+	result := &driver.ListTablesInSchemaResult{}
+	err := a.withExecutor(func(conn *executor.Executor) error {
+		tables, err := conn.ShowSchemaTables(conf.Schema)
+		if err != nil {
+			return err
+		}
+		result.Tables = make([]driver.Table, 0, len(tables))
+		for _, table := range tables {
+			result.Tables = append(result.Tables, driver.Table{Name: table})
+		}
+		return nil
+	})
+	return result, err
+}
+
+var (
+	tableColumnsInfoHead = []driver.AnalysisInfoHead{
+		{Name: "COLUMN_NAME", Desc: "列名"},
+		{Name: "COLUMN_TYPE", Desc: "列类型"},
+		{Name: "CHARACTER_SET_NAME", Desc: "列字符集"},
+		{Name: "IS_NULLABLE", Desc: "是否可以为空"},
+		{Name: "COLUMN_KEY", Desc: "列索引"},
+		{Name: "COLUMN_DEFAULT", Desc: "默认值"},
+		{Name: "EXTRA", Desc: "拓展信息"},
+		{Name: "COLUMN_COMMENT", Desc: "列说明"},
+	}
+
+	tableIndexesInfoHead = []driver.AnalysisInfoHead{
+		{Name: "Column_name", Desc: "列名"},
+		{Name: "Key_name", Desc: "索引名"},
+		{Name: "Non_unique", Desc: "唯一性"},
+		{Name: "Seq_in_index", Desc: "列序列"},
+		{Name: "Cardinality", Desc: "基数"},
+		{Name: "Null", Desc: "是否为空"},
+		{Name: "Index_type", Desc: "索引类型"},
+		{Name: "Comment", Desc: "备注"},
+	}
+)
+
+// getTableMeta returns the metadata of table, exist is false if the table is not exist.
+func getTableMeta(conn *executor.Executor, schema, table string) (meta *driver.TableMetaItem, exist bool, err error) {
+	columns, err := conn.GetTableColumnsInfo(schema, table)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(columns) == 0 {
+		return nil, false, nil
+	}
+	indexes, err := conn.GetTableIndexesInfo(util.QuoteName(schema), util.QuoteName(table))
+	if err != nil {
+		return nil, false, err
+	}
+	createTableSQL, err := conn.ShowCreateTable(util.QuoteName(schema), util.QuoteName(table))
+	if err != nil {
+		return nil, false, err
+	}
+
+	meta = &driver.TableMetaItem{
+		Name:           table,
+		Schema:         schema,
+		CreateTableSQL: createTableSQL,
+	}
+	meta.ColumnsInfo.Column = tableColumnsInfoHead
+	meta.ColumnsInfo.Rows = make([][]string, 0, len(columns))
+	for _, c := range columns {
+		meta.ColumnsInfo.Rows = append(meta.ColumnsInfo.Rows, []string{
+			c.ColumnName, c.ColumnType, c.CharacterSetName, c.IsNullable,
+			c.ColumnKey, c.ColumnDefault, c.Extra, c.ColumnComment,
+		})
+	}
+	meta.IndexesInfo.Column = tableIndexesInfoHead
+	meta.IndexesInfo.Rows = make([][]string, 0, len(indexes))
+	for _, i := range indexes {
+		meta.IndexesInfo.Rows = append(meta.IndexesInfo.Rows, []string{
+			i.ColumnName, i.KeyName, i.NonUnique, i.SeqInIndex,
+			i.Cardinality, i.Null, i.IndexType, i.Comment,
+		})
+	}
+	return meta, true, nil
+}
+
+func (a *AnalysisDriver) GetTableMetaByTableName(ctx context.Context, conf *driver.GetTableMetaByTableNameConf) (*driver.GetTableMetaByTableNameResult, error) {
+	result := &driver.GetTableMetaByTableNameResult{}
+	err := a.withExecutor(func(conn *executor.Executor) error {
+		meta, exist, err := getTableMeta(conn, conf.Schema, conf.Table)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return fmt.Errorf("table %s.%s is not exist", conf.Schema, conf.Table)
+		}
+		result.TableMeta = *meta
+		return nil
+	})
+	return result, err
+}
+
+// GetTableMetaBySQL returns the metadata of the tables used in SQL, the table which is
+// not exist, e.g. the table created by the SQL or CTE, is ignored.
+func (a *AnalysisDriver) GetTableMetaBySQL(ctx context.Context, conf *driver.GetTableMetaBySQLConf) (*driver.GetTableMetaBySQLResult, error) {
+	node, err := util.ParseOneSql(conf.Sql)
+	if err != nil {
+		return nil, err
+	}
+	extractor := &tableNamesExtractor{}
+	node.Accept(extractor)
+
+	result := &driver.GetTableMetaBySQLResult{}
+	err = a.withExecutor(func(conn *executor.Executor) error {
+		exist := map[string]struct{}{}
+		for _, t := range extractor.tables {
+			schema := t.Schema.O
+			if schema == "" {
+				schema = a.dsn.DatabaseName
+			}
+			key := fmt.Sprintf("%s.%s", schema, t.Name.O)
+			if _, ok := exist[key]; ok || schema == "" {
+				continue
+			}
+			exist[key] = struct{}{}
+
+			meta, ok, err := getTableMeta(conn, schema, t.Name.O)
+			if err != nil {
+				return err
+			}
+			if ok {
+				result.TableMetas = append(result.TableMetas, *meta)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// tableNamesExtractor implements ast.Visitor interface, it extracts table names in order.
+type tableNamesExtractor struct {
+	tables []*ast.TableName
+}
+
+func (te *tableNamesExtractor) Enter(in ast.Node) (node ast.Node, skipChildren bool) {
+	if stmt, ok := in.(*ast.TableName); ok {
+		te.tables = append(te.tables, stmt)
+	}
+	return in, false
+}
+
+func (te *tableNamesExtractor) Leave(in ast.Node) (node ast.Node, ok bool) {
+	return in, true
+}
+
+func (a *AnalysisDriver) Explain(ctx context.Context, conf *driver.ExplainConf) (*driver.ExplainResult, error) {
+	result := &driver.ExplainResult{}
+	err := a.withExecutor(func(conn *executor.Executor) error {
+		columns, rows, err := conn.Explain(conf.Sql)
+		if err != nil {
+			return err
+		}
+		classic := &result.ClassicResult
+		classic.Column = make([]driver.AnalysisInfoHead, 0, len(columns))
+		for _, column := range columns {
+			classic.Column = append(classic.Column, driver.AnalysisInfoHead{
+				Name: column,
+				Desc: column,
+			})
+		}
+		classic.Rows = make([][]string, 0, len(rows))
+		for _, row := range rows {
+			values := make([]string, 0, len(row))
+			for _, value := range row {
+				values = append(values, value.String)
+			}
+			classic.Rows = append(classic.Rows, values)
+		}
+		return nil
+	})
+	return result, err
+}
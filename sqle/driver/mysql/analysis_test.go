@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTableMeta(t *testing.T) {
+	e, mocker, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+
+	mocker.ExpectQuery(regexp.QuoteMeta("FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA=? AND TABLE_NAME=?")).
+		WithArgs("db1", "t1").WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "CHARACTER_SET_NAME", "IS_NULLABLE",
+		"COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT"}).
+		AddRow("id", "int(11)", "", "NO", "PRI", "", "auto_increment", "id"))
+	mocker.ExpectQuery(regexp.QuoteMeta("SHOW INDEX FROM `db1`.`t1`")).
+		WillReturnRows(sqlmock.NewRows([]string{"Column_name", "Key_name", "Non_unique", "Seq_in_index",
+			"Cardinality", "Null", "Index_type", "Comment"}).
+			AddRow("id", "PRIMARY", "0", "1", "0", "", "BTREE", ""))
+	mocker.ExpectQuery(regexp.QuoteMeta("show create table `db1`.`t1`")).
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+			AddRow("t1", "CREATE TABLE `t1` (`id` int(11) NOT NULL AUTO_INCREMENT, PRIMARY KEY (`id`))"))
+	mocker.ExpectQuery(regexp.QuoteMeta("FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA=? AND TABLE_NAME=?")).
+		WithArgs("db1", "t2").WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME"}))
+
+	meta, exist, err := getTableMeta(e, "db1", "t1")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "t1", meta.Name)
+	assert.Equal(t, "db1", meta.Schema)
+	assert.Equal(t, [][]string{{"id", "int(11)", "", "NO", "PRI", "", "auto_increment", "id"}}, meta.ColumnsInfo.Rows)
+	assert.Equal(t, [][]string{{"id", "PRIMARY", "0", "1", "0", "", "BTREE", ""}}, meta.IndexesInfo.Rows)
+	assert.Len(t, meta.ColumnsInfo.Column, 8)
+	assert.Len(t, meta.IndexesInfo.Column, 8)
+
+	_, exist, err = getTableMeta(e, "db1", "t2")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	mocker.MatchExpectationsInOrder(true)
+	assert.NoError(t, mocker.ExpectationsWereMet())
+}
+
+func TestGetTableMetaWithQuoteInName(t *testing.T) {
+	e, mocker, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+
+	schema, table := "db1' or '1'='1", "t1`; drop table t2; -- "
+	mocker.ExpectQuery(regexp.QuoteMeta("FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA=? AND TABLE_NAME=?")).
+		WithArgs(schema, table).WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE"}).AddRow("id", "int(11)"))
+	mocker.ExpectQuery(regexp.QuoteMeta("SHOW INDEX FROM `db1' or '1'='1`.`t1``; drop table t2; -- `")).
+		WillReturnRows(sqlmock.NewRows([]string{"Column_name"}))
+	mocker.ExpectQuery(regexp.QuoteMeta("show create table `db1' or '1'='1`.`t1``; drop table t2; -- `")).
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).AddRow("t1", "CREATE TABLE `t1` (`id` int(11))"))
+	mocker.ExpectQuery(regexp.QuoteMeta(`select TABLE_NAME from information_schema.tables where table_schema=?`)).
+		WithArgs(schema).WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}))
+
+	_, exist, err := getTableMeta(e, schema, table)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	_, err = e.ShowSchemaTables(schema)
+	assert.NoError(t, err)
+
+	mocker.MatchExpectationsInOrder(true)
+	assert.NoError(t, mocker.ExpectationsWereMet())
+}
+
+func TestTableNamesExtractor(t *testing.T) {
+	node, err := util.ParseOneSql("select * from db1.t1 join t2 on t1.id = t2.id where t1.id in (select id from t3)")
+	assert.NoError(t, err)
+	extractor := &tableNamesExtractor{}
+	node.Accept(extractor)
+
+	var names []string
+	for _, table := range extractor.tables {
+		names = append(names, table.Schema.O+"."+table.Name.O)
+	}
+	assert.Equal(t, []string{"db1.t1", ".t2", ".t3"}, names)
+}
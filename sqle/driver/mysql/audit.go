@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/actiontech/sqle/sqle/driver"
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
 	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	"github.com/actiontech/sqle/sqle/utils"
 
@@ -30,6 +31,9 @@ const (
 	ColumnsValuesNotMatchMessage       = "指定的值列数与字段列数不匹配"
 	DuplicatePrimaryKeyedColumnMessage = "主键字段 %s 重复"
 	DuplicateIndexedColumnMessage      = "索引 %s 字段 %s重复"
+	FeatureNotSupportedMessage         = "当前MySQL版本 %s 不支持%s, 需要 %s 及以上版本"
+	CheckConstraintIgnoredMessage      = "当前MySQL版本 %s 会忽略CHECK约束, 需要 %s 及以上版本才会生效"
+	CTEUnparsedMessage                 = "WITH(CTE)语句暂不支持完整审核，请人工确认SQL正确性"
 )
 
 const CheckInvalidErrorFormat = "预检查失败: %v"
@@ -67,10 +71,108 @@ func (i *Inspect) CheckInvalid(node ast.Node) error {
 	if err != nil {
 		return fmt.Errorf(CheckInvalidErrorFormat, err)
 	}
+	i.checkInvalidServerVersion(node)
 	return nil
 
 }
 
+// checkInvalidServerVersion checks the MySQL 8.0 features used by the statement are supported
+// by the version of MySQL server, it is skipped if the version is unknown.
+func (i *Inspect) checkInvalidServerVersion(node ast.Node) {
+	version, ok := rulepkg.LookupServerVersion(i.Ctx)
+	if !ok {
+		return
+	}
+	for _, feature := range getMySQL8Features(node) {
+		if feature.IsSupportedBy(version) {
+			continue
+		}
+		if feature == rulepkg.FeatureCheckConstraint {
+			// CHECK constraint is parsed but ignored by the older versions.
+			i.result.Add(driver.RuleLevelWarn, CheckConstraintIgnoredMessage, version, feature.Since)
+			continue
+		}
+		i.result.Add(driver.RuleLevelError, FeatureNotSupportedMessage, version, feature.Name, feature.Since)
+	}
+}
+
+// getMySQL8Features returns the MySQL 8.0 features used by the statement.
+func getMySQL8Features(node ast.Node) []rulepkg.Feature {
+	features := []rulepkg.Feature{}
+	add := func(f rulepkg.Feature) {
+		for _, feature := range features {
+			if feature == f {
+				return
+			}
+		}
+		features = append(features, f)
+	}
+	checkConstraint := func(constraint *ast.Constraint) {
+		if constraint == nil {
+			return
+		}
+		if constraint.Tp == ast.ConstraintCheck {
+			add(rulepkg.FeatureCheckConstraint)
+		}
+		if util.IsInvisibleIndex(constraint.Option) {
+			add(rulepkg.FeatureInvisibleIndex)
+		}
+		for _, key := range constraint.Keys {
+			if util.IsFunctionalIndexPart(key) {
+				add(rulepkg.FeatureFunctionalIndex)
+			}
+		}
+	}
+	checkColumns := func(cols []*ast.ColumnDef) {
+		for _, col := range cols {
+			if util.HasOneInOptions(col.Options, ast.ColumnOptionCheck) {
+				add(rulepkg.FeatureCheckConstraint)
+			}
+		}
+	}
+
+	switch stmt := node.(type) {
+	case *ast.UnparsedStmt:
+		if isCTE, _ := util.IsCTEStmt(stmt.Text()); isCTE {
+			add(rulepkg.FeatureCTE)
+		}
+	case *ast.CreateTableStmt:
+		checkColumns(stmt.Cols)
+		for _, constraint := range stmt.Constraints {
+			checkConstraint(constraint)
+		}
+	case *ast.AlterTableStmt:
+		for _, spec := range stmt.Specs {
+			switch spec.Tp {
+			case ast.AlterTableRenameColumn:
+				add(rulepkg.FeatureRenameColumn)
+			case ast.AlterTableIndexInvisible:
+				add(rulepkg.FeatureInvisibleIndex)
+			case ast.AlterTableAddConstraint:
+				checkConstraint(spec.Constraint)
+			case ast.AlterTableAddColumns, ast.AlterTableModifyColumn, ast.AlterTableChangeColumn:
+				checkColumns(spec.NewColumns)
+			}
+		}
+	case *ast.CreateIndexStmt:
+		if util.IsInvisibleIndex(stmt.IndexOption) {
+			add(rulepkg.FeatureInvisibleIndex)
+		}
+		for _, key := range stmt.IndexPartSpecifications {
+			if util.IsFunctionalIndexPart(key) {
+				add(rulepkg.FeatureFunctionalIndex)
+			}
+		}
+	case ast.DMLNode:
+		extractor := &util.WindowFuncExtractor{}
+		stmt.Accept(extractor)
+		if len(extractor.WindowFuncs) > 0 {
+			add(rulepkg.FeatureWindowFunc)
+		}
+	}
+	return features
+}
+
 func (i *Inspect) CheckExplain(node ast.Node) error {
 	var err error
 	switch node.(type) {
@@ -155,9 +257,8 @@ func (i *Inspect) checkInvalidCreateTable(stmt *ast.CreateTableStmt) error {
 			pkCounter += 1
 			names := []string{}
 			for _, col := range constraint.Keys {
-				colName := col.Column.Name.L
-				names = append(names, colName)
-				keyColsName = append(keyColsName, colName)
+				names = append(names, util.GetIndexPartName(col).L)
+				keyColsName = append(keyColsName, util.GetIndexPartColumns(col)...)
 			}
 			duplicateName := utils.GetDuplicate(names)
 			if len(duplicateName) > 0 {
@@ -173,9 +274,8 @@ func (i *Inspect) checkInvalidCreateTable(stmt *ast.CreateTableStmt) error {
 			}
 			names := []string{}
 			for _, col := range constraint.Keys {
-				colName := col.Column.Name.L
-				names = append(names, colName)
-				keyColsName = append(keyColsName, colName)
+				names = append(names, util.GetIndexPartName(col).L)
+				keyColsName = append(keyColsName, util.GetIndexPartColumns(col)...)
 			}
 			duplicateName := utils.GetDuplicate(names)
 			if len(duplicateName) > 0 {
@@ -301,6 +401,25 @@ func (i *Inspect) checkInvalidAlterTable(stmt *ast.AlterTableStmt) error {
 		}
 	}
 
+	// check rename column
+	for _, spec := range util.GetAlterTableSpecByTp(stmt.Specs, ast.AlterTableRenameColumn) {
+		oldColName := spec.OldColumnName.Name.L
+		newColName := spec.NewColumnName.Name.L
+		if _, ok := colNameMap[oldColName]; !ok {
+			needExistsColsName = append(needExistsColsName, oldColName)
+			continue
+		}
+		if newColName == oldColName {
+			continue
+		}
+		if _, ok := colNameMap[newColName]; ok {
+			needNotExistsColsName = append(needNotExistsColsName, newColName)
+		} else {
+			delete(colNameMap, oldColName)
+			colNameMap[newColName] = struct{}{}
+		}
+	}
+
 	// check add column
 	for _, spec := range util.GetAlterTableSpecByTp(stmt.Specs, ast.AlterTableAddColumns) {
 		for _, col := range spec.NewColumns {
@@ -372,10 +491,11 @@ func (i *Inspect) checkInvalidAlterTable(stmt *ast.AlterTableStmt) error {
 			}
 			names := []string{}
 			for _, col := range spec.Constraint.Keys {
-				colName := col.Column.Name.L
-				names = append(names, colName)
-				if _, ok := colNameMap[colName]; !ok {
-					needExistsKeyColsName = append(needExistsKeyColsName, colName)
+				names = append(names, util.GetIndexPartName(col).L)
+				for _, colName := range util.GetIndexPartColumns(col) {
+					if _, ok := colNameMap[colName]; !ok {
+						needExistsKeyColsName = append(needExistsKeyColsName, colName)
+					}
 				}
 			}
 			duplicateColumn := utils.GetDuplicate(names)
@@ -396,10 +516,11 @@ func (i *Inspect) checkInvalidAlterTable(stmt *ast.AlterTableStmt) error {
 			}
 			names := []string{}
 			for _, col := range spec.Constraint.Keys {
-				colName := col.Column.Name.L
-				names = append(names, colName)
-				if _, ok := colNameMap[colName]; !ok {
-					needExistsKeyColsName = append(needExistsKeyColsName, colName)
+				names = append(names, util.GetIndexPartName(col).L)
+				for _, colName := range util.GetIndexPartColumns(col) {
+					if _, ok := colNameMap[colName]; !ok {
+						needExistsKeyColsName = append(needExistsKeyColsName, colName)
+					}
 				}
 			}
 			duplicateColumn := utils.GetDuplicate(names)
@@ -583,10 +704,11 @@ func (i *Inspect) checkInvalidCreateIndex(stmt *ast.CreateIndexStmt) error {
 	keyColsName := []string{}
 	keyColNeedExist := []string{}
 	for _, col := range stmt.IndexPartSpecifications {
-		colName := col.Column.Name.L
-		keyColsName = append(keyColsName, colName)
-		if _, ok := colNameMap[col.Column.Name.L]; !ok {
-			keyColNeedExist = append(keyColNeedExist, colName)
+		keyColsName = append(keyColsName, util.GetIndexPartName(col).L)
+		for _, colName := range util.GetIndexPartColumns(col) {
+			if _, ok := colNameMap[colName]; !ok {
+				keyColNeedExist = append(keyColNeedExist, colName)
+			}
 		}
 	}
 	duplicateName := utils.GetDuplicate(keyColsName)
@@ -987,6 +1109,10 @@ func (i *Inspect) checkInvalidSelect(stmt *ast.SelectStmt) error {
 
 // checkUnparsedStmt might add more check in future.
 func (i *Inspect) checkUnparsedStmt(stmt *ast.UnparsedStmt) error {
+	if isCTE, _ := util.IsCTEStmt(stmt.Text()); isCTE {
+		i.result.Add(driver.RuleLevelNotice, CTEUnparsedMessage)
+		return nil
+	}
 	i.result.Add(driver.RuleLevelWarn, "语法错误或者解析器不支持，请人工确认SQL正确性")
 	return nil
 }
@@ -115,6 +115,7 @@ func runDefaultRulesInspectCase(t *testing.T, desc string, i *Inspect, sql strin
 		rulepkg.DMLCheckInsertColumnsExist:                  struct{}{},
 		rulepkg.DMLCheckLimitMustExist:                      struct{}{},
 		rulepkg.DMLCheckWhereExistImplicitConversion:        struct{}{},
+		rulepkg.DDLCheckAlterTableOnlineDDL:                 struct{}{},
 	}
 	for i := range rulepkg.RuleHandlers {
 		handler := rulepkg.RuleHandlers[i]
@@ -1149,6 +1150,40 @@ ALTER TABLE exist_db.exist_tb_1 Add column v6 varchar(255) NOT NULL DEFAULT "uni
 	)
 }
 
+func TestCheckAlterTableOnlineDDL(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.DDLCheckAlterTableOnlineDDL].Rule
+	message := rulepkg.RuleHandlerMap[rulepkg.DDLCheckAlterTableOnlineDDL].Message
+
+	runSingleRuleInspectCase(rule, t, "alter_table: add column on MySQL 5.7", DefaultMysqlInspect(),
+		"ALTER TABLE exist_db.exist_tb_1 ADD COLUMN v3 int;",
+		newTestResult().add(driver.RuleLevelNotice, message, "INPLACE", "NONE", "执行期间将重建该表"),
+	)
+
+	inspect := DefaultMysqlInspect()
+	inspect.Ctx.AddSystemVariable("version", "8.0.30")
+	runSingleRuleInspectCase(rule, t, "alter_table: add column on MySQL 8.0", inspect,
+		"ALTER TABLE exist_db.exist_tb_1 ADD COLUMN v3 int;",
+		newTestResult(),
+	)
+
+	runSingleRuleInspectCase(rule, t, "alter_table: modify column type", DefaultMysqlInspect(),
+		"ALTER TABLE exist_db.exist_tb_1 MODIFY COLUMN v2 int;",
+		newTestResult().add(driver.RuleLevelWarn, message, "COPY", "SHARED", "执行期间将阻塞对该表的写入"),
+	)
+
+	runSingleRuleInspectCase(rule, t, "alter_table: add index with algorithm instant", DefaultMysqlInspect(),
+		"ALTER TABLE exist_db.exist_tb_1 ADD INDEX idx_v2(v2), ALGORITHM=INSTANT;",
+		newTestResult().add(driver.RuleLevelNotice, message+", 指定的ALGORITHM=INSTANT不支持该操作, 执行将会失败",
+			"INPLACE", "NONE", "执行期间不会阻塞对该表的写入"),
+	)
+
+	runSingleRuleInspectCase(rule, t, "alter_table: drop primary key with algorithm inplace", DefaultMysqlInspect(),
+		"ALTER TABLE exist_db.exist_tb_1 DROP PRIMARY KEY, ALGORITHM=INPLACE;",
+		newTestResult().add(driver.RuleLevelWarn, message+", 指定的ALGORITHM=INPLACE不支持该操作, 执行将会失败",
+			"COPY", "SHARED", "执行期间将阻塞对该表的写入"),
+	)
+}
+
 func TestCheckObjectNameLength(t *testing.T) {
 	length64 := "aaaaaaaaaabbbbbbbbbbccccccccccddddddddddeeeeeeeeeeffffffffffabcd"
 	length65 := "aaaaaaaaaabbbbbbbbbbccccccccccddddddddddeeeeeeeeeeffffffffffabcde"
@@ -1708,7 +1743,8 @@ v2 varchar(255) NOT NULL DEFAULT "unit test" COMMENT "unit test"
 		`
 	ALTER TABLE exist_db.exist_tb_1 MODIFY column v2 varchar(255) CHARACTER SET utf8 NOT NULL DEFAULT "unit test" COMMENT "unit test";
 	`,
-		newTestResult().addResult(rulepkg.DDLCheckTableCharacterSet, "utf8mb4"),
+		newTestResult().addResult(rulepkg.DDLCheckColumnCharsetChange, "v2(utf8mb4 -> utf8)").
+			addResult(rulepkg.DDLCheckTableCharacterSet, "utf8mb4"),
 	)
 }
 
@@ -2023,6 +2059,25 @@ UPDATE exist_db.exist_tb_1 Set v1="2" where id=1 limit 1;
 	)
 }
 
+func TestCheckDMLWithLimit_Suppressed(t *testing.T) {
+	i := DefaultMysqlInspect()
+	rule := rulepkg.RuleHandlerMap[rulepkg.DMLCheckWithLimit].Rule
+	i.rules = []*driver.Rule{&rule}
+
+	result, err := i.Audit(context.TODO(),
+		`UPDATE /* sqle:ignore dml_check_with_limit reason="batch job" */ exist_db.exist_tb_1 Set v1="2" where id=1 limit 1`)
+	assert.NoError(t, err)
+	assert.Equal(t, driver.RuleLevelNull, result.Level())
+	assert.True(t, result.HasSuppressed())
+	assert.Equal(t, "[error]delete/update 语句不能有limit条件(已通过注释忽略, 原因: batch job)", result.Message())
+
+	result, err = i.Audit(context.TODO(),
+		`UPDATE /* sqle:ignore dml_check_where_is_invalid */ exist_db.exist_tb_1 Set v1="2" where id=1 limit 1`)
+	assert.NoError(t, err)
+	assert.Equal(t, driver.RuleLevelError, result.Level())
+	assert.False(t, result.HasSuppressed())
+}
+
 func TestCheckDMLWithLimit_FP(t *testing.T) {
 	runDefaultRulesInspectCase(t, "[fp]update: with limit", DefaultMysqlInspect(),
 		`
@@ -3968,3 +4023,259 @@ func TestInspect_CheckColumn(t *testing.T) {
 	`,
 		newTestResult())
 }
+
+func TestCheckMySQL8Features(t *testing.T) {
+	runDefaultRulesInspectCase(t, "window function", DefaultMysqlInspect(),
+		"select id, row_number() over (partition by v1 order by id) as rn from exist_db.exist_tb_1 where id > 1 limit 10;",
+		newTestResult())
+
+	runDefaultRulesInspectCase(t, "functional index", DefaultMysqlInspect(),
+		"alter table exist_db.exist_tb_1 add index idx_v2((lower(v2)));",
+		newTestResult())
+
+	runDefaultRulesInspectCase(t, "check constraint and invisible index", DefaultMysqlInspect(),
+		`
+CREATE TABLE if not exists exist_db.not_exist_tb_1 (
+id bigint unsigned NOT NULL AUTO_INCREMENT COMMENT "unit test",
+v1 varchar(255) NOT NULL DEFAULT "unit test" COMMENT "unit test",
+PRIMARY KEY (id),
+KEY idx_v1 (v1) INVISIBLE,
+CONSTRAINT ck_v1 CHECK (v1 <> '')
+)ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8mb4 COMMENT="unit test";
+`,
+		newTestResult().addResult(rulepkg.DDLCheckInvisibleIndex, "idx_v1"))
+
+	runDefaultRulesInspectCase(t, "rename column", DefaultMysqlInspect(),
+		`
+alter table exist_db.exist_tb_1 rename column v2 to v3;
+alter table exist_db.exist_tb_1 rename column v2 to v4;
+alter table exist_db.exist_tb_1 rename column v3 to v1;
+`,
+		newTestResult(),
+		newTestResult().add(driver.RuleLevelError, ColumnNotExistMessage, "v2").
+			addResult(rulepkg.DDLCheckAlterTableNeedMerge),
+		newTestResult().add(driver.RuleLevelError, ColumnExistMessage, "v1").
+			addResult(rulepkg.DDLCheckAlterTableNeedMerge))
+
+	runDefaultRulesInspectCase(t, "cte", DefaultMysqlInspect(),
+		"with cte as (select id from exist_db.exist_tb_1) select * from cte;",
+		newTestResult().add(driver.RuleLevelNotice, CTEUnparsedMessage))
+}
+
+func TestCheckMySQL8FeaturesWithServerVersion(t *testing.T) {
+	newInspect := func(version string) *Inspect {
+		i := DefaultMysqlInspect()
+		i.Ctx.AddSystemVariable("version", version)
+		return i
+	}
+
+	runEmptyRuleInspectCase(t, "window function on MySQL 5.7", newInspect("5.7.30-log"),
+		"select id, row_number() over (order by id) as rn from exist_db.exist_tb_1;",
+		newTestResult().add(driver.RuleLevelError, FeatureNotSupportedMessage, "5.7.30", "窗口函数", "8.0.0"))
+
+	runEmptyRuleInspectCase(t, "window function on MySQL 8.0", newInspect("8.0.30"),
+		"select id, row_number() over (order by id) as rn from exist_db.exist_tb_1;",
+		newTestResult())
+
+	runEmptyRuleInspectCase(t, "cte on MySQL 5.7", newInspect("5.7.30"),
+		"with cte as (select id from exist_db.exist_tb_1) select * from cte;",
+		newTestResult().add(driver.RuleLevelNotice, CTEUnparsedMessage).
+			add(driver.RuleLevelError, FeatureNotSupportedMessage, "5.7.30", "WITH(CTE)语句", "8.0.0"))
+
+	runEmptyRuleInspectCase(t, "rename column on MySQL 5.7", newInspect("5.7.30"),
+		"alter table exist_db.exist_tb_1 rename column v2 to v3;",
+		newTestResult().add(driver.RuleLevelError, FeatureNotSupportedMessage, "5.7.30", "RENAME COLUMN", "8.0.0"))
+
+	runEmptyRuleInspectCase(t, "functional index on MySQL 8.0.12", newInspect("8.0.12"),
+		"create index idx_v2 on exist_db.exist_tb_1((lower(v2)));",
+		newTestResult().add(driver.RuleLevelError, FeatureNotSupportedMessage, "8.0.12", "函数索引", "8.0.13"))
+
+	runEmptyRuleInspectCase(t, "invisible index on MySQL 5.7", newInspect("5.7.30"),
+		"alter table exist_db.exist_tb_1 alter index idx_1 invisible;",
+		newTestResult().add(driver.RuleLevelError, FeatureNotSupportedMessage, "5.7.30", "不可见索引", "8.0.0"))
+
+	runEmptyRuleInspectCase(t, "check constraint on MySQL 8.0.15", newInspect("8.0.15"),
+		"alter table exist_db.exist_tb_1 add constraint ck_v1 check (v1 <> '');",
+		newTestResult().add(driver.RuleLevelWarn, CheckConstraintIgnoredMessage, "8.0.15", "8.0.16"))
+
+	runEmptyRuleInspectCase(t, "check constraint on MySQL 8.0.16", newInspect("8.0.16"),
+		"alter table exist_db.exist_tb_1 add constraint ck_v1 check (v1 <> '');",
+		newTestResult())
+}
+
+func TestCheckRedundantIndexWithCheckConstraint(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.DDLCheckRedundantIndex].Rule
+	runSingleRuleInspectCase(rule, t, "create_table: check constraint is not an index", DefaultMysqlInspect(),
+		`
+CREATE TABLE if not exists exist_db.not_exist_tb_1 (
+id bigint unsigned NOT NULL AUTO_INCREMENT COMMENT "unit test",
+v1 varchar(255) NOT NULL DEFAULT "unit test" COMMENT "unit test",
+PRIMARY KEY (id),
+KEY idx_v1 (v1),
+KEY idx_v1_expr ((lower(v1))),
+CONSTRAINT ck_v1 CHECK (v1 <> '')
+)ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8mb4 COMMENT="unit test";
+`,
+		newTestResult())
+
+	runSingleRuleInspectCase(rule, t, "create_index: repeat functional index", DefaultMysqlInspect(),
+		`
+create index idx_v2_1 on exist_db.exist_tb_1((lower(v2)));
+create index idx_v2_2 on exist_db.exist_tb_1((lower(v2)));
+`,
+		newTestResult(),
+		newTestResult().addResult(rulepkg.DDLCheckRedundantIndex, "存在重复索引:idx_v2_2((lower(`v2`))); "))
+}
+
+func TestCheckWhereExistFuncWithFunctionalIndex(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.DMLCheckWhereExistFunc].Rule
+	runSingleRuleInspectCase(rule, t, "select: function is indexed by functional index", DefaultMysqlInspect(),
+		`
+create index idx_v2 on exist_db.exist_tb_1((lower(v2)));
+select v1 from exist_db.exist_tb_1 where lower(v2) = "v2";
+select v1 from exist_db.exist_tb_1 where upper(v2) = "V2";
+`,
+		newTestResult(),
+		newTestResult(),
+		newTestResult().addResult(rulepkg.DMLCheckWhereExistFunc),
+	)
+}
+
+func TestCheckRecursiveCTEWithoutLimit(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.DMLCheckRecursiveCTEWithoutLimit].Rule
+	runSingleRuleInspectCase(rule, t, "recursive cte without limit", DefaultMysqlInspect(),
+		"with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 10) select * from cte;",
+		newTestResult().add(driver.RuleLevelNotice, CTEUnparsedMessage).
+			addResult(rulepkg.DMLCheckRecursiveCTEWithoutLimit),
+	)
+
+	runSingleRuleInspectCase(rule, t, "recursive cte with limit", DefaultMysqlInspect(),
+		"with recursive cte(n) as (select 1 union all select n + 1 from cte limit 10) select * from cte;",
+		newTestResult().add(driver.RuleLevelNotice, CTEUnparsedMessage),
+	)
+
+	runSingleRuleInspectCase(rule, t, "recursive cte with limit only in outer query", DefaultMysqlInspect(),
+		"with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 10) select * from cte limit 10;",
+		newTestResult().add(driver.RuleLevelNotice, CTEUnparsedMessage).
+			addResult(rulepkg.DMLCheckRecursiveCTEWithoutLimit),
+	)
+
+	runSingleRuleInspectCase(rule, t, "recursive cte with limit in string literal", DefaultMysqlInspect(),
+		"with recursive cte(n, s) as (select 1, 'limit' union all select n + 1, s from cte where n < 10) select * from cte;",
+		newTestResult().add(driver.RuleLevelNotice, CTEUnparsedMessage).
+			addResult(rulepkg.DMLCheckRecursiveCTEWithoutLimit),
+	)
+
+	runSingleRuleInspectCase(rule, t, "recursive cte with limit in the recursive one", DefaultMysqlInspect(),
+		"with recursive c1 as (select id from exist_db.exist_tb_1), c2(n) as (select 1 union all (select n + 1 from c2 limit 10)) select * from c1, c2;",
+		newTestResult().add(driver.RuleLevelNotice, CTEUnparsedMessage),
+	)
+
+	runSingleRuleInspectCase(rule, t, "recursive cte with cte_max_recursion_depth", DefaultMysqlInspect(),
+		"with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 10) select /*+ SET_VAR(cte_max_recursion_depth = 100) */ * from cte;",
+		newTestResult().add(driver.RuleLevelNotice, CTEUnparsedMessage),
+	)
+
+	runSingleRuleInspectCase(rule, t, "cte is not recursive", DefaultMysqlInspect(),
+		"with cte as (select id from exist_db.exist_tb_1) select * from cte;",
+		newTestResult().add(driver.RuleLevelNotice, CTEUnparsedMessage),
+	)
+}
+
+func TestCheckInvisibleIndex(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.DDLCheckInvisibleIndex].Rule
+	runSingleRuleInspectCase(rule, t, "alter index invisible", DefaultMysqlInspect(),
+		`
+alter table exist_db.exist_tb_1 alter index idx_1 invisible;
+alter table exist_db.exist_tb_1 alter index idx_1 visible;
+create index idx_v2 on exist_db.exist_tb_1(v2) invisible;
+`,
+		newTestResult().addResult(rulepkg.DDLCheckInvisibleIndex, "idx_1"),
+		newTestResult(),
+		newTestResult().addResult(rulepkg.DDLCheckInvisibleIndex, "idx_v2"),
+	)
+}
+
+func TestCheckAffectedRows(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.DMLCheckAffectedRows].Rule
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+
+	inspect1 := NewMockInspect(e)
+	handler.ExpectQuery(regexp.QuoteMeta("EXPLAIN delete from exist_tb_1 where v1 = 'a'")).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "rows", "filtered"}).AddRow("ALL", "20000", "10.00"))
+	runSingleRuleInspectCase(rule, t, "delete: affected rows is filtered", inspect1,
+		"delete from exist_tb_1 where v1 = 'a'", newTestResult())
+	rows, ok := inspect1.result.EstimatedAffectedRows()
+	assert.True(t, ok)
+	assert.Equal(t, int64(2000), rows)
+
+	inspect2 := NewMockInspect(e)
+	handler.ExpectQuery(regexp.QuoteMeta("EXPLAIN update exist_tb_1 set v1 = 'a'")).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "rows"}).AddRow("ALL", "100000"))
+	runSingleRuleInspectCase(rule, t, "update: affected rows over threshold", inspect2,
+		"update exist_tb_1 set v1 = 'a'", newTestResult().addResult(rulepkg.DMLCheckAffectedRows, 100000, 10000))
+
+	inspect3 := NewMockInspect(e)
+	handler.ExpectQuery(regexp.QuoteMeta("EXPLAIN update exist_tb_1 set v1 = 'a' limit 100")).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "rows"}).AddRow("ALL", "100000"))
+	runSingleRuleInspectCase(rule, t, "update: affected rows is limited", inspect3,
+		"update exist_tb_1 set v1 = 'a' limit 100", newTestResult())
+	rows, ok = inspect3.result.EstimatedAffectedRows()
+	assert.True(t, ok)
+	assert.Equal(t, int64(100), rows)
+
+	countRule := rule
+	countRule.Params = rule.Params.Copy()
+	assert.NoError(t, countRule.Params.SetParamValue("count_timeout", "10"))
+	inspect4 := NewMockInspect(e)
+	handler.ExpectQuery(regexp.QuoteMeta("EXPLAIN delete from exist_tb_1 where v1 = 'a'")).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "rows"}).AddRow("ALL", "100000"))
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT /*+ MAX_EXECUTION_TIME(10000) */ COUNT(*) FROM `exist_tb_1` WHERE `v1` = \"a\"")).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow("5"))
+	runSingleRuleInspectCase(countRule, t, "delete: affected rows is counted", inspect4,
+		"delete from exist_tb_1 where v1 = 'a'", newTestResult())
+	rows, ok = inspect4.result.EstimatedAffectedRows()
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), rows)
+
+	inspect5 := NewMockInspect(e)
+	runSingleRuleInspectCase(rule, t, "select: affected rows is not estimated", inspect5,
+		"select * from exist_tb_1", newTestResult())
+	_, ok = inspect5.result.EstimatedAffectedRows()
+	assert.False(t, ok)
+
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestCheckColumnTypeChange(t *testing.T) {
+	runSingleRuleInspectCase(rulepkg.RuleHandlerMap[rulepkg.DDLCheckColumnTypeNarrowing].Rule, t,
+		"alter_table: varchar is narrowed", DefaultMysqlInspect(),
+		"alter table exist_db.exist_tb_1 modify column v2 varchar(50) comment 'unit test'",
+		newTestResult().addResult(rulepkg.DDLCheckColumnTypeNarrowing, "v2(VARCHAR(255) -> VARCHAR(50))"))
+
+	runSingleRuleInspectCase(rulepkg.RuleHandlerMap[rulepkg.DDLCheckColumnTypeNarrowing].Rule, t,
+		"alter_table: varchar is extended", DefaultMysqlInspect(),
+		"alter table exist_db.exist_tb_1 modify column v2 varchar(512) comment 'unit test'",
+		newTestResult())
+
+	runSingleRuleInspectCase(rulepkg.RuleHandlerMap[rulepkg.DDLCheckColumnSignChange].Rule, t,
+		"alter_table: unsigned is changed to signed", DefaultMysqlInspect(),
+		"alter table exist_db.exist_tb_1 modify column id bigint(10) not null auto_increment comment 'unit test'",
+		newTestResult().addResult(rulepkg.DDLCheckColumnSignChange, "id(BIGINT(10) UNSIGNED -> BIGINT(10))"))
+
+	runSingleRuleInspectCase(rulepkg.RuleHandlerMap[rulepkg.DDLCheckColumnCharsetChange].Rule, t,
+		"alter_table: charset is changed", DefaultMysqlInspect(),
+		"alter table exist_db.exist_tb_1 change column v2 v3 varchar(255) character set utf8 comment 'unit test'",
+		newTestResult().addResult(rulepkg.DDLCheckColumnCharsetChange, "v2(utf8mb4 -> utf8)"))
+
+	runSingleRuleInspectCase(rulepkg.RuleHandlerMap[rulepkg.DDLCheckColumnSetNotNullWithoutDefault].Rule, t,
+		"alter_table: set not null without default", DefaultMysqlInspect(),
+		"alter table exist_db.exist_tb_1 modify column v2 varchar(255) not null comment 'unit test'",
+		newTestResult().addResult(rulepkg.DDLCheckColumnSetNotNullWithoutDefault, "v2"))
+
+	runSingleRuleInspectCase(rulepkg.RuleHandlerMap[rulepkg.DDLCheckColumnSetNotNullWithoutDefault].Rule, t,
+		"alter_table: set not null with default", DefaultMysqlInspect(),
+		"alter table exist_db.exist_tb_1 modify column v2 varchar(255) not null default '' comment 'unit test'",
+		newTestResult())
+}
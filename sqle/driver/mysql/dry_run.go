@@ -0,0 +1,405 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pkg/errors"
+)
+
+// newShadowId returns the id which keeps the shadow schemas of dry runs apart.
+var newShadowId = func() string {
+	return fmt.Sprintf("%08x", rand.Uint32())
+}
+
+// DryRun implements driver.DryRunner. Each table is cloned to the shadow schema when it is used
+// at the first time, with the tables referenced by its foreign keys, then the query is rewritten
+// to use the shadow tables. Only the DDL on table and the DML are executed, the others, e.g.
+// CREATE DATABASE and CREATE PROCEDURE, are skipped.
+func (i *Inspect) DryRun(ctx context.Context, opts *driver.DryRunOptions, queries ...string) ([]*driver.DryRunResult, error) {
+	if i.IsOfflineAudit() {
+		return nil, errors.New("dry run is not supported in offline audit")
+	}
+	if opts == nil {
+		opts = &driver.DryRunOptions{}
+	}
+	source, err := i.getDbConn()
+	if err != nil {
+		return nil, err
+	}
+	target := source
+	if opts.DSN != nil {
+		target, err = executor.NewExecutor(i.log, opts.DSN, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "connect to the instance of shadow")
+		}
+		defer target.Db.Close()
+	}
+
+	s := &shadow{
+		i:             i,
+		source:        source,
+		target:        target,
+		sampleRows:    opts.SampleRows,
+		id:            newShadowId(),
+		currentSchema: i.Ctx.CurrentSchema(),
+		schemas:       map[string]string{},
+		tables:        map[string]struct{}{},
+	}
+	defer s.drop()
+
+	results := make([]*driver.DryRunResult, 0, len(queries))
+	for _, query := range queries {
+		results = append(results, s.exec(query))
+	}
+	return results, nil
+}
+
+var errShadowView = errors.New("view is not supported in dry run")
+
+// shadow keeps the shadow schemas of a dry run.
+type shadow struct {
+	i          *Inspect
+	source     *executor.Executor
+	target     *executor.Executor
+	sampleRows int64
+
+	id            string
+	currentSchema string
+	// schemas maps the schema to its shadow schema.
+	schemas map[string]string
+	// tables are the tables which have been prepared in shadow, the key is "schema.table".
+	tables map[string]struct{}
+}
+
+func (s *shadow) exec(query string) *driver.DryRunResult {
+	nodes, err := s.i.ParseSql(query)
+	if err != nil {
+		return &driver.DryRunResult{Err: err}
+	}
+	for _, node := range nodes {
+		if stmt, ok := node.(*ast.UseStmt); ok {
+			s.currentSchema = stmt.DBName
+			continue
+		}
+		if reason := dryRunSkipReason(node); reason != "" {
+			return &driver.DryRunResult{Skipped: true, Reason: reason}
+		}
+
+		err := s.prepareTables(collectTableNames(node), s.currentSchema)
+		if err == errShadowView {
+			return &driver.DryRunResult{Skipped: true, Reason: err.Error()}
+		}
+		if err != nil {
+			return &driver.DryRunResult{Err: errors.Wrap(err, "prepare shadow tables")}
+		}
+		rewritten, err := s.rewrite(node, s.currentSchema)
+		if err != nil {
+			return &driver.DryRunResult{Skipped: true, Reason: fmt.Sprintf("rewrite SQL failed, %v", err)}
+		}
+		if _, err := s.target.Db.Exec(rewritten); err != nil {
+			return &driver.DryRunResult{Err: errors.New(s.unshadow(err.Error()))}
+		}
+	}
+	return &driver.DryRunResult{}
+}
+
+// dryRunSkipReason returns the reason if the statement is not executed in dry run.
+func dryRunSkipReason(node ast.Node) string {
+	switch stmt := node.(type) {
+	case *ast.CreateTableStmt, *ast.DropTableStmt, *ast.AlterTableStmt, *ast.RenameTableStmt,
+		*ast.TruncateTableStmt, *ast.CreateIndexStmt, *ast.DropIndexStmt, *ast.CreateViewStmt,
+		*ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		return ""
+	case *ast.SelectStmt:
+		if stmt.SelectIntoOpt != nil {
+			return "SELECT ... INTO is not executed in dry run"
+		}
+		return ""
+	default:
+		return "the statement is not supported in dry run"
+	}
+}
+
+// prepareSchema creates the shadow schema of schema if it is not created.
+func (s *shadow) prepareSchema(schema string) error {
+	if _, ok := s.schemas[schema]; ok {
+		return nil
+	}
+	shadowSchema := fmt.Sprintf("_sqle_shadow_%s_%d", s.id, len(s.schemas)+1)
+	if _, err := s.target.Db.Exec(fmt.Sprintf("CREATE DATABASE `%s`", shadowSchema)); err != nil {
+		return err
+	}
+	s.schemas[schema] = shadowSchema
+	return nil
+}
+
+type shadowTable struct {
+	schema string
+	table  string
+	stmt   *ast.CreateTableStmt
+}
+
+// prepareTables clones the tables which have not been prepared to the shadow, the tables
+// referenced by their foreign keys are cloned too. The table which is not exist is not cloned,
+// it may be created by the query.
+func (s *shadow) prepareTables(tables []*ast.TableName, defaultSchema string) error {
+	clones := []*shadowTable{}
+	var collect func(tables []*ast.TableName, defaultSchema string) error
+	collect = func(tables []*ast.TableName, defaultSchema string) error {
+		for _, table := range tables {
+			schema := table.Schema.O
+			if schema == "" {
+				schema = defaultSchema
+			}
+			if schema == "" {
+				return errors.New("no database selected")
+			}
+			if s.isShadowSchema(schema) {
+				continue
+			}
+			if err := s.prepareSchema(schema); err != nil {
+				return err
+			}
+			key := fmt.Sprintf("%s.%s", schema, table.Name.O)
+			if _, ok := s.tables[key]; ok {
+				continue
+			}
+			s.tables[key] = struct{}{}
+
+			stmt, exist, err := s.showCreateTable(schema, table.Name.O)
+			if err != nil {
+				return err
+			}
+			if !exist {
+				continue
+			}
+			clones = append(clones, &shadowTable{schema: schema, table: table.Name.O, stmt: stmt})
+			if err := collect(collectTableNames(stmt), schema); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := collect(tables, defaultSchema); err != nil {
+		return err
+	}
+	if len(clones) == 0 {
+		return nil
+	}
+
+	// the tables are cloned regardless of the order of foreign keys, and the sample rows
+	// may not match the foreign keys.
+	if _, err := s.target.Db.Exec("SET FOREIGN_KEY_CHECKS = 0"); err != nil {
+		return err
+	}
+	defer func() {
+		if _, err := s.target.Db.Exec("SET FOREIGN_KEY_CHECKS = 1"); err != nil {
+			s.i.Logger().Errorf("enable foreign key checks after clone tables failed, %v", err)
+		}
+	}()
+	for _, clone := range clones {
+		columns := shadowColumns(clone.stmt)
+		query, err := s.rewrite(clone.stmt, clone.schema)
+		if err != nil {
+			return err
+		}
+		if _, err := s.target.Db.Exec(query); err != nil {
+			return err
+		}
+		if err := s.copyRows(clone.schema, clone.table, columns); err != nil {
+			return errors.Wrapf(err, "copy rows of table %s.%s", clone.schema, clone.table)
+		}
+	}
+	return nil
+}
+
+func (s *shadow) showCreateTable(schema, table string) (*ast.CreateTableStmt, bool, error) {
+	exist, err := s.i.Ctx.IsTableExist(&ast.TableName{Schema: model.NewCIStr(schema), Name: model.NewCIStr(table)})
+	if err != nil || !exist {
+		return nil, false, err
+	}
+	result, err := s.source.Db.Query(fmt.Sprintf("SHOW CREATE TABLE `%s`.`%s`", schema, table))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(result) != 1 {
+		return nil, false, fmt.Errorf("show create table error, result is %v", result)
+	}
+	query, ok := result[0]["Create Table"]
+	if !ok {
+		return nil, false, errShadowView
+	}
+	stmt, err := util.ParseCreateTableStmt(query.String)
+	if err != nil {
+		return nil, false, err
+	}
+	return stmt, true, nil
+}
+
+// shadowColumns returns the columns which can be copied to the shadow table, the generated
+// columns are excluded.
+func shadowColumns(stmt *ast.CreateTableStmt) []string {
+	columns := make([]string, 0, len(stmt.Cols))
+	for _, col := range stmt.Cols {
+		generated := false
+		for _, option := range col.Options {
+			if option.Tp == ast.ColumnOptionGenerated {
+				generated = true
+			}
+		}
+		if !generated {
+			columns = append(columns, fmt.Sprintf("`%s`", col.Name.Name.O))
+		}
+	}
+	return columns
+}
+
+// copyRows copies the sample rows from the table to its shadow, the rows are copied by
+// INSERT ... SELECT on the same instance, otherwise they are read and inserted as literals.
+func (s *shadow) copyRows(schema, table string, columns []string) error {
+	if s.sampleRows <= 0 || len(columns) == 0 {
+		return nil
+	}
+	cols := strings.Join(columns, ", ")
+	sourceTable := fmt.Sprintf("`%s`.`%s`", schema, table)
+	shadowTable := fmt.Sprintf("`%s`.`%s`", s.schemas[schema], table)
+
+	if s.target == s.source {
+		_, err := s.target.Db.Exec(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s LIMIT %d",
+			shadowTable, cols, cols, sourceTable, s.sampleRows))
+		return err
+	}
+
+	_, rows, err := s.source.Db.QueryWithContext(context.TODO(),
+		fmt.Sprintf("SELECT %s FROM %s LIMIT %d", cols, sourceTable, s.sampleRows))
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	// the values are bound as bytes rather than inlined as string literals, so the values of
+	// BIT and binary columns are copied as they are.
+	placeholders := fmt.Sprintf("(%s)", strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+	rowsPerInsert := maxPlaceholders / len(columns)
+	for start := 0; start < len(rows); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(rows) {
+			end = len(rows)
+		}
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*len(columns))
+		for _, row := range rows[start:end] {
+			values = append(values, placeholders)
+			for _, v := range row {
+				if v.Valid {
+					args = append(args, []byte(v.String))
+				} else {
+					args = append(args, nil)
+				}
+			}
+		}
+		_, err = s.target.Db.Exec(fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", shadowTable, cols, strings.Join(values, ", ")), args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxPlaceholders is the limit of placeholders in a prepared statement of MySQL.
+const maxPlaceholders = 65535
+
+// rewrite replaces the schemas of tables and columns in node with the shadow schemas.
+func (s *shadow) rewrite(node ast.Node, defaultSchema string) (string, error) {
+	shadowSchemaOf := func(schema model.CIStr) (model.CIStr, bool) {
+		if schema.O == "" {
+			schema = model.NewCIStr(defaultSchema)
+		}
+		shadowSchema, ok := s.schemas[schema.O]
+		return model.NewCIStr(shadowSchema), ok
+	}
+	node.Accept(&shadowRewriter{rewrite: shadowSchemaOf})
+	return util.RestoreSql(node)
+}
+
+func (s *shadow) isShadowSchema(schema string) bool {
+	for _, shadowSchema := range s.schemas {
+		if shadowSchema == schema {
+			return true
+		}
+	}
+	return false
+}
+
+// unshadow replaces the shadow schemas in message with the schemas they are cloned from.
+func (s *shadow) unshadow(message string) string {
+	for schema, shadowSchema := range s.schemas {
+		message = strings.ReplaceAll(message, shadowSchema, schema)
+	}
+	return message
+}
+
+func (s *shadow) drop() {
+	for _, shadowSchema := range s.schemas {
+		if _, err := s.target.Db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", shadowSchema)); err != nil {
+			s.i.Logger().Errorf("drop shadow schema %s failed, %v", shadowSchema, err)
+		}
+	}
+}
+
+// collectTableNames returns all the tables in node, including the tables referenced by
+// foreign keys and used in subqueries.
+func collectTableNames(node ast.Node) []*ast.TableName {
+	c := &tableNameCollector{}
+	node.Accept(c)
+	return c.tables
+}
+
+type tableNameCollector struct {
+	tables []*ast.TableName
+}
+
+func (c *tableNameCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if table, ok := in.(*ast.TableName); ok {
+		c.tables = append(c.tables, table)
+	}
+	return in, false
+}
+
+func (c *tableNameCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// shadowRewriter replaces the schemas of tables and columns, the schema is kept if it has
+// no shadow schema, it is the shadow schema which has been replaced already.
+type shadowRewriter struct {
+	rewrite func(schema model.CIStr) (model.CIStr, bool)
+}
+
+func (r *shadowRewriter) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.TableName:
+		if schema, ok := r.rewrite(node.Schema); ok {
+			node.Schema = schema
+		}
+	case *ast.ColumnName:
+		if node.Schema.O == "" {
+			break
+		}
+		if schema, ok := r.rewrite(node.Schema); ok {
+			node.Schema = schema
+		}
+	}
+	return in, false
+}
+
+func (r *shadowRewriter) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockShadowId(t *testing.T) {
+	origin := newShadowId
+	newShadowId = func() string { return "test" }
+	t.Cleanup(func() { newShadowId = origin })
+}
+
+func TestInspect_DryRun(t *testing.T) {
+	mockShadowId(t)
+	i, handler := newRollbackMockInspect(t)
+	i.Ctx.SetCurrentSchema("exist_db")
+
+	handler.ExpectExec(regexp.QuoteMeta("CREATE DATABASE `_sqle_shadow_test_1`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW CREATE TABLE `exist_db`.`exist_tb_1`")).
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).AddRow("exist_tb_1",
+			"CREATE TABLE `exist_tb_1` (`id` bigint NOT NULL, `v1` varchar(255) DEFAULT NULL, `v2` varchar(255) DEFAULT NULL, PRIMARY KEY (`id`))"))
+	handler.ExpectExec(regexp.QuoteMeta("SET FOREIGN_KEY_CHECKS = 0")).WillReturnResult(sqlmock.NewResult(0, 0))
+	handler.ExpectExec(regexp.QuoteMeta("CREATE TABLE `_sqle_shadow_test_1`.`exist_tb_1` (`id` BIGINT NOT NULL,`v1` VARCHAR(255) DEFAULT NULL,`v2` VARCHAR(255) DEFAULT NULL,PRIMARY KEY(`id`))")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	handler.ExpectExec(regexp.QuoteMeta("INSERT INTO `_sqle_shadow_test_1`.`exist_tb_1` (`id`, `v1`, `v2`) SELECT `id`, `v1`, `v2` FROM `exist_db`.`exist_tb_1` LIMIT 10")).
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	handler.ExpectExec(regexp.QuoteMeta("SET FOREIGN_KEY_CHECKS = 1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	handler.ExpectExec(regexp.QuoteMeta("ALTER TABLE `_sqle_shadow_test_1`.`exist_tb_1` ADD UNIQUE `uk_v1`(`v1`)")).
+		WillReturnError(errors.New("Duplicate entry 'a' for key '_sqle_shadow_test_1.exist_tb_1.uk_v1'"))
+	handler.ExpectExec(regexp.QuoteMeta("UPDATE `_sqle_shadow_test_1`.`exist_tb_1` SET `v1`='1' WHERE `_sqle_shadow_test_1`.`exist_tb_1`.`id`=1")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	handler.ExpectExec(regexp.QuoteMeta("DROP DATABASE IF EXISTS `_sqle_shadow_test_1`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	results, err := i.DryRun(context.TODO(), &driver.DryRunOptions{SampleRows: 10},
+		"alter table exist_tb_1 add unique index uk_v1(v1)",
+		"create database db1",
+		"update exist_tb_1 set v1 = '1' where exist_db.exist_tb_1.id = 1",
+	)
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.EqualError(t, results[0].Err, "Duplicate entry 'a' for key 'exist_db.exist_tb_1.uk_v1'")
+	assert.True(t, results[1].Skipped)
+	assert.NoError(t, results[2].Err)
+	assert.False(t, results[2].Skipped)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestInspect_DryRunWithForeignKey(t *testing.T) {
+	mockShadowId(t)
+	i, handler := newRollbackMockInspect(t)
+
+	handler.ExpectExec(regexp.QuoteMeta("CREATE DATABASE `_sqle_shadow_test_1`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW CREATE TABLE `exist_db`.`exist_tb_2`")).
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).AddRow("exist_tb_2",
+			"CREATE TABLE `exist_tb_2` (`id` bigint NOT NULL, `user_id` bigint DEFAULT NULL, CONSTRAINT `fk_1` FOREIGN KEY (`user_id`) REFERENCES `exist_tb_1` (`id`))"))
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW CREATE TABLE `exist_db`.`exist_tb_1`")).
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).AddRow("exist_tb_1",
+			"CREATE TABLE `exist_tb_1` (`id` bigint NOT NULL, PRIMARY KEY (`id`))"))
+	handler.ExpectExec(regexp.QuoteMeta("SET FOREIGN_KEY_CHECKS = 0")).WillReturnResult(sqlmock.NewResult(0, 0))
+	handler.ExpectExec(regexp.QuoteMeta("CREATE TABLE `_sqle_shadow_test_1`.`exist_tb_2` (`id` BIGINT NOT NULL,`user_id` BIGINT DEFAULT NULL,CONSTRAINT `fk_1` FOREIGN KEY (`user_id`) REFERENCES `_sqle_shadow_test_1`.`exist_tb_1`(`id`))")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	handler.ExpectExec(regexp.QuoteMeta("CREATE TABLE `_sqle_shadow_test_1`.`exist_tb_1` (`id` BIGINT NOT NULL,PRIMARY KEY(`id`))")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	handler.ExpectExec(regexp.QuoteMeta("SET FOREIGN_KEY_CHECKS = 1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	handler.ExpectExec(regexp.QuoteMeta("INSERT INTO `_sqle_shadow_test_1`.`exist_tb_2` VALUES (1,2)")).
+		WillReturnError(errors.New("Cannot add or update a child row: a foreign key constraint fails"))
+	handler.ExpectExec(regexp.QuoteMeta("DROP DATABASE IF EXISTS `_sqle_shadow_test_1`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	results, err := i.DryRun(context.TODO(), nil,
+		"insert into exist_db.exist_tb_2 values (1, 2)",
+		"create procedure p1() select 1",
+	)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.EqualError(t, results[0].Err, "Cannot add or update a child row: a foreign key constraint fails")
+	assert.True(t, results[1].Skipped)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestShadow_copyRowsAcrossInstances(t *testing.T) {
+	source, sourceHandler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	target, targetHandler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	s := &shadow{
+		source:     source,
+		target:     target,
+		sampleRows: 10,
+		schemas:    map[string]string{"exist_db": "_sqle_shadow_test_1"},
+	}
+
+	// the values of BIT and binary columns are not valid UTF-8 strings.
+	sourceHandler.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `v1`, `v2` FROM `exist_db`.`exist_tb_1` LIMIT 10")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "v1", "v2"}).
+			AddRow("1", "\x00\xff'\\", nil).
+			AddRow("2", "\x01", "a"))
+	targetHandler.ExpectExec(regexp.QuoteMeta("INSERT INTO `_sqle_shadow_test_1`.`exist_tb_1` (`id`, `v1`, `v2`) VALUES (?, ?, ?), (?, ?, ?)")).
+		WithArgs([]byte("1"), []byte("\x00\xff'\\"), nil, []byte("2"), []byte("\x01"), []byte("a")).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, s.copyRows("exist_db", "exist_tb_1", []string{"`id`", "`v1`", "`v2`"}))
+	assert.NoError(t, sourceHandler.ExpectationsWereMet())
+	assert.NoError(t, targetHandler.ExpectationsWereMet())
+}
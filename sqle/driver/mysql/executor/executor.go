@@ -9,6 +9,7 @@ import (
 	"time"
 
 	mdriver "github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	"github.com/actiontech/sqle/sqle/errors"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/sirupsen/logrus"
@@ -19,7 +20,7 @@ const DAIL_TIMEOUT = 5 * time.Second
 type Db interface {
 	Close()
 	Ping() error
-	Exec(query string) (driver.Result, error)
+	Exec(query string, args ...interface{}) (driver.Result, error)
 	Transact(qs ...string) ([]driver.Result, error)
 	Query(query string, args ...interface{}) ([]map[string]sql.NullString, error)
 	QueryWithContext(ctx context.Context, query string, args ...interface{}) (column []string, row [][]sql.NullString, err error)
@@ -83,8 +84,8 @@ func (c *BaseConn) Ping() error {
 	return errors.New(errors.ConnectRemoteDatabaseError, err)
 }
 
-func (c *BaseConn) Exec(query string) (driver.Result, error) {
-	result, err := c.conn.ExecContext(context.Background(), query)
+func (c *BaseConn) Exec(query string, args ...interface{}) (driver.Result, error) {
+	result, err := c.conn.ExecContext(context.Background(), query, args...)
 	if err != nil {
 		c.Logger().Errorf("exec sql failed; host: %s, port: %s, user: %s, query: %s, error: %s",
 			c.host, c.port, c.user, query, err.Error())
@@ -275,8 +276,8 @@ func (c *Executor) ShowDatabases(ignoreSysDatabase bool) ([]string, error) {
 }
 
 func (c *Executor) ShowSchemaTables(schema string) ([]string, error) {
-	result, err := c.Db.Query(fmt.Sprintf(
-		"select TABLE_NAME from information_schema.tables where table_schema=\"%s\" and TABLE_TYPE=\"BASE TABLE\"", schema))
+	result, err := c.Db.Query(
+		"select TABLE_NAME from information_schema.tables where table_schema=? and TABLE_TYPE=\"BASE TABLE\"", schema)
 	if err != nil {
 		return nil, err
 	}
@@ -296,8 +297,8 @@ func (c *Executor) ShowSchemaTables(schema string) ([]string, error) {
 }
 
 func (c *Executor) ShowSchemaViews(schema string) ([]string, error) {
-	result, err := c.Db.Query(fmt.Sprintf(
-		"select TABLE_NAME from information_schema.tables where table_schema=\"%s\" and TABLE_TYPE=\"VIEW\"", schema))
+	result, err := c.Db.Query(
+		"select TABLE_NAME from information_schema.tables where table_schema=? and TABLE_TYPE=\"VIEW\"", schema)
 	if err != nil {
 		return nil, err
 	}
@@ -336,6 +337,67 @@ func (c *Executor) ShowCreateView(tableName string) (string, error) {
 	}
 }
 
+// the types of the objects which are shown by ShowCreateObject.
+const (
+	ObjectTypeView      = "VIEW"
+	ObjectTypeTrigger   = "TRIGGER"
+	ObjectTypeProcedure = "PROCEDURE"
+	ObjectTypeFunction  = "FUNCTION"
+)
+
+var objectExistQueries = map[string]string{
+	ObjectTypeView: "SELECT TABLE_NAME FROM information_schema.views " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?",
+	ObjectTypeTrigger: "SELECT TRIGGER_NAME FROM information_schema.triggers " +
+		"WHERE TRIGGER_SCHEMA = ? AND TRIGGER_NAME = ?",
+	ObjectTypeProcedure: "SELECT ROUTINE_NAME FROM information_schema.routines " +
+		"WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = 'PROCEDURE'",
+	ObjectTypeFunction: "SELECT ROUTINE_NAME FROM information_schema.routines " +
+		"WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = 'FUNCTION'",
+}
+
+var objectCreateColumns = map[string]string{
+	ObjectTypeView:      "Create View",
+	ObjectTypeTrigger:   "SQL Original Statement",
+	ObjectTypeProcedure: "Create Procedure",
+	ObjectTypeFunction:  "Create Function",
+}
+
+// IsObjectExist returns true if the view, trigger, procedure or function exists.
+func (c *Executor) IsObjectExist(tp, schema, name string) (bool, error) {
+	existQuery, ok := objectExistQueries[tp]
+	if !ok {
+		return false, fmt.Errorf("unknown object type %v", tp)
+	}
+	result, err := c.Db.Query(existQuery, schema, name)
+	if err != nil {
+		return false, err
+	}
+	return len(result) > 0, nil
+}
+
+// ShowCreateObject returns the CREATE statement of the view, trigger, procedure or function,
+// exist is false if the object is not exist.
+func (c *Executor) ShowCreateObject(tp, schema, name string) (query string, exist bool, err error) {
+	exist, err = c.IsObjectExist(tp, schema, name)
+	if err != nil || !exist {
+		return "", false, err
+	}
+
+	result, err := c.Db.Query(fmt.Sprintf("SHOW CREATE %s %s.%s", tp, util.QuoteName(schema), util.QuoteName(name)))
+	if err != nil {
+		return "", false, err
+	}
+	column := objectCreateColumns[tp]
+	if len(result) != 1 || !result[0][column].Valid {
+		// the CREATE statement is NULL if the user has no privilege to show it.
+		err := fmt.Errorf("show create %s error, column \"%s\" not found in result %v", tp, column, result)
+		c.Db.Logger().Error(err)
+		return "", false, errors.New(errors.ConnectRemoteDatabaseError, err)
+	}
+	return result[0][column].String, true, nil
+}
+
 type ExplainRecord struct {
 	Id           string `json:"id"`
 	SelectType   string `json:"select_type"`
@@ -441,9 +503,8 @@ func (c *Executor) FetchMasterBinlogPos() (string, int64, error) {
 }
 
 func (c *Executor) ShowTableSizeMB(schema, table string) (float64, error) {
-	sql := fmt.Sprintf(`select (DATA_LENGTH + INDEX_LENGTH)/1024/1024 as Size from information_schema.tables 
-where table_schema = '%s' and table_name = '%s'`, schema, table)
-	result, err := c.Db.Query(sql)
+	result, err := c.Db.Query(`select (DATA_LENGTH + INDEX_LENGTH)/1024/1024 as Size from information_schema.tables 
+where table_schema = ? and table_name = ?`, schema, table)
 	if err != nil {
 		return 0, err
 	}
@@ -462,6 +523,30 @@ where table_schema = '%s' and table_name = '%s'`, schema, table)
 	}
 	return size, nil
 }
+
+// ShowSchemaTablesSizeMB returns the sizes of all the tables in schema by one query, the key is table name.
+func (c *Executor) ShowSchemaTablesSizeMB(schema string) (map[string]float64, error) {
+	result, err := c.Db.Query(`select TABLE_NAME as Name, (DATA_LENGTH + INDEX_LENGTH)/1024/1024 as Size 
+from information_schema.tables where table_schema = ?`, schema)
+	if err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]float64, len(result))
+	for _, row := range result {
+		sizeStr := row["Size"].String
+		if sizeStr == "" {
+			continue
+		}
+		size, err := strconv.ParseFloat(sizeStr, 64)
+		if err != nil {
+			c.Db.Logger().Error(err)
+			return nil, errors.New(errors.ConnectRemoteDatabaseError, err)
+		}
+		sizes[row["Name"].String] = size
+	}
+	return sizes, nil
+}
+
 func (c *Executor) ShowDefaultConfiguration(sql, column string) (string, error) {
 	result, err := c.Db.Query(sql)
 	if err != nil {
@@ -490,8 +575,8 @@ type TableColumnsInfo struct {
 }
 
 func (c *Executor) GetTableColumnsInfo(schema, tableName string) ([]*TableColumnsInfo, error) {
-	query := fmt.Sprintf("SELECT COLUMN_NAME, COLUMN_TYPE, CHARACTER_SET_NAME, IS_NULLABLE, COLUMN_KEY, COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA='%s' AND TABLE_NAME='%s'", schema, tableName)
-	records, err := c.Db.Query(query)
+	query := "SELECT COLUMN_NAME, COLUMN_TYPE, CHARACTER_SET_NAME, IS_NULLABLE, COLUMN_KEY, COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA=? AND TABLE_NAME=?"
+	records, err := c.Db.Query(query, schema, tableName)
 	if err != nil {
 		return nil, err
 	}
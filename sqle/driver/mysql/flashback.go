@@ -0,0 +1,419 @@
+package mysql
+
+import (
+	"context"
+	_driver "database/sql/driver"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/shopspring/decimal"
+)
+
+// flashbackTimeout is the max duration of reading the binlog events of the executed transaction.
+const flashbackTimeout = 30 * time.Second
+
+// TxWithFlashback implements driver.FlashbackExecutor. If dml_rollback_by_binlog is enabled, it records
+// the binlog position before and after the transaction, and generates the rollback SQLs from the row
+// events of the transaction in binlog, so the rollback SQLs are exact and not limited by dml_rollback_max_rows.
+func (i *Inspect) TxWithFlashback(ctx context.Context, queries ...string) ([]_driver.Result, *driver.Flashback, error) {
+	if i.IsOfflineAudit() || i.cnf == nil || !i.cnf.dmlRollbackByBinlog {
+		results, err := i.Tx(ctx, queries...)
+		return results, nil, err
+	}
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fb := &driver.Flashback{}
+	threadId, err := i.checkFlashback(conn)
+	if err == nil {
+		fb.StartBinlogFile, fb.StartBinlogPos, err = conn.FetchMasterBinlogPos()
+	}
+	if err == nil && fb.StartBinlogFile == "" {
+		err = fmt.Errorf("binlog is disabled")
+	}
+	if err != nil {
+		i.Logger().Warnf("rollback sql will not be generated from binlog, %v", err)
+		reason := err.Error()
+		results, err := i.Tx(ctx, queries...)
+		if err != nil {
+			return results, nil, err
+		}
+		return results, &driver.Flashback{FailedReason: reason}, nil
+	}
+
+	results, err := i.Tx(ctx, queries...)
+	if err != nil {
+		return results, nil, err
+	}
+	fb.EndBinlogFile, fb.EndBinlogPos, err = conn.FetchMasterBinlogPos()
+	if err != nil {
+		i.Logger().Errorf("fetch binlog position after execution failed, %v", err)
+		return results, &driver.Flashback{FailedReason: err.Error()}, nil
+	}
+
+	events, err := i.readFlashbackEvents(ctx, fb, threadId)
+	if err != nil {
+		i.Logger().Errorf("read binlog from %v:%v to %v:%v failed, %v",
+			fb.StartBinlogFile, fb.StartBinlogPos, fb.EndBinlogFile, fb.EndBinlogPos, err)
+		fb.FailedReason = err.Error()
+		return results, fb, nil
+	}
+	affectedRows := make([]int64, len(results))
+	for idx, result := range results {
+		affectedRows[idx], _ = result.RowsAffected()
+	}
+	fb.RollbackSQLs, err = i.generateFlashbackSQLs(events, affectedRows)
+	if err != nil {
+		i.Logger().Errorf("generate rollback sql from binlog failed, %v", err)
+		fb.FailedReason = err.Error()
+	}
+	return results, fb, nil
+}
+
+// checkFlashback checks the row events in binlog are full images, and returns the thread id of
+// the connection which is recorded in the binlog of transaction.
+func (i *Inspect) checkFlashback(conn *executor.Executor) (uint32, error) {
+	result, err := conn.Db.Query("SELECT @@session.binlog_format AS binlog_format, " +
+		"@@session.binlog_row_image AS binlog_row_image, CONNECTION_ID() AS thread_id")
+	if err != nil {
+		return 0, err
+	}
+	if len(result) != 1 {
+		return 0, fmt.Errorf("unexpected result %v of binlog variables", result)
+	}
+	if format := result[0]["binlog_format"].String; !strings.EqualFold(format, "ROW") {
+		return 0, fmt.Errorf("binlog_format is %v, not ROW", format)
+	}
+	if image := result[0]["binlog_row_image"].String; !strings.EqualFold(image, "FULL") {
+		return 0, fmt.Errorf("binlog_row_image is %v, not FULL", image)
+	}
+	threadId, err := strconv.ParseUint(result[0]["thread_id"].String, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(threadId), nil
+}
+
+// readFlashbackEvents reads the binlog between the positions of flashback as a replica, and
+// returns the row events of the transaction executed by the thread.
+func (i *Inspect) readFlashbackEvents(ctx context.Context, fb *driver.Flashback,
+	threadId uint32) ([]*replication.BinlogEvent, error) {
+	if fb.StartBinlogFile == fb.EndBinlogFile && fb.StartBinlogPos >= fb.EndBinlogPos {
+		return nil, nil
+	}
+	port, err := strconv.ParseUint(i.inst.Port, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	syncer := replication.NewBinlogSyncer(replication.BinlogSyncerConfig{
+		// the server id must be unique among the replicas of instance.
+		ServerID: uint32(rand.New(rand.NewSource(time.Now().UnixNano())).Int31n(1<<30)) + 1<<30,
+		Flavor:   gomysql.MySQLFlavor,
+		Host:     i.inst.Host,
+		Port:     uint16(port),
+		User:     i.inst.User,
+		Password: i.inst.Password,
+		// the TIMESTAMP value is converted to the time zone of session in rollback SQL.
+		TimestampStringLocation: time.UTC,
+		// DECIMAL is decoded as float64 by default, which loses the digits beyond the precision of float64.
+		UseDecimal: true,
+	})
+	defer syncer.Close()
+
+	streamer, err := syncer.StartSync(gomysql.Position{Name: fb.StartBinlogFile, Pos: uint32(fb.StartBinlogPos)})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, flashbackTimeout)
+	defer cancel()
+
+	c := &flashbackCollector{threadId: threadId}
+	file, pos := fb.StartBinlogFile, fb.StartBinlogPos
+	for file != fb.EndBinlogFile || pos < fb.EndBinlogPos {
+		ev, err := streamer.GetEvent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if rotate, ok := ev.Event.(*replication.RotateEvent); ok {
+			file, pos = string(rotate.NextLogName), int64(rotate.Position)
+			continue
+		}
+		if ev.Header.LogPos > 0 {
+			pos = int64(ev.Header.LogPos)
+		}
+		c.collect(ev)
+	}
+	return c.events, nil
+}
+
+// flashbackCollector collects the row events of the transactions executed by the thread.
+type flashbackCollector struct {
+	threadId uint32
+	inTx     bool
+	events   []*replication.BinlogEvent
+}
+
+func (c *flashbackCollector) collect(ev *replication.BinlogEvent) {
+	switch e := ev.Event.(type) {
+	case *replication.QueryEvent:
+		// the transaction in ROW format begins with a "BEGIN" query event, which records the thread id.
+		c.inTx = e.SlaveProxyID == c.threadId && string(e.Query) == "BEGIN"
+	case *replication.XIDEvent:
+		c.inTx = false
+	case *replication.RowsEvent:
+		if c.inTx {
+			c.events = append(c.events, ev)
+		}
+	}
+}
+
+// generateFlashbackSQLs generates the rollback SQL of each executed SQL from the row events, the rows
+// are assigned to the SQLs in order by their affected rows.
+func (i *Inspect) generateFlashbackSQLs(events []*replication.BinlogEvent, affectedRows []int64) ([]string, error) {
+	rollbackSQLs := make([][]string, len(affectedRows))
+	tables := map[string]*flashbackTable{}
+	idx := 0
+	assign := func(sql string) error {
+		for idx < len(affectedRows) && int64(len(rollbackSQLs[idx])) >= affectedRows[idx] {
+			idx++
+		}
+		if idx >= len(affectedRows) {
+			return fmt.Errorf("the rows in binlog are more than the affected rows")
+		}
+		rollbackSQLs[idx] = append(rollbackSQLs[idx], sql)
+		return nil
+	}
+
+	for _, ev := range events {
+		e := ev.Event.(*replication.RowsEvent)
+		key := fmt.Sprintf("%s.%s", e.Table.Schema, e.Table.Table)
+		table, ok := tables[key]
+		if !ok {
+			var err error
+			table, err = i.getFlashbackTable(string(e.Table.Schema), string(e.Table.Table))
+			if err != nil {
+				return nil, err
+			}
+			tables[key] = table
+		}
+
+		switch ev.Header.EventType {
+		case replication.WRITE_ROWS_EVENTv0, replication.WRITE_ROWS_EVENTv1, replication.WRITE_ROWS_EVENTv2:
+			for _, row := range e.Rows {
+				sql, err := table.deleteSQL(row)
+				if err == nil {
+					err = assign(sql)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		case replication.DELETE_ROWS_EVENTv0, replication.DELETE_ROWS_EVENTv1, replication.DELETE_ROWS_EVENTv2:
+			for _, row := range e.Rows {
+				sql, err := table.insertSQL(row)
+				if err == nil {
+					err = assign(sql)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		case replication.UPDATE_ROWS_EVENTv0, replication.UPDATE_ROWS_EVENTv1, replication.UPDATE_ROWS_EVENTv2:
+			// the rows of update event are pairs of the before image and the after image.
+			for j := 0; j+1 < len(e.Rows); j += 2 {
+				sql, err := table.updateSQL(e.Rows[j], e.Rows[j+1])
+				if err == nil {
+					err = assign(sql)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for idx, sqls := range rollbackSQLs {
+		if int64(len(sqls)) != affectedRows[idx] {
+			return nil, fmt.Errorf("the rows in binlog are less than the affected rows")
+		}
+	}
+
+	ret := make([]string, 0, len(rollbackSQLs))
+	for _, sqls := range rollbackSQLs {
+		// revert the rows in the reverse order of the changes.
+		rollbackSql := ""
+		for j := len(sqls) - 1; j >= 0; j-- {
+			rollbackSql += sqls[j]
+		}
+		ret = append(ret, rollbackSql)
+	}
+	return ret, nil
+}
+
+// flashbackTable is the table definition which the row images in binlog are decoded by.
+type flashbackTable struct {
+	name  string
+	cols  []*ast.ColumnDef
+	pk    map[string]struct{}
+	hasPk bool
+}
+
+func (i *Inspect) getFlashbackTable(schema, table string) (*flashbackTable, error) {
+	tableName := util.NewTableName(schema, table)
+	stmt, exist, err := i.Ctx.GetCreateTableStmt(tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, fmt.Errorf("table %s.%s is not exist", schema, table)
+	}
+	pk, hasPk, err := i.getPrimaryKey(stmt)
+	if err != nil {
+		return nil, err
+	}
+	return &flashbackTable{
+		name:  i.getTableNameWithQuote(tableName),
+		cols:  stmt.Cols,
+		pk:    pk,
+		hasPk: hasPk,
+	}, nil
+}
+
+func (t *flashbackTable) checkRow(row []interface{}) error {
+	if len(row) != len(t.cols) {
+		return fmt.Errorf("the row of table %s in binlog has %d columns, but the table has %d columns",
+			t.name, len(row), len(t.cols))
+	}
+	return nil
+}
+
+// where returns the condition which matches the row by primary key, or all the columns
+// if the table has no primary key.
+func (t *flashbackTable) where(row []interface{}) string {
+	where := []string{}
+	for idx, col := range t.cols {
+		if _, isPk := t.pk[col.Name.Name.L]; t.hasPk && !isPk {
+			continue
+		}
+		if row[idx] == nil {
+			where = append(where, fmt.Sprintf("`%s` IS NULL", col.Name.Name.O))
+			continue
+		}
+		where = append(where, fmt.Sprintf("`%s` = %s", col.Name.Name.O, flashbackValue(col, row[idx])))
+	}
+	cond := strings.Join(where, " AND ")
+	if !t.hasPk {
+		cond += " LIMIT 1"
+	}
+	return cond
+}
+
+func (t *flashbackTable) deleteSQL(row []interface{}) (string, error) {
+	if err := t.checkRow(row); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("DELETE FROM %s WHERE %s;", t.name, t.where(row)), nil
+}
+
+func (t *flashbackTable) insertSQL(row []interface{}) (string, error) {
+	if err := t.checkRow(row); err != nil {
+		return "", err
+	}
+	columns := make([]string, 0, len(t.cols))
+	values := make([]string, 0, len(t.cols))
+	for idx, col := range t.cols {
+		columns = append(columns, fmt.Sprintf("`%s`", col.Name.Name.O))
+		values = append(values, flashbackValue(col, row[idx]))
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", t.name,
+		strings.Join(columns, ", "), strings.Join(values, ", ")), nil
+}
+
+func (t *flashbackTable) updateSQL(before, after []interface{}) (string, error) {
+	if err := t.checkRow(before); err != nil {
+		return "", err
+	}
+	if err := t.checkRow(after); err != nil {
+		return "", err
+	}
+	value := []string{}
+	for idx, col := range t.cols {
+		v := flashbackValue(col, before[idx])
+		if v == flashbackValue(col, after[idx]) {
+			continue
+		}
+		value = append(value, fmt.Sprintf("`%s` = %s", col.Name.Name.O, v))
+	}
+	if len(value) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s;", t.name, strings.Join(value, ", "), t.where(after)), nil
+}
+
+var flashbackStringEscaper = strings.NewReplacer(
+	`\`, `\\`, `'`, `\'`, "\x00", `\0`, "\n", `\n`, "\r", `\r`, "\x1a", `\Z`)
+
+// flashbackValue formats the value of column decoded from binlog to SQL literal.
+func flashbackValue(col *ast.ColumnDef, v interface{}) string {
+	unsigned := mysql.HasUnsignedFlag(col.Tp.Flag) || col.Tp.Tp == mysql.TypeBit
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case int8:
+		if unsigned {
+			return strconv.FormatUint(uint64(uint8(v)), 10)
+		}
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		if unsigned {
+			return strconv.FormatUint(uint64(uint16(v)), 10)
+		}
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		if unsigned && col.Tp.Tp == mysql.TypeInt24 {
+			return strconv.FormatUint(uint64(uint32(v)&0xFFFFFF), 10)
+		}
+		if unsigned {
+			return strconv.FormatUint(uint64(uint32(v)), 10)
+		}
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		if unsigned {
+			return strconv.FormatUint(uint64(v), 10)
+		}
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case decimal.Decimal:
+		return v.String()
+	case []byte:
+		if col.Tp.Tp == mysql.TypeJSON {
+			return fmt.Sprintf("'%s'", flashbackStringEscaper.Replace(string(v)))
+		}
+		return fmt.Sprintf("X'%X'", v)
+	case string:
+		s := fmt.Sprintf("'%s'", flashbackStringEscaper.Replace(v))
+		if col.Tp.Tp == mysql.TypeTimestamp && !strings.HasPrefix(v, "0000-00-00") {
+			return fmt.Sprintf("CONVERT_TZ(%s, '+00:00', @@time_zone)", s)
+		}
+		return s
+	default:
+		return fmt.Sprintf("'%s'", flashbackStringEscaper.Replace(fmt.Sprint(v)))
+	}
+}
@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/pingcap/parser/ast"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRowsEvent(eventType replication.EventType, table string, rows ...[]interface{}) *replication.BinlogEvent {
+	return &replication.BinlogEvent{
+		Header: &replication.EventHeader{EventType: eventType},
+		Event: &replication.RowsEvent{
+			Table: &replication.TableMapEvent{Schema: []byte("exist_db"), Table: []byte(table)},
+			Rows:  rows,
+		},
+	}
+}
+
+func TestFlashbackCollector(t *testing.T) {
+	begin := func(threadId uint32) *replication.BinlogEvent {
+		return &replication.BinlogEvent{Event: &replication.QueryEvent{SlaveProxyID: threadId, Query: []byte("BEGIN")}}
+	}
+	commit := &replication.BinlogEvent{Event: &replication.XIDEvent{}}
+	row1 := newTestRowsEvent(replication.WRITE_ROWS_EVENTv2, "exist_tb_1", []interface{}{int64(1), "a", nil})
+	row2 := newTestRowsEvent(replication.WRITE_ROWS_EVENTv2, "exist_tb_1", []interface{}{int64(2), "b", nil})
+	row3 := newTestRowsEvent(replication.WRITE_ROWS_EVENTv2, "exist_tb_1", []interface{}{int64(3), "c", nil})
+
+	c := &flashbackCollector{threadId: 10}
+	for _, ev := range []*replication.BinlogEvent{
+		begin(9), row1, commit,
+		begin(10), row2, commit,
+		begin(11), row3, commit,
+	} {
+		c.collect(ev)
+	}
+	assert.Equal(t, []*replication.BinlogEvent{row2}, c.events)
+}
+
+func TestGenerateFlashbackSQLs(t *testing.T) {
+	i := DefaultMysqlInspect()
+
+	sqls, err := i.generateFlashbackSQLs([]*replication.BinlogEvent{
+		newTestRowsEvent(replication.WRITE_ROWS_EVENTv2, "exist_tb_1",
+			[]interface{}{int64(1), "a", nil},
+			[]interface{}{int64(2), "b'c", "d"},
+		),
+		newTestRowsEvent(replication.UPDATE_ROWS_EVENTv2, "exist_tb_1",
+			[]interface{}{int64(3), "v1", "old"}, []interface{}{int64(3), "v1", "new"},
+		),
+		newTestRowsEvent(replication.DELETE_ROWS_EVENTv2, "exist_tb_1",
+			[]interface{}{int64(-1), "v1", nil},
+		),
+	}, []int64{2, 0, 1, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"DELETE FROM `exist_db`.`exist_tb_1` WHERE `id` = 2;" +
+			"DELETE FROM `exist_db`.`exist_tb_1` WHERE `id` = 1;",
+		"",
+		"UPDATE `exist_db`.`exist_tb_1` SET `v2` = 'old' WHERE `id` = 3;",
+		"INSERT INTO `exist_db`.`exist_tb_1` (`id`, `v1`, `v2`) VALUES (18446744073709551615, 'v1', NULL);",
+	}, sqls)
+
+	// the table without primary key is matched by all the columns.
+	sqls, err = i.generateFlashbackSQLs([]*replication.BinlogEvent{
+		newTestRowsEvent(replication.WRITE_ROWS_EVENTv2, "exist_tb_2",
+			[]interface{}{int64(1), "v1", nil, int64(2)},
+		),
+	}, []int64{1})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"DELETE FROM `exist_db`.`exist_tb_2` WHERE `id` = 1 AND `v1` = 'v1' AND `v2` IS NULL AND `user_id` = 2 LIMIT 1;",
+	}, sqls)
+
+	// the rows in binlog must match the affected rows.
+	_, err = i.generateFlashbackSQLs([]*replication.BinlogEvent{
+		newTestRowsEvent(replication.WRITE_ROWS_EVENTv2, "exist_tb_1",
+			[]interface{}{int64(1), "a", nil},
+		),
+	}, []int64{2})
+	assert.Error(t, err)
+
+	_, err = i.generateFlashbackSQLs([]*replication.BinlogEvent{
+		newTestRowsEvent(replication.WRITE_ROWS_EVENTv2, "exist_tb_1",
+			[]interface{}{int64(1), "a"},
+		),
+	}, []int64{1})
+	assert.Error(t, err)
+}
+
+func TestFlashbackValue(t *testing.T) {
+	node, err := util.ParseOneSql("create table t1(id int unsigned, v1 varchar(32), v2 decimal(30,10))")
+	assert.NoError(t, err)
+	cols := node.(*ast.CreateTableStmt).Cols
+
+	assert.Equal(t, "4294967295", flashbackValue(cols[0], int32(-1)))
+	assert.Equal(t, "'a\\'b'", flashbackValue(cols[1], "a'b"))
+	assert.Equal(t, "NULL", flashbackValue(cols[2], nil))
+
+	// DECIMAL wider than the precision of float64 is kept exactly.
+	v, err := decimal.NewFromString("12345678901234567890.0123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678901234567890.0123456789", flashbackValue(cols[2], v))
+}
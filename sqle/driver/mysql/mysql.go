@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	_driver "database/sql/driver"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
@@ -27,13 +29,24 @@ func init() {
 		allRules[i] = &rulepkg.RuleHandlers[i].Rule
 	}
 
-	driver.RegisterAuditDriver(driver.DriverTypeMySQL, NewInspect, allRules, params.Params{})
+	driver.RegisterAuditDriver(driver.DriverTypeMySQL, NewInspect, allRules, params.Params{},
+		driver.CapabilityRollback, driver.CapabilityOnlineDDL, driver.CapabilityOfflineAudit, driver.CapabilitySandbox,
+		driver.CapabilitySchemaBaseline)
+	driver.RegisterSQLQueryDriver(driver.DriverTypeMySQL, NewQueryDriver)
+	driver.RegisterAnalysisDriver(driver.DriverTypeMySQL, NewAnalysisDriver)
 
 	if err := LoadPtTemplateFromFile("./scripts/pt-online-schema-change.template"); err != nil {
 		panic(err)
 	}
 }
 
+// sandboxSchemaName is the current schema of the sandbox audit.
+const sandboxSchemaName = "sandbox"
+
+// baselineSchemaName is the default schema of the schema baseline which default schema
+// is not specified.
+const baselineSchemaName = "baseline"
+
 // Inspect implements driver.Driver interface
 type Inspect struct {
 	// Ctx is SQL session.
@@ -58,6 +71,11 @@ type Inspect struct {
 	isConnected bool
 	// isOfflineAudit represent Audit without instance.
 	isOfflineAudit bool
+	// taskId is the id of task which the audited SQLs belong to, see driver.Config.TaskId.
+	taskId string
+	// executeSQLNumber is the number of the SQL in task which is being executed or generating
+	// rollback SQL, see driver.WithExecuteSQLNumber.
+	executeSQLNumber uint
 }
 
 func NewInspect(log *logrus.Entry, cfg *driver.Config) (driver.Driver, error) {
@@ -72,10 +90,27 @@ func NewInspect(log *logrus.Entry, cfg *driver.Config) (driver.Driver, error) {
 		inspect.dbConn = conn
 		inspect.inst = cfg.DSN
 
-		ctx := session.NewContext(nil, session.WithExecutor(conn))
+		ctx := session.NewContext(nil, session.WithExecutor(conn),
+			session.WithMetaCache(session.DefaultMetaCache, session.MetaCacheKey(cfg.DSN)))
 		ctx.SetCurrentSchema(cfg.DSN.DatabaseName)
 
 		inspect.Ctx = ctx
+	} else if cfg.SchemaBaseline != nil {
+		schema := cfg.SchemaBaseline.DefaultSchema
+		if schema == "" {
+			schema = baselineSchemaName
+		}
+		nodes, err := util.ParseSql(cfg.SchemaBaseline.SQL)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse schema baseline")
+		}
+		baseline := make([]ast.Node, 0, len(nodes))
+		for _, node := range nodes {
+			baseline = append(baseline, node)
+		}
+		inspect.Ctx = session.NewBaselineContext(schema, baseline)
+	} else if cfg.Sandbox {
+		inspect.Ctx = session.NewSandboxContext(sandboxSchemaName)
 	} else {
 		ctx := session.NewContext(nil)
 		inspect.Ctx = ctx
@@ -83,8 +118,11 @@ func NewInspect(log *logrus.Entry, cfg *driver.Config) (driver.Driver, error) {
 
 	inspect.log = log
 	inspect.rules = cfg.Rules
+	inspect.taskId = cfg.TaskId
 	inspect.result = driver.NewInspectResults()
-	inspect.isOfflineAudit = cfg.DSN == nil
+	// sandbox audit and baseline audit run all the rules against the mock context, though
+	// they have no instance.
+	inspect.isOfflineAudit = cfg.DSN == nil && !cfg.Sandbox && cfg.SchemaBaseline == nil
 
 	inspect.cnf = &Config{
 		DMLRollbackMaxRows: -1,
@@ -112,6 +150,16 @@ func NewInspect(log *logrus.Entry, cfg *driver.Config) (driver.Driver, error) {
 		if rule.Name == rulepkg.ConfigDMLExplainPreCheckEnable {
 			inspect.cnf.dmlExplainPreCheckEnable = true
 		}
+		if rule.Name == rulepkg.ConfigDMLRollbackByBinlog {
+			inspect.cnf.dmlRollbackByBinlog = true
+		}
+		if rule.Name == rulepkg.ConfigDDLTruncateBackup {
+			inspect.cnf.ddlTruncateBackup = true
+			inspect.cnf.ddlTruncateBackupMaxSize = defaultTruncateBackupMaxSize
+			if param := rule.Params.GetParam(rulepkg.DefaultSingleParamKeyName); param != nil {
+				inspect.cnf.ddlTruncateBackupMaxSize = int64(param.Int())
+			}
+		}
 	}
 
 	return inspect, nil
@@ -169,6 +217,7 @@ func (i *Inspect) Exec(ctx context.Context, query string) (_driver.Result, error
 	if err != nil {
 		return nil, errors.Wrap(err, "check whether use ghost or not")
 	}
+	defer i.invalidateMetaCache(query)
 
 	if useGhost {
 		if _, err := i.executeByGhost(ctx, query, true); err != nil {
@@ -181,6 +230,10 @@ func (i *Inspect) Exec(ctx context.Context, query string) (_driver.Result, error
 	if err != nil {
 		return nil, err
 	}
+	i.executeSQLNumber = driver.GetExecuteSQLNumber(ctx)
+	if err := i.backupTruncateTable(conn, query); err != nil {
+		return nil, errors.Wrap(err, "backup table before truncate")
+	}
 	return conn.Db.Exec(query)
 }
 
@@ -203,8 +256,20 @@ func (i *Inspect) onlineddlWithGhost(query string) (bool, error) {
 	if err != nil {
 		return false, errors.Wrap(err, "get table size")
 	}
+	if int64(tableSize) <= i.cnf.DDLGhostMinSize {
+		return false, nil
+	}
 
-	return int64(tableSize) > i.cnf.DDLGhostMinSize, nil
+	// the ALTER TABLE which neither blocks writes nor rebuilds the table is executed directly.
+	table, exist, err := i.Ctx.GetCreateTableStmt(stmt.Table)
+	if err != nil {
+		return false, errors.Wrap(err, "get create table statement")
+	}
+	if !exist {
+		table = nil
+	}
+	class := rulepkg.ClassifyAlterTable(stmt, table, rulepkg.GetServerVersion(i.Ctx))
+	return class.NeedOnlineDDLTool(), nil
 }
 
 func (i *Inspect) Tx(ctx context.Context, queries ...string) ([]_driver.Result, error) {
@@ -215,7 +280,108 @@ func (i *Inspect) Tx(ctx context.Context, queries ...string) ([]_driver.Result,
 	if err != nil {
 		return nil, err
 	}
-	return conn.Db.Transact(queries...)
+	defer i.invalidateMetaCache(queries...)
+	results, err := conn.Db.Transact(queries...)
+	if err != nil && len(results) < len(queries) {
+		// the results of the queries executed before the failure are returned.
+		return results, &driver.TxError{Index: len(results), Err: err}
+	}
+	return results, err
+}
+
+// invalidateMetaCache invalidates the cached metadata of instance if the executed queries
+// contain DDL, the query which can't be parsed is treated as DDL.
+func (i *Inspect) invalidateMetaCache(queries ...string) {
+	if i.inst == nil {
+		return
+	}
+	for _, query := range queries {
+		nodes, err := i.ParseSql(query)
+		if err != nil {
+			session.DefaultMetaCache.Invalidate(session.MetaCacheKey(i.inst))
+			return
+		}
+		for _, node := range nodes {
+			switch node.(type) {
+			case ast.DDLNode, *ast.UnparsedStmt:
+				session.DefaultMetaCache.Invalidate(session.MetaCacheKey(i.inst))
+				return
+			}
+		}
+	}
+}
+
+// CountMatchedRows counts the rows matched by the single table UPDATE/DELETE, and the rows
+// in VALUES of INSERT. The count query is also stopped on the server when the deadline
+// of ctx is exceeded.
+func (i *Inspect) CountMatchedRows(ctx context.Context, query string) (int64, bool, error) {
+	if i.IsOfflineAudit() {
+		return 0, false, nil
+	}
+	nodes, err := i.ParseSql(query)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(nodes) != 1 {
+		return 0, false, driver.ErrNodesCountExceedOne
+	}
+
+	var (
+		tableRefs *ast.TableRefsClause
+		where     ast.ExprNode
+		limit     *ast.Limit
+	)
+	switch stmt := nodes[0].(type) {
+	case *ast.InsertStmt:
+		if stmt.Select != nil {
+			return 0, false, nil
+		}
+		return int64(len(stmt.Lists)), true, nil
+	case *ast.UpdateStmt:
+		if stmt.MultipleTable {
+			return 0, false, nil
+		}
+		tableRefs, where, limit = stmt.TableRefs, stmt.Where, stmt.Limit
+	case *ast.DeleteStmt:
+		if stmt.IsMultiTable {
+			return 0, false, nil
+		}
+		tableRefs, where, limit = stmt.TableRefs, stmt.Where, stmt.Limit
+	default:
+		return 0, false, nil
+	}
+
+	table, err := util.RestoreSql(tableRefs.TableRefs)
+	if err != nil {
+		return 0, false, err
+	}
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", table)
+	if where != nil {
+		countQuery = fmt.Sprintf("%s WHERE %s", countQuery, util.ExprFormat(where))
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		countQuery = util.WithMaxExecutionTime(countQuery, time.Until(deadline))
+	}
+	conn, err := i.getDbConn()
+	if err != nil {
+		return 0, false, err
+	}
+	_, rows, err := conn.Db.QueryWithContext(ctx, countQuery)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(rows) != 1 || len(rows[0]) != 1 {
+		return 0, false, fmt.Errorf("do not match records for sql %v", countQuery)
+	}
+	count, err := strconv.ParseInt(rows[0][0].String, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	count, err = util.GetLimitCount(limit, count)
+	if err != nil {
+		return 0, false, err
+	}
+	return count, true, nil
 }
 
 func (i *Inspect) query(ctx context.Context, query string, args ...interface{}) ([]map[string]sql.NullString, error) {
@@ -246,9 +412,14 @@ func (i *Inspect) Parse(ctx context.Context, sqlText string) ([]driver.Node, err
 		}
 		n.Fingerprint = fingerprint
 		n.Text = nodes[i].Text()
-		switch nodes[i].(type) {
+		switch stmt := nodes[i].(type) {
 		case ast.DMLNode:
 			n.Type = driver.SQLTypeDML
+		case *ast.UnparsedStmt:
+			n.Type = driver.SQLTypeDDL
+			if isCTE, _ := util.IsCTEStmt(stmt.Text()); isCTE {
+				n.Type = driver.SQLTypeDML
+			}
 		default:
 			n.Type = driver.SQLTypeDDL
 		}
@@ -296,6 +467,13 @@ func (i *Inspect) Audit(ctx context.Context, sql string) (*driver.AuditResult, e
 			ghostRule = rule
 		}
 
+		if m, ok := driver.GetCustomRuleMatcher(rule); ok {
+			if err := rulepkg.CheckCustomRule(*rule, m, i.result, nodes[0]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
 		handler, ok := rulepkg.RuleHandlerMap[rule.Name]
 		if !ok || handler.Func == nil {
 			continue
@@ -340,7 +518,7 @@ func (i *Inspect) Audit(ctx context.Context, sql string) (*driver.AuditResult, e
 		if _, err := i.executeByGhost(ctx, sql, true); err != nil {
 			i.result.Add(driver.RuleLevelError, fmt.Sprintf("表空间大小超过%vMB, 将使用gh-ost进行上线, 但是dry-run抛出如下错误: %v", i.cnf.DDLGhostMinSize, err))
 		} else {
-			i.result.Add(ghostRule.Level, fmt.Sprintf("表空间大小超过%vMB, 将使用gh-ost进行上线", i.cnf.DDLGhostMinSize))
+			i.result.AddWithRule(*ghostRule, "表空间大小超过%vMB, 将使用gh-ost进行上线", i.cnf.DDLGhostMinSize)
 		}
 	}
 
@@ -352,6 +530,25 @@ func (i *Inspect) Audit(ctx context.Context, sql string) (*driver.AuditResult, e
 	if oscCommandLine != "" {
 		i.result.Add(driver.RuleLevelNotice, fmt.Sprintf("[osc]%s", oscCommandLine))
 	}
+
+	// the rules which know the offending node set the position in the statement text, and
+	// the position of other results is the whole SQL.
+	if offset := strings.LastIndex(sql, nodes[0].Text()); offset > 0 {
+		i.result.ShiftPosition(sql[:offset])
+	}
+	trimmedSQL := strings.TrimSpace(sql)
+	i.result.SetPosition(driver.NewSQLPosition(sql, strings.Index(sql, trimmedSQL), len(trimmedSQL)))
+
+	i.result.ApplySuppressions(sql)
+
+	fixedSQL, fixedRules, err := rulepkg.FixSQL(sql, i.rules, i.result)
+	if err != nil {
+		// the fix is only a suggestion, ignore the error.
+		i.log.Errorf("fix sql failed: %v", err)
+	} else {
+		i.result.SetFixedSQL(fixedSQL, fixedRules...)
+	}
+
 	i.Ctx.UpdateContext(nodes[0])
 	return i.result, nil
 }
@@ -369,6 +566,7 @@ func (i *Inspect) GenRollbackSQL(ctx context.Context, sql string) (string, strin
 		return "", "", err
 	}
 
+	i.executeSQLNumber = driver.GetExecuteSQLNumber(ctx)
 	rollback, reason, err := i.GenerateRollbackSql(nodes[0])
 	if err != nil {
 		return "", "", err
@@ -413,6 +611,9 @@ type Config struct {
 
 	optimizeIndexEnabled       bool
 	dmlExplainPreCheckEnable   bool
+	dmlRollbackByBinlog        bool
+	ddlTruncateBackup          bool
+	ddlTruncateBackupMaxSize   int64
 	calculateCardinalityMaxRow int
 	compositeIndexMaxColumn    int
 }
@@ -2,9 +2,16 @@ package mysql
 
 import (
 	"context"
+	"errors"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/actiontech/sqle/sqle/driver"
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	"github.com/actiontech/sqle/sqle/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,6 +54,18 @@ func TestInspect_onlineddlWithGhost(t *testing.T) {
 			wantErr: false,
 		},
 
+		{
+			name: "alter stmt(true); config onlineddl(true); table size enough(true); instant ddl",
+			setUp: func(i *Inspect) *Inspect {
+				i.Ctx.AddSystemVariable("version", "8.0.30")
+				i.Ctx.Schemas()["exist_db"].Tables["exist_tb_1"].Size = 17
+				return i
+			},
+			args:    args{query: "alter table exist_db.exist_tb_1 add column col1 varchar(100);"},
+			want:    false,
+			wantErr: false,
+		},
+
 		{
 			name: "alter stmt(true); config onlineddl(true); table size enough(false)",
 			setUp: func(i *Inspect) *Inspect {
@@ -162,3 +181,152 @@ func TestInspect_GenRollbackSQL(t *testing.T) {
 	assert.Equal(t, "", reason)
 	assert.Equal(t, "ALTER TABLE `exist_db`.`t1`\nDROP COLUMN `c1`;", rollback)
 }
+
+func TestInspect_AuditResultItems(t *testing.T) {
+	i := DefaultMysqlInspect()
+	rule := rulepkg.RuleHandlerMap[rulepkg.DMLCheckWhereIsInvalid].Rule
+	i.rules = []*driver.Rule{&rule}
+
+	result, err := i.Audit(context.TODO(), "  delete from exist_db.exist_tb_1\n  where 1=1")
+	assert.NoError(t, err)
+
+	items := result.Items()
+	assert.Len(t, items, 1)
+	assert.Equal(t, rulepkg.DMLCheckWhereIsInvalid, items[0].RuleName)
+	assert.Equal(t, rule.Category, items[0].Category)
+	assert.Equal(t, rule.Level, items[0].Level)
+	assert.Equal(t, &driver.SQLPosition{StartLine: 1, StartColumn: 3, EndLine: 2, EndColumn: 11}, items[0].Position)
+
+	// the position of result is the offending column.
+	i = DefaultMysqlInspect()
+	rule = rulepkg.RuleHandlerMap[rulepkg.DDLCheckColumnWithoutComment].Rule
+	i.rules = []*driver.Rule{&rule}
+	result, err = i.Audit(context.TODO(), "\n  create table t1(id int comment 'id',\n  `name` varchar(32))")
+	assert.NoError(t, err)
+	items = result.Items()
+	assert.Len(t, items, 1)
+	assert.Equal(t, &driver.SQLPosition{StartLine: 3, StartColumn: 4, EndLine: 3, EndColumn: 7}, items[0].Position)
+
+	// the column which has the same name as the table or schema is not located at them.
+	for sql, column := range map[string]string{
+		"create table t1(id int comment 'id', t1 int)":                "t1",
+		"create table exist_db.t1(id int comment 'id', exist_db int)": "exist_db",
+		"alter table exist_db.exist_tb_1 add column exist_tb_1 int":   "exist_tb_1",
+	} {
+		i = DefaultMysqlInspect()
+		i.rules = []*driver.Rule{&rule}
+		result, err = i.Audit(context.TODO(), sql)
+		assert.NoError(t, err)
+		items = result.Items()
+		assert.Len(t, items, 1, sql)
+		offset := strings.LastIndex(sql, column)
+		assert.Equal(t, &driver.SQLPosition{StartLine: 1, StartColumn: offset + 1, EndLine: 1, EndColumn: offset + len(column)}, items[0].Position, sql)
+	}
+}
+
+func TestInspect_AuditSandbox(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.DDLCheckColumnTypeNarrowing].Rule
+	d, err := NewInspect(log.NewEntry(), &driver.Config{Rules: []*driver.Rule{&rule}, Sandbox: true})
+	assert.NoError(t, err)
+
+	// the table is unknown before it is created by setup SQL.
+	result, err := d.Audit(context.TODO(), "alter table t1 modify column name varchar(16)")
+	assert.NoError(t, err)
+	assert.Contains(t, result.Message(), "表 sandbox.t1 不存在")
+
+	result, err = d.Audit(context.TODO(), "create table t1(id int primary key, name varchar(32))")
+	assert.NoError(t, err)
+	assert.Equal(t, driver.RuleLevelNull, result.Level())
+
+	result, err = d.Audit(context.TODO(), "alter table t1 modify column name varchar(16)")
+	assert.NoError(t, err)
+	items := result.Items()
+	assert.Len(t, items, 1)
+	assert.Equal(t, rulepkg.DDLCheckColumnTypeNarrowing, items[0].RuleName)
+}
+
+func TestInspect_AuditSchemaBaseline(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.DDLCheckColumnTypeNarrowing].Rule
+	d, err := NewInspect(log.NewEntry(), &driver.Config{
+		Rules: []*driver.Rule{&rule},
+		SchemaBaseline: &driver.SchemaBaseline{
+			DefaultSchema: "app1",
+			SQL: "/*!40101 SET NAMES utf8mb4 */;" +
+				"CREATE TABLE t1(id int primary key, name varchar(32));" +
+				"CREATE DATABASE app2; USE app2; CREATE TABLE t2(id int primary key);",
+		},
+	})
+	assert.NoError(t, err)
+	assert.False(t, d.(*Inspect).IsOfflineAudit())
+
+	// the tables in the baseline exist, the default schema is the current schema.
+	result, err := d.Audit(context.TODO(), "alter table t1 modify column name varchar(16)")
+	assert.NoError(t, err)
+	items := result.Items()
+	assert.Len(t, items, 1)
+	assert.Equal(t, rulepkg.DDLCheckColumnTypeNarrowing, items[0].RuleName)
+
+	result, err = d.Audit(context.TODO(), "alter table app2.t2 add column name varchar(32)")
+	assert.NoError(t, err)
+	assert.Equal(t, driver.RuleLevelNull, result.Level())
+
+	result, err = d.Audit(context.TODO(), "alter table t2 add column name varchar(32)")
+	assert.NoError(t, err)
+	assert.Contains(t, result.Message(), "表 app1.t2 不存在")
+}
+
+func TestInspect_CountMatchedRows(t *testing.T) {
+	i, handler := newRollbackMockInspect(t)
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM `exist_db`.`exist_tb_1` WHERE `id` > 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow("10"))
+	rows, ok, err := i.CountMatchedRows(context.TODO(), "update exist_db.exist_tb_1 set v1 = 'a' where id > 1 limit 5")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), rows)
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM `exist_db`.`exist_tb_1` WHERE `id` = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow("1"))
+	rows, ok, err = i.CountMatchedRows(context.TODO(), "delete from exist_db.exist_tb_1 where id = 1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), rows)
+
+	rows, ok, err = i.CountMatchedRows(context.TODO(), "insert into exist_db.exist_tb_1 values (1, 'a', 'b'), (2, 'c', 'd')")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), rows)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Minute)
+	defer cancel()
+	handler.ExpectQuery(`SELECT /\*\+ MAX_EXECUTION_TIME\(\d+\) \*/ COUNT\(\*\) FROM .exist_db.\..exist_tb_1. WHERE .id. = 2`).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow("0"))
+	rows, ok, err = i.CountMatchedRows(ctx, "delete from exist_db.exist_tb_1 where id = 2")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), rows)
+
+	_, ok, err = i.CountMatchedRows(context.TODO(), "alter table exist_db.exist_tb_1 add column v3 int")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestInspect_Tx(t *testing.T) {
+	i, handler := newRollbackMockInspect(t)
+
+	handler.ExpectBegin()
+	handler.ExpectExec(regexp.QuoteMeta("INSERT INTO exist_tb_1 VALUES (1)")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	handler.ExpectExec(regexp.QuoteMeta("INSERT INTO exist_tb_1 VALUES (2)")).
+		WillReturnError(errors.New("mock error: Duplicate entry"))
+	handler.ExpectRollback()
+	results, err := i.Tx(context.TODO(), "INSERT INTO exist_tb_1 VALUES (1)",
+		"INSERT INTO exist_tb_1 VALUES (2)", "INSERT INTO exist_tb_1 VALUES (3)")
+	assert.Len(t, results, 1)
+	txErr := &driver.TxError{}
+	assert.ErrorAs(t, err, &txErr)
+	assert.Equal(t, 1, txErr.Index)
+	assert.EqualError(t, err, "mock error: Duplicate entry")
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
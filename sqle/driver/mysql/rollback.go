@@ -3,9 +3,11 @@ package mysql
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
 	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	"github.com/actiontech/sqle/sqle/errors"
 
@@ -19,6 +21,8 @@ func (i *Inspect) GenerateRollbackSql(node ast.Node) (string, string, error) {
 		return i.GenerateDDLStmtRollbackSql(node)
 	case ast.DMLNode:
 		return i.GenerateDMLStmtRollbackSql(node)
+	case *ast.UnparsedStmt:
+		return i.generateUnparsedStmtRollbackSql(node.Text())
 	}
 	return "", "", nil
 }
@@ -32,17 +36,29 @@ func (i *Inspect) GenerateDDLStmtRollbackSql(node ast.Node) (rollbackSql, unable
 	case *ast.CreateDatabaseStmt:
 		rollbackSql, unableRollbackReason, err = i.generateCreateSchemaRollbackSql(stmt)
 	case *ast.DropTableStmt:
-		rollbackSql, unableRollbackReason, err = i.generateDropTableRollbackSql(stmt)
+		if stmt.IsView {
+			rollbackSql, unableRollbackReason, err = i.generateDropViewRollbackSql(stmt)
+		} else {
+			rollbackSql, unableRollbackReason, err = i.generateDropTableRollbackSql(stmt)
+		}
 	case *ast.CreateIndexStmt:
 		rollbackSql, unableRollbackReason, err = i.generateCreateIndexRollbackSql(stmt)
 	case *ast.DropIndexStmt:
 		rollbackSql, unableRollbackReason, err = i.generateDropIndexRollbackSql(stmt)
+	case *ast.RenameTableStmt:
+		rollbackSql, unableRollbackReason, err = i.generateRenameTableRollbackSql(stmt)
+	case *ast.CreateViewStmt:
+		rollbackSql, unableRollbackReason, err = i.generateCreateViewRollbackSql(stmt)
+	case *ast.TruncateTableStmt:
+		rollbackSql, unableRollbackReason, err = i.generateTruncateTableRollbackSql(stmt)
 	}
 	return rollbackSql, unableRollbackReason, err
 }
 
 func (i *Inspect) GenerateDMLStmtRollbackSql(node ast.Node) (rollbackSql, unableRollbackReason string, err error) {
 	// Inspect may skip initialized cnf when Audited SQLs in whitelist.
+	// The rollback SQL is replaced by the one generated from binlog on execution if dmlRollbackByBinlog
+	// is enabled, and it is kept if the generation from binlog is failed, see TxWithFlashback.
 	if i.cnf == nil || i.cnf.DMLRollbackMaxRows < 0 {
 		return "", "", nil
 	}
@@ -65,6 +81,12 @@ const (
 	NotSupportNoPrimaryKeyTableRollback       = "不支持回滚没有主键的表的DML语句"
 	NotSupportInsertWithoutPrimaryKeyRollback = "不支持回滚 INSERT 没有指定主键的语句"
 	NotSupportExceedMaxRowsRollback           = "预计影响行数超过配置的最大值，不生成回滚语句"
+	NotSupportTruncateWithoutBackupRollback   = "未开启 TRUNCATE 备份，不生成回滚语句"
+	NotSupportBackupTableNameTooLongRollback  = "备份表的表名超过 64 个字符，不生成回滚语句"
+	NotSupportBackupTableTooLargeRollback     = "表空间超过 TRUNCATE 备份的最大值，不生成回滚语句"
+	NotSupportTruncatePartitionRollback       = "暂不支持回滚 TRUNCATE PARTITION 语句"
+	NotSupportNoPartitionTableRollback        = "表没有分区定义，不生成分区操作的回滚语句"
+	DropPartitionRollbackWithoutData          = "DROP PARTITION 的回滚语句只恢复分区定义，不恢复分区中的数据"
 )
 
 // generateAlterTableRollbackSql generate alter table SQL for alter table.
@@ -76,6 +98,10 @@ func (i *Inspect) generateAlterTableRollbackSql(stmt *ast.AlterTableStmt) (strin
 	if err != nil || !exist {
 		return "", "", err
 	}
+	// the partition operation can't be used with the other operations in one ALTER TABLE.
+	if len(stmt.Specs) == 1 && isPartitionSpec(stmt.Specs[0]) {
+		return i.generateAlterPartitionRollbackSql(util.NewTableName(schemaName, tableName), createTableStmt, stmt.Specs[0])
+	}
 	rollbackStmt := &ast.AlterTableStmt{
 		Table: util.NewTableName(schemaName, tableName),
 		Specs: []*ast.AlterTableSpec{},
@@ -353,6 +379,354 @@ func (i *Inspect) generateDropIndexRollbackSql(stmt *ast.DropIndexStmt) (string,
 	return rollbackSql, "", nil
 }
 
+// generateRenameTableRollbackSql generate rename table SQL in reverse order for rename table.
+func (i *Inspect) generateRenameTableRollbackSql(stmt *ast.RenameTableStmt) (string, string, error) {
+	renames := make([]string, 0, len(stmt.TableToTables))
+	for idx := len(stmt.TableToTables) - 1; idx >= 0; idx-- {
+		t2t := stmt.TableToTables[idx]
+		renames = append(renames, fmt.Sprintf("%s TO %s",
+			i.getTableNameWithQuote(t2t.NewTable), i.getTableNameWithQuote(t2t.OldTable)))
+	}
+	return fmt.Sprintf("RENAME TABLE %s;", strings.Join(renames, ", ")), "", nil
+}
+
+// generateCreateViewRollbackSql generate drop view SQL for create view, or the original view
+// for create or replace view.
+func (i *Inspect) generateCreateViewRollbackSql(stmt *ast.CreateViewStmt) (string, string, error) {
+	schemaName := i.Ctx.GetSchemaName(stmt.ViewName)
+	viewName := stmt.ViewName.Name.String()
+	if stmt.OrReplace {
+		query, exist, err := i.showCreateObject(executor.ObjectTypeView, schemaName, viewName)
+		if err != nil {
+			return "", "", err
+		}
+		if exist {
+			query = strings.Replace(query, "CREATE ", "CREATE OR REPLACE ", 1)
+			return i.createInSchema(schemaName, query), "", nil
+		}
+	}
+	return fmt.Sprintf("DROP VIEW IF EXISTS %s.%s;", util.QuoteName(schemaName), util.QuoteName(viewName)), "", nil
+}
+
+// generateDropViewRollbackSql generate create view SQL for drop view.
+func (i *Inspect) generateDropViewRollbackSql(stmt *ast.DropTableStmt) (string, string, error) {
+	rollbackSql := ""
+	for _, view := range stmt.Tables {
+		query, exist, err := i.showCreateObject(executor.ObjectTypeView, i.Ctx.GetSchemaName(view), view.Name.String())
+		if err != nil {
+			return "", "", err
+		}
+		// if view not exist, can not rollback it.
+		if !exist {
+			continue
+		}
+		rollbackSql += i.createInSchema(i.Ctx.GetSchemaName(view), query) + "\n"
+	}
+	return rollbackSql, "", nil
+}
+
+// routineStmtReg matches the CREATE and DROP statement of trigger, procedure and function, which
+// are not supported by parser, e.g.
+//
+//	CREATE DEFINER=`root`@`%` TRIGGER db1.trigger1 BEFORE INSERT ON t1 FOR EACH ROW ...
+//	DROP PROCEDURE IF EXISTS `db1`.`procedure1`
+var routineStmtReg = regexp.MustCompile("(?is)^\\s*(CREATE|DROP)\\s+(?:DEFINER\\s*=\\s*\\S+\\s+)?(TRIGGER|PROCEDURE|FUNCTION)\\s+" +
+	"(IF\\s+(?:NOT\\s+)?EXISTS\\s+)?((?:`[^`]+`|[\\w$]+)(?:\\s*\\.\\s*(?:`[^`]+`|[\\w$]+))?)")
+
+var identifierReg = regexp.MustCompile("`([^`]+)`|([\\w$]+)")
+
+// generateUnparsedStmtRollbackSql generate drop SQL for create trigger, procedure and function,
+// and the original definition for drop them.
+func (i *Inspect) generateUnparsedStmtRollbackSql(sql string) (string, string, error) {
+	match := routineStmtReg.FindStringSubmatch(sql)
+	if match == nil {
+		return "", "", nil
+	}
+	tp := strings.ToUpper(match[2])
+	names := []string{}
+	for _, identifier := range identifierReg.FindAllStringSubmatch(match[4], -1) {
+		names = append(names, identifier[1]+identifier[2])
+	}
+	schemaName, name := i.Ctx.CurrentSchema(), names[len(names)-1]
+	if len(names) > 1 {
+		schemaName = names[0]
+	}
+
+	if strings.EqualFold(match[1], "DROP") {
+		query, exist, err := i.showCreateObject(tp, schemaName, name)
+		if err != nil || !exist {
+			return "", "", err
+		}
+		return i.createInSchema(schemaName, query), "", nil
+	}
+	// CREATE ... IF NOT EXISTS does nothing if the object exists.
+	if match[3] != "" {
+		conn, err := i.getDbConn()
+		if err != nil {
+			return "", "", err
+		}
+		exist, err := conn.IsObjectExist(tp, schemaName, name)
+		if err != nil || exist {
+			return "", "", err
+		}
+	}
+	return fmt.Sprintf("DROP %s IF EXISTS %s.%s;", tp, util.QuoteName(schemaName), util.QuoteName(name)), "", nil
+}
+
+func (i *Inspect) showCreateObject(tp, schema, name string) (string, bool, error) {
+	conn, err := i.getDbConn()
+	if err != nil {
+		return "", false, err
+	}
+	return conn.ShowCreateObject(tp, schema, name)
+}
+
+// createInSchema returns the CREATE statement shown by instance which doesn't specify the schema
+// of object, it is executed in the schema and then switches back to the current schema.
+func (i *Inspect) createInSchema(schema, query string) string {
+	query = strings.TrimRight(strings.TrimSpace(query), ";") + ";"
+	current := i.Ctx.CurrentSchema()
+	if schema == current {
+		return query
+	}
+	if current == "" {
+		return fmt.Sprintf("USE `%s`;\n%s", schema, query)
+	}
+	return fmt.Sprintf("USE `%s`;\n%s\nUSE `%s`;", schema, query, current)
+}
+
+// defaultTruncateBackupMaxSize is the max size(MB) of table which is backed up before truncate,
+// it is used if the max size is not set in rule ddl_truncate_backup.
+const defaultTruncateBackupMaxSize = 1024
+
+// truncateBackupTableName returns the table which keeps the data of table before truncate,
+// see backupTruncateTable. The backup table is named by task and the number of SQL in task,
+// so the backup tables of the tasks or the SQLs which truncate the same table don't overwrite
+// each other.
+func (i *Inspect) truncateBackupTableName(table string) string {
+	name := fmt.Sprintf("_%s_truncate_bak", table)
+	if i.taskId != "" {
+		name = fmt.Sprintf("%s_%s", name, i.taskId)
+	}
+	if i.executeSQLNumber > 0 {
+		name = fmt.Sprintf("%s_%d", name, i.executeSQLNumber)
+	}
+	return name
+}
+
+// isTruncateBackupTooLarge returns true if the table is larger than the max size of backup,
+// 0 means no limit.
+func (i *Inspect) isTruncateBackupTooLarge(table *ast.TableName) (bool, error) {
+	if i.cnf.ddlTruncateBackupMaxSize <= 0 {
+		return false, nil
+	}
+	size, err := i.Ctx.GetTableSize(table)
+	if err != nil {
+		return false, err
+	}
+	return size > float64(i.cnf.ddlTruncateBackupMaxSize), nil
+}
+
+// generateTruncateTableRollbackSql generate insert SQL from the backup table for truncate table,
+// the backup table is dropped after the data is restored.
+func (i *Inspect) generateTruncateTableRollbackSql(stmt *ast.TruncateTableStmt) (string, string, error) {
+	if i.cnf == nil || !i.cnf.ddlTruncateBackup {
+		return "", NotSupportTruncateWithoutBackupRollback, nil
+	}
+	backupTableName := i.truncateBackupTableName(stmt.Table.Name.String())
+	if len(backupTableName) > 64 {
+		return "", NotSupportBackupTableNameTooLongRollback, nil
+	}
+	tableExist, err := i.Ctx.IsTableExist(stmt.Table)
+	if err != nil || !tableExist {
+		return "", "", err
+	}
+	tooLarge, err := i.isTruncateBackupTooLarge(stmt.Table)
+	if err != nil {
+		return "", "", err
+	}
+	if tooLarge {
+		return "", NotSupportBackupTableTooLargeRollback, nil
+	}
+	backupTable := fmt.Sprintf("%s.%s", util.QuoteName(i.Ctx.GetSchemaName(stmt.Table)), util.QuoteName(backupTableName))
+	return fmt.Sprintf("INSERT INTO %s SELECT * FROM %s;\nDROP TABLE %s;",
+		i.getTableNameWithQuote(stmt.Table), backupTable, backupTable), "", nil
+}
+
+// backupTruncateTable copies the data of table to the backup table before truncate table,
+// the rollback SQL of truncate table restores the data from it. It fails if the backup table
+// exists or the table is larger than the max size of backup, so truncate table is not executed
+// without backup. The backup table is dropped by the rollback SQL, it is kept in the schema
+// until the task is rolled back or it is dropped manually.
+func (i *Inspect) backupTruncateTable(conn *executor.Executor, query string) error {
+	if i.cnf == nil || !i.cnf.ddlTruncateBackup {
+		return nil
+	}
+	nodes, err := i.ParseSql(query)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	stmt, ok := nodes[0].(*ast.TruncateTableStmt)
+	if !ok {
+		return nil
+	}
+	backupTableName := i.truncateBackupTableName(stmt.Table.Name.String())
+	if len(backupTableName) > 64 {
+		return nil
+	}
+	tooLarge, err := i.isTruncateBackupTooLarge(stmt.Table)
+	if err != nil {
+		return err
+	}
+	if tooLarge {
+		return fmt.Errorf("table %s is larger than %dMB, it is not backed up", stmt.Table.Name.String(),
+			i.cnf.ddlTruncateBackupMaxSize)
+	}
+	table := i.getTableNameWithQuote(stmt.Table)
+	backupTable := fmt.Sprintf("%s.%s", util.QuoteName(i.Ctx.GetSchemaName(stmt.Table)), util.QuoteName(backupTableName))
+	// CREATE TABLE fails if the backup table exists, the backup of the other execution is kept.
+	if _, err := conn.Db.Exec(fmt.Sprintf("CREATE TABLE %s LIKE %s", backupTable, table)); err != nil {
+		return err
+	}
+	if _, err := conn.Db.Exec(fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", backupTable, table)); err != nil {
+		if _, dropErr := conn.Db.Exec(fmt.Sprintf("DROP TABLE %s", backupTable)); dropErr != nil {
+			i.Logger().Errorf("drop the incomplete backup table %s failed, %v", backupTable, dropErr)
+		}
+		return err
+	}
+	i.Logger().Infof("table %s is backed up to %s before truncate, drop it if the rollback is not needed", table, backupTable)
+	return nil
+}
+
+func isPartitionSpec(spec *ast.AlterTableSpec) bool {
+	switch spec.Tp {
+	case ast.AlterTableAddPartitions, ast.AlterTableCoalescePartitions, ast.AlterTableDropPartition,
+		ast.AlterTableTruncatePartition, ast.AlterTablePartition, ast.AlterTableRemovePartitioning,
+		ast.AlterTableRebuildPartition, ast.AlterTableReorganizePartition, ast.AlterTableCheckPartitions,
+		ast.AlterTableExchangePartition, ast.AlterTableOptimizePartition, ast.AlterTableRepairPartition,
+		ast.AlterTableImportPartitionTablespace, ast.AlterTableDiscardPartitionTablespace:
+		return true
+	}
+	return false
+}
+
+// generateAlterPartitionRollbackSql generate alter table SQL for the partition operation of alter table.
+func (i *Inspect) generateAlterPartitionRollbackSql(table *ast.TableName, createTableStmt *ast.CreateTableStmt,
+	spec *ast.AlterTableSpec) (string, string, error) {
+	partition := createTableStmt.Partition
+	var rollbackSpec *ast.AlterTableSpec
+	reason := ""
+
+	switch spec.Tp {
+	case ast.AlterTableAddPartitions:
+		// ADD PARTITION (PARTITION p1 ...) for RANGE and LIST, ADD PARTITION PARTITIONS n for HASH and KEY.
+		if len(spec.PartDefinitions) > 0 {
+			names := make([]_model.CIStr, 0, len(spec.PartDefinitions))
+			for _, def := range spec.PartDefinitions {
+				names = append(names, def.Name)
+			}
+			rollbackSpec = &ast.AlterTableSpec{Tp: ast.AlterTableDropPartition, PartitionNames: names}
+		} else {
+			rollbackSpec = &ast.AlterTableSpec{Tp: ast.AlterTableCoalescePartitions, Num: spec.Num}
+		}
+	case ast.AlterTableCoalescePartitions:
+		rollbackSpec = &ast.AlterTableSpec{Tp: ast.AlterTableAddPartitions, Num: spec.Num}
+	case ast.AlterTableDropPartition:
+		if partition == nil {
+			return "", NotSupportNoPartitionTableRollback, nil
+		}
+		dropped := map[string]struct{}{}
+		for _, name := range spec.PartitionNames {
+			dropped[name.L] = struct{}{}
+		}
+		first := -1
+		defs := []*ast.PartitionDefinition{}
+		for idx, def := range partition.Definitions {
+			if _, ok := dropped[def.Name.L]; ok {
+				if first < 0 {
+					first = idx
+				}
+				defs = append(defs, def)
+			}
+		}
+		if first < 0 {
+			return "", "", nil
+		}
+		rollbackSpec = &ast.AlterTableSpec{Tp: ast.AlterTableAddPartitions, PartDefinitions: defs}
+		// the RANGE partitions must be in ascending order, so the partitions after the first dropped
+		// one are reorganized to the original partitions.
+		if partition.Tp == _model.PartitionTypeRange {
+			remains := []_model.CIStr{}
+			for _, def := range partition.Definitions[first:] {
+				if _, ok := dropped[def.Name.L]; !ok {
+					remains = append(remains, def.Name)
+				}
+			}
+			rollbackSpec.PartDefinitions = partition.Definitions[first:]
+			if len(remains) > 0 {
+				rollbackSpec.Tp = ast.AlterTableReorganizePartition
+				rollbackSpec.PartitionNames = remains
+			}
+		}
+		reason = DropPartitionRollbackWithoutData
+	case ast.AlterTableTruncatePartition:
+		return "", NotSupportTruncatePartitionRollback, nil
+	case ast.AlterTableReorganizePartition:
+		if partition == nil || spec.OnAllPartitions {
+			return "", NotSupportNoPartitionTableRollback, nil
+		}
+		reorganized := map[string]struct{}{}
+		for _, name := range spec.PartitionNames {
+			reorganized[name.L] = struct{}{}
+		}
+		defs := []*ast.PartitionDefinition{}
+		for _, def := range partition.Definitions {
+			if _, ok := reorganized[def.Name.L]; ok {
+				defs = append(defs, def)
+			}
+		}
+		names := make([]_model.CIStr, 0, len(spec.PartDefinitions))
+		for _, def := range spec.PartDefinitions {
+			names = append(names, def.Name)
+		}
+		rollbackSpec = &ast.AlterTableSpec{Tp: ast.AlterTableReorganizePartition, PartitionNames: names, PartDefinitions: defs}
+	case ast.AlterTablePartition:
+		if partition == nil {
+			rollbackSpec = &ast.AlterTableSpec{Tp: ast.AlterTableRemovePartitioning}
+		} else {
+			rollbackSpec = &ast.AlterTableSpec{Tp: ast.AlterTablePartition, Partition: partition}
+		}
+	case ast.AlterTableRemovePartitioning:
+		if partition == nil {
+			return "", "", nil
+		}
+		rollbackSpec = &ast.AlterTableSpec{Tp: ast.AlterTablePartition, Partition: partition}
+	case ast.AlterTableExchangePartition:
+		// exchange the partition and the table again.
+		rollbackSpec = &ast.AlterTableSpec{
+			Tp:             ast.AlterTableExchangePartition,
+			PartitionNames: spec.PartitionNames,
+			NewTable:       util.NewTableName(i.Ctx.GetSchemaName(spec.NewTable), spec.NewTable.Name.String()),
+			WithValidation: spec.WithValidation,
+		}
+	default:
+		// the other partition operations, e.g. REBUILD PARTITION, don't change the table.
+		return "", "", nil
+	}
+
+	rollbackSql, err := util.RestoreSql(&ast.AlterTableStmt{
+		Table: table,
+		Specs: []*ast.AlterTableSpec{rollbackSpec},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return rollbackSql + ";", reason, nil
+}
+
 // generateInsertRollbackSql generate delete SQL for insert.
 func (i *Inspect) generateInsertRollbackSql(stmt *ast.InsertStmt) (string, string, error) {
 	tables := util.GetTables(stmt.Table.TableRefs)
@@ -2,9 +2,15 @@ package mysql
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"testing"
 
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
 	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -177,4 +183,298 @@ func TestInsertRollbackSql(t *testing.T) {
 		`INSERT INTO exist_db.exist_tb_1 set id=10,v1="v1",v2="v2";`,
 		"DELETE FROM `exist_db`.`exist_tb_1` WHERE id = '10';\n",
 	)
+
+	// the rollback SQL is kept as the fallback of the rollback SQL generated from binlog.
+	i := DefaultMysqlInspect()
+	i.cnf.dmlRollbackByBinlog = true
+	runRollbackCase(t, "insert into: need delete when rollback by binlog", i,
+		`INSERT INTO exist_db.exist_tb_1 set id=10,v1="v1",v2="v2";`,
+		"DELETE FROM `exist_db`.`exist_tb_1` WHERE id = '10';\n",
+	)
+}
+
+// newRollbackMockInspect returns the Inspect which queries the instance by the mock executor.
+func newRollbackMockInspect(t *testing.T) (*Inspect, sqlmock.Sqlmock) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	i := NewMockInspect(e)
+	i.isConnected = true
+	return i, handler
+}
+
+func runRollbackReasonCase(t *testing.T, desc string, i *Inspect, sql string, results, reason string) {
+	nodes, err := util.ParseSql(sql)
+	assert.NoError(t, err)
+	rollbackSql, unableRollbackReason, err := i.GenerateRollbackSql(nodes[0])
+	assert.NoError(t, err)
+	assert.Equal(t, results, rollbackSql, desc)
+	assert.Equal(t, reason, unableRollbackReason, desc)
+}
+
+func TestRenameTableRollbackSql(t *testing.T) {
+	runRollbackCase(t, "rename table need rename back", DefaultMysqlInspect(),
+		"RENAME TABLE exist_tb_1 TO tb_1;",
+		"RENAME TABLE `exist_db`.`tb_1` TO `exist_db`.`exist_tb_1`;",
+	)
+	runRollbackCase(t, "rename tables need rename back in reverse order", DefaultMysqlInspect(),
+		"RENAME TABLE exist_tb_1 TO tb_1, exist_db.exist_tb_2 TO exist_tb_1;",
+		"RENAME TABLE `exist_db`.`exist_tb_1` TO `exist_db`.`exist_tb_2`, `exist_db`.`tb_1` TO `exist_db`.`exist_tb_1`;",
+	)
+}
+
+func TestViewRollbackSql(t *testing.T) {
+	runRollbackCase(t, "create view need drop", DefaultMysqlInspect(),
+		"CREATE VIEW v1 AS SELECT * FROM exist_tb_1;",
+		"DROP VIEW IF EXISTS `exist_db`.`v1`;",
+	)
+
+	i, handler := newRollbackMockInspect(t)
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT TABLE_NAME FROM information_schema.views")).
+		WithArgs("exist_db", "v1").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}))
+	runRollbackCase(t, "create or replace view need drop if view not exist", i,
+		"CREATE OR REPLACE VIEW v1 AS SELECT * FROM exist_tb_1;",
+		"DROP VIEW IF EXISTS `exist_db`.`v1`;",
+	)
+
+	createView := "CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `v1` AS " +
+		"select `exist_db`.`exist_tb_1`.`id` AS `id` from `exist_db`.`exist_tb_1`"
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT TABLE_NAME FROM information_schema.views")).
+		WithArgs("exist_db", "v1").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}).AddRow("v1"))
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW CREATE VIEW `exist_db`.`v1`")).
+		WillReturnRows(sqlmock.NewRows([]string{"View", "Create View"}).AddRow("v1", createView))
+	runRollbackCase(t, "create or replace view need replace with the original view", i,
+		"CREATE OR REPLACE VIEW v1 AS SELECT * FROM exist_tb_1;",
+		"CREATE OR REPLACE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `v1` AS "+
+			"select `exist_db`.`exist_tb_1`.`id` AS `id` from `exist_db`.`exist_tb_1`;",
+	)
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT TABLE_NAME FROM information_schema.views")).
+		WithArgs("exist_db", "v1").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}).AddRow("v1"))
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW CREATE VIEW `exist_db`.`v1`")).
+		WillReturnRows(sqlmock.NewRows([]string{"View", "Create View"}).AddRow("v1", createView))
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT TABLE_NAME FROM information_schema.views")).
+		WithArgs("db2", "v2").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}).AddRow("v2"))
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW CREATE VIEW `db2`.`v2`")).
+		WillReturnRows(sqlmock.NewRows([]string{"View", "Create View"}).AddRow("v2", "CREATE VIEW `v2` AS select 1 AS `1`"))
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT TABLE_NAME FROM information_schema.views")).
+		WithArgs("exist_db", "v3").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}))
+	runRollbackCase(t, "drop view need create", i,
+		"DROP VIEW IF EXISTS v1, db2.v2, v3;",
+		createView+";\n"+
+			"USE `db2`;\nCREATE VIEW `v2` AS select 1 AS `1`;\nUSE `exist_db`;\n",
+	)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestRoutineRollbackSql(t *testing.T) {
+	runRollbackCase(t, "create trigger need drop", DefaultMysqlInspect(),
+		"CREATE DEFINER=`root`@`%` TRIGGER `trigger1` BEFORE INSERT ON exist_tb_1 FOR EACH ROW SET NEW.v2 = 'v2';",
+		"DROP TRIGGER IF EXISTS `exist_db`.`trigger1`;",
+	)
+	runRollbackCase(t, "create procedure need drop", DefaultMysqlInspect(),
+		"CREATE PROCEDURE db2.procedure1() BEGIN SELECT 1; END;",
+		"DROP PROCEDURE IF EXISTS `db2`.`procedure1`;",
+	)
+	runRollbackCase(t, "create function need drop", DefaultMysqlInspect(),
+		"CREATE FUNCTION `db2`.`function1`() RETURNS INT RETURN 1;",
+		"DROP FUNCTION IF EXISTS `db2`.`function1`;",
+	)
+
+	i, handler := newRollbackMockInspect(t)
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT ROUTINE_NAME FROM information_schema.routines")).
+		WithArgs("exist_db", "procedure1").
+		WillReturnRows(sqlmock.NewRows([]string{"ROUTINE_NAME"}).AddRow("procedure1"))
+	runRollbackCase(t, "create procedure if not exists need not drop the exist procedure", i,
+		"CREATE PROCEDURE IF NOT EXISTS procedure1() BEGIN SELECT 1; END;",
+		"",
+	)
+
+	createTrigger := "CREATE DEFINER=`root`@`%` TRIGGER `trigger1` BEFORE INSERT ON `exist_tb_1` FOR EACH ROW SET NEW.v2 = 'v2'"
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT TRIGGER_NAME FROM information_schema.triggers")).
+		WithArgs("exist_db", "trigger1").
+		WillReturnRows(sqlmock.NewRows([]string{"TRIGGER_NAME"}).AddRow("trigger1"))
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW CREATE TRIGGER `exist_db`.`trigger1`")).
+		WillReturnRows(sqlmock.NewRows([]string{"Trigger", "SQL Original Statement"}).AddRow("trigger1", createTrigger))
+	runRollbackCase(t, "drop trigger need create", i,
+		"DROP TRIGGER trigger1;",
+		createTrigger+";",
+	)
+
+	createProcedure := "CREATE DEFINER=`root`@`%` PROCEDURE `procedure1`()\nBEGIN SELECT 1; END"
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT ROUTINE_NAME FROM information_schema.routines")).
+		WithArgs("db2", "procedure1").
+		WillReturnRows(sqlmock.NewRows([]string{"ROUTINE_NAME"}).AddRow("procedure1"))
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW CREATE PROCEDURE `db2`.`procedure1`")).
+		WillReturnRows(sqlmock.NewRows([]string{"Procedure", "Create Procedure"}).AddRow("procedure1", createProcedure))
+	runRollbackCase(t, "drop procedure need create in the schema", i,
+		"DROP PROCEDURE IF EXISTS db2.procedure1;",
+		"USE `db2`;\n"+createProcedure+";\nUSE `exist_db`;",
+	)
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT ROUTINE_NAME FROM information_schema.routines")).
+		WithArgs("exist_db", "function1").
+		WillReturnRows(sqlmock.NewRows([]string{"ROUTINE_NAME"}))
+	runRollbackCase(t, "drop function not exist need not create", i,
+		"DROP FUNCTION IF EXISTS function1;",
+		"",
+	)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestTruncateTableRollbackSql(t *testing.T) {
+	runRollbackReasonCase(t, "truncate table without backup", DefaultMysqlInspect(),
+		"TRUNCATE TABLE exist_tb_1;",
+		"", NotSupportTruncateWithoutBackupRollback,
+	)
+
+	i, handler := newRollbackMockInspect(t)
+	i.cnf.ddlTruncateBackup = true
+	i.taskId = "1"
+	runRollbackCase(t, "truncate table need insert from backup table", i,
+		"TRUNCATE TABLE exist_tb_1;",
+		"INSERT INTO `exist_db`.`exist_tb_1` SELECT * FROM `exist_db`.`_exist_tb_1_truncate_bak_1`;\n"+
+			"DROP TABLE `exist_db`.`_exist_tb_1_truncate_bak_1`;",
+	)
+	runRollbackCase(t, "truncate table not exist", i,
+		"TRUNCATE TABLE not_exist_tb_1;",
+		"",
+	)
+
+	handler.ExpectExec(regexp.QuoteMeta("CREATE TABLE `exist_db`.`_exist_tb_1_truncate_bak_1` LIKE `exist_db`.`exist_tb_1`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	handler.ExpectExec(regexp.QuoteMeta("INSERT INTO `exist_db`.`_exist_tb_1_truncate_bak_1` SELECT * FROM `exist_db`.`exist_tb_1`")).
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	handler.ExpectExec(regexp.QuoteMeta("TRUNCATE TABLE exist_tb_1")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	_, err := i.Exec(context.TODO(), "TRUNCATE TABLE exist_tb_1")
+	assert.NoError(t, err)
+	assert.NoError(t, handler.ExpectationsWereMet())
+
+	// the SQLs which truncate the same table in a task are backed up to different tables.
+	i, handler = newRollbackMockInspect(t)
+	i.cnf.ddlTruncateBackup = true
+	i.taskId = "1"
+	for _, number := range []uint{2, 3} {
+		ctx := driver.WithExecuteSQLNumber(context.TODO(), number)
+		rollbackSQL, _, err := i.GenRollbackSQL(ctx, "TRUNCATE TABLE exist_tb_1")
+		assert.NoError(t, err)
+		backupTable := fmt.Sprintf("`exist_db`.`_exist_tb_1_truncate_bak_1_%d`", number)
+		assert.Equal(t, fmt.Sprintf("INSERT INTO `exist_db`.`exist_tb_1` SELECT * FROM %s;\nDROP TABLE %s;", backupTable, backupTable), rollbackSQL)
+
+		handler.ExpectExec(regexp.QuoteMeta(fmt.Sprintf("CREATE TABLE %s LIKE `exist_db`.`exist_tb_1`", backupTable))).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		handler.ExpectExec(regexp.QuoteMeta(fmt.Sprintf("INSERT INTO %s SELECT * FROM `exist_db`.`exist_tb_1`", backupTable))).
+			WillReturnResult(sqlmock.NewResult(0, 10))
+		handler.ExpectExec(regexp.QuoteMeta("TRUNCATE TABLE exist_tb_1")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		_, err = i.Exec(ctx, "TRUNCATE TABLE exist_tb_1")
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, handler.ExpectationsWereMet())
+
+	// the backup table of previous task must not be dropped.
+	i, handler = newRollbackMockInspect(t)
+	i.cnf.ddlTruncateBackup = true
+	handler.ExpectExec(regexp.QuoteMeta("CREATE TABLE `exist_db`.`_exist_tb_1_truncate_bak` LIKE `exist_db`.`exist_tb_1`")).
+		WillReturnError(fmt.Errorf("table already exists"))
+	_, err = i.Exec(context.TODO(), "TRUNCATE TABLE exist_tb_1")
+	assert.Error(t, err)
+	assert.NoError(t, handler.ExpectationsWereMet())
+
+	i, handler = newRollbackMockInspect(t)
+	i.cnf.ddlTruncateBackup = true
+	i.cnf.ddlTruncateBackupMaxSize = 10
+	runRollbackReasonCase(t, "truncate table too large to backup", i,
+		"TRUNCATE TABLE exist_tb_4;",
+		"", NotSupportBackupTableTooLargeRollback,
+	)
+	_, err = i.Exec(context.TODO(), "TRUNCATE TABLE exist_tb_4")
+	assert.Error(t, err)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestAlterPartitionRollbackSql(t *testing.T) {
+	newPartitionInspect := func() *Inspect {
+		i := DefaultMysqlInspect()
+		for _, sql := range []string{
+			"CREATE TABLE exist_db.exist_tb_range (id int NOT NULL, PRIMARY KEY (id)) PARTITION BY RANGE (id) " +
+				"(PARTITION p1 VALUES LESS THAN (100), PARTITION p2 VALUES LESS THAN (200), PARTITION p3 VALUES LESS THAN MAXVALUE)",
+			"CREATE TABLE exist_db.exist_tb_list (id int NOT NULL, PRIMARY KEY (id)) PARTITION BY LIST (id) " +
+				"(PARTITION p1 VALUES IN (1, 2), PARTITION p2 VALUES IN (3, 4))",
+		} {
+			nodes, err := util.ParseSql(sql)
+			assert.NoError(t, err)
+			i.Ctx.UpdateContext(nodes[0])
+		}
+		return i
+	}
+
+	runRollbackCase(t, "add partition need drop", newPartitionInspect(),
+		"ALTER TABLE exist_tb_range ADD PARTITION (PARTITION p4 VALUES LESS THAN (400), PARTITION p5 VALUES LESS THAN (500));",
+		"ALTER TABLE `exist_db`.`exist_tb_range` DROP PARTITION `p4`,`p5`;",
+	)
+	runRollbackCase(t, "add hash partitions need coalesce", newPartitionInspect(),
+		"ALTER TABLE exist_tb_range ADD PARTITION PARTITIONS 2;",
+		"ALTER TABLE `exist_db`.`exist_tb_range` COALESCE PARTITION 2;",
+	)
+	runRollbackCase(t, "coalesce partition need add", newPartitionInspect(),
+		"ALTER TABLE exist_tb_range COALESCE PARTITION 2;",
+		"ALTER TABLE `exist_db`.`exist_tb_range` ADD PARTITION PARTITIONS 2;",
+	)
+	runRollbackReasonCase(t, "drop the last range partition need add", newPartitionInspect(),
+		"ALTER TABLE exist_tb_range DROP PARTITION p3;",
+		"ALTER TABLE `exist_db`.`exist_tb_range` ADD PARTITION (PARTITION `p3` VALUES LESS THAN (MAXVALUE));",
+		DropPartitionRollbackWithoutData,
+	)
+	runRollbackReasonCase(t, "drop range partitions need reorganize", newPartitionInspect(),
+		"ALTER TABLE exist_tb_range DROP PARTITION p1, p3;",
+		"ALTER TABLE `exist_db`.`exist_tb_range` REORGANIZE PARTITION `p2` INTO "+
+			"(PARTITION `p1` VALUES LESS THAN (100), PARTITION `p2` VALUES LESS THAN (200), PARTITION `p3` VALUES LESS THAN (MAXVALUE));",
+		DropPartitionRollbackWithoutData,
+	)
+	runRollbackReasonCase(t, "drop list partition need add", newPartitionInspect(),
+		"ALTER TABLE exist_tb_list DROP PARTITION p1;",
+		"ALTER TABLE `exist_db`.`exist_tb_list` ADD PARTITION (PARTITION `p1` VALUES IN (1, 2));",
+		DropPartitionRollbackWithoutData,
+	)
+	runRollbackReasonCase(t, "drop partition of table without partition", newPartitionInspect(),
+		"ALTER TABLE exist_tb_1 DROP PARTITION p1;",
+		"", NotSupportNoPartitionTableRollback,
+	)
+	runRollbackReasonCase(t, "truncate partition", newPartitionInspect(),
+		"ALTER TABLE exist_tb_range TRUNCATE PARTITION p1;",
+		"", NotSupportTruncatePartitionRollback,
+	)
+	runRollbackCase(t, "reorganize partition need reorganize back", newPartitionInspect(),
+		"ALTER TABLE exist_tb_range REORGANIZE PARTITION p1, p2 INTO (PARTITION p12 VALUES LESS THAN (200));",
+		"ALTER TABLE `exist_db`.`exist_tb_range` REORGANIZE PARTITION `p12` INTO "+
+			"(PARTITION `p1` VALUES LESS THAN (100), PARTITION `p2` VALUES LESS THAN (200));",
+	)
+	runRollbackCase(t, "remove partitioning need partition by", newPartitionInspect(),
+		"ALTER TABLE exist_tb_list REMOVE PARTITIONING;",
+		"ALTER TABLE `exist_db`.`exist_tb_list` PARTITION BY LIST (`id`) "+
+			"(PARTITION `p1` VALUES IN (1, 2),PARTITION `p2` VALUES IN (3, 4));",
+	)
+	runRollbackCase(t, "partition by need remove partitioning", newPartitionInspect(),
+		"ALTER TABLE exist_tb_1 PARTITION BY HASH (id) PARTITIONS 4;",
+		"ALTER TABLE `exist_db`.`exist_tb_1` REMOVE PARTITIONING;",
+	)
+	runRollbackCase(t, "partition by need the original partition", newPartitionInspect(),
+		"ALTER TABLE exist_tb_list PARTITION BY HASH (id) PARTITIONS 4;",
+		"ALTER TABLE `exist_db`.`exist_tb_list` PARTITION BY LIST (`id`) "+
+			"(PARTITION `p1` VALUES IN (1, 2),PARTITION `p2` VALUES IN (3, 4));",
+	)
+	runRollbackCase(t, "exchange partition need exchange again", newPartitionInspect(),
+		"ALTER TABLE exist_tb_range EXCHANGE PARTITION p1 WITH TABLE exist_tb_1;",
+		"ALTER TABLE `exist_db`.`exist_tb_range` EXCHANGE PARTITION `p1` WITH TABLE `exist_db`.`exist_tb_1`;",
+	)
+	runRollbackCase(t, "rebuild partition need not rollback", newPartitionInspect(),
+		"ALTER TABLE exist_tb_range REBUILD PARTITION p1;",
+		"",
+	)
 }
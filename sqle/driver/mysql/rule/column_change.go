@@ -0,0 +1,520 @@
+package rule
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
+)
+
+// ColumnChange is the change of a column by MODIFY COLUMN or CHANGE COLUMN.
+type ColumnChange struct {
+	Name   string
+	OldCol *ast.ColumnDef
+	NewCol *ast.ColumnDef
+
+	// TableCharset and TableCollation are inherited by the column which character set
+	// and collation are not specified, they are empty if unknown.
+	TableCharset   string
+	TableCollation string
+}
+
+// GetColumnChanges returns the changes of the columns which exist in the table.
+func GetColumnChanges(stmt *ast.AlterTableStmt, table *ast.CreateTableStmt) []*ColumnChange {
+	changes := []*ColumnChange{}
+	if table == nil {
+		return changes
+	}
+	charset, collation := getTableCharsetAndCollation(table)
+	for _, spec := range util.GetAlterTableSpecByTp(stmt.Specs, ast.AlterTableModifyColumn, ast.AlterTableChangeColumn) {
+		for _, col := range spec.NewColumns {
+			oldName := col.Name.Name.L
+			if spec.OldColumnName != nil {
+				oldName = spec.OldColumnName.Name.L
+			}
+			oldCol := getColumn(table, oldName)
+			if oldCol == nil || oldCol.Tp == nil || col.Tp == nil {
+				continue
+			}
+			changes = append(changes, &ColumnChange{
+				Name:           oldCol.Name.Name.O,
+				OldCol:         oldCol,
+				NewCol:         col,
+				TableCharset:   charset,
+				TableCollation: collation,
+			})
+		}
+	}
+	return changes
+}
+
+func getTableCharsetAndCollation(table *ast.CreateTableStmt) (charset, collation string) {
+	for _, op := range table.Options {
+		switch op.Tp {
+		case ast.TableOptionCharset:
+			charset = op.StrValue
+		case ast.TableOptionCollate:
+			collation = op.StrValue
+		}
+	}
+	return charset, collation
+}
+
+// column type families, the change across the incompatible families is always treated
+// as narrowing, see isNarrowingAcrossFamily.
+const (
+	columnTypeFamilyInteger = iota
+	columnTypeFamilyDecimal
+	columnTypeFamilyFloat
+	columnTypeFamilyString
+	columnTypeFamilyTime
+	columnTypeFamilyOther
+)
+
+func getColumnTypeFamily(tp byte) int {
+	switch {
+	case mysql.IsIntegerType(tp):
+		return columnTypeFamilyInteger
+	case tp == mysql.TypeNewDecimal:
+		return columnTypeFamilyDecimal
+	case tp == mysql.TypeFloat || tp == mysql.TypeDouble:
+		return columnTypeFamilyFloat
+	case types.IsTypeChar(tp) || types.IsTypeBlob(tp) || tp == mysql.TypeEnum || tp == mysql.TypeSet:
+		return columnTypeFamilyString
+	case tp == mysql.TypeDate || tp == mysql.TypeDatetime || tp == mysql.TypeTimestamp:
+		return columnTypeFamilyTime
+	default:
+		return columnTypeFamilyOther
+	}
+}
+
+var integerTypeBytes = map[byte]int{
+	mysql.TypeTiny:     1,
+	mysql.TypeShort:    2,
+	mysql.TypeInt24:    3,
+	mysql.TypeLong:     4,
+	mysql.TypeLonglong: 8,
+}
+
+// blobTypeLength is the max bytes of TEXT and BLOB.
+var blobTypeLength = map[byte]int{
+	mysql.TypeTinyBlob:   1<<8 - 1,
+	mysql.TypeBlob:       1<<16 - 1,
+	mysql.TypeMediumBlob: 1<<24 - 1,
+	mysql.TypeLongBlob:   1<<32 - 1,
+}
+
+// IsNarrowing returns true if the new column type can not hold all values of the old
+// column type, the values may be truncated or the execution may fail.
+func (c *ColumnChange) IsNarrowing() bool {
+	oldTp, newTp := c.OldCol.Tp, c.NewCol.Tp
+	family := getColumnTypeFamily(oldTp.Tp)
+	if family != getColumnTypeFamily(newTp.Tp) {
+		return isNarrowingAcrossFamily(oldTp, newTp)
+	}
+	switch family {
+	case columnTypeFamilyInteger:
+		return integerTypeBytes[newTp.Tp] < integerTypeBytes[oldTp.Tp]
+	case columnTypeFamilyDecimal:
+		oldFlen, oldDecimal := getColumnLengthAndDecimal(oldTp)
+		newFlen, newDecimal := getColumnLengthAndDecimal(newTp)
+		return newDecimal < oldDecimal || newFlen-newDecimal < oldFlen-oldDecimal
+	case columnTypeFamilyFloat:
+		return oldTp.Tp == mysql.TypeDouble && newTp.Tp == mysql.TypeFloat
+	case columnTypeFamilyString:
+		if oldTp.Tp == mysql.TypeEnum || oldTp.Tp == mysql.TypeSet {
+			if newTp.Tp != oldTp.Tp {
+				// the members are converted to strings, it is narrowing if the string is shorter.
+				return getStringTypeLength(newTp) < getMaxElemLength(oldTp)
+			}
+			return !isSubset(oldTp.Elems, newTp.Elems)
+		}
+		if newTp.Tp == mysql.TypeEnum || newTp.Tp == mysql.TypeSet {
+			return true
+		}
+		return c.isStringNarrowing()
+	case columnTypeFamilyTime:
+		_, oldFsp := getColumnLengthAndDecimal(oldTp)
+		_, newFsp := getColumnLengthAndDecimal(newTp)
+		if newFsp < oldFsp {
+			return true
+		}
+		// TIMESTAMP only covers the range from 1970 to 2038.
+		if oldTp.Tp != mysql.TypeTimestamp && newTp.Tp == mysql.TypeTimestamp {
+			return true
+		}
+		// the time part is lost if the DATETIME or TIMESTAMP is changed to DATE.
+		return oldTp.Tp != mysql.TypeDate && newTp.Tp == mysql.TypeDate
+	default:
+		return oldTp.Tp != newTp.Tp || oldTp.Flen > newTp.Flen
+	}
+}
+
+// charsetMaxLength is the max bytes of a character in the common character sets.
+var charsetMaxLength = map[string]int{
+	"utf8":    3,
+	"utf8mb3": 3,
+	"utf8mb4": 4,
+	"latin1":  1,
+	"ascii":   1,
+	"binary":  1,
+	"gbk":     2,
+	"gb2312":  2,
+	"big5":    2,
+	"ucs2":    2,
+	"utf16":   4,
+	"utf32":   4,
+}
+
+// isStringNarrowing compares the length of the string types. The length of TEXT and BLOB is
+// in bytes while the length of the others is in characters, the characters are converted to
+// bytes by the max length of the column's character set when they are compared with bytes.
+func (c *ColumnChange) isStringNarrowing() bool {
+	oldTp, newTp := c.OldCol.Tp, c.NewCol.Tp
+	oldLength, newLength := getStringTypeLength(oldTp), getStringTypeLength(newTp)
+	_, oldInBytes := blobTypeLength[oldTp.Tp]
+	_, newInBytes := blobTypeLength[newTp.Tp]
+	if !oldInBytes && newInBytes {
+		oldLength *= getCharsetMaxLength(getColumnCharset(c.OldCol, c.TableCharset))
+	}
+	// the bytes of TEXT may all be single-byte characters, so they are compared with the characters directly.
+	return newLength < oldLength
+}
+
+// getCharsetMaxLength returns the max bytes of a character, it is 1 if the character set is unknown.
+func getCharsetMaxLength(charset string) int {
+	if length, ok := charsetMaxLength[strings.ToLower(charset)]; ok {
+		return length
+	}
+	return 1
+}
+
+// floatTypeDigits is the number of significant decimal digits which FLOAT and DOUBLE can
+// hold without losing precision.
+var floatTypeDigits = map[byte]int{
+	mysql.TypeFloat:  7,
+	mysql.TypeDouble: 15,
+}
+
+// floatTypeStringLength is the max length of FLOAT and DOUBLE converted to string, e.g. "-2.2250738585072014e-308".
+var floatTypeStringLength = map[byte]int{
+	mysql.TypeFloat:  12,
+	mysql.TypeDouble: 24,
+}
+
+// isNarrowingAcrossFamily compares the range and precision of the types in different
+// families. The number and the time can be converted to a string which is long enough,
+// the integer, DECIMAL, FLOAT and DOUBLE can be converted to each other if the digits
+// are kept, the other changes are always narrowing.
+func isNarrowingAcrossFamily(oldTp, newTp *types.FieldType) bool {
+	oldFamily, newFamily := getColumnTypeFamily(oldTp.Tp), getColumnTypeFamily(newTp.Tp)
+	if newFamily == columnTypeFamilyString {
+		if newTp.Tp == mysql.TypeEnum || newTp.Tp == mysql.TypeSet {
+			return true
+		}
+		length, ok := getStringLengthOfValue(oldTp)
+		return !ok || getStringTypeLength(newTp) < length
+	}
+	if !isNumericColumnType(oldTp.Tp) || !isNumericColumnType(newTp.Tp) {
+		return true
+	}
+	// the precision of FLOAT and DOUBLE is approximate, it can't be kept by the exact types.
+	if oldFamily == columnTypeFamilyFloat {
+		return true
+	}
+
+	oldFlen, oldDecimal := getColumnLengthAndDecimal(oldTp)
+	if oldFamily == columnTypeFamilyInteger {
+		oldFlen, oldDecimal = getIntegerTypeDigits(oldTp), 0
+	}
+	switch newFamily {
+	case columnTypeFamilyInteger:
+		// the max value of the integer type is not 99..9, so the digits of DECIMAL should be less.
+		return oldDecimal > 0 || oldFlen-oldDecimal >= getIntegerTypeDigits(newTp)
+	case columnTypeFamilyDecimal:
+		newFlen, newDecimal := getColumnLengthAndDecimal(newTp)
+		return newDecimal < oldDecimal || newFlen-newDecimal < oldFlen-oldDecimal
+	default:
+		return oldFlen > floatTypeDigits[newTp.Tp]
+	}
+}
+
+// getIntegerTypeDigits returns the number of digits of the max value of the integer type.
+func getIntegerTypeDigits(tp *types.FieldType) int {
+	bits := uint(integerTypeBytes[tp.Tp] * 8)
+	if !mysql.HasUnsignedFlag(tp.Flag) {
+		bits--
+	}
+	max := uint64(math.MaxUint64)
+	if bits < 64 {
+		max = 1<<bits - 1
+	}
+	return len(strconv.FormatUint(max, 10))
+}
+
+// getStringLengthOfValue returns the max length of the value of the number or time type
+// converted to string, it returns false if the length is unknown.
+func getStringLengthOfValue(tp *types.FieldType) (int, bool) {
+	signLength := 1
+	if mysql.HasUnsignedFlag(tp.Flag) {
+		signLength = 0
+	}
+	switch getColumnTypeFamily(tp.Tp) {
+	case columnTypeFamilyInteger:
+		return getIntegerTypeDigits(tp) + signLength, true
+	case columnTypeFamilyDecimal:
+		flen, decimal := getColumnLengthAndDecimal(tp)
+		if decimal > 0 {
+			// the decimal point.
+			flen++
+		}
+		return flen + signLength, true
+	case columnTypeFamilyFloat:
+		return floatTypeStringLength[tp.Tp], true
+	case columnTypeFamilyTime:
+		if tp.Tp == mysql.TypeDate {
+			// "2006-01-02"
+			return 10, true
+		}
+		// "2006-01-02 15:04:05.000000"
+		_, fsp := getColumnLengthAndDecimal(tp)
+		if fsp > 0 {
+			return 19 + 1 + fsp, true
+		}
+		return 19, true
+	default:
+		return 0, false
+	}
+}
+
+// IsSignChanged returns true if the numeric column is changed between SIGNED and UNSIGNED.
+func (c *ColumnChange) IsSignChanged() bool {
+	oldTp, newTp := c.OldCol.Tp, c.NewCol.Tp
+	if !isNumericColumnType(oldTp.Tp) || !isNumericColumnType(newTp.Tp) {
+		return false
+	}
+	return mysql.HasUnsignedFlag(oldTp.Flag) != mysql.HasUnsignedFlag(newTp.Flag)
+}
+
+// GetCharsetChange returns the old and new character set or collation if the string column
+// is converted, the column which character set is not specified in MODIFY/CHANGE COLUMN
+// inherits the table's, so it may be converted implicitly.
+func (c *ColumnChange) GetCharsetChange() (from, to string, changed bool) {
+	if !isCharsetColumnType(c.OldCol.Tp) || !isCharsetColumnType(c.NewCol.Tp) {
+		return "", "", false
+	}
+	oldCharset := getColumnCharset(c.OldCol, c.TableCharset)
+	newCharset := getColumnCharset(c.NewCol, c.TableCharset)
+	if oldCharset != "" && newCharset != "" && !strings.EqualFold(oldCharset, newCharset) {
+		return oldCharset, newCharset, true
+	}
+	newCollation := getColumnCollation(c.NewCol, "")
+	if newCollation == "" {
+		return "", "", false
+	}
+	oldCollation := getColumnCollation(c.OldCol, c.TableCollation)
+	if oldCollation != "" {
+		if !strings.EqualFold(oldCollation, newCollation) {
+			return oldCollation, newCollation, true
+		}
+		return "", "", false
+	}
+	// the old column uses the default collation of the character set.
+	if oldCharset != "" && !isDefaultCollation(oldCharset, newCollation) {
+		return oldCharset, newCollation, true
+	}
+	return "", "", false
+}
+
+// defaultCollations is the default collations of the common character sets, the default
+// collation of utf8mb4 is changed in MySQL 8.0.
+var defaultCollations = map[string][]string{
+	"utf8":    {"utf8_general_ci"},
+	"utf8mb3": {"utf8mb3_general_ci", "utf8_general_ci"},
+	"utf8mb4": {"utf8mb4_general_ci", "utf8mb4_0900_ai_ci"},
+	"latin1":  {"latin1_swedish_ci"},
+	"gbk":     {"gbk_chinese_ci"},
+	"ascii":   {"ascii_general_ci"},
+}
+
+func isDefaultCollation(charset, collation string) bool {
+	collations, ok := defaultCollations[strings.ToLower(charset)]
+	if !ok {
+		// the default collation is unknown, assume it is not changed.
+		return true
+	}
+	for _, c := range collations {
+		if strings.EqualFold(c, collation) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSetNotNullWithoutDefault returns true if the nullable column is changed to NOT NULL
+// without default value, the existing NULL values make the execution fail in strict mode.
+func (c *ColumnChange) IsSetNotNullWithoutDefault() bool {
+	if isColumnNotNull(c.OldCol) || !isColumnNotNull(c.NewCol) {
+		return false
+	}
+	return !util.HasOneInOptions(c.NewCol.Options, ast.ColumnOptionDefaultValue, ast.ColumnOptionAutoIncrement)
+}
+
+func isColumnNotNull(col *ast.ColumnDef) bool {
+	return util.HasOneInOptions(col.Options, ast.ColumnOptionNotNull, ast.ColumnOptionPrimaryKey)
+}
+
+func isNumericColumnType(tp byte) bool {
+	switch getColumnTypeFamily(tp) {
+	case columnTypeFamilyInteger, columnTypeFamilyDecimal, columnTypeFamilyFloat:
+		return true
+	default:
+		return false
+	}
+}
+
+func isCharsetColumnType(tp *types.FieldType) bool {
+	if getColumnTypeFamily(tp.Tp) != columnTypeFamilyString {
+		return false
+	}
+	// BLOB, BINARY and VARBINARY are binary strings.
+	return !strings.EqualFold(tp.Charset, "binary")
+}
+
+func getColumnCharset(col *ast.ColumnDef, tableCharset string) string {
+	if col.Tp.Charset != "" {
+		return col.Tp.Charset
+	}
+	return tableCharset
+}
+
+func getColumnCollation(col *ast.ColumnDef, tableCollation string) string {
+	for _, op := range col.Options {
+		if op.Tp == ast.ColumnOptionCollate {
+			return op.StrValue
+		}
+	}
+	if col.Tp.Collate != "" {
+		return col.Tp.Collate
+	}
+	// the default collation of the column's character set is used if the character set is
+	// specified, it is unknown here.
+	if col.Tp.Charset != "" {
+		return ""
+	}
+	return tableCollation
+}
+
+func getColumnLengthAndDecimal(tp *types.FieldType) (flen, decimal int) {
+	defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(tp.Tp)
+	flen, decimal = tp.Flen, tp.Decimal
+	if flen == types.UnspecifiedLength {
+		flen = defaultFlen
+	}
+	if decimal == types.UnspecifiedLength {
+		decimal = defaultDecimal
+	}
+	if decimal < 0 {
+		decimal = 0
+	}
+	return flen, decimal
+}
+
+// getStringTypeLength returns the max length of the string type, it is the number of
+// characters for CHAR and VARCHAR, and the number of bytes for TEXT and BLOB.
+func getStringTypeLength(tp *types.FieldType) int {
+	if length, ok := blobTypeLength[tp.Tp]; ok {
+		return length
+	}
+	if tp.Tp == mysql.TypeEnum || tp.Tp == mysql.TypeSet {
+		return getMaxElemLength(tp)
+	}
+	if tp.Flen == types.UnspecifiedLength {
+		// CHAR is CHAR(1) by default.
+		return 1
+	}
+	return tp.Flen
+}
+
+func getMaxElemLength(tp *types.FieldType) int {
+	max := 0
+	if tp.Tp == mysql.TypeSet {
+		// the value of SET is the members separated by commas.
+		max = len(tp.Elems) - 1
+		for _, elem := range tp.Elems {
+			max += len(elem)
+		}
+		return max
+	}
+	for _, elem := range tp.Elems {
+		if len(elem) > max {
+			max = len(elem)
+		}
+	}
+	return max
+}
+
+func isSubset(elems, of []string) bool {
+	set := make(map[string]struct{}, len(of))
+	for _, elem := range of {
+		set[elem] = struct{}{}
+	}
+	for _, elem := range elems {
+		if _, ok := set[elem]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func getColumnTypeDesc(col *ast.ColumnDef) string {
+	return strings.ToUpper(col.Tp.String())
+}
+
+func checkColumnTypeChange(ctx *session.Context, rule driver.Rule, res *driver.AuditResult, node ast.Node) error {
+	stmt, ok := node.(*ast.AlterTableStmt)
+	if !ok {
+		return nil
+	}
+	table, exist, err := ctx.GetCreateTableStmt(stmt.Table)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return nil
+	}
+	var descs []string
+	for _, change := range GetColumnChanges(stmt, table) {
+		switch rule.Name {
+		case DDLCheckColumnTypeNarrowing:
+			if change.IsNarrowing() {
+				descs = append(descs, fmt.Sprintf("%s(%s -> %s)", change.Name,
+					getColumnTypeDesc(change.OldCol), getColumnTypeDesc(change.NewCol)))
+			}
+		case DDLCheckColumnSignChange:
+			if change.IsSignChanged() {
+				descs = append(descs, fmt.Sprintf("%s(%s -> %s)", change.Name,
+					getColumnTypeDesc(change.OldCol), getColumnTypeDesc(change.NewCol)))
+			}
+		case DDLCheckColumnCharsetChange:
+			if from, to, changed := change.GetCharsetChange(); changed {
+				descs = append(descs, fmt.Sprintf("%s(%s -> %s)", change.Name, from, to))
+			}
+		case DDLCheckColumnSetNotNullWithoutDefault:
+			if change.IsSetNotNullWithoutDefault() {
+				descs = append(descs, change.Name)
+			}
+		}
+	}
+	if len(descs) > 0 {
+		addResult(res, rule, rule.Name, strings.Join(descs, ","))
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnChange(t *testing.T) {
+	tableNode, err := util.ParseOneSql(`create table t1(id bigint unsigned not null primary key,
+v1 varchar(255) not null, v2 varchar(255) character set utf8, v3 int, v4 decimal(10,2), v5 datetime(3),
+v6 enum('a','b'), v7 text, v8 double, v9 varbinary(32), v12 varchar(200), v13 varchar(200) character set latin1) default charset=utf8mb4`)
+	assert.NoError(t, err)
+	table := tableNode.(*ast.CreateTableStmt)
+
+	args := []struct {
+		sql           string
+		narrowing     bool
+		signChanged   bool
+		charsetChange bool
+		setNotNull    bool
+	}{
+		{"alter table t1 modify column v1 varchar(50) not null", true, false, false, false},
+		{"alter table t1 modify column v1 varchar(512) not null", false, false, false, false},
+		{"alter table t1 modify column v1 text not null", false, false, false, false},
+		{"alter table t1 modify column v1 tinytext not null", true, false, false, false},
+		{"alter table t1 modify column v12 tinytext", true, false, false, false},
+		{"alter table t1 modify column v13 tinytext character set latin1", false, false, false, false},
+		{"alter table t1 modify column v7 varchar(255)", true, false, false, false},
+		{"alter table t1 modify column id int unsigned not null", true, false, false, false},
+		{"alter table t1 modify column id bigint not null", false, true, false, false},
+		{"alter table t1 modify column v3 bigint", false, false, false, false},
+		{"alter table t1 modify column v3 int unsigned", false, true, false, false},
+		{"alter table t1 modify column v3 varchar(32)", false, false, false, false},
+		{"alter table t1 modify column v3 varchar(10)", true, false, false, false},
+		{"alter table t1 modify column v3 decimal(10,0)", false, false, false, false},
+		{"alter table t1 modify column v3 decimal(10,2)", true, false, false, false},
+		{"alter table t1 modify column v3 double", false, false, false, false},
+		{"alter table t1 modify column v3 float", true, false, false, false},
+		{"alter table t1 modify column id decimal(20,0) unsigned not null", false, false, false, false},
+		{"alter table t1 modify column id double not null", true, true, false, false},
+		{"alter table t1 modify column v4 bigint", true, false, false, false},
+		{"alter table t1 modify column v4 varchar(12)", false, false, false, false},
+		{"alter table t1 modify column v4 varchar(11)", true, false, false, false},
+		{"alter table t1 modify column v4 double", false, false, false, false},
+		{"alter table t1 modify column v8 decimal(30,10)", true, false, false, false},
+		{"alter table t1 modify column v8 varchar(24)", false, false, false, false},
+		{"alter table t1 modify column v5 varchar(23)", false, false, false, false},
+		{"alter table t1 modify column v5 varchar(19)", true, false, false, false},
+		{"alter table t1 modify column v1 int not null", true, false, false, false},
+		{"alter table t1 modify column v5 bigint", true, false, false, false},
+		{"alter table t1 modify column v4 decimal(12,2)", false, false, false, false},
+		{"alter table t1 modify column v4 decimal(10,1)", true, false, false, false},
+		{"alter table t1 modify column v4 decimal(10,4)", true, false, false, false},
+		{"alter table t1 modify column v5 datetime", true, false, false, false},
+		{"alter table t1 modify column v5 date", true, false, false, false},
+		{"alter table t1 modify column v5 timestamp(3)", true, false, false, false},
+		{"alter table t1 modify column v5 datetime(6)", false, false, false, false},
+		{"alter table t1 modify column v6 enum('a','b','c')", false, false, false, false},
+		{"alter table t1 modify column v6 enum('a')", true, false, false, false},
+		{"alter table t1 modify column v6 varchar(10)", false, false, false, false},
+		{"alter table t1 modify column v8 float", true, false, false, false},
+		{"alter table t1 modify column v9 varbinary(64)", false, false, false, false},
+		{"alter table t1 modify column v2 varchar(255) character set utf8", false, false, false, false},
+		{"alter table t1 modify column v2 varchar(255)", false, false, true, false},
+		{"alter table t1 modify column v1 varchar(255) character set latin1 not null", false, false, true, false},
+		{"alter table t1 modify column v1 varchar(255) collate utf8mb4_bin not null", false, false, true, false},
+		{"alter table t1 modify column v1 varchar(255) collate utf8mb4_general_ci not null", false, false, false, false},
+		{"alter table t1 modify column v2 varchar(255) character set utf8 not null", false, false, false, true},
+		{"alter table t1 modify column v3 int not null default 0", false, false, false, false},
+		{"alter table t1 change column v3 v10 int not null", false, false, false, true},
+		{"alter table t1 modify column v11 int not null", false, false, false, false},
+	}
+	for _, arg := range args {
+		node, err := util.ParseOneSql(arg.sql)
+		assert.NoError(t, err)
+		narrowing, signChanged, charsetChange, setNotNull := false, false, false, false
+		for _, change := range GetColumnChanges(node.(*ast.AlterTableStmt), table) {
+			narrowing = narrowing || change.IsNarrowing()
+			signChanged = signChanged || change.IsSignChanged()
+			_, _, changed := change.GetCharsetChange()
+			charsetChange = charsetChange || changed
+			setNotNull = setNotNull || change.IsSetNotNullWithoutDefault()
+		}
+		assert.Equal(t, arg.narrowing, narrowing, arg.sql)
+		assert.Equal(t, arg.signChanged, signChanged, arg.sql)
+		assert.Equal(t, arg.charsetChange, charsetChange, arg.sql)
+		assert.Equal(t, arg.setNotNull, setNotNull, arg.sql)
+	}
+}
@@ -0,0 +1,228 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/pingcap/parser/ast"
+)
+
+// customRuleRegexps caches the compiled regexps of custom rules, key is the pattern.
+var customRuleRegexps sync.Map
+
+func compileCustomRuleRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := customRuleRegexps.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	customRuleRegexps.Store(pattern, re)
+	return re, nil
+}
+
+// CheckCustomRule audits node by the custom rule, the description of rule is used
+// as audit message when the rule is matched.
+func CheckCustomRule(rule driver.Rule, m *driver.CustomRuleMatcher, res *driver.AuditResult, node ast.Node) error {
+	if len(m.StmtTypes) > 0 && !containsStmtType(m.StmtTypes, getCustomRuleStmtType(node)) {
+		return nil
+	}
+	match, err := matchCustomRule(m, node)
+	if err != nil {
+		return fmt.Errorf("check custom rule %s failed: %v", rule.Name, err)
+	}
+	if match {
+		res.AddWithRule(rule, "%s", rule.Desc)
+	}
+	return nil
+}
+
+func containsStmtType(stmtTypes []string, stmtType string) bool {
+	for _, t := range stmtTypes {
+		if t == stmtType {
+			return true
+		}
+	}
+	return false
+}
+
+func matchCustomRule(m *driver.CustomRuleMatcher, node ast.Node) (bool, error) {
+	switch m.MatchType {
+	case driver.CustomRuleMatchTypeSQLRegex:
+		re, err := compileCustomRuleRegexp(m.Matcher)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(node.Text()), nil
+	case driver.CustomRuleMatchTypeFingerprintRegex:
+		re, err := compileCustomRuleRegexp(m.Matcher)
+		if err != nil {
+			return false, err
+		}
+		fingerprint, err := util.Fingerprint(node.Text(), true)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(fingerprint), nil
+	case driver.CustomRuleMatchTypeASTPredicate:
+		p, err := m.ASTPredicate()
+		if err != nil {
+			return false, err
+		}
+		return matchCustomRuleASTPredicate(p, node)
+	default:
+		return false, fmt.Errorf("unknown match type %s", m.MatchType)
+	}
+}
+
+// getCustomRuleStmtType returns the statement type of node which is used by custom rule.
+func getCustomRuleStmtType(node ast.Node) string {
+	switch stmt := node.(type) {
+	case *ast.SelectStmt, *ast.UnionStmt:
+		return driver.CustomRuleStmtTypeSelect
+	case *ast.InsertStmt:
+		if stmt.IsReplace {
+			return driver.CustomRuleStmtTypeReplace
+		}
+		return driver.CustomRuleStmtTypeInsert
+	case *ast.UpdateStmt:
+		return driver.CustomRuleStmtTypeUpdate
+	case *ast.DeleteStmt:
+		return driver.CustomRuleStmtTypeDelete
+	case *ast.CreateTableStmt:
+		return driver.CustomRuleStmtTypeCreateTable
+	case *ast.AlterTableStmt:
+		return driver.CustomRuleStmtTypeAlterTable
+	case *ast.DropTableStmt:
+		return driver.CustomRuleStmtTypeDropTable
+	case *ast.TruncateTableStmt:
+		return driver.CustomRuleStmtTypeTruncateTable
+	case *ast.CreateIndexStmt:
+		return driver.CustomRuleStmtTypeCreateIndex
+	case *ast.DropIndexStmt:
+		return driver.CustomRuleStmtTypeDropIndex
+	case *ast.CreateDatabaseStmt:
+		return driver.CustomRuleStmtTypeCreateDatabase
+	case *ast.DropDatabaseStmt:
+		return driver.CustomRuleStmtTypeDropDatabase
+	case *ast.CreateViewStmt:
+		return driver.CustomRuleStmtTypeCreateView
+	default:
+		return driver.CustomRuleStmtTypeOther
+	}
+}
+
+func matchCustomRuleASTPredicate(p *driver.CustomRuleASTPredicate, node ast.Node) (bool, error) {
+	v := &customRuleVisitor{clauses: map[string]bool{}}
+	node.Accept(v)
+
+	if p.TableNamePattern != "" {
+		re, err := compileCustomRuleRegexp(p.TableNamePattern)
+		if err != nil {
+			return false, err
+		}
+		if !anyMatch(re, v.tables) {
+			return false, nil
+		}
+	}
+	if p.ColumnNamePattern != "" {
+		re, err := compileCustomRuleRegexp(p.ColumnNamePattern)
+		if err != nil {
+			return false, err
+		}
+		if !anyMatch(re, v.columns) {
+			return false, nil
+		}
+	}
+	for _, c := range p.HasClauses {
+		if !v.clauses[c] {
+			return false, nil
+		}
+	}
+	for _, c := range p.MissingClauses {
+		if v.clauses[c] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func anyMatch(re *regexp.Regexp, names []string) bool {
+	for _, name := range names {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// customRuleVisitor implements ast.Visitor interface, it collects the table names,
+// column names and clauses of the top level statement.
+type customRuleVisitor struct {
+	depth   int
+	tables  []string
+	columns []string
+	clauses map[string]bool
+}
+
+func (v *customRuleVisitor) Enter(in ast.Node) (node ast.Node, skipChildren bool) {
+	v.depth++
+	switch stmt := in.(type) {
+	case *ast.TableName:
+		v.tables = append(v.tables, stmt.Name.L)
+	case *ast.ColumnName:
+		v.columns = append(v.columns, stmt.Name.L)
+	case *ast.ColumnDef:
+		if stmt.Name != nil {
+			v.columns = append(v.columns, stmt.Name.Name.L)
+		}
+	case *ast.SubqueryExpr:
+		v.clauses[driver.CustomRuleClauseSubquery] = true
+	case *ast.TableSource:
+		switch stmt.Source.(type) {
+		case *ast.SelectStmt, *ast.UnionStmt:
+			v.clauses[driver.CustomRuleClauseSubquery] = true
+		}
+	case *ast.Join:
+		if stmt.Right != nil {
+			v.clauses[driver.CustomRuleClauseJoin] = true
+		}
+	case *ast.SelectStmt:
+		// clauses of sub query are not the clauses of the audited statement.
+		if v.depth == 1 {
+			v.setClauses(stmt.Where, stmt.Limit, stmt.OrderBy, stmt.GroupBy, stmt.Having)
+		}
+	case *ast.UnionStmt:
+		if v.depth == 1 {
+			v.setClauses(nil, stmt.Limit, stmt.OrderBy, nil, nil)
+		}
+	case *ast.UpdateStmt:
+		if v.depth == 1 {
+			v.setClauses(stmt.Where, stmt.Limit, stmt.Order, nil, nil)
+		}
+	case *ast.DeleteStmt:
+		if v.depth == 1 {
+			v.setClauses(stmt.Where, stmt.Limit, stmt.Order, nil, nil)
+		}
+	}
+	return in, false
+}
+
+func (v *customRuleVisitor) setClauses(where ast.ExprNode, limit *ast.Limit, orderBy *ast.OrderByClause,
+	groupBy *ast.GroupByClause, having *ast.HavingClause) {
+	v.clauses[driver.CustomRuleClauseWhere] = where != nil
+	v.clauses[driver.CustomRuleClauseLimit] = limit != nil
+	v.clauses[driver.CustomRuleClauseOrderBy] = orderBy != nil
+	v.clauses[driver.CustomRuleClauseGroupBy] = groupBy != nil
+	v.clauses[driver.CustomRuleClauseHaving] = having != nil
+}
+
+func (v *customRuleVisitor) Leave(in ast.Node) (node ast.Node, ok bool) {
+	v.depth--
+	return in, true
+}
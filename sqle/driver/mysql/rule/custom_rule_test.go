@@ -0,0 +1,117 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCustomRule(t *testing.T) {
+	rule := driver.Rule{
+		Name:     "custom_check_tmp_table",
+		Desc:     "禁止操作临时表",
+		Category: "自定义规则",
+		Level:    driver.RuleLevelWarn,
+	}
+	args := []struct {
+		name      string
+		matcher   *driver.CustomRuleMatcher
+		sql       string
+		isMatched bool
+	}{
+		{
+			name:      "sql regex matched",
+			matcher:   &driver.CustomRuleMatcher{MatchType: driver.CustomRuleMatchTypeSQLRegex, Matcher: `(?i)from\s+tmp_`},
+			sql:       "select * from tmp_t1",
+			isMatched: true,
+		},
+		{
+			name:      "sql regex not matched",
+			matcher:   &driver.CustomRuleMatcher{MatchType: driver.CustomRuleMatchTypeSQLRegex, Matcher: `(?i)from\s+tmp_`},
+			sql:       "select * from t1",
+			isMatched: false,
+		},
+		{
+			name:      "fingerprint regex matched",
+			matcher:   &driver.CustomRuleMatcher{MatchType: driver.CustomRuleMatchTypeFingerprintRegex, Matcher: "WHERE `id`=\\?"},
+			sql:       "select * from t1 where id = 1",
+			isMatched: true,
+		},
+		{
+			name: "stmt type not matched",
+			matcher: &driver.CustomRuleMatcher{
+				MatchType: driver.CustomRuleMatchTypeSQLRegex,
+				Matcher:   "tmp_",
+				StmtTypes: []string{driver.CustomRuleStmtTypeUpdate},
+			},
+			sql:       "select * from tmp_t1",
+			isMatched: false,
+		},
+		{
+			name: "ast predicate matched",
+			matcher: &driver.CustomRuleMatcher{
+				MatchType: driver.CustomRuleMatchTypeASTPredicate,
+				Matcher:   `{"table_name_pattern": "^tmp_", "missing_clauses": ["where"]}`,
+			},
+			sql:       "delete from tmp_t1",
+			isMatched: true,
+		},
+		{
+			name: "ast predicate not matched by clause",
+			matcher: &driver.CustomRuleMatcher{
+				MatchType: driver.CustomRuleMatchTypeASTPredicate,
+				Matcher:   `{"table_name_pattern": "^tmp_", "missing_clauses": ["where"]}`,
+			},
+			sql:       "delete from tmp_t1 where id = 1",
+			isMatched: false,
+		},
+		{
+			name: "ast predicate ignores clauses of sub query",
+			matcher: &driver.CustomRuleMatcher{
+				MatchType: driver.CustomRuleMatchTypeASTPredicate,
+				Matcher:   `{"has_clauses": ["subquery"], "missing_clauses": ["where"]}`,
+			},
+			sql:       "select * from (select * from t1 where id = 1) as t",
+			isMatched: true,
+		},
+		{
+			name: "ast predicate matched by column",
+			matcher: &driver.CustomRuleMatcher{
+				MatchType: driver.CustomRuleMatchTypeASTPredicate,
+				Matcher:   `{"column_name_pattern": "^password$"}`,
+			},
+			sql:       "create table t1(id int, password varchar(32))",
+			isMatched: true,
+		},
+	}
+	for _, arg := range args {
+		t.Run(arg.name, func(t *testing.T) {
+			assert.NoError(t, arg.matcher.Validate())
+			node, err := util.ParseOneSql(arg.sql)
+			assert.NoError(t, err)
+
+			res := driver.NewInspectResults()
+			assert.NoError(t, CheckCustomRule(rule, arg.matcher, res, node))
+			if arg.isMatched {
+				assert.Equal(t, "[warn]禁止操作临时表", res.Message())
+			} else {
+				assert.False(t, res.HasResult())
+			}
+		})
+	}
+}
+
+func TestCustomRuleMatcherValidate(t *testing.T) {
+	invalid := []*driver.CustomRuleMatcher{
+		{MatchType: "unknown", Matcher: "a"},
+		{MatchType: driver.CustomRuleMatchTypeSQLRegex, Matcher: "("},
+		{MatchType: driver.CustomRuleMatchTypeSQLRegex, Matcher: "a", StmtTypes: []string{"unknown"}},
+		{MatchType: driver.CustomRuleMatchTypeASTPredicate, Matcher: "{}"},
+		{MatchType: driver.CustomRuleMatchTypeASTPredicate, Matcher: `{"has_clauses": ["unknown"]}`},
+	}
+	for _, m := range invalid {
+		assert.Error(t, m.Validate())
+	}
+}
@@ -0,0 +1,241 @@
+package rule
+
+import (
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
+)
+
+const (
+	// fixDMLLimitCount is the limit added to delete/update statement without limit.
+	fixDMLLimitCount = 1000
+	// fixTableCommentPlaceholder is the comment added to the table without comment.
+	fixTableCommentPlaceholder = "TODO: 请填写表注释"
+)
+
+// FixSQL rewrites sql to fix the problems in res by the Fix of rule handlers, fixed is
+// empty if no problem is fixed. fixedRules are the rules which problems are fixed. The
+// suppressed problems are not fixed, and the comments of sql are kept in fixed.
+func FixSQL(sql string, rules []*driver.Rule, res *driver.AuditResult) (fixed string, fixedRules []string, err error) {
+	ruleMap := make(map[string]*driver.Rule, len(rules))
+	for _, rule := range rules {
+		ruleMap[rule.Name] = rule
+	}
+
+	var node ast.Node
+	for _, item := range res.Items() {
+		if item.Suppressed {
+			continue
+		}
+		handler, ok := RuleHandlerMap[item.RuleName]
+		if !ok || handler.Fix == nil {
+			continue
+		}
+		rule, ok := ruleMap[item.RuleName]
+		if !ok {
+			continue
+		}
+		if node == nil {
+			// parse a new node, the audited node is kept unchanged.
+			node, err = util.ParseOneSql(sql)
+			if err != nil {
+				return "", nil, err
+			}
+		}
+		if handler.Fix(*rule, node) {
+			fixedRules = append(fixedRules, item.RuleName)
+		}
+	}
+	if len(fixedRules) == 0 {
+		return "", nil, nil
+	}
+	fixed, err = util.RestoreSqlWithComments(node, sql)
+	if err != nil {
+		return "", nil, err
+	}
+	return fixed, fixedRules, nil
+}
+
+func fixIfNotExist(rule driver.Rule, node ast.Node) bool {
+	stmt, ok := node.(*ast.CreateTableStmt)
+	if !ok || stmt.IfNotExists {
+		return false
+	}
+	stmt.IfNotExists = true
+	return true
+}
+
+func fixDMLLimitExist(rule driver.Rule, node ast.Node) bool {
+	limit := &ast.Limit{Count: ast.NewValueExpr(uint64(fixDMLLimitCount), "", "")}
+	switch stmt := node.(type) {
+	case *ast.UpdateStmt:
+		// multiple-table update does not support limit.
+		if stmt.Limit != nil || stmt.MultipleTable {
+			return false
+		}
+		stmt.Limit = limit
+		return true
+	case *ast.DeleteStmt:
+		if stmt.Limit != nil || stmt.IsMultiTable {
+			return false
+		}
+		stmt.Limit = limit
+		return true
+	}
+	return false
+}
+
+func fixTableWithoutComment(rule driver.Rule, node ast.Node) bool {
+	stmt, ok := node.(*ast.CreateTableStmt)
+	if !ok || stmt.ReferTable != nil {
+		return false
+	}
+	for _, option := range stmt.Options {
+		if option.Tp == ast.TableOptionComment {
+			return false
+		}
+	}
+	stmt.Options = append(stmt.Options, &ast.TableOption{
+		Tp:       ast.TableOptionComment,
+		StrValue: fixTableCommentPlaceholder,
+	})
+	return true
+}
+
+func fixPrimaryKeyBigintUnsigned(rule driver.Rule, node ast.Node) bool {
+	stmt, ok := node.(*ast.CreateTableStmt)
+	if !ok {
+		return false
+	}
+	var pkCol *ast.ColumnDef
+	for _, col := range stmt.Cols {
+		if util.IsAllInOptions(col.Options, ast.ColumnOptionPrimaryKey) {
+			pkCol = col
+		}
+	}
+	for _, constraint := range stmt.Constraints {
+		if constraint.Tp == ast.ConstraintPrimaryKey && len(constraint.Keys) == 1 {
+			for _, col := range stmt.Cols {
+				if col.Name.Name.L == constraint.Keys[0].Column.Name.L {
+					pkCol = col
+				}
+			}
+		}
+	}
+	if pkCol == nil {
+		return false
+	}
+	switch pkCol.Tp.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+	default:
+		// only integer primary key can be changed to bigint unsigned mechanically.
+		return false
+	}
+	if pkCol.Tp.Tp == mysql.TypeLonglong && mysql.HasUnsignedFlag(pkCol.Tp.Flag) {
+		return false
+	}
+	pkCol.Tp.Tp = mysql.TypeLonglong
+	pkCol.Tp.Flen = types.UnspecifiedLength
+	pkCol.Tp.Flag |= mysql.UnsignedFlag
+	return true
+}
+
+// fixCharacterSet changes the character set in statement to the expected character set,
+// the collation which does not belong to the expected character set is removed.
+func fixCharacterSet(rule driver.Rule, node ast.Node) bool {
+	expectCS := strings.ToLower(rule.Params.GetParam(DefaultSingleParamKeyName).String())
+	if expectCS == "" {
+		return false
+	}
+	isExpectCollation := func(collation string) bool {
+		return strings.HasPrefix(strings.ToLower(collation), expectCS+"_")
+	}
+
+	var changed bool
+	fixTableOptions := func(options []*ast.TableOption, addIfNotExist bool) []*ast.TableOption {
+		var hasCharset bool
+		ret := make([]*ast.TableOption, 0, len(options)+1)
+		for _, op := range options {
+			switch op.Tp {
+			case ast.TableOptionCharset:
+				hasCharset = true
+				if !strings.EqualFold(op.StrValue, expectCS) {
+					op.StrValue = expectCS
+					changed = true
+				}
+			case ast.TableOptionCollate:
+				if !isExpectCollation(op.StrValue) {
+					changed = true
+					continue
+				}
+			}
+			ret = append(ret, op)
+		}
+		if !hasCharset && addIfNotExist {
+			ret = append(ret, &ast.TableOption{Tp: ast.TableOptionCharset, StrValue: expectCS})
+			changed = true
+		}
+		return ret
+	}
+	fixColumns := func(cols []*ast.ColumnDef) {
+		for _, col := range cols {
+			if col.Tp == nil || col.Tp.Charset == "" || strings.EqualFold(col.Tp.Charset, expectCS) {
+				continue
+			}
+			col.Tp.Charset = expectCS
+			if col.Tp.Collate != "" && !isExpectCollation(col.Tp.Collate) {
+				col.Tp.Collate = ""
+			}
+			changed = true
+		}
+	}
+	fixDatabaseOptions := func(options []*ast.DatabaseOption, addIfNotExist bool) []*ast.DatabaseOption {
+		var hasCharset bool
+		ret := make([]*ast.DatabaseOption, 0, len(options)+1)
+		for _, op := range options {
+			switch op.Tp {
+			case ast.DatabaseOptionCharset:
+				hasCharset = true
+				if !strings.EqualFold(op.Value, expectCS) {
+					op.Value = expectCS
+					changed = true
+				}
+			case ast.DatabaseOptionCollate:
+				if !isExpectCollation(op.Value) {
+					changed = true
+					continue
+				}
+			}
+			ret = append(ret, op)
+		}
+		if !hasCharset && addIfNotExist {
+			ret = append(ret, &ast.DatabaseOption{Tp: ast.DatabaseOptionCharset, Value: expectCS})
+			changed = true
+		}
+		return ret
+	}
+
+	switch stmt := node.(type) {
+	case *ast.CreateTableStmt:
+		if stmt.ReferTable != nil {
+			return false
+		}
+		stmt.Options = fixTableOptions(stmt.Options, true)
+		fixColumns(stmt.Cols)
+	case *ast.AlterTableStmt:
+		for _, spec := range stmt.Specs {
+			spec.Options = fixTableOptions(spec.Options, false)
+			fixColumns(spec.NewColumns)
+		}
+	case *ast.CreateDatabaseStmt:
+		stmt.Options = fixDatabaseOptions(stmt.Options, true)
+	case *ast.AlterDatabaseStmt:
+		stmt.Options = fixDatabaseOptions(stmt.Options, false)
+	}
+	return changed
+}
@@ -0,0 +1,85 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixSQL(t *testing.T) {
+	charsetRule := RuleHandlerMap[DDLCheckTableCharacterSet].Rule
+	limitRule := RuleHandlerMap[DMLCheckLimitMustExist].Rule
+	ifNotExistRule := RuleHandlerMap[DDLCheckPKWithoutIfNotExists].Rule
+	rules := []*driver.Rule{&charsetRule, &limitRule, &ifNotExistRule}
+
+	args := []struct {
+		name       string
+		sql        string
+		ruleNames  []string
+		fixed      string
+		fixedRules []string
+		// applySuppressed applies the suppression hints of sql to the results.
+		applySuppressed bool
+	}{
+		{
+			name:       "add limit to delete",
+			sql:        "delete from t1 where id > 1",
+			ruleNames:  []string{DMLCheckLimitMustExist},
+			fixed:      "DELETE FROM `t1` WHERE `id`>1 LIMIT 1000",
+			fixedRules: []string{DMLCheckLimitMustExist},
+		},
+		{
+			name:      "multi-table delete is not fixed",
+			sql:       "delete t1 from t1 join t2 on t1.id = t2.id",
+			ruleNames: []string{DMLCheckLimitMustExist},
+		},
+		{
+			name:       "add if not exists and charset to create table",
+			sql:        "create table t1(id int) charset=latin1 collate=latin1_bin",
+			ruleNames:  []string{DDLCheckPKWithoutIfNotExists, DDLCheckTableCharacterSet},
+			fixed:      "CREATE TABLE IF NOT EXISTS `t1` (`id` INT) DEFAULT CHARACTER SET = UTF8MB4",
+			fixedRules: []string{DDLCheckPKWithoutIfNotExists, DDLCheckTableCharacterSet},
+		},
+		{
+			name:       "comments are kept",
+			sql:        "/* task */ delete from t1 where id > 1 /* sqle:ignore dml_check_where_is_invalid */ -- end",
+			ruleNames:  []string{DMLCheckLimitMustExist},
+			fixed:      "/* task */ DELETE FROM `t1` WHERE `id`>1 LIMIT 1000 /* sqle:ignore dml_check_where_is_invalid */ -- end",
+			fixedRules: []string{DMLCheckLimitMustExist},
+		},
+		{
+			name:       "comment in string is not moved",
+			sql:        "delete from t1 where name = '/* a */'",
+			ruleNames:  []string{DMLCheckLimitMustExist},
+			fixed:      "DELETE FROM `t1` WHERE `name`='/* a */' LIMIT 1000",
+			fixedRules: []string{DMLCheckLimitMustExist},
+		},
+		{
+			name:            "suppressed problem is not fixed",
+			sql:             "delete from t1 where id > 1 /* sqle:ignore dml_check_limit_must_exist */",
+			ruleNames:       []string{DMLCheckLimitMustExist},
+			applySuppressed: true,
+		},
+		{
+			name:      "rule without fix",
+			sql:       "select * from t1",
+			ruleNames: []string{DMLCheckWhereIsInvalid},
+		},
+	}
+	for _, arg := range args {
+		t.Run(arg.name, func(t *testing.T) {
+			res := driver.NewInspectResults()
+			for _, name := range arg.ruleNames {
+				res.AddWithRule(RuleHandlerMap[name].Rule, "%s", name)
+			}
+			if arg.applySuppressed {
+				res.ApplySuppressions(arg.sql)
+			}
+			fixed, fixedRules, err := FixSQL(arg.sql, rules, res)
+			assert.NoError(t, err)
+			assert.Equal(t, arg.fixed, fixed)
+			assert.Equal(t, arg.fixedRules, fixedRules)
+		})
+	}
+}
@@ -0,0 +1,509 @@
+package rule
+
+import "github.com/actiontech/sqle/sqle/driver"
+
+// ruleKnowledges is the knowledge base of rules, it is attached to the rule in RuleHandlers
+// by rule name, every rule should have its knowledge.
+var ruleKnowledges = map[string]driver.RuleKnowledge{
+	DDLCheckPKNotExist: {
+		Rationale: "InnoDB 使用主键组织数据(聚簇索引), 没有显式主键时会使用隐藏的 row_id, 该值全局共享且无法被查询使用。" +
+			"没有主键的表在基于 ROW 格式的复制中, 从库回放 UPDATE/DELETE 时可能需要全表扫描, 导致严重的复制延迟; 同时也无法使用 gh-ost、pt-osc 等工具进行在线变更。",
+		BadExample:  "CREATE TABLE t1(name VARCHAR(32) NOT NULL DEFAULT '');",
+		GoodExample: "CREATE TABLE t1(id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT, name VARCHAR(32) NOT NULL DEFAULT '', PRIMARY KEY(id));",
+		Remediation: "为表添加主键, 没有合适的业务主键时添加自增列作为主键。",
+	},
+	DDLCheckPKWithoutAutoIncrement: {
+		Rationale:   "InnoDB 按主键顺序存储数据, 自增主键使新数据总是追加在索引末尾; 随机的主键(如 UUID)会导致频繁的页分裂和碎片, 降低写入性能并增加空间占用。",
+		BadExample:  "CREATE TABLE t1(id VARCHAR(36) NOT NULL, PRIMARY KEY(id));",
+		GoodExample: "CREATE TABLE t1(id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT, PRIMARY KEY(id));",
+		Remediation: "主键列添加 AUTO_INCREMENT 属性, 原有的业务唯一值可以改为唯一索引。",
+	},
+	DDLDisableFK: {
+		Rationale:   "外键约束的检查在数据库中完成, 写入时需要额外加锁访问父表, 高并发下容易产生锁等待和死锁; 外键也会让表结构变更、数据迁移和分库分表变得困难。",
+		BadExample:  "CREATE TABLE orders(id BIGINT PRIMARY KEY, user_id BIGINT, FOREIGN KEY(user_id) REFERENCES users(id));",
+		GoodExample: "CREATE TABLE orders(id BIGINT PRIMARY KEY, user_id BIGINT, KEY idx_user_id(user_id));",
+		Remediation: "去掉外键约束, 为关联列建立普通索引, 在应用层保证数据的一致性。",
+	},
+	DMLCheckWhereIsInvalid: {
+		Rationale:   "没有 WHERE 条件或条件恒为真(如 WHERE 1=1)的 UPDATE/DELETE 会修改全表数据, 通常是拼接 SQL 时遗漏了条件, 一旦执行难以恢复; 同样的 SELECT 会扫描全表。",
+		BadExample:  "DELETE FROM t1 WHERE 1=1;",
+		GoodExample: "DELETE FROM t1 WHERE id = 1;",
+		Remediation: "为语句添加有效的过滤条件; 如果确实需要清空表, 使用 TRUNCATE TABLE 并通过单独的流程审批。",
+	},
+	DDLCheckColumnWithoutDefault: {
+		Rationale:   "没有默认值的列在 INSERT 未指定该列时, 在严格模式下会报错, 在非严格模式下会写入隐式的默认值, 行为依赖于 sql_mode, 容易出现不一致的数据。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY AUTO_INCREMENT, name VARCHAR(32) NOT NULL);",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY AUTO_INCREMENT, name VARCHAR(32) NOT NULL DEFAULT '');",
+		Remediation: "为自增列和 BLOB/TEXT 以外的列添加 DEFAULT 子句。",
+	},
+	DMLCheckWithLimit: {
+		Rationale:   "UPDATE/DELETE 带 LIMIT 时, 被修改的行取决于执行计划, 在 STATEMENT 格式的 binlog 下主从可能修改不同的行, 造成数据不一致; 也无法生成准确的回滚语句。",
+		BadExample:  "DELETE FROM t1 WHERE create_time < '2022-01-01' LIMIT 1000;",
+		GoodExample: "DELETE FROM t1 WHERE create_time < '2022-01-01' AND id BETWEEN 1 AND 1000;",
+		Remediation: "使用确定的条件(如主键范围)代替 LIMIT 分批修改数据。",
+	},
+	DMLCheckWithOrderBy: {
+		Rationale:   "UPDATE/DELETE 中的 ORDER BY 会引入排序, 增加执行时间和锁的持有时间, 通常只在配合 LIMIT 时才有意义, 而 LIMIT 同样不建议使用。",
+		BadExample:  "UPDATE t1 SET status = 1 WHERE status = 0 ORDER BY id;",
+		GoodExample: "UPDATE t1 SET status = 1 WHERE status = 0;",
+		Remediation: "去掉 ORDER BY, 通过确定的过滤条件指定需要修改的行。",
+	},
+	DMLCheckInsertColumnsExist: {
+		Rationale:   "不指定列的 INSERT 依赖表中列的顺序, 表结构变更(如增加列)后语句会报错或把值写入错误的列。",
+		BadExample:  "INSERT INTO t1 VALUES(1, 'a');",
+		GoodExample: "INSERT INTO t1(id, name) VALUES(1, 'a');",
+		Remediation: "在 INSERT 语句中显式列出需要写入的列。",
+	},
+	DMLDisableSelectAllColumn: {
+		Rationale:   "SELECT * 会读取不需要的列, 增加网络传输和内存消耗, 并且无法使用覆盖索引; 表结构变更后结果集也会随之变化, 可能导致应用解析出错。",
+		BadExample:  "SELECT * FROM t1 WHERE id = 1;",
+		GoodExample: "SELECT id, name FROM t1 WHERE id = 1;",
+		Remediation: "只查询需要的列。",
+	},
+	DMLCheckWhereExistFunc: {
+		Rationale:   "对条件中的列使用函数后, 优化器无法使用该列上的索引, 只能逐行计算后比较, 导致全表扫描。",
+		BadExample:  "SELECT id FROM t1 WHERE DATE(create_time) = '2022-01-01';",
+		GoodExample: "SELECT id FROM t1 WHERE create_time >= '2022-01-01' AND create_time < '2022-01-02';",
+		Remediation: "将函数运算改写到常量一侧, 或者在 MySQL 8.0 中为表达式建立函数索引。",
+	},
+	DMLCheckWhereExistImplicitConversion: {
+		Rationale:   "列与常量的类型不一致时, MySQL 会进行隐式类型转换, 如字符串列与数值比较时会把列转换为数值, 导致该列上的索引无法使用, 并可能匹配到预期之外的行。",
+		BadExample:  "SELECT id FROM t1 WHERE phone = 13800000000;",
+		GoodExample: "SELECT id FROM t1 WHERE phone = '13800000000';",
+		Remediation: "使用与列类型一致的常量进行比较。",
+	},
+	DMLCheckFuzzySearch: {
+		Rationale:   "以 % 开头的 LIKE 条件无法使用 B+ 树索引的有序性, 只能全表或全索引扫描。",
+		BadExample:  "SELECT id FROM t1 WHERE name LIKE '%abc%';",
+		GoodExample: "SELECT id FROM t1 WHERE name LIKE 'abc%';",
+		Remediation: "改为右模糊匹配; 确实需要全文检索时使用全文索引或搜索引擎。",
+	},
+	DDLCheckIsExistLimitOffset: {
+		Rationale:   "LIMIT M,N 需要先读取并丢弃前 M 行, 偏移量越大越慢, 深分页时会扫描大量无用的数据。",
+		BadExample:  "SELECT id, name FROM t1 ORDER BY id LIMIT 100000, 10;",
+		GoodExample: "SELECT id, name FROM t1 WHERE id > 100000 ORDER BY id LIMIT 10;",
+		Remediation: "记录上一页最后一行的排序值, 使用条件加 LIMIT N 的方式翻页。",
+	},
+	DDLDisableDropStatement: {
+		Rationale:   "DROP DATABASE/TABLE 会直接删除数据和表结构, 无法通过回滚语句恢复, 误操作的代价极高。",
+		BadExample:  "DROP TABLE t1;",
+		GoodExample: "RENAME TABLE t1 TO t1_deprecated_20220101;",
+		Remediation: "先将表重命名并观察一段时间, 确认没有访问后再通过单独的流程删除。",
+	},
+	DDLCheckColumnTypeNarrowing: {
+		Rationale:   "缩小列的类型范围(如 BIGINT 改为 INT, VARCHAR(64) 改为 VARCHAR(32))会导致已有数据被截断或修改失败, 并且需要重建表。",
+		BadExample:  "ALTER TABLE t1 MODIFY COLUMN name VARCHAR(16) NOT NULL DEFAULT '';",
+		GoodExample: "ALTER TABLE t1 MODIFY COLUMN name VARCHAR(64) NOT NULL DEFAULT '';",
+		Remediation: "保持或扩大列的类型范围; 确需缩小时, 先确认已有数据都在新的范围内。",
+	},
+	DDLCheckColumnSetNotNullWithoutDefault: {
+		Rationale:   "将列修改为 NOT NULL 时, 已有的 NULL 值在严格模式下会导致变更失败, 在非严格模式下会被转换为隐式默认值; 没有默认值也会让未指定该列的 INSERT 报错。",
+		BadExample:  "ALTER TABLE t1 MODIFY COLUMN name VARCHAR(32) NOT NULL;",
+		GoodExample: "ALTER TABLE t1 MODIFY COLUMN name VARCHAR(32) NOT NULL DEFAULT '';",
+		Remediation: "先将已有的 NULL 值更新为合适的值, 再修改列为 NOT NULL 并指定默认值。",
+	},
+	DMLCheckAffectedRows: {
+		Rationale:   "一次修改大量数据会产生大事务, 长时间持有行锁, 产生大量 binlog 和 undo, 导致主从延迟, 失败回滚的代价也很高。",
+		BadExample:  "UPDATE t1 SET status = 1 WHERE create_time < '2022-01-01';",
+		GoodExample: "UPDATE t1 SET status = 1 WHERE create_time < '2022-01-01' AND id BETWEEN 1 AND 10000;",
+		Remediation: "按主键范围拆分成多个小批次执行, 每批之间适当间隔。",
+	},
+	DDLCheckPKWithoutIfNotExists: {
+		Rationale:   "上线脚本可能因为失败重试等原因被重复执行, 不带 IF NOT EXISTS 的 CREATE TABLE 在表已存在时会报错, 导致后续语句无法执行。",
+		BadExample:  "CREATE TABLE t1(id BIGINT UNSIGNED PRIMARY KEY);",
+		GoodExample: "CREATE TABLE IF NOT EXISTS t1(id BIGINT UNSIGNED PRIMARY KEY);",
+		Remediation: "在 CREATE TABLE 语句中加上 IF NOT EXISTS。",
+	},
+	DDLCheckObjectNameLength: {
+		Rationale:   "过长的对象名不便于书写和阅读, 超过 MySQL 的长度限制(64 字符)时会直接报错, 一些中间件和工具对名称长度也有更严格的限制。",
+		BadExample:  "CREATE TABLE t_user_login_history_record_of_mobile_client_and_web_client_2022(id BIGINT PRIMARY KEY);",
+		GoodExample: "CREATE TABLE t_user_login_history(id BIGINT PRIMARY KEY);",
+		Remediation: "使用简短且有意义的名称, 名称长度不超过规则设置的字节数。",
+	},
+	DDLCheckPKWithoutBigintUnsigned: {
+		Rationale:   "INT 类型的自增主键最大约 21 亿, 数据增长或自增值跳跃后可能耗尽, 修改主键类型需要重建整张表; 主键值不会为负数, 使用无符号类型可以得到双倍的取值范围。",
+		BadExample:  "CREATE TABLE t1(id INT NOT NULL AUTO_INCREMENT PRIMARY KEY);",
+		GoodExample: "CREATE TABLE t1(id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY);",
+		Remediation: "将主键列定义为 BIGINT UNSIGNED。",
+	},
+	DDLCheckColumnCharLength: {
+		Rationale:   "CHAR 是定长类型, 不足长度的值会用空格填充, 较长的 CHAR 列在存储变长数据时浪费空间, 也会降低缓存和索引的效率。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, remark CHAR(200));",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY, remark VARCHAR(200));",
+		Remediation: "长度大于 20 的字符串列使用 VARCHAR 类型。",
+	},
+	DDLCheckIndexCount: {
+		Rationale:   "每个索引都需要在写入时同步维护, 索引过多会降低 INSERT/UPDATE/DELETE 的性能并占用更多空间, 也会增加优化器选错索引的可能。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, a INT, b INT, c INT, d INT, e INT, f INT, KEY(a), KEY(b), KEY(c), KEY(d), KEY(e), KEY(f));",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY, a INT, b INT, c INT, KEY idx_a_b(a, b), KEY idx_c(c));",
+		Remediation: "根据实际的查询条件设计索引, 合并可以共用的索引, 删除不再使用的索引。",
+	},
+	DDLCheckCompositeIndexMax: {
+		Rationale:   "复合索引的列越多, 索引越大, 维护成本越高, 而由于最左前缀匹配, 靠后的列通常很少能被查询用到。",
+		BadExample:  "CREATE INDEX idx_a_b_c_d_e_f ON t1(a, b, c, d, e, f);",
+		GoodExample: "CREATE INDEX idx_a_b_c ON t1(a, b, c);",
+		Remediation: "只保留查询中区分度高且常用的列, 复合索引的列数不超过规则设置的阈值。",
+	},
+	DDLCheckObjectNameUsingKeyword: {
+		Rationale:   "使用保留字作为对象名时必须用反引号引用, 遗漏时会出现语法错误, 数据库升级后新增的保留字也可能让原本正常的语句失败。",
+		BadExample:  "CREATE TABLE `order`(id BIGINT PRIMARY KEY, `desc` VARCHAR(32));",
+		GoodExample: "CREATE TABLE t_order(id BIGINT PRIMARY KEY, description VARCHAR(32));",
+		Remediation: "修改对象名, 避免使用 MySQL 的关键字和保留字。",
+	},
+	DDLCheckObjectNameUseCN: {
+		Rationale:   "包含中文或特殊字符的对象名依赖客户端和连接的字符集, 在不同的工具和环境中容易出现乱码或引用错误。",
+		BadExample:  "CREATE TABLE 用户(id BIGINT PRIMARY KEY);",
+		GoodExample: "CREATE TABLE t_user(id BIGINT PRIMARY KEY);",
+		Remediation: "对象名只使用英文字母、数字和下划线, 并以英文字母开头。",
+	},
+	DDLCheckTableDBEngine: {
+		Rationale:   "不同存储引擎在事务、崩溃恢复和锁粒度上差异很大, 例如 MyISAM 不支持事务和行锁, 混用引擎会导致数据一致性和运维上的问题。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY) ENGINE=MyISAM;",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY) ENGINE=InnoDB;",
+		Remediation: "建表时显式指定规则要求的存储引擎。",
+	},
+	DDLCheckTableCharacterSet: {
+		Rationale:   "表和列的字符集不一致时, 关联查询会发生隐式的字符集转换导致索引失效, 写入不支持的字符(如 utf8 下的 emoji)也会报错或乱码。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, name VARCHAR(32)) DEFAULT CHARSET=latin1;",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY, name VARCHAR(32)) DEFAULT CHARSET=utf8mb4;",
+		Remediation: "建表时显式指定规则要求的字符集, 列上不单独指定其他字符集。",
+	},
+	DDLCheckIndexedColumnWithBlob: {
+		Rationale:   "BLOB/TEXT 列只能建立前缀索引, 前缀索引无法用于排序和覆盖索引, 而且索引很大, 写入时的维护成本高。",
+		BadExample:  "CREATE INDEX idx_content ON t1(content(255));",
+		GoodExample: "CREATE INDEX idx_content_md5 ON t1(content_md5);",
+		Remediation: "不对 BLOB/TEXT 列建立索引, 需要按内容查询时可以增加摘要列并对摘要列建立索引, 或使用全文检索。",
+	},
+	DDLCheckAlterTableNeedMerge: {
+		Rationale:   "对同一张表的每条 ALTER 语句都可能重建一次表, 多条语句会多次复制数据并多次获取元数据锁, 延长变更时间并放大对业务的影响。",
+		BadExample:  "ALTER TABLE t1 ADD COLUMN a INT; ALTER TABLE t1 ADD INDEX idx_a(a);",
+		GoodExample: "ALTER TABLE t1 ADD COLUMN a INT, ADD INDEX idx_a(a);",
+		Remediation: "将对同一张表的多个修改合并为一条 ALTER TABLE 语句。",
+	},
+	DDLCheckAlterTableOnlineDDL: {
+		Rationale:   "不同的 ALTER 操作使用的算法(INSTANT/INPLACE/COPY)和锁级别不同, 使用 COPY 算法或需要排他锁的操作在大表上会长时间阻塞写入。",
+		BadExample:  "ALTER TABLE t1 MODIFY COLUMN id BIGINT;",
+		GoodExample: "ALTER TABLE t1 ADD COLUMN a INT, ALGORITHM=INPLACE, LOCK=NONE;",
+		Remediation: "确认变更使用的算法和锁级别, 会阻塞写入的变更使用 gh-ost、pt-osc 等工具在业务低峰期执行。",
+	},
+	DDLCheckInvisibleIndex: {
+		Rationale:   "不可见索引不会被优化器使用, 但仍然需要在写入时维护并占用空间, 长期保留的不可见索引通常是遗忘清理的无用索引。",
+		BadExample:  "CREATE INDEX idx_a ON t1(a) INVISIBLE;",
+		GoodExample: "CREATE INDEX idx_a ON t1(a);",
+		Remediation: "只在验证删除索引的影响时短期使用不可见索引, 验证完成后删除索引或将其恢复为可见。",
+	},
+	DDLCheckColumnSignChange: {
+		Rationale:   "修改数值列的有无符号属性会改变取值范围, 已有的负数或超出有符号范围的值在严格模式下会导致变更失败, 在非严格模式下会被截断。",
+		BadExample:  "ALTER TABLE t1 MODIFY COLUMN balance INT UNSIGNED;",
+		GoodExample: "ALTER TABLE t1 ADD COLUMN balance_v2 BIGINT;",
+		Remediation: "确认已有数据都在新类型的取值范围内, 必要时使用更大的类型或新增列迁移数据。",
+	},
+	DDLCheckColumnCharsetChange: {
+		Rationale:   "修改列的字符集或排序规则需要重建表并转换全部数据, 不能转换的字符会丢失, 排序规则的变化也会影响比较结果和唯一索引的判断。",
+		BadExample:  "ALTER TABLE t1 MODIFY COLUMN name VARCHAR(32) CHARACTER SET latin1;",
+		GoodExample: "ALTER TABLE t1 MODIFY COLUMN name VARCHAR(64);",
+		Remediation: "确认数据可以无损转换并评估对查询和唯一约束的影响, 尽量在建表时确定字符集和排序规则。",
+	},
+	DMLCheckRecursiveCTEWithoutLimit: {
+		Rationale:   "递归 CTE 的终止依赖于递归部分的条件, 条件错误或数据中存在环时会一直递归, 直到达到 cte_max_recursion_depth 报错, 期间消耗大量资源。",
+		BadExample:  "WITH RECURSIVE t(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM t) SELECT * FROM t;",
+		GoodExample: "WITH RECURSIVE t(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM t LIMIT 100) SELECT * FROM t;",
+		Remediation: "在递归 CTE 的递归部分中使用 LIMIT, 或通过 /*+ SET_VAR(cte_max_recursion_depth = N) */ 提示限制递归的深度。",
+	},
+	DDLCheckTableWithoutComment: {
+		Rationale:   "没有注释的表在人员变动后难以理解其用途, 也无法判断能否清理, 增加维护和数据治理的成本。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY);",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY) COMMENT '用户登录记录';",
+		Remediation: "建表时使用 COMMENT 说明表的用途。",
+	},
+	DDLCheckColumnWithoutComment: {
+		Rationale:   "没有注释的列难以理解其含义、单位和取值, 例如状态列的每个取值代表什么, 容易导致错误的使用。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, status TINYINT);",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY COMMENT '主键', status TINYINT COMMENT '状态: 0-禁用, 1-启用');",
+		Remediation: "为每个列使用 COMMENT 说明其含义和取值。",
+	},
+	DDLCheckIndexPrefix: {
+		Rationale:   "统一的索引命名前缀可以从名称上区分索引的类型, 便于在执行计划、慢日志和变更中快速识别索引。",
+		BadExample:  "CREATE INDEX a ON t1(a);",
+		GoodExample: "CREATE INDEX idx_a ON t1(a);",
+		Remediation: "普通索引使用规则设置的前缀命名。",
+	},
+	DDLCheckUniqueIndexPrefix: {
+		Rationale:   "统一的唯一索引命名前缀可以从名称上看出该索引带有唯一约束, 便于排查写入时的唯一键冲突。",
+		BadExample:  "CREATE UNIQUE INDEX a ON t1(a);",
+		GoodExample: "CREATE UNIQUE INDEX uniq_a ON t1(a);",
+		Remediation: "唯一索引使用规则设置的前缀命名。",
+	},
+	DDLCheckUniqueIndex: {
+		Rationale:   "包含表名和列名的唯一索引名可以直接看出约束的内容, 唯一键冲突的报错信息中只有索引名, 规范的命名便于定位问题。",
+		BadExample:  "CREATE UNIQUE INDEX uk1 ON t1(name);",
+		GoodExample: "CREATE UNIQUE INDEX IDX_UK_t1_name ON t1(name);",
+		Remediation: "唯一索引按照 IDX_UK_表名_字段名 的格式命名。",
+	},
+	DDLCheckColumnTimestampWithoutDefault: {
+		Rationale:   "TIMESTAMP 列没有默认值时, 其行为依赖于 explicit_defaults_for_timestamp 等配置, 可能被自动设置为当前时间或在写入时报错。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, update_time TIMESTAMP);",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY, update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP);",
+		Remediation: "为 TIMESTAMP 列显式指定默认值。",
+	},
+	DDLCheckColumnBlobWithNotNull: {
+		Rationale:   "BLOB/TEXT 列在 MySQL 8.0.13 之前不能指定默认值, 设置为 NOT NULL 后未指定该列的 INSERT 在严格模式下会报错。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, content TEXT NOT NULL);",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY, content TEXT);",
+		Remediation: "BLOB/TEXT 列不设置 NOT NULL, 由应用处理 NULL 值。",
+	},
+	DDLCheckColumnBlobDefaultIsNotNull: {
+		Rationale:   "BLOB/TEXT 列在 MySQL 8.0.13 之前不支持非 NULL 的默认值, 指定后建表会失败, 在不同版本间的行为也不一致。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, content TEXT DEFAULT '');",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY, content TEXT);",
+		Remediation: "去掉 BLOB/TEXT 列的非 NULL 默认值。",
+	},
+	DMLCheckBatchInsertListsMax: {
+		Rationale:   "单条 INSERT 插入过多的行会产生大事务和很长的 SQL, 可能超过 max_allowed_packet, 并导致主从延迟和较长时间的锁等待。",
+		BadExample:  "INSERT INTO t1(id, name) VALUES(1, 'a'), (2, 'b'), ...; -- 上万行",
+		GoodExample: "INSERT INTO t1(id, name) VALUES(1, 'a'), (2, 'b'), ...; -- 每批不超过阈值",
+		Remediation: "将批量插入拆分成多条语句, 每条插入的行数不超过规则设置的阈值。",
+	},
+	DDLCheckPKProhibitAutoIncrement: {
+		Rationale:   "在分库分表或多活写入的场景下, 各个库的自增值会冲突, 数据合并和迁移时也需要处理主键冲突, 因此需要使用全局唯一的主键。",
+		BadExample:  "CREATE TABLE t1(id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY);",
+		GoodExample: "CREATE TABLE t1(id BIGINT UNSIGNED NOT NULL PRIMARY KEY COMMENT '由发号器生成');",
+		Remediation: "使用雪花算法、发号器等方式生成全局唯一的主键。",
+	},
+	DMLCheckWhereExistNot: {
+		Rationale:   "NOT IN、<>、NOT LIKE 等负向条件通常无法有效利用索引, 会退化为全表或全索引扫描; NOT IN 的子查询结果包含 NULL 时还会返回空结果。",
+		BadExample:  "SELECT id FROM t1 WHERE status <> 1;",
+		GoodExample: "SELECT id FROM t1 WHERE status IN (0, 2);",
+		Remediation: "将负向条件改写为正向的条件, 或使用 NOT EXISTS、LEFT JOIN 代替 NOT IN 子查询。",
+	},
+	DMLWhereExistNull: {
+		Rationale:   "IS NULL/IS NOT NULL 条件在 NULL 值较多时区分度很低, 优化器往往选择全表扫描, 同时也说明列允许 NULL, 在比较和聚合时容易出现意外的结果。",
+		BadExample:  "SELECT id FROM t1 WHERE name IS NULL;",
+		GoodExample: "SELECT id FROM t1 WHERE name = '';",
+		Remediation: "将列定义为 NOT NULL 并使用有意义的默认值, 通过默认值进行查询。",
+	},
+	DMLCheckLimitMustExist: {
+		Rationale:   "没有 LIMIT 的 UPDATE/DELETE 在条件错误时会一次修改大量数据, 产生大事务和长时间的锁等待, 限制每次修改的行数可以控制误操作的影响范围。",
+		BadExample:  "DELETE FROM t1 WHERE create_time < '2022-01-01';",
+		GoodExample: "DELETE FROM t1 WHERE create_time < '2022-01-01' LIMIT 1000;",
+		Remediation: "为 UPDATE/DELETE 添加 LIMIT, 分批执行直到影响行数为 0。",
+	},
+	DMLCheckWhereExistScalarSubquery: {
+		Rationale:   "标量子查询对外层查询的每一行都可能执行一次, 外层结果集较大时性能很差, 子查询返回多行时还会直接报错。",
+		BadExample:  "SELECT id, (SELECT name FROM t2 WHERE t2.id = t1.uid) AS name FROM t1;",
+		GoodExample: "SELECT t1.id, t2.name FROM t1 LEFT JOIN t2 ON t2.id = t1.uid;",
+		Remediation: "将标量子查询改写为 JOIN。",
+	},
+	DDLCheckIndexesExistBeforeCreateConstraints: {
+		Rationale:   "添加唯一约束时会隐式创建索引, 先创建索引可以提前评估建立索引的时间和影响, 也便于在约束创建失败时排查是否存在重复数据。",
+		BadExample:  "ALTER TABLE t1 ADD CONSTRAINT uk_name UNIQUE (name);",
+		GoodExample: "CREATE INDEX idx_name ON t1(name); ALTER TABLE t1 ADD CONSTRAINT uk_name UNIQUE (name);",
+		Remediation: "在创建约束之前, 先为约束的列创建索引。",
+	},
+	DMLCheckSelectForUpdate: {
+		Rationale:   "SELECT ... FOR UPDATE 会对扫描到的记录和间隙加锁, 条件没有命中索引时会锁住大量记录, 在高并发下容易产生锁等待和死锁。",
+		BadExample:  "SELECT * FROM t1 WHERE name = 'a' FOR UPDATE;",
+		GoodExample: "UPDATE t1 SET stock = stock - 1 WHERE id = 1 AND stock > 0;",
+		Remediation: "尽量使用带条件的 UPDATE 等乐观的方式代替加锁读, 必须使用时确保条件命中唯一索引并缩短事务。",
+	},
+	DDLCheckDatabaseCollation: {
+		Rationale:   "排序规则决定了字符串的比较和排序结果, 例如是否区分大小写; 不同对象的排序规则不一致时, 关联和比较可能报错或无法使用索引。",
+		BadExample:  "CREATE DATABASE db1 COLLATE utf8mb4_bin;",
+		GoodExample: "CREATE DATABASE db1 COLLATE utf8mb4_0900_ai_ci;",
+		Remediation: "创建数据库和表时使用规则要求的排序规则。",
+	},
+	DDLCheckDecimalTypeColumn: {
+		Rationale:   "FLOAT/DOUBLE 是近似值类型, 存储和计算时会产生精度误差, 用于金额等需要精确计算的数据时会导致对账不一致。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, amount DOUBLE);",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY, amount DECIMAL(16, 2));",
+		Remediation: "需要精确计算的小数使用 DECIMAL 类型。",
+	},
+	DMLCheckNeedlessFunc: {
+		Rationale:   "在 SQL 中使用非必要的内置函数会增加数据库的 CPU 消耗, 而数据库是最难扩展的资源; 对列使用函数还会导致索引失效。",
+		BadExample:  "SELECT id FROM t1 WHERE md5(name) = 'e10adc3949ba59abbe56e057f20f883e';",
+		GoodExample: "SELECT id FROM t1 WHERE name_md5 = 'e10adc3949ba59abbe56e057f20f883e';",
+		Remediation: "将计算移到应用中完成, 或预先计算并存储到单独的列中。",
+	},
+	DDLCheckDatabaseSuffix: {
+		Rationale:   "统一的数据库名后缀可以从名称上区分数据库的环境或用途, 避免在错误的环境中执行变更。",
+		BadExample:  "CREATE DATABASE app;",
+		GoodExample: "CREATE DATABASE app_db;",
+		Remediation: "数据库名使用规则设置的后缀结尾。",
+	},
+	DDLCheckPKName: {
+		Rationale:   "统一的主键命名可以从名称上看出约束所属的表, 便于在迁移到其他数据库或排查约束冲突时识别主键。",
+		BadExample:  "CREATE TABLE t1(id BIGINT, CONSTRAINT pk1 PRIMARY KEY(id));",
+		GoodExample: "CREATE TABLE t1(id BIGINT, CONSTRAINT PK_t1 PRIMARY KEY(id));",
+		Remediation: "主键按照 PK_表名 的格式命名。",
+	},
+	DDLCheckTransactionIsolationLevel: {
+		Rationale:   "RR 隔离级别下会使用间隙锁, 增加锁冲突和死锁的可能; RC 隔离级别的锁范围更小, 并发性能更好, 能满足大部分业务的一致性要求。",
+		BadExample:  "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ;",
+		GoodExample: "SET TRANSACTION ISOLATION LEVEL READ COMMITTED;",
+		Remediation: "使用 READ COMMITTED 隔离级别, 并配合 ROW 格式的 binlog。",
+	},
+	DDLCheckTablePartition: {
+		Rationale:   "分区表的查询不带分区键时需要访问所有分区, 分区的维护操作会锁住整张表, 分区表在各种工具和中间件中的支持也不完善。",
+		BadExample:  "CREATE TABLE t1(id BIGINT, create_time DATE) PARTITION BY RANGE(YEAR(create_time)) (PARTITION p2022 VALUES LESS THAN (2023));",
+		GoodExample: "CREATE TABLE t1_2022(id BIGINT PRIMARY KEY, create_time DATE);",
+		Remediation: "使用分表或归档代替分区表, 由应用或中间件路由到对应的表。",
+	},
+	DMLCheckNumberOfJoinTables: {
+		Rationale:   "JOIN 的表越多, 优化器需要评估的连接顺序越多, 越容易选错执行计划, 中间结果也会迅速膨胀, 导致查询变慢并占用大量内存。",
+		BadExample:  "SELECT * FROM t1 JOIN t2 ON ... JOIN t3 ON ... JOIN t4 ON ... JOIN t5 ON ...;",
+		GoodExample: "SELECT * FROM t1 JOIN t2 ON ...;",
+		Remediation: "拆分查询, 或通过适当的冗余字段减少需要关联的表。",
+	},
+	DMLCheckIfAfterUnionDistinct: {
+		Rationale:   "UNION 会对结果集去重, 需要额外的排序或临时表; 在结果不会重复或不需要去重时, UNION ALL 可以避免这部分开销。",
+		BadExample:  "SELECT id FROM t1 UNION SELECT id FROM t2;",
+		GoodExample: "SELECT id FROM t1 UNION ALL SELECT id FROM t2;",
+		Remediation: "不需要去重时使用 UNION ALL 代替 UNION。",
+	},
+	DDLCheckIndexOption: {
+		Rationale:   "区分度低的列(如状态、性别)上的索引过滤效果很差, 优化器通常不会使用, 却仍然需要在写入时维护。",
+		BadExample:  "CREATE INDEX idx_gender ON t1(gender);",
+		GoodExample: "CREATE INDEX idx_phone ON t1(phone);",
+		Remediation: "选择区分度高的列建立索引, 区分度低的列可以作为复合索引中的非前导列。",
+	},
+	DDLCheckColumnEnumNotice: {
+		Rationale:   "ENUM 的取值定义在表结构中, 增加或调整取值需要修改表结构; ENUM 按定义的序号排序和比较, 使用数字作为取值时容易混淆序号和值。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, status ENUM('on', 'off'));",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY, status TINYINT NOT NULL DEFAULT 0 COMMENT '0-off, 1-on');",
+		Remediation: "使用 TINYINT 或 VARCHAR 代替 ENUM, 在注释或应用中说明取值的含义。",
+	},
+	DDLCheckColumnSetNotice: {
+		Rationale:   "SET 的取值定义在表结构中, 调整取值需要修改表结构, 查询其中的某个取值需要使用 FIND_IN_SET 等函数, 无法使用索引。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, tags SET('a', 'b', 'c'));",
+		GoodExample: "CREATE TABLE t1_tag(id BIGINT PRIMARY KEY, t1_id BIGINT NOT NULL, tag VARCHAR(16) NOT NULL);",
+		Remediation: "使用关联表存储多个取值, 或使用整数按位存储并在应用中处理。",
+	},
+	DDLCheckColumnBlobNotice: {
+		Rationale:   "BLOB/TEXT 列的数据可能存储在溢出页中, 读取时需要额外的 IO, 排序和分组时无法使用内存临时表, 也会让表占用大量空间并拖慢备份。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, content TEXT);",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY, content VARCHAR(2000));",
+		Remediation: "使用足够长度的 VARCHAR 代替, 确实需要存储大字段时拆分到单独的表, 或存储到对象存储中只保存地址。",
+	},
+	DMLCheckExplainAccessTypeAll: {
+		Rationale:   "执行计划的访问类型为 ALL 表示全表扫描, 扫描的行数随数据量线性增长, 数据量大时查询会越来越慢, 并影响其他查询。",
+		BadExample:  "SELECT id FROM t1 WHERE name = 'a'; -- name 列没有索引",
+		GoodExample: "SELECT id FROM t1 WHERE name = 'a'; -- 已创建 idx_name(name)",
+		Remediation: "为查询条件中的列建立合适的索引, 或增加能使用索引的过滤条件。",
+	},
+	DMLCheckExplainExtraUsingFilesort: {
+		Rationale:   "Using filesort 表示无法利用索引的顺序, 需要额外排序, 数据量超过 sort_buffer_size 时还会使用磁盘文件排序, 消耗 CPU 和 IO。",
+		BadExample:  "SELECT id FROM t1 WHERE status = 1 ORDER BY create_time; -- 只有 idx_status(status)",
+		GoodExample: "SELECT id FROM t1 WHERE status = 1 ORDER BY create_time; -- 已创建 idx_status_create_time(status, create_time)",
+		Remediation: "建立包含过滤列和排序列的复合索引, 使排序可以利用索引的顺序。",
+	},
+	DMLCheckExplainExtraUsingTemporary: {
+		Rationale:   "Using temporary 表示查询需要创建临时表保存中间结果, 常见于 GROUP BY、DISTINCT 和 UNION, 临时表过大时会转为磁盘临时表, 性能显著下降。",
+		BadExample:  "SELECT DISTINCT name FROM t1; -- name 列没有索引",
+		GoodExample: "SELECT DISTINCT name FROM t1; -- 已创建 idx_name(name)",
+		Remediation: "为分组和去重的列建立索引, 或改写查询减少中间结果集。",
+	},
+	DDLCheckCreateView: {
+		Rationale:   "视图隐藏了真实的查询, 多层视图的执行计划难以优化和排查, 视图中的算法和权限(DEFINER)在迁移和升级时也容易出现问题。",
+		BadExample:  "CREATE VIEW v1 AS SELECT t1.id, t2.name FROM t1 JOIN t2 ON t1.uid = t2.id;",
+		GoodExample: "SELECT t1.id, t2.name FROM t1 JOIN t2 ON t1.uid = t2.id;",
+		Remediation: "在应用中直接编写查询, 不使用视图。",
+	},
+	DDLCheckCreateTrigger: {
+		Rationale:   "触发器在写入时隐式执行, 业务逻辑对开发者不可见, 会放大写入的开销和锁的范围, 也会导致 gh-ost 等在线变更工具无法使用。",
+		BadExample:  "CREATE TRIGGER tr1 AFTER INSERT ON t1 FOR EACH ROW INSERT INTO t1_log(id) VALUES(NEW.id);",
+		GoodExample: "INSERT INTO t1(id) VALUES(1); INSERT INTO t1_log(id) VALUES(1); -- 在应用的同一事务中执行",
+		Remediation: "将触发器中的逻辑移到应用中实现。",
+	},
+	DDLCheckCreateFunction: {
+		Rationale:   "自定义函数在数据库中执行业务逻辑, 难以调试、测试和版本管理, 在查询中使用时还会逐行调用, 消耗数据库的 CPU 并导致索引失效。",
+		BadExample:  "CREATE FUNCTION f1(a INT) RETURNS INT DETERMINISTIC RETURN a * 2;",
+		GoodExample: "SELECT a FROM t1; -- 在应用中计算 a * 2",
+		Remediation: "将函数中的逻辑移到应用中实现。",
+	},
+	DDLCheckCreateProcedure: {
+		Rationale:   "存储过程在数据库中执行业务逻辑, 难以调试、测试和版本管理, 无法随应用水平扩展, 也会让业务和特定的数据库产品强耦合。",
+		BadExample:  "CREATE PROCEDURE p1() BEGIN UPDATE t1 SET status = 1 WHERE status = 0; END;",
+		GoodExample: "UPDATE t1 SET status = 1 WHERE status = 0; -- 由应用执行",
+		Remediation: "将存储过程中的逻辑移到应用中实现。",
+	},
+	DDLCheckTableSize: {
+		Rationale:   "对大表执行 DDL 需要复制或重建大量数据, 执行时间长, 产生的 IO 和主从延迟会影响业务, 失败后回滚的代价也很高。",
+		BadExample:  "ALTER TABLE big_table ADD COLUMN a INT;",
+		GoodExample: "gh-ost --alter=\"ADD COLUMN a INT\" --table=big_table ...",
+		Remediation: "评估变更对业务的影响, 使用 gh-ost、pt-osc 等在线变更工具在业务低峰期执行。",
+	},
+	DDLCheckIndexTooMany: {
+		Rationale:   "同一个列上的索引过多时, 这些索引往往存在重复, 写入时需要维护多份索引, 优化器也更容易选错索引。",
+		BadExample:  "CREATE INDEX idx_a ON t1(a); CREATE INDEX idx_a_b ON t1(a, b); CREATE INDEX idx_a_c ON t1(a, c);",
+		GoodExample: "CREATE INDEX idx_a_b ON t1(a, b);",
+		Remediation: "合并以相同列开头的索引, 删除不再使用的索引。",
+	},
+	DDLCheckRedundantIndex: {
+		Rationale:   "冗余索引(与已有索引相同或是其最左前缀)不会带来额外的查询收益, 却需要在写入时维护并占用空间。",
+		BadExample:  "CREATE TABLE t1(id BIGINT PRIMARY KEY, a INT, b INT, KEY idx_a(a), KEY idx_a_b(a, b));",
+		GoodExample: "CREATE TABLE t1(id BIGINT PRIMARY KEY, a INT, b INT, KEY idx_a_b(a, b));",
+		Remediation: "删除被其他索引覆盖的冗余索引。",
+	},
+	DMLCheckTableSize: {
+		Rationale:   "对大表执行 DML 时, 即使条件命中索引, 也可能因为统计信息不准确选择全表扫描, 一旦影响的行数较多, 产生的锁等待和主从延迟会影响业务。",
+		BadExample:  "UPDATE big_table SET status = 1 WHERE create_time < '2022-01-01';",
+		GoodExample: "UPDATE big_table SET status = 1 WHERE id BETWEEN 1 AND 10000;",
+		Remediation: "确认执行计划和影响的行数, 按主键范围分批执行。",
+	},
+	ConfigDMLRollbackMaxRows: {
+		Rationale:   "生成 DML 的回滚语句需要在执行前查询出将被修改的数据, 影响行数很大时查询本身会给数据库带来很大的压力, 生成的回滚语句也难以执行。",
+		Remediation: "根据实例的负载设置合适的最大行数, 超过最大行数的 DML 应事先做好数据备份。",
+	},
+	ConfigDMLRollbackByBinlog: {
+		Rationale:   "执行前查询数据生成的回滚语句受最大影响行数的限制, 而且查询和执行之间的数据变化会让回滚语句不准确; 从 binlog 中解析的是实际修改的数据。",
+		Remediation: "实例开启 ROW 格式的 binlog 并为 SQLE 的账号授予 REPLICATION 权限后启用; 解析失败时会使用审核时生成的回滚语句。",
+	},
+	ConfigDDLTruncateBackup: {
+		Rationale:   "TRUNCATE 会直接删除全部数据, 无法像 DELETE 一样生成回滚语句, 执行前将数据复制到备份表后才可以恢复数据。",
+		Remediation: "根据磁盘空间和执行时间设置备份的最大表空间; 备份表只在执行回滚时删除, 确认回滚不再需要后手动删除回滚语句中的备份表。",
+	},
+	ConfigDDLOSCMinSize: {
+		Rationale:   "对大表直接执行 ALTER 可能长时间阻塞写入或产生严重的主从延迟, pt-online-schema-change 通过影子表和触发器在线完成变更。",
+		Remediation: "根据实例的负载设置合适的表空间大小, 超过该大小的改表使用输出的 pt-osc 命令执行。",
+	},
+	ConfigDMLExplainPreCheckEnable: {
+		Rationale:   "部分问题(如语法和对象是否存在)只有在实例上执行 EXPLAIN 才能发现, 审核时预先 EXPLAIN 可以在上线前发现这些错误。",
+		Remediation: "在可以接受审核时执行 EXPLAIN 的实例上开启。",
+	},
+	ConfigOptimizeIndexEnabled: {
+		Rationale:   "缺少合适的索引是慢查询最常见的原因, 根据查询条件和执行计划给出索引建议, 可以在上线前发现需要添加的索引。",
+		Remediation: "开启后参考审核结果中的索引建议, 结合表上已有的索引决定是否创建。",
+	},
+	ConfigDDLGhostMinSize: {
+		Rationale:   "对大表直接执行 ALTER 可能长时间阻塞写入或产生严重的主从延迟, gh-ost 通过 binlog 同步影子表, 可以在线完成变更并随时暂停。",
+		Remediation: "根据实例的负载设置合适的表空间大小, 确认实例开启了 ROW 格式的 binlog。",
+	},
+	SchemaDesignCheckPKExist: {
+		Rationale:   "没有主键的表无法高效地定位和复制行数据, ROW 格式的 binlog 在从库回放时可能逐行全表扫描, 造成严重的主从延迟。",
+		Remediation: "为表添加主键, 没有合适业务字段时使用自增的 BIGINT UNSIGNED 列。",
+	},
+	SchemaDesignCheckDuplicateIndex: {
+		Rationale:   "列完全相同的索引没有任何收益, 却会增加写入开销和磁盘空间, 并干扰优化器选择执行计划。",
+		Remediation: "保留其中一个索引, 删除其余重复的索引。",
+	},
+	SchemaDesignCheckRedundantIndex: {
+		Rationale:   "列是另一个索引最左前缀的索引通常是冗余的, 查询可以使用更长的索引, 冗余索引只会增加写入开销。",
+		Remediation: "确认没有查询依赖该索引的特性(如唯一约束)后删除冗余索引。",
+	},
+	SchemaDesignCheckTableCharset: {
+		Rationale:   "同一个库中表的字符集不一致时, 关联查询的字段需要转换字符集, 可能导致索引失效和乱码。",
+		Remediation: "将表的字符集修改为库中统一使用的字符集。",
+	},
+	SchemaDesignCheckColumnCharset: {
+		Rationale:   "列的字符集与表不一致时, 与其他列比较或关联需要转换字符集, 可能导致索引失效和乱码。",
+		Remediation: "去掉列单独指定的字符集, 使用表的字符集。",
+	},
+	SchemaDesignCheckFKLikeColumn: {
+		Rationale:   "命名为 xxx_id 的列通常引用 xxx 表, 库中不存在对应的表时可能是命名不规范或者表已经被删除。",
+		Remediation: "确认列引用的表, 必要时修改列名或补充对应的表。",
+	},
+	SchemaDesignCheckTableSize: {
+		Rationale:   "过大的表会让查询、备份和 DDL 变慢, 也增加了故障恢复的时间。",
+		Remediation: "将历史数据归档到其他表或库, 或者按业务拆分表。",
+	},
+}
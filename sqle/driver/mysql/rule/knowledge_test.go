@@ -0,0 +1,25 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleKnowledge(t *testing.T) {
+	for name, knowledge := range ruleKnowledges {
+		handler, ok := RuleHandlerMap[name]
+		if !assert.True(t, ok, "rule %s has knowledge but not exist", name) {
+			continue
+		}
+		assert.Equal(t, knowledge, handler.Rule.Knowledge)
+		assert.NotEmpty(t, knowledge.Rationale, name)
+		assert.NotEmpty(t, knowledge.Remediation, name)
+	}
+
+	// every rule should have knowledge.
+	for _, handler := range RuleHandlers {
+		_, ok := ruleKnowledges[handler.Rule.Name]
+		assert.True(t, ok, "rule %s has no knowledge", handler.Rule.Name)
+	}
+}
@@ -0,0 +1,401 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
+)
+
+// DDLAlgorithm is the algorithm which MySQL uses to execute the ALTER TABLE, the order of
+// the values is the cost of the algorithm.
+type DDLAlgorithm int
+
+const (
+	DDLAlgorithmInstant DDLAlgorithm = iota
+	DDLAlgorithmInplace
+	DDLAlgorithmCopy
+)
+
+func (a DDLAlgorithm) String() string {
+	switch a {
+	case DDLAlgorithmInstant:
+		return "INSTANT"
+	case DDLAlgorithmInplace:
+		return "INPLACE"
+	default:
+		return "COPY"
+	}
+}
+
+// DDLLock is the lock level which is held on the table while executing the ALTER TABLE.
+type DDLLock int
+
+const (
+	DDLLockNone DDLLock = iota
+	DDLLockShared
+	DDLLockExclusive
+)
+
+func (l DDLLock) String() string {
+	switch l {
+	case DDLLockNone:
+		return "NONE"
+	case DDLLockShared:
+		return "SHARED"
+	default:
+		return "EXCLUSIVE"
+	}
+}
+
+// OnlineDDLClass is the classification of the online DDL, see
+// https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html.
+type OnlineDDLClass struct {
+	Algorithm DDLAlgorithm
+	Lock      DDLLock
+	// Rebuild represents the table is rebuilt by the INPLACE algorithm.
+	Rebuild bool
+}
+
+func (c OnlineDDLClass) merge(o OnlineDDLClass) OnlineDDLClass {
+	if o.Algorithm > c.Algorithm {
+		c.Algorithm = o.Algorithm
+	}
+	if o.Lock > c.Lock {
+		c.Lock = o.Lock
+	}
+	c.Rebuild = c.Rebuild || o.Rebuild
+	return c
+}
+
+// BlockWrites returns true if the writes to the table are blocked while executing the ALTER TABLE.
+func (c OnlineDDLClass) BlockWrites() bool {
+	return c.Algorithm == DDLAlgorithmCopy || c.Lock != DDLLockNone
+}
+
+// NeedOnlineDDLTool returns true if the ALTER TABLE blocks writes or rebuilds the table, it's
+// better to execute it by online DDL tool, e.g. gh-ost, for large table.
+func (c OnlineDDLClass) NeedOnlineDDLTool() bool {
+	return c.BlockWrites() || c.Rebuild
+}
+
+var (
+	instant = OnlineDDLClass{Algorithm: DDLAlgorithmInstant, Lock: DDLLockNone}
+	inplace = OnlineDDLClass{Algorithm: DDLAlgorithmInplace, Lock: DDLLockNone}
+	// inplaceRebuild is the INPLACE algorithm which rebuilds the table.
+	inplaceRebuild = OnlineDDLClass{Algorithm: DDLAlgorithmInplace, Lock: DDLLockNone, Rebuild: true}
+	copyShared     = OnlineDDLClass{Algorithm: DDLAlgorithmCopy, Lock: DDLLockShared, Rebuild: true}
+)
+
+// ClassifyAlterTable returns the online DDL classification of the ALTER TABLE on the version
+// of MySQL server. table is the table before altered, it is used to check whether the column
+// type is changed, it can be nil.
+func ClassifyAlterTable(stmt *ast.AlterTableStmt, table *ast.CreateTableStmt, version *semver.Version) OnlineDDLClass {
+	class := instant
+	for _, spec := range stmt.Specs {
+		class = class.merge(classifyAlterTableSpec(spec, table, version))
+	}
+	return class
+}
+
+func classifyAlterTableSpec(spec *ast.AlterTableSpec, table *ast.CreateTableStmt, version *semver.Version) OnlineDDLClass {
+	is80 := !version.LessThan(mysql80)
+	switch spec.Tp {
+	case ast.AlterTableAddColumns:
+		class := instant
+		for _, col := range spec.NewColumns {
+			class = class.merge(classifyAddColumn(col, spec.Position, version))
+		}
+		return class
+	case ast.AlterTableDropColumn:
+		if !version.LessThan(mysql8029) {
+			return instant
+		}
+		return inplaceRebuild
+	case ast.AlterTableRenameColumn:
+		if !version.LessThan(mysql8028) {
+			return instant
+		}
+		return inplace
+	case ast.AlterTableModifyColumn, ast.AlterTableChangeColumn:
+		class := instant
+		for _, col := range spec.NewColumns {
+			oldName := col.Name.Name.L
+			if spec.OldColumnName != nil {
+				oldName = spec.OldColumnName.Name.L
+			}
+			class = class.merge(classifyChangeColumn(getColumn(table, oldName), col, spec.Position))
+		}
+		return class
+	case ast.AlterTableAlterColumn:
+		// set or drop the default value of the column.
+		if is80 {
+			return instant
+		}
+		return inplace
+	case ast.AlterTableAddConstraint:
+		return classifyAddConstraint(spec.Constraint)
+	case ast.AlterTableDropPrimaryKey:
+		return copyShared
+	case ast.AlterTableDropIndex, ast.AlterTableDropForeignKey:
+		return inplace
+	case ast.AlterTableRenameIndex, ast.AlterTableRenameTable:
+		if is80 {
+			return instant
+		}
+		return inplace
+	case ast.AlterTableIndexInvisible:
+		return instant
+	case ast.AlterTableOption:
+		class := instant
+		for _, option := range spec.Options {
+			class = class.merge(classifyTableOption(option, is80))
+		}
+		return class
+	case ast.AlterTableForce:
+		return inplaceRebuild
+	case ast.AlterTableLock, ast.AlterTableAlgorithm:
+		return instant
+	default:
+		// the classification of the other operations, e.g. partition operations, is unknown,
+		// the most expensive one is assumed.
+		return copyShared
+	}
+}
+
+func classifyAddColumn(col *ast.ColumnDef, position *ast.ColumnPosition, version *semver.Version) OnlineDDLClass {
+	for _, op := range col.Options {
+		switch op.Tp {
+		case ast.ColumnOptionAutoIncrement:
+			return OnlineDDLClass{Algorithm: DDLAlgorithmInplace, Lock: DDLLockShared, Rebuild: true}
+		case ast.ColumnOptionGenerated:
+			if op.Stored {
+				return copyShared
+			}
+			return instant
+		case ast.ColumnOptionPrimaryKey, ast.ColumnOptionUniqKey:
+			return inplaceRebuild
+		}
+	}
+	isLast := position == nil || position.Tp == ast.ColumnPositionNone
+	if !version.LessThan(mysql8029) || (!version.LessThan(mysql8012) && isLast) {
+		return instant
+	}
+	return inplaceRebuild
+}
+
+func classifyChangeColumn(oldCol, newCol *ast.ColumnDef, position *ast.ColumnPosition) OnlineDDLClass {
+	if oldCol == nil || oldCol.Tp == nil || newCol.Tp == nil {
+		// the column type is unknown, assume it is changed.
+		return copyShared
+	}
+	class := inplace
+	if position != nil && position.Tp != ast.ColumnPositionNone {
+		class = inplaceRebuild
+	}
+	if util.HasOneInOptions(oldCol.Options, ast.ColumnOptionNotNull) != util.HasOneInOptions(newCol.Options, ast.ColumnOptionNotNull) {
+		// making a column NULL or NOT NULL rebuilds the table.
+		class = class.merge(inplaceRebuild)
+	}
+	if isSameColumnType(oldCol, newCol) || isVarcharExtendedInplace(oldCol, newCol) {
+		return class
+	}
+	return copyShared
+}
+
+// isSameColumnType returns true if the column type is not changed, the character set and
+// collation are compared only if they are specified in the new column.
+func isSameColumnType(oldCol, newCol *ast.ColumnDef) bool {
+	oldTp, newTp := oldCol.Tp, newCol.Tp
+	if oldTp.Tp != newTp.Tp || oldTp.Decimal != newTp.Decimal ||
+		mysql.HasUnsignedFlag(oldTp.Flag) != mysql.HasUnsignedFlag(newTp.Flag) {
+		return false
+	}
+	if oldTp.Flen != newTp.Flen && newTp.Flen != types.UnspecifiedLength {
+		return false
+	}
+	if strings.Join(oldTp.Elems, ",") != strings.Join(newTp.Elems, ",") {
+		return false
+	}
+	if newTp.Charset != "" && !strings.EqualFold(oldTp.Charset, newTp.Charset) {
+		return false
+	}
+	if newTp.Collate != "" && !strings.EqualFold(oldTp.Collate, newTp.Collate) {
+		return false
+	}
+	return true
+}
+
+// isVarcharExtendedInplace returns true if the VARCHAR column size is extended and the number
+// of length bytes is not changed, the length bytes is 1 if the size is less than 256 bytes.
+// utf8mb4 is assumed because the character set of the column may be inherited from the table.
+func isVarcharExtendedInplace(oldCol, newCol *ast.ColumnDef) bool {
+	if oldCol.Tp.Tp != mysql.TypeVarchar || newCol.Tp.Tp != mysql.TypeVarchar {
+		return false
+	}
+	if newCol.Tp.Charset != "" && !strings.EqualFold(oldCol.Tp.Charset, newCol.Tp.Charset) {
+		return false
+	}
+	const maxBytesPerChar = 4
+	oldLen, newLen := oldCol.Tp.Flen, newCol.Tp.Flen
+	return newLen >= oldLen && (oldLen*maxBytesPerChar < 256) == (newLen*maxBytesPerChar < 256)
+}
+
+func classifyAddConstraint(constraint *ast.Constraint) OnlineDDLClass {
+	if constraint == nil {
+		return copyShared
+	}
+	switch constraint.Tp {
+	case ast.ConstraintIndex, ast.ConstraintKey, ast.ConstraintUniq, ast.ConstraintUniqIndex, ast.ConstraintUniqKey:
+		return inplace
+	case ast.ConstraintFulltext:
+		return OnlineDDLClass{Algorithm: DDLAlgorithmInplace, Lock: DDLLockShared, Rebuild: true}
+	case ast.ConstraintPrimaryKey:
+		return inplaceRebuild
+	case ast.ConstraintForeignKey:
+		// INPLACE is only supported when foreign_key_checks is disabled.
+		return copyShared
+	default:
+		return copyShared
+	}
+}
+
+func classifyTableOption(option *ast.TableOption, is80 bool) OnlineDDLClass {
+	switch option.Tp {
+	case ast.TableOptionComment:
+		if is80 {
+			return instant
+		}
+		return inplace
+	case ast.TableOptionAutoIncrement:
+		return inplace
+	case ast.TableOptionCharset, ast.TableOptionCollate:
+		// changing the character set of the table, or converting the character set.
+		return copyShared
+	default:
+		// e.g. ENGINE, ROW_FORMAT and KEY_BLOCK_SIZE.
+		return inplaceRebuild
+	}
+}
+
+func getColumn(table *ast.CreateTableStmt, name string) *ast.ColumnDef {
+	if table == nil {
+		return nil
+	}
+	for _, col := range table.Cols {
+		if col.Name.Name.L == name {
+			return col
+		}
+	}
+	return nil
+}
+
+// specifiedAlgorithms and specifiedLocks map the ALGORITHM and LOCK clauses of ALTER TABLE.
+var (
+	specifiedAlgorithms = map[ast.AlgorithmType]DDLAlgorithm{
+		ast.AlgorithmTypeInstant: DDLAlgorithmInstant,
+		ast.AlgorithmTypeInplace: DDLAlgorithmInplace,
+		ast.AlgorithmTypeCopy:    DDLAlgorithmCopy,
+	}
+	specifiedLocks = map[ast.LockType]DDLLock{
+		ast.LockTypeNone:      DDLLockNone,
+		ast.LockTypeShared:    DDLLockShared,
+		ast.LockTypeExclusive: DDLLockExclusive,
+	}
+)
+
+const (
+	onlineDDLCopyLevelKeyName           = "copy_level"
+	onlineDDLInplaceLockLevelKeyName    = "inplace_lock_level"
+	onlineDDLInplaceRebuildLevelKeyName = "inplace_rebuild_level"
+)
+
+// getOnlineDDLLevel returns the result level of the classification, RuleLevelNull is returned
+// if the ALTER TABLE neither blocks writes nor rebuilds the table.
+func getOnlineDDLLevel(rule driver.Rule, class OnlineDDLClass) driver.RuleLevel {
+	var key string
+	switch {
+	case class.Algorithm == DDLAlgorithmCopy:
+		key = onlineDDLCopyLevelKeyName
+	case class.Lock != DDLLockNone:
+		key = onlineDDLInplaceLockLevelKeyName
+	case class.Rebuild:
+		key = onlineDDLInplaceRebuildLevelKeyName
+	default:
+		return driver.RuleLevelNull
+	}
+	param := rule.Params.GetParam(key)
+	if param == nil {
+		return rule.Level
+	}
+	switch level := driver.RuleLevel(param.String()); level {
+	case driver.RuleLevelNormal, driver.RuleLevelNotice, driver.RuleLevelWarn, driver.RuleLevelError:
+		return level
+	default:
+		return rule.Level
+	}
+}
+
+func checkAlterTableOnlineDDL(ctx *session.Context, rule driver.Rule, res *driver.AuditResult, node ast.Node) error {
+	stmt, ok := node.(*ast.AlterTableStmt)
+	if !ok {
+		return nil
+	}
+	table, exist, err := ctx.GetCreateTableStmt(stmt.Table)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return nil
+	}
+	class := ClassifyAlterTable(stmt, table, GetServerVersion(ctx))
+
+	// the ALGORITHM and LOCK clauses which are not supported by the operation fail the execution.
+	var unsupported []string
+	for _, spec := range util.GetAlterTableSpecByTp(stmt.Specs, ast.AlterTableAlgorithm) {
+		if algorithm, ok := specifiedAlgorithms[spec.Algorithm]; ok && algorithm < class.Algorithm {
+			unsupported = append(unsupported, fmt.Sprintf("ALGORITHM=%s", algorithm))
+		}
+	}
+	for _, spec := range util.GetAlterTableSpecByTp(stmt.Specs, ast.AlterTableLock) {
+		if lock, ok := specifiedLocks[spec.LockType]; ok && lock < class.Lock {
+			unsupported = append(unsupported, fmt.Sprintf("LOCK=%s", lock))
+		}
+	}
+
+	level := getOnlineDDLLevel(rule, class)
+	if level == driver.RuleLevelNull {
+		if len(unsupported) == 0 {
+			return nil
+		}
+		level = rule.Level
+	}
+
+	var effect string
+	switch {
+	case class.BlockWrites():
+		effect = "执行期间将阻塞对该表的写入"
+	case class.Rebuild:
+		effect = "执行期间将重建该表"
+	default:
+		effect = "执行期间不会阻塞对该表的写入"
+	}
+	message := fmt.Sprintf(RuleHandlerMap[DDLCheckAlterTableOnlineDDL].Message, class.Algorithm, class.Lock, effect)
+	if len(unsupported) > 0 {
+		message += fmt.Sprintf(", 指定的%s不支持该操作, 执行将会失败", strings.Join(unsupported, ","))
+	}
+	res.AddItem(&driver.AuditResultItem{
+		Level:    level,
+		Message:  message,
+		RuleName: rule.Name,
+		Category: rule.Category,
+	})
+	return nil
+}
@@ -0,0 +1,53 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyAlterTable(t *testing.T) {
+	tableNode, err := util.ParseOneSql(`create table t1(id bigint unsigned not null primary key,
+v1 varchar(32) not null, v2 varchar(255), v3 int)`)
+	assert.NoError(t, err)
+	table := tableNode.(*ast.CreateTableStmt)
+
+	args := []struct {
+		sql     string
+		version string
+		expect  OnlineDDLClass
+	}{
+		{"alter table t1 add column v4 int", "5.7.30", inplaceRebuild},
+		{"alter table t1 add column v4 int", "8.0.12", instant},
+		{"alter table t1 add column v4 int after id", "8.0.12", inplaceRebuild},
+		{"alter table t1 add column v4 int after id", "8.0.29", instant},
+		{"alter table t1 drop column v3", "8.0.28", inplaceRebuild},
+		{"alter table t1 drop column v3", "8.0.29-log", instant},
+		{"alter table t1 add index idx_v1(v1)", "8.0.30", inplace},
+		{"alter table t1 modify column v3 bigint", "8.0.30", copyShared},
+		{"alter table t1 modify column v1 varchar(60) not null", "8.0.30", inplace},
+		{"alter table t1 modify column v1 varchar(128) not null", "8.0.30", copyShared},
+		{"alter table t1 change column v3 v4 int", "5.7.30", inplace},
+		{"alter table t1 modify column v2 varchar(255) not null", "5.7.30", inplaceRebuild},
+		{"alter table t1 drop primary key", "8.0.30", copyShared},
+		{"alter table t1 alter column v3 set default 1", "8.0.30", instant},
+		{"alter table t1 comment 'test', add column v4 int", "5.7.30", inplaceRebuild},
+		{"alter table t1 convert to character set utf8mb4", "8.0.30", copyShared},
+		{"alter table t1 add column v4 int, modify column v3 bigint", "8.0.30", copyShared},
+		{"alter table t1 add column v4 int", "10.5.8-MariaDB", inplaceRebuild},
+	}
+	for _, arg := range args {
+		t.Run(arg.sql+"@"+arg.version, func(t *testing.T) {
+			node, err := util.ParseOneSql(arg.sql)
+			assert.NoError(t, err)
+			version, ok := parseServerVersion(arg.version)
+			if !ok {
+				version = mysqlDefault
+			}
+			assert.Equal(t, arg.expect, ClassifyAlterTable(node.(*ast.AlterTableStmt), table, version))
+		})
+	}
+}
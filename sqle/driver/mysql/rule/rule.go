@@ -6,7 +6,9 @@ import (
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/actiontech/sqle/sqle/driver"
@@ -33,6 +35,7 @@ const (
 	RuleTypeDMLConvention      = "DML规范"
 	RuleTypeUsageSuggestion    = "使用建议"
 	RuleTypeIndexOptimization  = "索引优化"
+	RuleTypeSchemaDesign       = "库表设计"
 )
 
 // inspector DDL rules
@@ -52,6 +55,12 @@ const (
 	DDLCheckTableCharacterSet                   = "ddl_check_table_character_set"
 	DDLCheckIndexedColumnWithBlob               = "ddl_check_index_column_with_blob"
 	DDLCheckAlterTableNeedMerge                 = "ddl_check_alter_table_need_merge"
+	DDLCheckAlterTableOnlineDDL                 = "ddl_check_alter_table_online_ddl"
+	DDLCheckInvisibleIndex                      = "ddl_check_invisible_index"
+	DDLCheckColumnTypeNarrowing                 = "ddl_check_column_type_narrowing"
+	DDLCheckColumnSignChange                    = "ddl_check_column_sign_change"
+	DDLCheckColumnCharsetChange                 = "ddl_check_column_charset_change"
+	DDLCheckColumnSetNotNullWithoutDefault      = "ddl_check_column_set_not_null_without_default"
 	DDLDisableDropStatement                     = "ddl_disable_drop_statement"
 	DDLCheckTableWithoutComment                 = "ddl_check_table_without_comment"
 	DDLCheckColumnWithoutComment                = "ddl_check_column_without_comment"
@@ -107,6 +116,20 @@ const (
 	DMLCheckExplainExtraUsingFilesort    = "dml_check_explain_extra_using_filesort"
 	DMLCheckExplainExtraUsingTemporary   = "dml_check_explain_extra_using_temporary"
 	DMLCheckTableSize                    = "dml_check_table_size"
+	DMLCheckRecursiveCTEWithoutLimit     = "dml_check_recursive_cte_without_limit"
+	DMLCheckAffectedRows                 = "dml_check_affected_rows"
+)
+
+// schema design rules, they are checked by AuditSchemaDesign with all the tables of schema
+// instead of auditing the SQL one by one.
+const (
+	SchemaDesignCheckPKExist        = "schema_design_check_pk_exist"
+	SchemaDesignCheckDuplicateIndex = "schema_design_check_duplicate_index"
+	SchemaDesignCheckRedundantIndex = "schema_design_check_redundant_index"
+	SchemaDesignCheckTableCharset   = "schema_design_check_table_charset"
+	SchemaDesignCheckColumnCharset  = "schema_design_check_column_charset"
+	SchemaDesignCheckFKLikeColumn   = "schema_design_check_fk_like_column"
+	SchemaDesignCheckTableSize      = "schema_design_check_table_size"
 )
 
 // inspector config code
@@ -116,6 +139,8 @@ const (
 	ConfigDDLGhostMinSize          = "ddl_ghost_min_size"
 	ConfigOptimizeIndexEnabled     = "optimize_index_enabled"
 	ConfigDMLExplainPreCheckEnable = "dml_enable_explain_pre_check"
+	ConfigDMLRollbackByBinlog      = "dml_rollback_by_binlog"
+	ConfigDDLTruncateBackup        = "ddl_truncate_backup"
 )
 
 type RuleHandler struct {
@@ -124,6 +149,10 @@ type RuleHandler struct {
 	Func                 func(*session.Context, driver.Rule, *driver.AuditResult, ast.Node) error
 	AllowOffline         bool
 	NotAllowOfflineStmts []ast.Node
+
+	// Fix rewrites node to fix the problem reported by the rule, it returns false if
+	// node is not changed. Fix is nil if the problem can not be fixed mechanically.
+	Fix func(rule driver.Rule, node ast.Node) bool
 }
 
 // In order to reuse some code, some rules use the same rule handler.
@@ -136,9 +165,55 @@ func addResult(result *driver.AuditResult, currentRule driver.Rule, ruleName str
 	if ruleName != currentRule.Name {
 		return
 	}
-	level := currentRule.Level
 	message := RuleHandlerMap[ruleName].Message
-	result.Add(level, message, args...)
+	result.AddWithRule(currentRule, message, args...)
+}
+
+// addResultAt is like addResult, and the position of result is the identifier name in the
+// statement, e.g. the column which violates the rule. The position is left to the caller
+// if name is not found.
+func addResultAt(result *driver.AuditResult, currentRule driver.Rule, ruleName string, node ast.Node, name string, args ...interface{}) {
+	if ruleName != currentRule.Name {
+		return
+	}
+	var position *driver.SQLPosition
+	start := definitionOffset(node, name)
+	if offset := util.IdentifierOffset(node.Text()[start:], name); offset >= 0 {
+		position = driver.NewSQLPosition(node.Text(), start+offset, len(name))
+	}
+	message := RuleHandlerMap[ruleName].Message
+	result.AddWithRuleAt(currentRule, position, message, args...)
+}
+
+// definitionOffset returns the offset after the names which lead the definitions of columns
+// and indexes in the statement, e.g. the table name of CREATE TABLE, so the column which has
+// the same name as the table is not located at the table name. It returns 0 if name is the
+// leading name, e.g. the index name of CREATE INDEX.
+func definitionOffset(node ast.Node, name string) int {
+	var names []string
+	switch stmt := node.(type) {
+	case *ast.CreateTableStmt:
+		names = []string{stmt.Table.Schema.O, stmt.Table.Name.O}
+	case *ast.AlterTableStmt:
+		names = []string{stmt.Table.Schema.O, stmt.Table.Name.O}
+	case *ast.CreateIndexStmt:
+		if strings.EqualFold(stmt.IndexName, name) {
+			return 0
+		}
+		names = []string{stmt.IndexName, stmt.Table.Schema.O, stmt.Table.Name.O}
+	default:
+		return 0
+	}
+	offset := 0
+	for _, leading := range names {
+		if leading == "" {
+			continue
+		}
+		if i := util.IdentifierOffset(node.Text()[offset:], leading); i >= 0 {
+			offset += i + len(leading)
+		}
+	}
+	return offset
 }
 
 func (rh *RuleHandler) IsAllowOfflineRule(node ast.Node) bool {
@@ -184,6 +259,32 @@ var RuleHandlers = []RuleHandler{
 		},
 		Func: nil,
 	},
+	{
+		Rule: driver.Rule{
+			Name:     ConfigDMLRollbackByBinlog,
+			Desc:     "DML 语句执行后解析 binlog 生成回滚语句，不受最大影响行数限制",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeGlobalConfig,
+		},
+		Func: nil,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     ConfigDDLTruncateBackup,
+			Desc:     "TRUNCATE 表前将数据备份到 _表名_truncate_bak_工单任务ID_SQL序号 表，用于生成回滚语句；备份表在回滚时删除，不回滚时需要手动删除",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeGlobalConfig,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "1024",
+					Desc:  "表空间超过该大小(MB)时不备份，不执行 TRUNCATE",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Func: nil,
+	},
 	{
 		Rule: driver.Rule{
 			Name: ConfigDDLOSCMinSize,
@@ -329,6 +430,7 @@ var RuleHandlers = []RuleHandler{
 		Message:      "新建表必须加入if not exists create，保证重复执行不报错",
 		AllowOffline: true,
 		Func:         checkIfNotExist,
+		Fix:          fixIfNotExist,
 	},
 	{
 		Rule: driver.Rule{
@@ -385,6 +487,7 @@ var RuleHandlers = []RuleHandler{
 		AllowOffline:         true,
 		NotAllowOfflineStmts: []ast.Node{&ast.AlterTableStmt{}},
 		Func:                 checkPrimaryKey,
+		Fix:                  fixPrimaryKeyBigintUnsigned,
 	},
 	{
 		Rule: driver.Rule{
@@ -511,6 +614,7 @@ var RuleHandlers = []RuleHandler{
 		Message:      "必须使用%v数据库字符集",
 		AllowOffline: false,
 		Func:         checkCharacterSet,
+		Fix:          fixCharacterSet,
 	},
 	{
 		Rule: driver.Rule{
@@ -546,6 +650,103 @@ var RuleHandlers = []RuleHandler{
 		AllowOffline: false,
 		Func:         checkMergeAlterTable,
 	},
+	{
+		Rule: driver.Rule{
+			Name:     DDLCheckAlterTableOnlineDDL,
+			Desc:     "改表语句需要关注执行时使用的算法和锁级别",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeUsageSuggestion,
+			Params: params.Params{
+				&params.Param{
+					Key:   onlineDDLCopyLevelKeyName,
+					Value: string(driver.RuleLevelWarn),
+					Desc:  "使用COPY算法时的审核等级",
+					Type:  params.ParamTypeString,
+				},
+				&params.Param{
+					Key:   onlineDDLInplaceLockLevelKeyName,
+					Value: string(driver.RuleLevelWarn),
+					Desc:  "使用INPLACE算法但需要锁表时的审核等级",
+					Type:  params.ParamTypeString,
+				},
+				&params.Param{
+					Key:   onlineDDLInplaceRebuildLevelKeyName,
+					Value: string(driver.RuleLevelNotice),
+					Desc:  "使用INPLACE算法且需要重建表时的审核等级",
+					Type:  params.ParamTypeString,
+				},
+			},
+		},
+		Message:      "该改表语句预计使用ALGORITHM=%s, LOCK=%s执行, %s",
+		AllowOffline: false,
+		Func:         checkAlterTableOnlineDDL,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DDLCheckInvisibleIndex,
+			Desc:     "不建议使用不可见索引",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeIndexingConvention,
+		},
+		Message:      "索引 %s 为不可见索引, 不会被优化器使用但写入时仍需维护, 请确认后删除或设为可见",
+		AllowOffline: true,
+		Func:         checkInvisibleIndex,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DDLCheckColumnTypeNarrowing,
+			Desc:     "修改列类型时不建议缩小类型范围",
+			Level:    driver.RuleLevelError,
+			Category: RuleTypeDDLConvention,
+		},
+		Message:      "列 %s 的类型范围被缩小, 已有数据可能被截断或导致执行失败",
+		AllowOffline: false,
+		Func:         checkColumnTypeChange,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DDLCheckColumnSignChange,
+			Desc:     "修改列类型时不建议改变数值的有无符号属性",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeDDLConvention,
+		},
+		Message:      "列 %s 的有无符号属性被改变, 超出范围的已有数据可能被截断或导致执行失败",
+		AllowOffline: false,
+		Func:         checkColumnTypeChange,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DDLCheckColumnCharsetChange,
+			Desc:     "修改列时不建议改变字符集或排序规则",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeDDLConvention,
+		},
+		Message:      "列 %s 的字符集或排序规则被改变, 执行时将重建表",
+		AllowOffline: false,
+		Func:         checkColumnTypeChange,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DDLCheckColumnSetNotNullWithoutDefault,
+			Desc:     "将列修改为NOT NULL时需要指定默认值",
+			Level:    driver.RuleLevelError,
+			Category: RuleTypeDDLConvention,
+		},
+		Message:      "列 %s 被修改为NOT NULL但没有指定默认值, 已有的NULL数据将导致执行失败",
+		AllowOffline: false,
+		Func:         checkColumnTypeChange,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DMLCheckRecursiveCTEWithoutLimit,
+			Desc:     "递归CTE需要使用LIMIT限制递归次数",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeDMLConvention,
+		},
+		Message:      "递归CTE没有使用LIMIT限制, 递归条件有误时将递归至cte_max_recursion_depth后报错",
+		AllowOffline: true,
+		Func:         checkRecursiveCTEWithoutLimit,
+	},
 	{
 		Rule: driver.Rule{
 			Name:     DMLDisableSelectAllColumn,
@@ -578,6 +779,7 @@ var RuleHandlers = []RuleHandler{
 		Message:      "表建议添加注释",
 		AllowOffline: true,
 		Func:         checkTableWithoutComment,
+		Fix:          fixTableWithoutComment,
 	},
 	{
 		Rule: driver.Rule{
@@ -804,6 +1006,7 @@ var RuleHandlers = []RuleHandler{
 		Message:      "delete/update 语句必须有limit条件",
 		Func:         checkDMLLimitExist,
 		AllowOffline: true,
+		Fix:          fixDMLLimitExist,
 	},
 	{
 		Rule: driver.Rule{
@@ -1088,6 +1291,31 @@ var RuleHandlers = []RuleHandler{
 		AllowOffline: false,
 		Func:         checkExplain,
 	},
+	{
+		Rule: driver.Rule{
+			Name:     DMLCheckAffectedRows,
+			Desc:     "UPDATE/DELETE操作影响行数不建议超过阈值",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeDMLConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   affectedRowsMaxKeyName,
+					Value: "10000",
+					Desc:  "最大影响行数",
+					Type:  params.ParamTypeInt,
+				},
+				&params.Param{
+					Key:   affectedRowsCountTimeoutKeyName,
+					Value: "0",
+					Desc:  "精确统计影响行数的超时时间(秒), 为0时只通过EXPLAIN估算",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message:      "该语句预计影响行数为%v, 超过阈值%v",
+		AllowOffline: false,
+		Func:         checkAffectedRows,
+	},
 	{
 		Rule: driver.Rule{
 			Name:     DDLCheckCreateView,
@@ -1132,11 +1360,91 @@ var RuleHandlers = []RuleHandler{
 		AllowOffline: true,
 		Func:         checkCreateProcedure,
 	},
+	// schema design
+	{
+		Rule: driver.Rule{
+			Name:     SchemaDesignCheckPKExist,
+			Desc:     "库表设计审核时, 表必须有主键",
+			Level:    driver.RuleLevelError,
+			Category: RuleTypeSchemaDesign,
+		},
+		Message: "表没有主键",
+		Func:    nil,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     SchemaDesignCheckDuplicateIndex,
+			Desc:     "库表设计审核时, 表不应存在重复索引",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeSchemaDesign,
+		},
+		Message: "存在重复索引:%v",
+		Func:    nil,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     SchemaDesignCheckRedundantIndex,
+			Desc:     "库表设计审核时, 表不建议存在冗余索引",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeSchemaDesign,
+		},
+		Message: "已存在索引 %v , 索引 %v 为冗余索引",
+		Func:    nil,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     SchemaDesignCheckTableCharset,
+			Desc:     "库表设计审核时, 表的字符集建议与库中大多数表一致",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeSchemaDesign,
+		},
+		Message: "表字符集 %s 与库中大多数表的字符集 %s 不一致",
+		Func:    nil,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     SchemaDesignCheckColumnCharset,
+			Desc:     "库表设计审核时, 列的字符集建议与表一致",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeSchemaDesign,
+		},
+		Message: "列 %s 的字符集与表字符集 %s 不一致",
+		Func:    nil,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     SchemaDesignCheckFKLikeColumn,
+			Desc:     "库表设计审核时, 命名类似外键的列建议在库中存在对应的表",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeSchemaDesign,
+		},
+		Message: "列 %s 的命名类似外键, 但库中不存在对应的表",
+		Func:    nil,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     SchemaDesignCheckTableSize,
+			Desc:     "库表设计审核时, 表大小不建议超过指定值",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeSchemaDesign,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "10240",
+					Desc:  "表大小（MB）",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message: "表大小为%vMB, 超过了%vMB, 建议归档或拆分",
+		Func:    nil,
+	},
 }
 
 func init() {
-	for _, rh := range RuleHandlers {
-		RuleHandlerMap[rh.Rule.Name] = rh
+	for i := range RuleHandlers {
+		RuleHandlers[i].Rule.Knowledge = ruleKnowledges[RuleHandlers[i].Rule.Name]
+		RuleHandlerMap[RuleHandlers[i].Rule.Name] = RuleHandlers[i]
 	}
 }
 
@@ -1244,7 +1552,7 @@ func checkIndexesExistBeforeCreatConstraints(ctx *session.Context, rule driver.R
 			if spec.Constraint != nil && (spec.Constraint.Tp == ast.ConstraintPrimaryKey ||
 				spec.Constraint.Tp == ast.ConstraintUniq || spec.Constraint.Tp == ast.ConstraintUniqKey) {
 				for _, key := range spec.Constraint.Keys {
-					cols = append(cols, key.Column.Name.String())
+					cols = append(cols, util.GetIndexPartName(key).String())
 				}
 			}
 		}
@@ -1257,7 +1565,7 @@ func checkIndexesExistBeforeCreatConstraints(ctx *session.Context, rule driver.R
 		}
 		for _, constraints := range createTableStmt.Constraints {
 			for _, key := range constraints.Keys {
-				constraintMap[key.Column.Name.String()] = struct{}{}
+				constraintMap[util.GetIndexPartName(key).String()] = struct{}{}
 			}
 		}
 		for _, col := range cols {
@@ -1316,7 +1624,7 @@ func checkPrimaryKey(ctx *session.Context, rule driver.Rule, res *driver.AuditRe
 			if constraint.Tp == ast.ConstraintPrimaryKey {
 				hasPk = true
 				if len(constraint.Keys) == 1 {
-					columnName := constraint.Keys[0].Column.Name.String()
+					columnName := util.GetIndexPartName(constraint.Keys[0]).String()
 					for _, col := range stmt.Cols {
 						if col.Name.Name.String() == columnName {
 							pkColumnExist = true
@@ -1354,7 +1662,7 @@ func checkPrimaryKey(ctx *session.Context, rule driver.Rule, res *driver.AuditRe
 					if spec.Constraint.Tp == ast.ConstraintPrimaryKey {
 						if len(spec.Constraint.Keys) == 1 {
 							for _, col := range originTable.Cols {
-								if col.Name.Name.L == spec.Constraint.Keys[0].Column.Name.L {
+								if col.Name.Name.L == util.GetIndexPartName(spec.Constraint.Keys[0]).L {
 									alterPK = true
 									inspectCol(col)
 								}
@@ -1571,7 +1879,7 @@ func disableAddIndexForColumnsTypeBlob(ctx *session.Context, rule driver.Rule, r
 			switch constraint.Tp {
 			case ast.ConstraintIndex, ast.ConstraintUniqIndex, ast.ConstraintKey, ast.ConstraintUniqKey:
 				for _, col := range constraint.Keys {
-					if isTypeBlobCols[col.Column.Name.String()] {
+					if isTypeBlobCols[util.GetIndexPartName(col).String()] {
 						indexDataTypeIsBlob = true
 						break
 					}
@@ -1615,7 +1923,7 @@ func disableAddIndexForColumnsTypeBlob(ctx *session.Context, rule driver.Rule, r
 			switch spec.Constraint.Tp {
 			case ast.ConstraintIndex, ast.ConstraintUniq:
 				for _, col := range spec.Constraint.Keys {
-					if isTypeBlobCols[col.Column.Name.String()] {
+					if isTypeBlobCols[util.GetIndexPartName(col).String()] {
 						indexDataTypeIsBlob = true
 						break
 					}
@@ -1635,7 +1943,7 @@ func disableAddIndexForColumnsTypeBlob(ctx *session.Context, rule driver.Rule, r
 			}
 		}
 		for _, indexColumns := range stmt.IndexPartSpecifications {
-			if isTypeBlobCols[indexColumns.Column.Name.String()] {
+			if isTypeBlobCols[util.GetIndexPartName(indexColumns).String()] {
 				indexDataTypeIsBlob = true
 				break
 			}
@@ -1782,6 +2090,10 @@ func checkIndex(ctx *session.Context, rule driver.Rule, res *driver.AuditResult,
 	case *ast.CreateTableStmt:
 		// check index
 		for _, constraint := range stmt.Constraints {
+			// CHECK constraint is not an index.
+			if constraint.Tp == ast.ConstraintCheck {
+				continue
+			}
 			switch constraint.Tp {
 			case ast.ConstraintIndex, ast.ConstraintUniqIndex, ast.ConstraintKey, ast.ConstraintUniqKey:
 				indexCounter++
@@ -1791,15 +2103,15 @@ func checkIndex(ctx *session.Context, rule driver.Rule, res *driver.AuditResult,
 			}
 			singleConstraint := index{Name: constraint.Name, Column: []string{}}
 			for _, key := range constraint.Keys {
-				singleConstraint.Column = append(singleConstraint.Column, key.Column.Name.L)
-				singleIndexCounter[key.Column.Name.L]++
+				singleConstraint.Column = append(singleConstraint.Column, util.GetIndexPartName(key).L)
+				singleIndexCounter[util.GetIndexPartName(key).L]++
 			}
 			newIndexs = append(newIndexs, singleConstraint)
 		}
 	case *ast.AlterTableStmt:
 		hasAddConstraint := false
 		for _, spec := range stmt.Specs {
-			if spec.Constraint == nil {
+			if spec.Constraint == nil || spec.Constraint.Tp == ast.ConstraintCheck {
 				continue
 			}
 			switch spec.Constraint.Tp {
@@ -1814,8 +2126,8 @@ func checkIndex(ctx *session.Context, rule driver.Rule, res *driver.AuditResult,
 				hasAddConstraint = true
 				singleConstraint := index{Name: spec.Constraint.Name, Column: []string{}}
 				for _, key := range spec.Constraint.Keys {
-					singleConstraint.Column = append(singleConstraint.Column, key.Column.Name.L)
-					singleIndexCounter[key.Column.Name.L]++
+					singleConstraint.Column = append(singleConstraint.Column, util.GetIndexPartName(key).L)
+					singleIndexCounter[util.GetIndexPartName(key).L]++
 				}
 				newIndexs = append(newIndexs, singleConstraint)
 			}
@@ -1826,15 +2138,18 @@ func checkIndex(ctx *session.Context, rule driver.Rule, res *driver.AuditResult,
 		}
 		if exist {
 			for _, constraint := range createTableStmt.Constraints {
+				if constraint.Tp == ast.ConstraintCheck {
+					continue
+				}
 				switch constraint.Tp {
 				case ast.ConstraintIndex, ast.ConstraintUniqIndex, ast.ConstraintKey, ast.ConstraintUniqKey:
 					indexCounter++
 				}
 				singleConstraint := index{Name: constraint.Name, Column: []string{}}
 				for _, key := range constraint.Keys {
-					singleConstraint.Column = append(singleConstraint.Column, key.Column.Name.L)
+					singleConstraint.Column = append(singleConstraint.Column, util.GetIndexPartName(key).L)
 					if hasAddConstraint {
-						singleIndexCounter[key.Column.Name.L]++
+						singleIndexCounter[util.GetIndexPartName(key).L]++
 					}
 				}
 				tableIndexs = append(tableIndexs, singleConstraint)
@@ -1848,8 +2163,8 @@ func checkIndex(ctx *session.Context, rule driver.Rule, res *driver.AuditResult,
 		}
 		singleConstraint := index{Name: stmt.IndexName, Column: []string{}}
 		for _, key := range stmt.IndexPartSpecifications {
-			singleConstraint.Column = append(singleConstraint.Column, key.Column.Name.L)
-			singleIndexCounter[key.Column.Name.L]++
+			singleConstraint.Column = append(singleConstraint.Column, util.GetIndexPartName(key).L)
+			singleIndexCounter[util.GetIndexPartName(key).L]++
 		}
 		newIndexs = append(newIndexs, singleConstraint)
 		createTableStmt, exist, err := ctx.GetCreateTableStmt(stmt.Table)
@@ -1858,14 +2173,17 @@ func checkIndex(ctx *session.Context, rule driver.Rule, res *driver.AuditResult,
 		}
 		if exist {
 			for _, constraint := range createTableStmt.Constraints {
+				if constraint.Tp == ast.ConstraintCheck {
+					continue
+				}
 				switch constraint.Tp {
 				case ast.ConstraintIndex, ast.ConstraintUniqIndex, ast.ConstraintKey, ast.ConstraintUniqKey:
 					indexCounter++
 				}
 				singleConstraint := index{Name: constraint.Name, Column: []string{}}
 				for _, key := range constraint.Keys {
-					singleConstraint.Column = append(singleConstraint.Column, key.Column.Name.L)
-					singleIndexCounter[key.Column.Name.L]++
+					singleConstraint.Column = append(singleConstraint.Column, util.GetIndexPartName(key).L)
+					singleIndexCounter[util.GetIndexPartName(key).L]++
 				}
 				tableIndexs = append(tableIndexs, singleConstraint)
 			}
@@ -1998,14 +2316,14 @@ func checkStringType(ctx *session.Context, rule driver.Rule, res *driver.AuditRe
 		// if char length >20 using varchar.
 		for _, col := range stmt.Cols {
 			if col.Tp != nil && col.Tp.Tp == mysql.TypeString && col.Tp.Flen > 20 {
-				addResult(res, rule, DDLCheckColumnCharLength)
+				addResultAt(res, rule, DDLCheckColumnCharLength, node, col.Name.Name.O)
 			}
 		}
 	case *ast.AlterTableStmt:
 		for _, spec := range stmt.Specs {
 			for _, col := range spec.NewColumns {
 				if col.Tp != nil && col.Tp.Tp == mysql.TypeString && col.Tp.Flen > 20 {
-					addResult(res, rule, DDLCheckColumnCharLength)
+					addResultAt(res, rule, DDLCheckColumnCharLength, node, col.Name.Name.O)
 				}
 			}
 		}
@@ -2146,7 +2464,7 @@ func checkColumnWithoutComment(ctx *session.Context, rule driver.Rule, res *driv
 				}
 			}
 			if !columnHasComment {
-				addResult(res, rule, DDLCheckColumnWithoutComment)
+				addResultAt(res, rule, DDLCheckColumnWithoutComment, node, col.Name.Name.O)
 				return nil
 			}
 		}
@@ -2163,7 +2481,7 @@ func checkColumnWithoutComment(ctx *session.Context, rule driver.Rule, res *driv
 					}
 				}
 				if !columnHasComment {
-					addResult(res, rule, DDLCheckColumnWithoutComment)
+					addResultAt(res, rule, DDLCheckColumnWithoutComment, node, col.Name.Name.O)
 					return nil
 				}
 			}
@@ -2199,7 +2517,7 @@ func checkIndexPrefix(ctx *session.Context, rule driver.Rule, res *driver.AuditR
 	prefix := rule.Params.GetParam(DefaultSingleParamKeyName).String()
 	for _, name := range indexesName {
 		if !utils.HasPrefix(name, prefix, false) {
-			addResult(res, rule, DDLCheckIndexPrefix, prefix)
+			addResultAt(res, rule, DDLCheckIndexPrefix, node, name, prefix)
 			return nil
 		}
 	}
@@ -2211,7 +2529,7 @@ func checkUniqIndexPrefix(ctx *session.Context, rule driver.Rule, res *driver.Au
 	prefix := rule.Params.GetParam(DefaultSingleParamKeyName).String()
 	for index := range indexes {
 		if !utils.HasPrefix(index, prefix, false) {
-			addResult(res, rule, DDLCheckUniqueIndexPrefix, prefix)
+			addResultAt(res, rule, DDLCheckUniqueIndexPrefix, node, index, prefix)
 			return nil
 		}
 	}
@@ -2222,7 +2540,7 @@ func checkUniqIndex(ctx *session.Context, rule driver.Rule, res *driver.AuditRes
 	tableName, indexes := getTableUniqIndex(node)
 	for index, indexedCols := range indexes {
 		if !strings.EqualFold(index, fmt.Sprintf("IDX_UK_%v_%v", tableName, strings.Join(indexedCols, "_"))) {
-			addResult(res, rule, DDLCheckUniqueIndex)
+			addResultAt(res, rule, DDLCheckUniqueIndex, node, index)
 			return nil
 		}
 	}
@@ -2240,7 +2558,7 @@ func getTableUniqIndex(node ast.Node) (string, map[string][]string) {
 			switch constraint.Tp {
 			case ast.ConstraintUniq:
 				for _, key := range constraint.Keys {
-					indexes[constraint.Name] = append(indexes[constraint.Name], key.Column.Name.String())
+					indexes[constraint.Name] = append(indexes[constraint.Name], util.GetIndexPartName(key).String())
 				}
 			}
 		}
@@ -2250,7 +2568,7 @@ func getTableUniqIndex(node ast.Node) (string, map[string][]string) {
 			switch spec.Constraint.Tp {
 			case ast.ConstraintUniq:
 				for _, key := range spec.Constraint.Keys {
-					indexes[spec.Constraint.Name] = append(indexes[spec.Constraint.Name], key.Column.Name.String())
+					indexes[spec.Constraint.Name] = append(indexes[spec.Constraint.Name], util.GetIndexPartName(key).String())
 				}
 			}
 		}
@@ -2258,7 +2576,7 @@ func getTableUniqIndex(node ast.Node) (string, map[string][]string) {
 		tableName = stmt.Table.Name.String()
 		if stmt.KeyType == ast.IndexKeyTypeUnique {
 			for _, indexCol := range stmt.IndexPartSpecifications {
-				indexes[stmt.IndexName] = append(indexes[stmt.IndexName], indexCol.Column.Name.String())
+				indexes[stmt.IndexName] = append(indexes[stmt.IndexName], util.GetIndexPartName(indexCol).String())
 			}
 		}
 	default:
@@ -2292,7 +2610,7 @@ func checkColumnWithoutDefault(ctx *session.Context, rule driver.Rule, res *driv
 				continue
 			}
 			if !columnHasDefault {
-				addResult(res, rule, DDLCheckColumnWithoutDefault)
+				addResultAt(res, rule, DDLCheckColumnWithoutDefault, node, col.Name.Name.O)
 				return nil
 			}
 		}
@@ -2321,7 +2639,7 @@ func checkColumnWithoutDefault(ctx *session.Context, rule driver.Rule, res *driv
 					continue
 				}
 				if !columnHasDefault {
-					addResult(res, rule, DDLCheckColumnWithoutDefault)
+					addResultAt(res, rule, DDLCheckColumnWithoutDefault, node, col.Name.Name.O)
 					return nil
 				}
 			}
@@ -2344,7 +2662,7 @@ func checkColumnTimestampWithoutDefault(ctx *session.Context, rule driver.Rule,
 				}
 			}
 			if !columnHasDefault && (col.Tp.Tp == mysql.TypeTimestamp || col.Tp.Tp == mysql.TypeDatetime) {
-				addResult(res, rule, DDLCheckColumnTimestampWithoutDefault)
+				addResultAt(res, rule, DDLCheckColumnTimestampWithoutDefault, node, col.Name.Name.O)
 				return nil
 			}
 		}
@@ -2361,7 +2679,7 @@ func checkColumnTimestampWithoutDefault(ctx *session.Context, rule driver.Rule,
 					}
 				}
 				if !columnHasDefault && (col.Tp.Tp == mysql.TypeTimestamp || col.Tp.Tp == mysql.TypeDatetime) {
-					addResult(res, rule, DDLCheckColumnTimestampWithoutDefault)
+					addResultAt(res, rule, DDLCheckColumnTimestampWithoutDefault, node, col.Name.Name.O)
 					return nil
 				}
 			}
@@ -2384,7 +2702,7 @@ func checkColumnBlobNotNull(ctx *session.Context, rule driver.Rule, res *driver.
 			case mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeTinyBlob, mysql.TypeLongBlob:
 				for _, opt := range col.Options {
 					if opt.Tp == ast.ColumnOptionNotNull {
-						addResult(res, rule, DDLCheckColumnBlobWithNotNull)
+						addResultAt(res, rule, DDLCheckColumnBlobWithNotNull, node, col.Name.Name.O)
 						return nil
 					}
 				}
@@ -2404,7 +2722,7 @@ func checkColumnBlobNotNull(ctx *session.Context, rule driver.Rule, res *driver.
 				case mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeTinyBlob, mysql.TypeLongBlob:
 					for _, opt := range col.Options {
 						if opt.Tp == ast.ColumnOptionNotNull {
-							addResult(res, rule, DDLCheckColumnBlobWithNotNull)
+							addResultAt(res, rule, DDLCheckColumnBlobWithNotNull, node, col.Name.Name.O)
 							return nil
 						}
 					}
@@ -2435,7 +2753,7 @@ func checkColumnShouldNotBeType(rule driver.Rule, res *driver.AuditResult, node
 				continue
 			}
 			if bytes.Contains(colTypes, []byte{col.Tp.Tp}) {
-				addResult(res, rule, rule.Name)
+				addResultAt(res, rule, rule.Name, node, col.Name.Name.O)
 				return nil
 			}
 		}
@@ -2455,7 +2773,7 @@ func checkColumnShouldNotBeType(rule driver.Rule, res *driver.AuditResult, node
 				}
 
 				if bytes.Contains(colTypes, []byte{newCol.Tp.Tp}) {
-					addResult(res, rule, rule.Name)
+					addResultAt(res, rule, rule.Name, node, newCol.Name.Name.O)
 					return nil
 				}
 			}
@@ -2479,7 +2797,7 @@ func checkColumnBlobDefaultNull(ctx *session.Context, rule driver.Rule, res *dri
 			case mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeTinyBlob, mysql.TypeLongBlob:
 				for _, opt := range col.Options {
 					if opt.Tp == ast.ColumnOptionDefaultValue && opt.Expr.GetType().Tp != mysql.TypeNull {
-						addResult(res, rule, DDLCheckColumnBlobDefaultIsNotNull)
+						addResultAt(res, rule, DDLCheckColumnBlobDefaultIsNotNull, node, col.Name.Name.O)
 						return nil
 					}
 				}
@@ -2499,7 +2817,7 @@ func checkColumnBlobDefaultNull(ctx *session.Context, rule driver.Rule, res *dri
 				case mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeTinyBlob, mysql.TypeLongBlob:
 					for _, opt := range col.Options {
 						if opt.Tp == ast.ColumnOptionDefaultValue && opt.Expr.GetType().Tp != mysql.TypeNull {
-							addResult(res, rule, DDLCheckColumnBlobDefaultIsNotNull)
+							addResultAt(res, rule, DDLCheckColumnBlobDefaultIsNotNull, node, col.Name.Name.O)
 							return nil
 						}
 					}
@@ -2636,17 +2954,30 @@ func checkExistFunc(ctx *session.Context, rule driver.Rule, res *driver.AuditRes
 		return false
 	}
 	var cols []*ast.ColumnDef
+	// the function is allowed if it is indexed by functional index, see
+	// https://dev.mysql.com/doc/refman/8.0/en/create-index.html#create-index-functional-key-parts
+	indexedExprs := make(map[string]struct{})
 	for _, tableName := range tables {
 		createTableStmt, exist, err := ctx.GetCreateTableStmt(tableName)
 		if exist && err == nil {
 			cols = append(cols, createTableStmt.Cols...)
+			for _, constraint := range createTableStmt.Constraints {
+				for _, key := range constraint.Keys {
+					if !util.IsFunctionalIndexPart(key) {
+						continue
+					}
+					if expr, err := util.RestoreSql(key.Expr); err == nil {
+						indexedExprs[strings.ToLower(expr)] = struct{}{}
+					}
+				}
+			}
 		}
 	}
 	colMap := make(map[string]struct{})
 	for _, col := range cols {
 		colMap[col.Name.String()] = struct{}{}
 	}
-	if util.IsFuncUsedOnColumnInWhereStmt(colMap, where) {
+	if util.IsFuncUsedOnColumnInWhereStmt(colMap, indexedExprs, where) {
 		addResult(res, rule, DMLCheckWhereExistFunc)
 		return true
 	}
@@ -2854,14 +3185,14 @@ func checkDecimalTypeColumn(ctx *session.Context, rule driver.Rule, res *driver.
 	case *ast.CreateTableStmt:
 		for _, col := range stmt.Cols {
 			if col.Tp != nil && (col.Tp.Tp == mysql.TypeFloat || col.Tp.Tp == mysql.TypeDouble) {
-				addResult(res, rule, DDLCheckDecimalTypeColumn)
+				addResultAt(res, rule, DDLCheckDecimalTypeColumn, node, col.Name.Name.O)
 			}
 		}
 	case *ast.AlterTableStmt:
 		for _, spec := range stmt.Specs {
 			for _, col := range spec.NewColumns {
 				if col.Tp != nil && (col.Tp.Tp == mysql.TypeFloat || col.Tp.Tp == mysql.TypeDouble) {
-					addResult(res, rule, DDLCheckDecimalTypeColumn)
+					addResultAt(res, rule, DDLCheckDecimalTypeColumn, node, col.Name.Name.O)
 				}
 			}
 		}
@@ -3033,12 +3364,19 @@ func checkIndexOption(ctx *session.Context, rule driver.Rule, res *driver.AuditR
 				continue
 			}
 			for _, key := range spec.Constraint.Keys {
+				// the cardinality of the functional key part can not be calculated.
+				if util.IsFunctionalIndexPart(key) {
+					continue
+				}
 				indexColumns = append(indexColumns, key.Column.Name.String())
 			}
 		}
 	case *ast.CreateIndexStmt:
 		tableName = stmt.Table
 		for _, indexCol := range stmt.IndexPartSpecifications {
+			if util.IsFunctionalIndexPart(indexCol) {
+				continue
+			}
 			indexColumns = append(indexColumns, indexCol.Column.Name.String())
 		}
 	default:
@@ -3173,3 +3511,243 @@ func checkCreateProcedure(ctx *session.Context, rule driver.Rule, res *driver.Au
 	}
 	return nil
 }
+
+func checkInvisibleIndex(ctx *session.Context, rule driver.Rule, res *driver.AuditResult, node ast.Node) error {
+	indexes := []string{}
+	switch stmt := node.(type) {
+	case *ast.CreateTableStmt:
+		for _, constraint := range stmt.Constraints {
+			if util.IsInvisibleIndex(constraint.Option) {
+				indexes = append(indexes, constraint.Name)
+			}
+		}
+	case *ast.AlterTableStmt:
+		for _, spec := range stmt.Specs {
+			switch spec.Tp {
+			case ast.AlterTableAddConstraint:
+				if util.IsInvisibleIndex(spec.Constraint.Option) {
+					indexes = append(indexes, spec.Constraint.Name)
+				}
+			case ast.AlterTableIndexInvisible:
+				if spec.Visibility == ast.IndexVisibilityInvisible {
+					indexes = append(indexes, spec.Name)
+				}
+			}
+		}
+	case *ast.CreateIndexStmt:
+		if util.IsInvisibleIndex(stmt.IndexOption) {
+			indexes = append(indexes, stmt.IndexName)
+		}
+	}
+	if len(indexes) > 0 {
+		addResult(res, rule, DDLCheckInvisibleIndex, strings.Join(indexes, ","))
+	}
+	return nil
+}
+
+var (
+	limitReg             = regexp.MustCompile(`(?i)\blimit\b`)
+	cteMaxRecursionReg   = regexp.MustCompile(`(?i)\bcte_max_recursion_depth\b`)
+	cteBodyStartReg      = regexp.MustCompile(`(?i)\bas\s*\(`)
+	cteNameBeforeBodyReg = regexp.MustCompile("([`\\w]+)\\s*(?:\\([^()]*\\))?\\s*$")
+)
+
+// The parser does not support WITH statement now, so the recursive CTE is matched by characters.
+// The LIMIT is only accepted in the body of the recursive CTE, or the recursion depth is limited
+// by the SET_VAR(cte_max_recursion_depth = N) hint.
+func checkRecursiveCTEWithoutLimit(ctx *session.Context, rule driver.Rule, res *driver.AuditResult, node ast.Node) error {
+	stmt, ok := node.(*ast.UnparsedStmt)
+	if !ok {
+		return nil
+	}
+	if _, isRecursive := util.IsCTEStmt(stmt.Text()); !isRecursive {
+		return nil
+	}
+	masked := maskSQLLiterals(stmt.Text())
+	if cteMaxRecursionReg.MatchString(masked) {
+		return nil
+	}
+	for _, body := range getRecursiveCTEBodies(stmt.Text(), masked) {
+		if !limitReg.MatchString(body) {
+			addResult(res, rule, DMLCheckRecursiveCTEWithoutLimit)
+			return nil
+		}
+	}
+	return nil
+}
+
+// getRecursiveCTEBodies returns the bodies of the CTEs which refer to themselves, the bodies
+// are cut from the masked sql, see maskSQLLiterals.
+func getRecursiveCTEBodies(sql, masked string) []string {
+	bodies := []string{}
+	bodyEnd := 0
+	for _, loc := range cteBodyStartReg.FindAllStringIndex(masked, -1) {
+		// the CTE is defined at the top level of the statement.
+		if loc[0] < bodyEnd || strings.Count(masked[:loc[0]], "(") != strings.Count(masked[:loc[0]], ")") {
+			continue
+		}
+		bodyStart := loc[1]
+		bodyEnd = bodyStart
+		for depth := 1; bodyEnd < len(masked) && depth > 0; bodyEnd++ {
+			switch masked[bodyEnd] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+		}
+		name := cteNameBeforeBodyReg.FindStringSubmatch(sql[:loc[0]])
+		if name == nil {
+			continue
+		}
+		nameReg := regexp.MustCompile(`(?i)(^|[^\w])` + regexp.QuoteMeta(strings.Trim(name[1], "`")) + `([^\w]|$)`)
+		if nameReg.MatchString(sql[bodyStart:bodyEnd]) {
+			bodies = append(bodies, masked[bodyStart:bodyEnd])
+		}
+	}
+	return bodies
+}
+
+// maskSQLLiterals replaces the string literals, quoted identifiers and comments in the sql with
+// spaces, so the keywords in them are not matched. The optimizer hints are kept.
+func maskSQLLiterals(sql string) string {
+	masked := []byte(sql)
+	mask := func(start, end int) {
+		for ; start < end && start < len(masked); start++ {
+			masked[start] = ' '
+		}
+	}
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for ; end < len(sql); end++ {
+				if sql[end] == '\\' && c != '`' {
+					end++
+					continue
+				}
+				if sql[end] == c {
+					if end+1 < len(sql) && sql[end+1] == c {
+						end++
+						continue
+					}
+					break
+				}
+			}
+			mask(i, end+1)
+			i = end
+		case c == '#' || (c == '-' && strings.HasPrefix(sql[i:], "-- ")):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			mask(i, i+end)
+			i += end
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				end = len(sql) - i - 2
+			}
+			if !strings.HasPrefix(sql[i:], "/*+") {
+				mask(i, i+end+4)
+			}
+			i += end + 3
+		}
+	}
+	return string(masked)
+}
+
+const (
+	affectedRowsMaxKeyName          = "max_affected_rows"
+	affectedRowsCountTimeoutKeyName = "count_timeout"
+)
+
+func checkAffectedRows(ctx *session.Context, rule driver.Rule, res *driver.AuditResult, node ast.Node) error {
+	var (
+		tableRefs    *ast.TableRefsClause
+		where        ast.ExprNode
+		limit        *ast.Limit
+		isMultiTable bool
+	)
+	switch stmt := node.(type) {
+	case *ast.UpdateStmt:
+		tableRefs, where, limit, isMultiTable = stmt.TableRefs, stmt.Where, stmt.Limit, stmt.MultipleTable
+	case *ast.DeleteStmt:
+		tableRefs, where, limit, isMultiTable = stmt.TableRefs, stmt.Where, stmt.Limit, stmt.IsMultiTable
+	default:
+		return nil
+	}
+
+	rows, ok, err := estimateAffectedRows(ctx, node)
+	if err != nil {
+		log.NewEntry().Errorf("estimate affected rows failed, sql: %v, error: %v", node.Text(), err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	// the count is exact only for single table, it is expensive on big table, so it
+	// is canceled when timeout and the estimated rows is used.
+	timeout := rule.Params.GetParam(affectedRowsCountTimeoutKeyName).Int()
+	if timeout > 0 && !isMultiTable && tableRefs != nil {
+		count, err := countAffectedRows(ctx, tableRefs, where, time.Duration(timeout)*time.Second)
+		if err != nil {
+			log.NewEntry().Warnf("count affected rows failed, use the estimated rows, sql: %v, error: %v", node.Text(), err)
+		} else {
+			rows = count
+		}
+	}
+
+	if limit != nil {
+		max, err := util.GetLimitCount(limit, rows)
+		if err == nil && max < rows {
+			rows = max
+		}
+	}
+
+	res.SetEstimatedAffectedRows(rows)
+	max := rule.Params.GetParam(affectedRowsMaxKeyName).Int()
+	if rows > int64(max) {
+		addResult(res, rule, DMLCheckAffectedRows, rows, max)
+	}
+	return nil
+}
+
+// estimateAffectedRows estimates the affected rows by the execution plan, the rows of
+// each table is filtered by the percentage of the WHERE condition.
+func estimateAffectedRows(ctx *session.Context, node ast.Node) (int64, bool, error) {
+	records, err := ctx.GetExecutionPlan(node.Text())
+	if err != nil {
+		return 0, false, err
+	}
+	if len(records) == 0 {
+		return 0, false, nil
+	}
+	var rows int64
+	for _, record := range records {
+		filtered, err := strconv.ParseFloat(record.Filtered, 64)
+		if err != nil {
+			// the filtered column is not shown before MySQL 5.7.
+			filtered = 100
+		}
+		estimated := int64(float64(record.Rows) * filtered / 100)
+		if estimated > rows {
+			rows = estimated
+		}
+	}
+	return rows, true, nil
+}
+
+func countAffectedRows(ctx *session.Context, tableRefs *ast.TableRefsClause, where ast.ExprNode,
+	timeout time.Duration) (int64, error) {
+	table, err := util.RestoreSql(tableRefs.TableRefs)
+	if err != nil {
+		return 0, err
+	}
+	sql := fmt.Sprintf("SELECT COUNT(*) FROM %s", table)
+	if where != nil {
+		sql = fmt.Sprintf("%s WHERE %s", sql, util.ExprFormat(where))
+	}
+	return ctx.GetRecordCount(sql, timeout)
+}
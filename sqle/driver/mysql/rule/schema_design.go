@@ -0,0 +1,200 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/pingcap/parser/ast"
+)
+
+// SchemaTable is a table of the schema which design is audited as a unit.
+type SchemaTable struct {
+	Stmt   *ast.CreateTableStmt
+	SizeMB float64
+}
+
+// schemaInfo is the information of the whole schema which the tables are checked against.
+type schemaInfo struct {
+	tableNames   map[string]struct{}
+	majorCharset string
+}
+
+type schemaDesignCheckFunc func(rule driver.Rule, table *SchemaTable, schema *schemaInfo, res *driver.AuditResult)
+
+// schemaDesignChecks key is the schema design rule name.
+var schemaDesignChecks = map[string]schemaDesignCheckFunc{
+	SchemaDesignCheckPKExist:        checkSchemaTablePrimaryKey,
+	SchemaDesignCheckDuplicateIndex: checkSchemaTableIndex,
+	SchemaDesignCheckRedundantIndex: checkSchemaTableIndex,
+	SchemaDesignCheckTableCharset:   checkSchemaTableCharset,
+	SchemaDesignCheckColumnCharset:  checkSchemaColumnCharset,
+	SchemaDesignCheckFKLikeColumn:   checkSchemaTableFKLikeColumn,
+	SchemaDesignCheckTableSize:      checkSchemaTableSize,
+}
+
+// AuditSchemaDesign audits the design of all the tables in a schema by the schema design
+// rules in rules, the other rules are ignored. Unlike the rules which audit the SQL one by
+// one, it checks the problems across the tables. The audit results are returned in the
+// same order as the tables.
+func AuditSchemaDesign(tables []*SchemaTable, rules []*driver.Rule) []*driver.AuditResult {
+	schema := &schemaInfo{
+		tableNames:   make(map[string]struct{}, len(tables)),
+		majorCharset: getSchemaMajorCharset(tables),
+	}
+	for _, table := range tables {
+		schema.tableNames[table.Stmt.Table.Name.L] = struct{}{}
+	}
+
+	results := make([]*driver.AuditResult, 0, len(tables))
+	for _, table := range tables {
+		res := driver.NewInspectResults()
+		for _, rule := range rules {
+			if check, ok := schemaDesignChecks[rule.Name]; ok {
+				check(*rule, table, schema, res)
+			}
+		}
+		res.SortByLevel()
+		results = append(results, res)
+	}
+	return results
+}
+
+func checkSchemaTablePrimaryKey(rule driver.Rule, table *SchemaTable, _ *schemaInfo, res *driver.AuditResult) {
+	if _, hasPk := util.GetPrimaryKey(table.Stmt); !hasPk {
+		addResult(res, rule, SchemaDesignCheckPKExist)
+	}
+}
+
+func checkSchemaTableIndex(rule driver.Rule, table *SchemaTable, _ *schemaInfo, res *driver.AuditResult) {
+	indexes := []index{}
+	for _, constraint := range table.Stmt.Constraints {
+		var name string
+		switch constraint.Tp {
+		case ast.ConstraintPrimaryKey:
+			name = "PRIMARY"
+		case ast.ConstraintIndex, ast.ConstraintKey, ast.ConstraintUniq, ast.ConstraintUniqIndex, ast.ConstraintUniqKey:
+			name = constraint.Name
+		default:
+			continue
+		}
+		columns := []string{}
+		for _, key := range constraint.Keys {
+			columns = append(columns, util.GetIndexPartName(key).L)
+		}
+		indexes = append(indexes, index{Name: name, Column: columns})
+	}
+	repeat, redundancy := checkRedundantIndex(indexes)
+	if len(repeat) > 0 {
+		addResult(res, rule, SchemaDesignCheckDuplicateIndex, strings.Join(repeat, " , "))
+	}
+	redundant := make([]string, 0, len(redundancy))
+	for red := range redundancy {
+		redundant = append(redundant, red)
+	}
+	sort.Strings(redundant)
+	for _, red := range redundant {
+		addResult(res, rule, SchemaDesignCheckRedundantIndex, redundancy[red], red)
+	}
+}
+
+func getTableCharset(table *ast.CreateTableStmt) string {
+	charset, _ := getTableCharsetAndCollation(table)
+	return strings.ToLower(charset)
+}
+
+// getSchemaMajorCharset returns the character set used by most of the tables.
+func getSchemaMajorCharset(tables []*SchemaTable) string {
+	counter := map[string]int{}
+	for _, table := range tables {
+		if charset := getTableCharset(table.Stmt); charset != "" {
+			counter[charset]++
+		}
+	}
+	var major string
+	for charset, count := range counter {
+		if count > counter[major] || (count == counter[major] && charset < major) {
+			major = charset
+		}
+	}
+	return major
+}
+
+func checkSchemaTableCharset(rule driver.Rule, table *SchemaTable, schema *schemaInfo, res *driver.AuditResult) {
+	charset := getTableCharset(table.Stmt)
+	if charset != "" && schema.majorCharset != "" && charset != schema.majorCharset {
+		addResult(res, rule, SchemaDesignCheckTableCharset, charset, schema.majorCharset)
+	}
+}
+
+func checkSchemaColumnCharset(rule driver.Rule, table *SchemaTable, _ *schemaInfo, res *driver.AuditResult) {
+	charset := getTableCharset(table.Stmt)
+	if charset == "" {
+		return
+	}
+	columns := []string{}
+	for _, col := range table.Stmt.Cols {
+		if col.Tp == nil || !isCharsetColumnType(col.Tp) || col.Tp.Charset == "" {
+			continue
+		}
+		if !strings.EqualFold(col.Tp.Charset, charset) {
+			columns = append(columns, fmt.Sprintf("%s(%s)", col.Name.Name.O, strings.ToLower(col.Tp.Charset)))
+		}
+	}
+	if len(columns) > 0 {
+		addResult(res, rule, SchemaDesignCheckColumnCharset, strings.Join(columns, ","), charset)
+	}
+}
+
+var fkLikeColumnReg = regexp.MustCompile(`^(\w+)_id$`)
+
+// checkSchemaTableFKLikeColumn checks the column which is named like a foreign key, e.g.
+// "user_id", but the referenced table does not exist in the schema.
+func checkSchemaTableFKLikeColumn(rule driver.Rule, table *SchemaTable, schema *schemaInfo, res *driver.AuditResult) {
+	fkColumns := map[string]struct{}{}
+	for _, constraint := range table.Stmt.Constraints {
+		if constraint.Tp != ast.ConstraintForeignKey {
+			continue
+		}
+		for _, key := range constraint.Keys {
+			fkColumns[util.GetIndexPartName(key).L] = struct{}{}
+		}
+	}
+	orphans := []string{}
+	for _, col := range table.Stmt.Cols {
+		name := col.Name.Name.L
+		if _, ok := fkColumns[name]; ok {
+			continue
+		}
+		matches := fkLikeColumnReg.FindStringSubmatch(name)
+		if len(matches) != 2 {
+			continue
+		}
+		if !isTableExistInSchema(matches[1], schema.tableNames) {
+			orphans = append(orphans, col.Name.Name.O)
+		}
+	}
+	if len(orphans) > 0 {
+		addResult(res, rule, SchemaDesignCheckFKLikeColumn, strings.Join(orphans, ","))
+	}
+}
+
+// isTableExistInSchema checks the referenced table by the singular and plural name.
+func isTableExistInSchema(name string, tableNames map[string]struct{}) bool {
+	for _, candidate := range []string{name, name + "s", name + "es", strings.TrimSuffix(name, "y") + "ies"} {
+		if _, ok := tableNames[candidate]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func checkSchemaTableSize(rule driver.Rule, table *SchemaTable, _ *schemaInfo, res *driver.AuditResult) {
+	max := rule.Params.GetParam(DefaultSingleParamKeyName).Int()
+	if max > 0 && table.SizeMB > float64(max) {
+		addResult(res, rule, SchemaDesignCheckTableSize, table.SizeMB, max)
+	}
+}
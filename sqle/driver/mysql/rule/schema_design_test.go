@@ -0,0 +1,63 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditSchemaDesign(t *testing.T) {
+	sqls := []string{
+		`create table users(id bigint primary key, name varchar(32), key idx_name(name)) default charset=utf8mb4`,
+		`create table orders(id bigint primary key, user_id bigint, shop_id bigint, remark varchar(255) character set latin1,
+key idx_user(user_id), key idx_user_2(user_id), key idx_user_shop(user_id, shop_id)) default charset=utf8mb4`,
+		`create table logs(content text) default charset=utf8`,
+	}
+	tables := []*SchemaTable{}
+	for _, sql := range sqls {
+		node, err := util.ParseOneSql(sql)
+		assert.NoError(t, err)
+		tables = append(tables, &SchemaTable{Stmt: node.(*ast.CreateTableStmt), SizeMB: 10})
+	}
+	tables[0].SizeMB = 2048
+
+	rules := []*driver.Rule{}
+	for _, handler := range RuleHandlers {
+		rule := handler.Rule
+		if rule.Category != RuleTypeSchemaDesign {
+			continue
+		}
+		if rule.Name == SchemaDesignCheckTableSize {
+			rule.Params = rule.Params.Copy()
+			rule.Params.SetParamValue(DefaultSingleParamKeyName, "1024")
+		}
+		rules = append(rules, &rule)
+	}
+	// the rules which are not about schema design are ignored.
+	pkRule := RuleHandlerMap[DDLCheckPKNotExist].Rule
+	rules = append(rules, &pkRule)
+
+	results := AuditSchemaDesign(tables, rules)
+	assert.Len(t, results, 3)
+
+	assert.Equal(t, driver.RuleLevelWarn, results[0].Level())
+	assert.Equal(t, "[warn]表大小为2048MB, 超过了1024MB, 建议归档或拆分", results[0].Message())
+
+	assert.Equal(t, driver.RuleLevelWarn, results[1].Level())
+	assert.Contains(t, results[1].Message(), "存在重复索引:idx_user(user_id)")
+	assert.Contains(t, results[1].Message(), "索引 idx_user_2(user_id) 为冗余索引")
+	assert.Contains(t, results[1].Message(), "列 remark(latin1) 的字符集与表字符集 utf8mb4 不一致")
+	assert.Contains(t, results[1].Message(), "列 shop_id 的命名类似外键, 但库中不存在对应的表")
+
+	assert.Equal(t, driver.RuleLevelError, results[2].Level())
+	assert.Contains(t, results[2].Message(), "表没有主键")
+	assert.Contains(t, results[2].Message(), "表字符集 utf8 与库中大多数表的字符集 utf8mb4 不一致")
+
+	// the check is skipped if its rule is not in the rule template.
+	results = AuditSchemaDesign(tables, []*driver.Rule{&pkRule})
+	assert.Equal(t, driver.RuleLevelNull, results[2].Level())
+}
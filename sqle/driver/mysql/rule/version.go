@@ -0,0 +1,74 @@
+package rule
+
+import (
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var (
+	mysql80      = semver.MustParse("8.0.0")
+	mysql8012    = semver.MustParse("8.0.12")
+	mysql8013    = semver.MustParse("8.0.13")
+	mysql8016    = semver.MustParse("8.0.16")
+	mysql8028    = semver.MustParse("8.0.28")
+	mysql8029    = semver.MustParse("8.0.29")
+	mysqlDefault = semver.MustParse("5.7.0")
+)
+
+// MySQL 8.0 features which are not supported by the older versions.
+var (
+	FeatureCTE             = Feature{Name: "WITH(CTE)语句", Since: mysql80}
+	FeatureWindowFunc      = Feature{Name: "窗口函数", Since: mysql80}
+	FeatureInvisibleIndex  = Feature{Name: "不可见索引", Since: mysql80}
+	FeatureRenameColumn    = Feature{Name: "RENAME COLUMN", Since: mysql80}
+	FeatureFunctionalIndex = Feature{Name: "函数索引", Since: mysql8013}
+	FeatureCheckConstraint = Feature{Name: "CHECK约束", Since: mysql8016}
+)
+
+// Feature is a feature of MySQL which is supported since the version.
+type Feature struct {
+	Name  string
+	Since *semver.Version
+}
+
+// IsSupportedBy returns true if the feature is supported by the version.
+func (f Feature) IsSupportedBy(version *semver.Version) bool {
+	return !version.LessThan(f.Since)
+}
+
+// GetServerVersion returns the version of MySQL server, 5.7 is returned if the version is
+// unknown, e.g. audit offline, or the server is not MySQL.
+func GetServerVersion(ctx *session.Context) *semver.Version {
+	if version, ok := LookupServerVersion(ctx); ok {
+		return version
+	}
+	return mysqlDefault
+}
+
+// LookupServerVersion returns the version of MySQL server, ok is false if the version is unknown.
+func LookupServerVersion(ctx *session.Context) (version *semver.Version, ok bool) {
+	v, err := ctx.GetSystemVariable(session.SysVarVersion)
+	if err != nil {
+		return nil, false
+	}
+	return parseServerVersion(v)
+}
+
+func parseServerVersion(v string) (*semver.Version, bool) {
+	if v == "" || strings.Contains(strings.ToLower(v), "mariadb") {
+		return nil, false
+	}
+	version, err := semver.NewVersion(v)
+	if err != nil {
+		return nil, false
+	}
+	// the suffix of version, e.g. "-log" in "5.7.30-log", is not a pre-release.
+	release, err := version.SetPrerelease("")
+	if err != nil {
+		return nil, false
+	}
+	return &release, true
+}
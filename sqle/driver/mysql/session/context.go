@@ -1,9 +1,11 @@
 package session
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/log"
 
@@ -81,6 +83,11 @@ type Context struct {
 
 	// historySqlInfo historical sql information record
 	historySqlInfo *HistorySQLInfo
+
+	// metaCache shares the metadata loaded by executor between contexts of the
+	// same instance, metaCacheKey is the key of the instance.
+	metaCache    *MetaCache
+	metaCacheKey string
 }
 
 type contextOption func(*Context)
@@ -107,6 +114,10 @@ func NewContext(parent *Context, opts ...contextOption) *Context {
 	}
 	ctx.schemaHasLoad = parent.schemaHasLoad
 	ctx.currentSchema = parent.currentSchema
+	if ctx.metaCache == nil {
+		ctx.metaCache = parent.metaCache
+		ctx.metaCacheKey = parent.metaCacheKey
+	}
 	for schemaName, schema := range parent.schemas {
 		newSchema := &SchemaInfo{
 			Tables: map[string]*TableInfo{},
@@ -133,12 +144,50 @@ func NewContext(parent *Context, opts ...contextOption) *Context {
 	return ctx
 }
 
+// NewSandboxContext creates a context without executor, the database of the context only
+// has an empty schema which is the current schema, so the tables are created by the
+// audited SQL.
+func NewSandboxContext(schema string) *Context {
+	ctx := NewContext(nil)
+	ctx.schemas[schema] = &SchemaInfo{Tables: map[string]*TableInfo{}}
+	ctx.setSchemasLoad()
+	ctx.sysVars[SysVarLowerCaseTableNames] = "0"
+	ctx.currentSchema = schema
+	return ctx
+}
+
+// NewBaselineContext creates a context without executor, the database of the context is
+// built by the CREATE statements of a schema dump, other statements in the dump are
+// ignored. The current schema is the default schema, which is created if it's not in
+// the dump.
+func NewBaselineContext(schema string, nodes []ast.Node) *Context {
+	ctx := NewSandboxContext(schema)
+	for _, node := range nodes {
+		switch node.(type) {
+		case *ast.UseStmt, *ast.CreateDatabaseStmt, *ast.CreateTableStmt:
+			ctx.UpdateContext(node)
+		}
+	}
+	// the tables in the dump exist before the audited SQL.
+	ctx.currentSchema = schema
+	ctx.historySqlInfo = &HistorySQLInfo{}
+	return ctx
+}
+
 func WithExecutor(e *executor.Executor) contextOption {
 	return func(ctx *Context) {
 		ctx.e = e
 	}
 }
 
+// WithMetaCache makes the context load the metadata from cache before executor.
+func WithMetaCache(cache *MetaCache, key string) contextOption {
+	return func(ctx *Context) {
+		ctx.metaCache = cache
+		ctx.metaCacheKey = key
+	}
+}
+
 func (c *Context) GetHistorySQLInfo() *HistorySQLInfo {
 	if c.historySqlInfo == nil {
 		c.historySqlInfo = &HistorySQLInfo{}
@@ -346,28 +395,66 @@ func (c *Context) UpdateContext(node ast.Node) {
 		}
 
 	case *ast.AlterTableStmt:
-		info, exist := c.GetTableInfo(s.Table)
-		if exist {
-			var oldTable *ast.CreateTableStmt
-			var err error
-			if info.MergedTable != nil {
-				oldTable = info.MergedTable
-			} else if info.OriginalTable != nil {
-				oldTable, err = util.ParseCreateTableStmt(info.OriginalTable.Text())
-				if err != nil {
-					return
-				}
-			}
-			info.MergedTable, _ = util.MergeAlterToTable(oldTable, s)
-			info.AlterTables = append(info.AlterTables, s)
-			// rename table
-			if s.Table.Name.String() != info.MergedTable.Table.Name.String() {
-				schemaName := c.GetSchemaName(s.Table)
-				c.delTable(schemaName, s.Table.Name.String())
-				c.addTable(schemaName, info.MergedTable.Table.Name.String(), info)
-			}
+		c.alterTable(s)
+	case *ast.CreateIndexStmt:
+		// create index is the same as alter table add index.
+		c.alterTable(&ast.AlterTableStmt{
+			Table: s.Table,
+			Specs: []*ast.AlterTableSpec{{
+				Tp: ast.AlterTableAddConstraint,
+				Constraint: &ast.Constraint{
+					Tp:     getIndexConstraintType(s.KeyType),
+					Name:   s.IndexName,
+					Keys:   s.IndexPartSpecifications,
+					Option: s.IndexOption,
+				},
+			}},
+		})
+	case *ast.DropIndexStmt:
+		c.alterTable(&ast.AlterTableStmt{
+			Table: s.Table,
+			Specs: []*ast.AlterTableSpec{{
+				Tp:   ast.AlterTableDropIndex,
+				Name: s.IndexName,
+			}},
+		})
+	default:
+	}
+}
+
+func (c *Context) alterTable(s *ast.AlterTableStmt) {
+	info, exist := c.GetTableInfo(s.Table)
+	if !exist {
+		return
+	}
+	var oldTable *ast.CreateTableStmt
+	var err error
+	if info.MergedTable != nil {
+		oldTable = info.MergedTable
+	} else if info.OriginalTable != nil {
+		oldTable, err = util.ParseCreateTableStmt(info.OriginalTable.Text())
+		if err != nil {
+			return
 		}
+	}
+	info.MergedTable, _ = util.MergeAlterToTable(oldTable, s)
+	info.AlterTables = append(info.AlterTables, s)
+	// rename table
+	if s.Table.Name.String() != info.MergedTable.Table.Name.String() {
+		schemaName := c.GetSchemaName(s.Table)
+		c.delTable(schemaName, s.Table.Name.String())
+		c.addTable(schemaName, info.MergedTable.Table.Name.String(), info)
+	}
+}
+
+func getIndexConstraintType(keyType ast.IndexKeyType) ast.ConstraintType {
+	switch keyType {
+	case ast.IndexKeyTypeUnique:
+		return ast.ConstraintUniq
+	case ast.IndexKeyTypeFullText:
+		return ast.ConstraintFulltext
 	default:
+		return ast.ConstraintIndex
 	}
 }
 
@@ -394,7 +481,7 @@ func (c *Context) IsSchemaExist(schemaName string) (bool, error) {
 			return false, nil
 		}
 
-		schemas, err := c.e.ShowDatabases(false)
+		schemas, err := c.showDatabases()
 		if err != nil {
 			return false, err
 		}
@@ -433,7 +520,7 @@ func (c *Context) IsTableExist(stmt *ast.TableName) (bool, error) {
 			return false, nil
 		}
 
-		tables, err := c.e.ShowSchemaTables(schemaName)
+		tables, err := c.showSchemaTables(schemaName)
 		if err != nil {
 			return false, err
 		}
@@ -463,6 +550,7 @@ func (c *Context) IsTableExist(stmt *ast.TableName) (bool, error) {
 
 const (
 	SysVarLowerCaseTableNames = "lower_case_table_names"
+	SysVarVersion             = "version"
 )
 
 // GetSystemVariable get system variable.
@@ -515,7 +603,7 @@ func (c *Context) GetCreateTableStmt(stmt *ast.TableName) (*ast.CreateTableStmt,
 		return nil, false, nil
 	}
 
-	createTableSql, err := c.e.ShowCreateTable("", util.GetTableNameWithQuote(stmt))
+	createTableSql, err := c.showCreateTable(stmt)
 	if err != nil {
 		return nil, exist, err
 	}
@@ -527,6 +615,57 @@ func (c *Context) GetCreateTableStmt(stmt *ast.TableName) (*ast.CreateTableStmt,
 	return createStmt, exist, nil
 }
 
+func (c *Context) showDatabases() ([]string, error) {
+	if c.metaCache == nil {
+		return c.e.ShowDatabases(false)
+	}
+	if schemas, ok := c.metaCache.getSchemas(c.metaCacheKey); ok {
+		return schemas, nil
+	}
+	generation := c.metaCache.Generation(c.metaCacheKey)
+	schemas, err := c.e.ShowDatabases(false)
+	if err != nil {
+		return nil, err
+	}
+	c.metaCache.setSchemas(c.metaCacheKey, generation, schemas)
+	return schemas, nil
+}
+
+func (c *Context) showSchemaTables(schemaName string) ([]string, error) {
+	if c.metaCache == nil {
+		return c.e.ShowSchemaTables(schemaName)
+	}
+	if tables, ok := c.metaCache.getTables(c.metaCacheKey, schemaName); ok {
+		return tables, nil
+	}
+	generation := c.metaCache.Generation(c.metaCacheKey)
+	tables, err := c.e.ShowSchemaTables(schemaName)
+	if err != nil {
+		return nil, err
+	}
+	c.metaCache.setTables(c.metaCacheKey, generation, schemaName, tables)
+	return tables, nil
+}
+
+// showCreateTable caches the SQL instead of the stmt, because the stmt of context may be
+// modified by the audited SQL.
+func (c *Context) showCreateTable(stmt *ast.TableName) (string, error) {
+	if c.metaCache == nil {
+		return c.e.ShowCreateTable("", util.GetTableNameWithQuote(stmt))
+	}
+	schemaName, tableName := c.GetSchemaName(stmt), stmt.Name.String()
+	if createTableSql, ok := c.metaCache.getCreateTable(c.metaCacheKey, schemaName, tableName); ok {
+		return createTableSql, nil
+	}
+	generation := c.metaCache.Generation(c.metaCacheKey)
+	createTableSql, err := c.e.ShowCreateTable("", util.GetTableNameWithQuote(stmt))
+	if err != nil {
+		return "", err
+	}
+	c.metaCache.setCreateTable(c.metaCacheKey, generation, schemaName, tableName, createTableSql)
+	return createTableSql, nil
+}
+
 // GetCollationDatabase get collation database.
 func (c *Context) GetCollationDatabase(stmt *ast.TableName, schemaName string) (string, error) {
 	if schemaName == "" {
@@ -695,6 +834,24 @@ func (c *Context) GetExecutionPlan(sql string) ([]*executor.ExplainRecord, error
 	return records, nil
 }
 
+// GetRecordCount get the count of records by the count SQL, the query is canceled
+// and stopped on the server if it is not finished before timeout.
+func (c *Context) GetRecordCount(sql string, timeout time.Duration) (int64, error) {
+	if c.e == nil {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, rows, err := c.e.Db.QueryWithContext(ctx, util.WithMaxExecutionTime(sql, timeout))
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) != 1 || len(rows[0]) != 1 {
+		return 0, fmt.Errorf("do not match records for sql %v", sql)
+	}
+	return strconv.ParseInt(rows[0][0].String, 10, 64)
+}
+
 // GetTableRowCount get table row count by show table status.
 func (c *Context) GetTableRowCount(tn *ast.TableName) (int, error) {
 	ti, exist := c.GetTableInfo(tn)
@@ -0,0 +1,241 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+)
+
+// DefaultMetaCache is shared by the contexts of all tasks and audit plans. It is disabled
+// by default, because the tables changed outside SQLE are not seen until they are expired.
+var DefaultMetaCache = NewMetaCache(0)
+
+// MetaCacheKey returns the key of instance in meta cache, the instances connected by
+// different users are cached separately because they may see different schemas.
+func MetaCacheKey(dsn *driver.DSN) string {
+	return fmt.Sprintf("%s@%s:%s", dsn.User, dsn.Host, dsn.Port)
+}
+
+// MetaCache caches the metadata of MySQL instances, e.g. the schemas, the tables and
+// "SHOW CREATE TABLE" result, so the contexts of different tasks needn't reload them.
+// The cached metadata is expired after TTL, and it should be invalidated when the
+// schema of instance is changed by SQLE.
+//
+// The metadata loaded before invalidation may be cached after it by a concurrent audit,
+// so the loader gets the generation of instance before loading, and the metadata is
+// dropped if the generation is changed by invalidation when it is cached.
+type MetaCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	instances map[string]*instanceMeta
+}
+
+type instanceMeta struct {
+	hits   uint64
+	misses uint64
+	// generation is increased when the instance is invalidated.
+	generation uint64
+
+	schemas      *metaEntry
+	tables       map[string] /*schema*/ *metaEntry
+	createTables map[string] /*schema.table*/ *metaEntry
+}
+
+type metaEntry struct {
+	value    interface{}
+	expireAt time.Time
+}
+
+// NewMetaCache creates a meta cache, the cache is disabled if ttl is not positive.
+func NewMetaCache(ttl time.Duration) *MetaCache {
+	return &MetaCache{
+		ttl:       ttl,
+		instances: map[string]*instanceMeta{},
+	}
+}
+
+// SetTTL sets the TTL of metadata cached later, the cache is disabled if ttl is not positive.
+func (m *MetaCache) SetTTL(ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ttl = ttl
+	if ttl <= 0 {
+		m.instances = map[string]*instanceMeta{}
+	}
+}
+
+func (m *MetaCache) getInstance(key string) *instanceMeta {
+	inst, ok := m.instances[key]
+	if !ok {
+		inst = &instanceMeta{
+			tables:       map[string]*metaEntry{},
+			createTables: map[string]*metaEntry{},
+		}
+		m.instances[key] = inst
+	}
+	return inst
+}
+
+func (m *MetaCache) get(key string, entry func(inst *instanceMeta) *metaEntry) (interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ttl <= 0 {
+		return nil, false
+	}
+	inst := m.getInstance(key)
+	e := entry(inst)
+	if e == nil || time.Now().After(e.expireAt) {
+		inst.misses++
+		return nil, false
+	}
+	inst.hits++
+	return e.value, true
+}
+
+// Generation returns the generation of instance, it should be got before loading the
+// metadata which is cached later.
+func (m *MetaCache) Generation(key string) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.getInstance(key).generation
+}
+
+func (m *MetaCache) set(key string, generation uint64, set func(inst *instanceMeta, e *metaEntry), value interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ttl <= 0 {
+		return
+	}
+	inst := m.getInstance(key)
+	if inst.generation != generation {
+		// the metadata is loaded before invalidation.
+		return
+	}
+	set(inst, &metaEntry{value: value, expireAt: time.Now().Add(m.ttl)})
+}
+
+func (m *MetaCache) getSchemas(key string) ([]string, bool) {
+	v, ok := m.get(key, func(inst *instanceMeta) *metaEntry {
+		return inst.schemas
+	})
+	if !ok {
+		return nil, false
+	}
+	return v.([]string), true
+}
+
+func (m *MetaCache) setSchemas(key string, generation uint64, schemas []string) {
+	m.set(key, generation, func(inst *instanceMeta, e *metaEntry) {
+		inst.schemas = e
+	}, schemas)
+}
+
+func (m *MetaCache) getTables(key, schema string) ([]string, bool) {
+	v, ok := m.get(key, func(inst *instanceMeta) *metaEntry {
+		return inst.tables[schema]
+	})
+	if !ok {
+		return nil, false
+	}
+	return v.([]string), true
+}
+
+func (m *MetaCache) setTables(key string, generation uint64, schema string, tables []string) {
+	m.set(key, generation, func(inst *instanceMeta, e *metaEntry) {
+		inst.tables[schema] = e
+	}, tables)
+}
+
+func createTableKey(schema, table string) string {
+	return fmt.Sprintf("%s.%s", schema, table)
+}
+
+func (m *MetaCache) getCreateTable(key, schema, table string) (string, bool) {
+	v, ok := m.get(key, func(inst *instanceMeta) *metaEntry {
+		return inst.createTables[createTableKey(schema, table)]
+	})
+	if !ok {
+		return "", false
+	}
+	return v.(string), true
+}
+
+func (m *MetaCache) setCreateTable(key string, generation uint64, schema, table, createTableSQL string) {
+	m.set(key, generation, func(inst *instanceMeta, e *metaEntry) {
+		inst.createTables[createTableKey(schema, table)] = e
+	}, createTableSQL)
+}
+
+// UpdateSchema replaces the tables of schema by the metadata collected from instance, the
+// tables which are not in createTableSQLs are removed from cache. generation is got by
+// Generation before collecting, the metadata is dropped if the instance is invalidated.
+func (m *MetaCache) UpdateSchema(key string, generation uint64, schema string, createTableSQLs map[string] /*table*/ string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ttl <= 0 {
+		return
+	}
+	inst := m.getInstance(key)
+	if inst.generation != generation {
+		return
+	}
+	expireAt := time.Now().Add(m.ttl)
+	prefix := createTableKey(schema, "")
+	for k := range inst.createTables {
+		if len(k) > len(prefix) && k[:len(prefix)] == prefix {
+			delete(inst.createTables, k)
+		}
+	}
+	tables := make([]string, 0, len(createTableSQLs))
+	for table, sql := range createTableSQLs {
+		tables = append(tables, table)
+		inst.createTables[createTableKey(schema, table)] = &metaEntry{value: sql, expireAt: expireAt}
+	}
+	sort.Strings(tables)
+	inst.tables[schema] = &metaEntry{value: tables, expireAt: expireAt}
+}
+
+// Invalidate removes the metadata of instance from cache, the statistics is kept. The
+// metadata loaded before invalidation is not cached later.
+func (m *MetaCache) Invalidate(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inst, ok := m.instances[key]
+	if !ok {
+		return
+	}
+	inst.generation++
+	inst.schemas = nil
+	inst.tables = map[string]*metaEntry{}
+	inst.createTables = map[string]*metaEntry{}
+}
+
+// MetaCacheStats is the statistics of meta cache of an instance.
+type MetaCacheStats struct {
+	Instance     string
+	Hits         uint64
+	Misses       uint64
+	CachedTables int
+}
+
+// Stats returns the statistics of all instances, sorted by instance.
+func (m *MetaCache) Stats() []*MetaCacheStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := make([]*MetaCacheStats, 0, len(m.instances))
+	for key, inst := range m.instances {
+		stats = append(stats, &MetaCacheStats{
+			Instance:     key,
+			Hits:         inst.hits,
+			Misses:       inst.misses,
+			CachedTables: len(inst.createTables),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Instance < stats[j].Instance
+	})
+	return stats
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetaCache(t *testing.T) {
+	e, mocker, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	cache := NewMetaCache(time.Minute)
+	table := &ast.TableName{Schema: model.NewCIStr("db1"), Name: model.NewCIStr("t1")}
+
+	expectLoad := func() {
+		mocker.ExpectQuery(regexp.QuoteMeta("show databases")).
+			WillReturnRows(sqlmock.NewRows([]string{"Database"}).AddRow("db1"))
+		mocker.ExpectQuery(regexp.QuoteMeta(`select TABLE_NAME from information_schema.tables where table_schema=?`)).
+			WithArgs("db1").WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}).AddRow("t1"))
+		mocker.ExpectQuery(regexp.QuoteMeta("show create table `db1`.`t1`")).
+			WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+				AddRow("t1", "CREATE TABLE `t1` (`id` int(11) NOT NULL AUTO_INCREMENT, PRIMARY KEY (`id`))"))
+	}
+	getCreateTable := func() {
+		ctx := NewContext(nil, WithExecutor(e), WithMetaCache(cache, "root@127.0.0.1:3306"))
+		ctx.AddSystemVariable(SysVarLowerCaseTableNames, "0")
+		stmt, exist, err := ctx.GetCreateTableStmt(table)
+		assert.NoError(t, err)
+		assert.True(t, exist)
+		assert.Equal(t, "t1", stmt.Table.Name.O)
+	}
+
+	// the second context loads the metadata from cache.
+	expectLoad()
+	getCreateTable()
+	getCreateTable()
+	assert.NoError(t, mocker.ExpectationsWereMet())
+	assert.Equal(t, []*MetaCacheStats{
+		{Instance: "root@127.0.0.1:3306", Hits: 3, Misses: 3, CachedTables: 1},
+	}, cache.Stats())
+
+	// the invalidated metadata is reloaded.
+	cache.Invalidate("root@127.0.0.1:3306")
+	expectLoad()
+	getCreateTable()
+	assert.NoError(t, mocker.ExpectationsWereMet())
+
+	// the collected tables of schema replace the cached tables.
+	cache.UpdateSchema("root@127.0.0.1:3306", cache.Generation("root@127.0.0.1:3306"), "db1", map[string]string{
+		"t2": "CREATE TABLE `t2` (`id` int(11) NOT NULL)",
+	})
+	tables, ok := cache.getTables("root@127.0.0.1:3306", "db1")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"t2"}, tables)
+	_, ok = cache.getCreateTable("root@127.0.0.1:3306", "db1", "t1")
+	assert.False(t, ok)
+
+	// the metadata loaded before invalidation is not cached.
+	generation := cache.Generation("root@127.0.0.1:3306")
+	cache.Invalidate("root@127.0.0.1:3306")
+	cache.setCreateTable("root@127.0.0.1:3306", generation, "db1", "t1", "CREATE TABLE `t1` (`id` int(11) NOT NULL)")
+	_, ok = cache.getCreateTable("root@127.0.0.1:3306", "db1", "t1")
+	assert.False(t, ok)
+	cache.UpdateSchema("root@127.0.0.1:3306", generation, "db1", map[string]string{
+		"t1": "CREATE TABLE `t1` (`id` int(11) NOT NULL)",
+	})
+	_, ok = cache.getTables("root@127.0.0.1:3306", "db1")
+	assert.False(t, ok)
+
+	// the expired metadata is reloaded.
+	cache.SetTTL(time.Nanosecond)
+	cache.Invalidate("root@127.0.0.1:3306")
+	expectLoad()
+	getCreateTable()
+	time.Sleep(time.Millisecond)
+	expectLoad()
+	getCreateTable()
+	assert.NoError(t, mocker.ExpectationsWereMet())
+}
@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/sirupsen/logrus"
+)
+
+// QueryDriver implements driver.SQLQueryDriver interface, it is used by SQL query
+// to execute read-only SQL on MySQL.
+type QueryDriver struct {
+	log *logrus.Entry
+	dsn *driver.DSN
+}
+
+func NewQueryDriver(log *logrus.Entry, dsn *driver.DSN) (driver.SQLQueryDriver, error) {
+	return &QueryDriver{
+		log: log,
+		dsn: dsn,
+	}, nil
+}
+
+// QueryPrepare checks the SQL is a query and rewrites its limit by conf, the limit of
+// the SQL is kept if it is less than the limit of conf.
+func (q *QueryDriver) QueryPrepare(ctx context.Context, sql string, conf *driver.QueryPrepareConf) (*driver.QueryPrepareResult, error) {
+	node, err := util.ParseOneSql(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	var limit **ast.Limit
+	var selects []*ast.SelectStmt
+	switch stmt := node.(type) {
+	case *ast.SelectStmt:
+		limit = &stmt.Limit
+		selects = []*ast.SelectStmt{stmt}
+	case *ast.UnionStmt:
+		limit = &stmt.Limit
+		if stmt.SelectList != nil {
+			selects = stmt.SelectList.Selects
+		}
+	default:
+		return &driver.QueryPrepareResult{
+			ErrorType: driver.ErrorTypeNotQuery,
+			Error:     "only SELECT statement is allowed in SQL query",
+		}, nil
+	}
+	for _, s := range selects {
+		if s.LockTp != ast.SelectLockNone {
+			return &driver.QueryPrepareResult{
+				ErrorType: driver.ErrorTypeNotQuery,
+				Error:     "SELECT with lock is not allowed in SQL query",
+			}, nil
+		}
+		if s.SelectIntoOpt != nil {
+			return &driver.QueryPrepareResult{
+				ErrorType: driver.ErrorTypeNotQuery,
+				Error:     "SELECT INTO is not allowed in SQL query",
+			}, nil
+		}
+	}
+
+	if conf != nil && conf.Limit > 0 {
+		newLimit, err := mergeQueryLimit(*limit, conf.Limit, conf.Offset)
+		if err != nil {
+			return nil, err
+		}
+		*limit = newLimit
+	}
+
+	buf := new(bytes.Buffer)
+	if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, buf)); err != nil {
+		return nil, err
+	}
+	return &driver.QueryPrepareResult{
+		NewSQL:    buf.String(),
+		ErrorType: driver.ErrorTypeNotError,
+	}, nil
+}
+
+// mergeQueryLimit returns the limit which reads the page [offset, offset+limit) from the
+// result set of the origin limit.
+func mergeQueryLimit(origin *ast.Limit, limit, offset uint32) (*ast.Limit, error) {
+	count, start := uint64(limit), uint64(offset)
+	if origin != nil {
+		originCount, err := strconv.ParseUint(util.ExprFormat(origin.Count), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse limit count failed: %v", err)
+		}
+		var originOffset uint64
+		if origin.Offset != nil {
+			originOffset, err = strconv.ParseUint(util.ExprFormat(origin.Offset), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse limit offset failed: %v", err)
+			}
+		}
+		if start >= originCount {
+			count = 0
+		} else if originCount-start < count {
+			count = originCount - start
+		}
+		start += originOffset
+	}
+	return &ast.Limit{
+		Count:  ast.NewValueExpr(count, "", ""),
+		Offset: ast.NewValueExpr(start, "", ""),
+	}, nil
+}
+
+// Close does nothing, the connection is opened and closed in every Query.
+func (q *QueryDriver) Close(ctx context.Context) {}
+
+// Query executes the SQL which is rewritten by QueryPrepare.
+func (q *QueryDriver) Query(ctx context.Context, sql string, conf *driver.QueryConf) (*driver.QueryResult, error) {
+	if q.dsn == nil {
+		return nil, fmt.Errorf("dsn is required for SQL query")
+	}
+	conn, err := executor.NewExecutor(q.log, q.dsn, q.dsn.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Db.Close()
+
+	if conf != nil && conf.TimeOutSecond > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(conf.TimeOutSecond)*time.Second)
+		defer cancel()
+	}
+	columns, rows, err := conn.Db.QueryWithContext(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &driver.QueryResult{
+		Column: make(params.Params, 0, len(columns)),
+		Rows:   make([]*driver.QueryResultRow, 0, len(rows)),
+	}
+	for _, column := range columns {
+		result.Column = append(result.Column, &params.Param{
+			Key:   column,
+			Value: column,
+			Type:  params.ParamTypeString,
+		})
+	}
+	for _, row := range rows {
+		r := &driver.QueryResultRow{
+			Values: make([]*driver.QueryResultValue, 0, len(row)),
+		}
+		for _, value := range row {
+			r.Values = append(r.Values, &driver.QueryResultValue{Value: value.String})
+		}
+		result.Rows = append(result.Rows, r)
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryDriver_QueryPrepare(t *testing.T) {
+	q := &QueryDriver{}
+	tests := []struct {
+		sql       string
+		conf      *driver.QueryPrepareConf
+		newSQL    string
+		errorType driver.ErrorType
+	}{
+		{
+			sql:       "select * from t1",
+			conf:      &driver.QueryPrepareConf{Limit: 100, Offset: 0},
+			newSQL:    "SELECT * FROM `t1` LIMIT 0,100",
+			errorType: driver.ErrorTypeNotError,
+		},
+		{
+			sql:       "select * from t1 limit 10",
+			conf:      &driver.QueryPrepareConf{Limit: 100, Offset: 0},
+			newSQL:    "SELECT * FROM `t1` LIMIT 0,10",
+			errorType: driver.ErrorTypeNotError,
+		},
+		{
+			sql:       "select * from t1 limit 5, 30",
+			conf:      &driver.QueryPrepareConf{Limit: 20, Offset: 20},
+			newSQL:    "SELECT * FROM `t1` LIMIT 25,10",
+			errorType: driver.ErrorTypeNotError,
+		},
+		{
+			sql:       "select * from t1 limit 10",
+			conf:      &driver.QueryPrepareConf{Limit: 20, Offset: 20},
+			newSQL:    "SELECT * FROM `t1` LIMIT 20,0",
+			errorType: driver.ErrorTypeNotError,
+		},
+		{
+			sql:       "select id from t1 union select id from t2",
+			conf:      &driver.QueryPrepareConf{Limit: 10, Offset: 10},
+			newSQL:    "SELECT `id` FROM `t1` UNION SELECT `id` FROM `t2` LIMIT 10,10",
+			errorType: driver.ErrorTypeNotError,
+		},
+		{
+			sql:       "select * from t1 for update",
+			conf:      &driver.QueryPrepareConf{Limit: 10},
+			errorType: driver.ErrorTypeNotQuery,
+		},
+		{
+			sql:       "delete from t1",
+			conf:      &driver.QueryPrepareConf{Limit: 10},
+			errorType: driver.ErrorTypeNotQuery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			res, err := q.QueryPrepare(context.TODO(), tt.sql, tt.conf)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.errorType, res.ErrorType)
+			assert.Equal(t, tt.newSQL, res.NewSQL)
+		})
+	}
+}
@@ -4,12 +4,33 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/log"
 
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
 )
 
+// QuoteName quotes the identifier with backticks, the backticks in it are escaped.
+func QuoteName(name string) string {
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+}
+
+// WithMaxExecutionTime adds the MAX_EXECUTION_TIME optimizer hint to the SELECT, so the
+// query is also stopped by the server rather than only canceled by the client. The hint
+// is ignored as a comment before MySQL 5.7.8.
+func WithMaxExecutionTime(query string, timeout time.Duration) string {
+	const selectKeyword = "SELECT"
+	// MAX_EXECUTION_TIME(0) means no limit, so the timeout less than 1ms is not hinted.
+	if timeout < time.Millisecond || len(query) <= len(selectKeyword) ||
+		!strings.EqualFold(query[:len(selectKeyword)], selectKeyword) {
+		return query
+	}
+	return fmt.Sprintf("%s /*+ MAX_EXECUTION_TIME(%d) */%s", query[:len(selectKeyword)],
+		timeout.Milliseconds(), query[len(selectKeyword):])
+}
+
 func AlterTableStmtFormat(stmt *ast.AlterTableStmt) string {
 	if len(stmt.Specs) <= 0 {
 		return ""
@@ -176,6 +197,10 @@ func indexColumnsFormat(keys []*ast.IndexPartSpecification) string {
 	}
 	columnsName := make([]string, 0, len(keys))
 	for _, key := range keys {
+		if IsFunctionalIndexPart(key) {
+			columnsName = append(columnsName, GetIndexPartName(key).String())
+			continue
+		}
 		columnsName = append(columnsName, fmt.Sprintf("`%s`", key.Column.Name.String()))
 	}
 	if len(columnsName) > 0 {
@@ -199,3 +224,135 @@ func referDefFormat(refer *ast.ReferenceDef) string {
 	}
 	return format
 }
+
+// RestoreSql restores node to SQL text.
+func RestoreSql(node ast.Node) (string, error) {
+	return restoreToSqlWithFlag(format.DefaultRestoreFlags, node)
+}
+
+// RestoreSqlWithComments restores node to SQL text like RestoreSql, and keeps the comments
+// of sql which are dropped by the parser, e.g. the "sqle:ignore" hints. The comments before
+// the statement are kept in front of it, and the others are moved to the end of it.
+func RestoreSqlWithComments(node ast.Node, sql string) (string, error) {
+	restored, err := RestoreSql(node)
+	if err != nil {
+		return "", err
+	}
+	leading, others := splitComments(sql)
+	if len(leading) == 0 && len(others) == 0 {
+		return restored, nil
+	}
+
+	buf := bytes.Buffer{}
+	for _, comment := range leading {
+		buf.WriteString(comment)
+		buf.WriteString(commentSeparator(comment))
+	}
+	buf.WriteString(restored)
+	for _, comment := range others {
+		buf.WriteString(" ")
+		buf.WriteString(comment)
+		if commentSeparator(comment) == "\n" {
+			buf.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+// commentSeparator returns the separator after the comment, the line comment must be ended
+// with a new line.
+func commentSeparator(comment string) string {
+	if strings.HasPrefix(comment, "/*") {
+		return " "
+	}
+	return "\n"
+}
+
+// splitComments returns the comments of sql, leading are the comments before the statement.
+// The executable comments "/*! */" and optimizer hints "/*+ */" are a part of the statement
+// for the parser, so they are not returned.
+func splitComments(sql string) (leading, others []string) {
+	var inStatement bool
+	add := func(comment string) {
+		if strings.HasPrefix(comment, "/*!") || strings.HasPrefix(comment, "/*+") {
+			return
+		}
+		if inStatement {
+			others = append(others, comment)
+		} else {
+			leading = append(leading, comment)
+		}
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			inStatement = true
+			for i++; i < len(sql) && sql[i] != c; i++ {
+				if sql[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return leading, others
+			}
+			add(sql[i : i+2+end+2])
+			i += 2 + end + 1
+		case c == '#' || (c == '-' && strings.HasPrefix(sql[i:], "-- ")):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			add(strings.TrimRight(sql[i:i+end], "\r"))
+			i += end
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			inStatement = true
+		}
+	}
+	return leading, others
+}
+
+// IdentifierOffset returns the offset of the first identifier which equals to name case
+// insensitively in sql, the string literals and comments are skipped. It returns -1 if
+// the identifier is not found.
+func IdentifierOffset(sql, name string) int {
+	if name == "" {
+		return -1
+	}
+	isIdentChar := func(c byte) bool {
+		return c == '_' || c == '$' || c >= 0x80 ||
+			('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+	}
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"':
+			for i++; i < len(sql) && sql[i] != c; i++ {
+				if sql[i] == '\\' {
+					i++
+				}
+			}
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return -1
+			}
+			i += 2 + end + 1
+		case c == '#' || (c == '-' && strings.HasPrefix(sql[i:], "-- ")):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				return -1
+			}
+			i += end
+		case i+len(name) <= len(sql) && strings.EqualFold(sql[i:i+len(name)], name) &&
+			(i == 0 || !isIdentChar(sql[i-1])) &&
+			(i+len(name) == len(sql) || !isIdentChar(sql[i+len(name)])):
+			return i
+		}
+	}
+	return -1
+}
@@ -23,6 +23,14 @@ func ParseSql(sql string) ([]ast.StmtNode, error) {
 	if err != nil {
 		return nil, err
 	}
+	for i, stmt := range stmts {
+		if _, ok := stmt.(*ast.UnparsedStmt); !ok {
+			continue
+		}
+		if node, err := parseWithWindowFunc(stmt.Text()); err == nil {
+			stmts[i] = node
+		}
+	}
 	return stmts, nil
 }
 
@@ -30,12 +38,82 @@ func ParseOneSql(sql string) (ast.StmtNode, error) {
 	p := parser.New()
 	stmt, err := p.ParseOneStmt(sql, "", "")
 	if err != nil {
+		if node, wErr := parseWithWindowFunc(sql); wErr == nil {
+			return node, nil
+		}
 		fmt.Printf("parse error: %v\nsql: %v", err, sql)
 		return nil, err
 	}
 	return stmt, nil
 }
 
+// parseWithWindowFunc parses the sql which uses the window functions of MySQL 8.0. The window
+// function names, e.g. RANK, are keywords when the window functions are enabled, so it is only
+// tried after the sql can not be parsed by default.
+func parseWithWindowFunc(sql string) (ast.StmtNode, error) {
+	p := parser.New()
+	p.EnableWindowFunc(true)
+	return p.ParseOneStmt(sql, "", "")
+}
+
+// cteReg matches the statement with common table expressions of MySQL 8.0, the parser does not
+// support it now, so it is matched by characters.
+var cteReg = regexp.MustCompile(`(?is)^\s*(?:/\*.*?\*/\s*)*with\s+(recursive\s+)?` + "[`\\w]+" + `[\s\S]*\bas\s*\(`)
+
+// IsCTEStmt returns true if the statement starts with WITH clause, isRecursive is true
+// if it is WITH RECURSIVE.
+func IsCTEStmt(sql string) (isCTE bool, isRecursive bool) {
+	match := cteReg.FindStringSubmatch(sql)
+	if match == nil {
+		return false, false
+	}
+	return true, match[1] != ""
+}
+
+// GetIndexPartName returns the column name of the index part. For the functional key part,
+// e.g. INDEX idx_1((v1+1)) which is supported since MySQL 8.0.13, the expression is returned.
+func GetIndexPartName(part *ast.IndexPartSpecification) _model.CIStr {
+	if part.Column != nil {
+		return part.Column.Name
+	}
+	if part.Expr == nil {
+		return _model.CIStr{}
+	}
+	expr, err := RestoreSql(part.Expr)
+	if err != nil {
+		return _model.CIStr{}
+	}
+	return _model.NewCIStr(fmt.Sprintf("(%s)", expr))
+}
+
+// GetIndexPartColumns returns the names of the columns which are used by the index part.
+func GetIndexPartColumns(part *ast.IndexPartSpecification) []string {
+	if part.Column != nil {
+		return []string{part.Column.Name.L}
+	}
+	if part.Expr == nil {
+		return nil
+	}
+	visitor := &ColumnNameVisitor{}
+	part.Expr.Accept(visitor)
+	names := make([]string, 0, len(visitor.ColumnNameList))
+	for _, col := range visitor.ColumnNameList {
+		names = append(names, col.Name.Name.L)
+	}
+	return names
+}
+
+// IsFunctionalIndexPart returns true if the index part is a functional key part.
+func IsFunctionalIndexPart(part *ast.IndexPartSpecification) bool {
+	return part.Column == nil && part.Expr != nil
+}
+
+// IsInvisibleIndex returns true if the index is declared as INVISIBLE, which is supported
+// since MySQL 8.0.
+func IsInvisibleIndex(option *ast.IndexOption) bool {
+	return option != nil && option.Visibility == ast.IndexVisibilityInvisible
+}
+
 func GetNumberOfJoinTables(stmt *ast.Join) int {
 	nums := 0
 	if stmt == nil {
@@ -261,11 +339,21 @@ func WhereStmtHasOneColumn(where ast.ExprNode) bool {
 	return hasColumn
 }
 
-func IsFuncUsedOnColumnInWhereStmt(cols map[string]struct{}, where ast.ExprNode) bool {
+// IsFuncUsedOnColumnInWhereStmt returns true if the function is used on the column in where.
+// indexedExprs are the expressions of the functional indexes, the function which is the same
+// as the indexed expression can use the index.
+func IsFuncUsedOnColumnInWhereStmt(cols map[string]struct{}, indexedExprs map[string]struct{}, where ast.ExprNode) bool {
 	usedFunc := false
 	ScanWhereStmt(func(expr ast.ExprNode) (skip bool) {
 		switch x := expr.(type) {
 		case *ast.FuncCallExpr:
+			if len(indexedExprs) > 0 {
+				if text, err := RestoreSql(x); err == nil {
+					if _, ok := indexedExprs[strings.ToLower(text)]; ok {
+						return true
+					}
+				}
+			}
 			for _, columnNameExpr := range x.Args {
 				if col1, ok := columnNameExpr.(*ast.ColumnNameExpr); ok {
 					if _, ok := cols[col1.Name.String()]; ok {
@@ -418,7 +506,7 @@ func GetPrimaryKey(stmt *ast.CreateTableStmt) (map[string]struct{}, bool) {
 		if constraint.Tp == ast.ConstraintPrimaryKey {
 			hasPk = true
 			for _, col := range constraint.Keys {
-				pkColumnsName[col.Column.Name.L] = struct{}{}
+				pkColumnsName[GetIndexPartName(col).L] = struct{}{}
 			}
 		}
 	}
@@ -539,6 +627,28 @@ func MergeAlterToTable(oldTable *ast.CreateTableStmt, alterTable *ast.AlterTable
 		}
 	}
 
+	for _, spec := range GetAlterTableSpecByTp(alterTable.Specs, ast.AlterTableRenameColumn) {
+		colExists := false
+		for i, col := range newTable.Cols {
+			if col.Name.Name.L == spec.OldColumnName.Name.L {
+				colExists = true
+				newCol := *col
+				newCol.Name = &ast.ColumnName{Name: spec.NewColumnName.Name}
+				newTable.Cols[i] = &newCol
+			}
+		}
+		if !colExists {
+			return oldTable, nil
+		}
+		for _, constraint := range newTable.Constraints {
+			for _, key := range constraint.Keys {
+				if key.Column != nil && key.Column.Name.L == spec.OldColumnName.Name.L {
+					key.Column = &ast.ColumnName{Name: spec.NewColumnName.Name}
+				}
+			}
+		}
+	}
+
 	for _, spec := range GetAlterTableSpecByTp(alterTable.Specs, ast.AlterTableAddColumns) {
 		for _, newCol := range spec.NewColumns {
 			colExist := false
@@ -604,6 +714,41 @@ func MergeAlterToTable(oldTable *ast.CreateTableStmt, alterTable *ast.AlterTable
 		}
 	}
 
+	for _, spec := range GetAlterTableSpecByTp(alterTable.Specs, ast.AlterTableIndexInvisible) {
+		constraintExists := false
+		for i, constraint := range newTable.Constraints {
+			if constraint.Name != spec.Name {
+				continue
+			}
+			constraintExists = true
+			newConstraint := *constraint
+			option := &ast.IndexOption{}
+			if constraint.Option != nil {
+				*option = *constraint.Option
+			}
+			option.Visibility = spec.Visibility
+			newConstraint.Option = option
+			newTable.Constraints[i] = &newConstraint
+		}
+		if !constraintExists {
+			return oldTable, nil
+		}
+	}
+
+	for _, spec := range GetAlterTableSpecByTp(alterTable.Specs, ast.AlterTableDropCheck) {
+		constraintExists := false
+		for i, constraint := range newTable.Constraints {
+			if constraint.Tp == ast.ConstraintCheck && constraint.Name == spec.Constraint.Name {
+				constraintExists = true
+				newTable.Constraints = append(newTable.Constraints[:i], newTable.Constraints[i+1:]...)
+				break
+			}
+		}
+		if !constraintExists {
+			return oldTable, nil
+		}
+	}
+
 	for _, spec := range GetAlterTableSpecByTp(alterTable.Specs, ast.AlterTableAddConstraint) {
 		switch spec.Constraint.Tp {
 		case ast.ConstraintPrimaryKey:
@@ -742,7 +887,7 @@ func ExtractIndexFromCreateTableStmt(table *ast.CreateTableStmt) map[string] /*i
 		if constraint.Tp == ast.ConstraintPrimaryKey {
 			// The name of a PRIMARY KEY is always PRIMARY,
 			// which thus cannot be used as the name for any other kind of index.
-			result["PRIMARY"] = []string{constraint.Keys[0].Column.Name.L}
+			result["PRIMARY"] = []string{GetIndexPartName(constraint.Keys[0]).L}
 		}
 
 		if constraint.Tp == ast.ConstraintIndex ||
@@ -751,7 +896,7 @@ func ExtractIndexFromCreateTableStmt(table *ast.CreateTableStmt) map[string] /*i
 			constraint.Tp == ast.ConstraintUniqIndex ||
 			constraint.Tp == ast.ConstraintUniqKey {
 			for _, key := range constraint.Keys {
-				result[constraint.Name] = append(result[constraint.Name], key.Column.Name.L)
+				result[constraint.Name] = append(result[constraint.Name], GetIndexPartName(key).L)
 			}
 		}
 	}
@@ -114,3 +114,37 @@ func (se *SelectStmtExtractor) Enter(in ast.Node) (node ast.Node, skipChildren b
 func (se *SelectStmtExtractor) Leave(in ast.Node) (node ast.Node, ok bool) {
 	return in, true
 }
+
+// ColumnNameVisitor implements ast.Visitor interface.
+type ColumnNameVisitor struct {
+	ColumnNameList []*ast.ColumnNameExpr
+}
+
+func (v *ColumnNameVisitor) Enter(in ast.Node) (node ast.Node, skipChildren bool) {
+	switch stmt := in.(type) {
+	case *ast.ColumnNameExpr:
+		v.ColumnNameList = append(v.ColumnNameList, stmt)
+	}
+	return in, false
+}
+
+func (v *ColumnNameVisitor) Leave(in ast.Node) (node ast.Node, ok bool) {
+	return in, true
+}
+
+// WindowFuncExtractor implements ast.Visitor interface.
+type WindowFuncExtractor struct {
+	WindowFuncs []*ast.WindowFuncExpr
+}
+
+func (we *WindowFuncExtractor) Enter(in ast.Node) (node ast.Node, skipChildren bool) {
+	switch stmt := in.(type) {
+	case *ast.WindowFuncExpr:
+		we.WindowFuncs = append(we.WindowFuncs, stmt)
+	}
+	return in, false
+}
+
+func (we *WindowFuncExtractor) Leave(in ast.Node) (node ast.Node, ok bool) {
+	return in, true
+}
@@ -1,13 +1,10 @@
 package driver
 
 import (
-	"fmt"
-	"os"
 	"path/filepath"
 	"sync"
 
 	goPlugin "github.com/hashicorp/go-plugin"
-	"github.com/pingcap/errors"
 )
 
 const (
@@ -68,18 +65,8 @@ func InitPlugins(pluginDir string) error {
 	}
 
 	// read plugin file
-	var plugins []os.FileInfo
-	if err := filepath.Walk(pluginDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return errors.Wrap(err, "init plugin")
-		}
-
-		if info.IsDir() || info.Mode()&0111 == 0 {
-			return nil
-		}
-		plugins = append(plugins, info)
-		return nil
-	}); err != nil {
+	plugins, err := listPluginFiles(pluginDir)
+	if err != nil {
 		return err
 	}
 
@@ -88,19 +75,12 @@ func InitPlugins(pluginDir string) error {
 		binaryPath := filepath.Join(pluginDir, p.Name())
 
 		// check plugin
-		var client PluginClient
-		client = newClientFromFile(binaryPath)
-		if !testConnClient(client) {
-			client = newOldClientFromFile(binaryPath)
-			if !testConnClient(client) {
-				return fmt.Errorf("unable to load plugin: %v", binaryPath)
-			}
-		}
-		if err := RegisterDriverFromClient(client); err != nil {
+		if _, err := loadPlugin(binaryPath); err != nil {
 			return err
 		}
-
+		setPluginLoaded(binaryPath, pluginNameByPath(binaryPath), p.ModTime())
 	}
+	setPluginDir(pluginDir)
 	return nil
 }
 
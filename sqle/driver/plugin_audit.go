@@ -132,13 +132,18 @@ func (d *auditDriverGRPCServer) Audit(ctx context.Context, req *proto.AuditReque
 	if err != nil {
 		return &proto.AuditResponse{}, err
 	}
+	return convertAuditResultFromDriverToProto(auditResults), nil
+}
 
-	resp := &proto.AuditResponse{}
-	for _, result := range auditResults.results {
-		resp.Results = append(resp.Results, &proto.AuditResult{
-			Level:   string(result.level),
-			Message: result.message,
-		})
+func (d *auditDriverGRPCServer) AuditBatch(ctx context.Context, req *proto.AuditBatchRequest) (*proto.AuditBatchResponse, error) {
+	auditResults, err := AuditSQLs(ctx, d.impl, req.GetSqls())
+	if err != nil {
+		return &proto.AuditBatchResponse{}, err
+	}
+
+	resp := &proto.AuditBatchResponse{}
+	for _, auditResult := range auditResults {
+		resp.Results = append(resp.Results, convertAuditResultFromDriverToProto(auditResult))
 	}
 	return resp, nil
 }
@@ -158,10 +163,18 @@ func (d *auditDriverGRPCServer) Metas(ctx context.Context, req *proto.Empty) (*p
 		protoRules[i] = convertRuleFromDriverToProto(r)
 	}
 
+	var caps []string
+	if cr, ok := d.r.(CapabilityRegisterer); ok {
+		for _, c := range cr.Capabilities() {
+			caps = append(caps, string(c))
+		}
+	}
+
 	return &proto.MetasResponse{
 		Name:             d.r.Name(),
 		Rules:            protoRules,
 		AdditionalParams: proto.ConvertParamToProtoParam(d.r.AdditionalParams()),
+		Capabilities:     caps,
 	}, nil
 }
 
@@ -0,0 +1,42 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/proto"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRegisterer struct {
+	caps []Capability
+}
+
+func (r *testRegisterer) Name() string { return "test" }
+
+func (r *testRegisterer) Rules() []*Rule { return nil }
+
+func (r *testRegisterer) AdditionalParams() params.Params { return nil }
+
+type testCapabilityRegisterer struct {
+	testRegisterer
+}
+
+func (r *testCapabilityRegisterer) Capabilities() []Capability { return r.caps }
+
+func TestAuditDriverGRPCServer_Metas(t *testing.T) {
+	srv := NewAuditDriverPlugin(&testRegisterer{}, nil).Srv
+	resp, err := srv.Metas(context.TODO(), &proto.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, "test", resp.GetName())
+	assert.Empty(t, resp.GetCapabilities())
+
+	srv = NewAuditDriverPlugin(&testCapabilityRegisterer{testRegisterer{
+		caps: []Capability{CapabilityOfflineAudit, CapabilityRollback},
+	}}, nil).Srv
+	resp, err = srv.Metas(context.TODO(), &proto.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"offline_audit", "rollback"}, resp.GetCapabilities())
+}
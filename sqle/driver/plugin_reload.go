@@ -0,0 +1,259 @@
+package driver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/log"
+	sqleErrors "github.com/actiontech/sqle/sqle/pkg/errors"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/pingcap/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// pluginDrainTimeout is the max time to wait for the in-flight drivers of a replaced plugin.
+const pluginDrainTimeout = 10 * time.Minute
+
+// registeredPlugin is a plugin process which is registered as driver.
+type registeredPlugin struct {
+	name   string
+	path   string
+	client PluginClient
+
+	// inUse is the number of drivers which are created by this plugin and not closed.
+	inUse int64
+}
+
+func (p *registeredPlugin) acquire() {
+	atomic.AddInt64(&p.inUse, 1)
+}
+
+func (p *registeredPlugin) release() {
+	atomic.AddInt64(&p.inUse, -1)
+}
+
+func (p *registeredPlugin) inUseCount() int64 {
+	return atomic.LoadInt64(&p.inUse)
+}
+
+// PluginStatus is the load status of a plugin binary in plugin directory.
+type PluginStatus struct {
+	Path        string
+	Name        string
+	ModTime     time.Time
+	LoadedAt    time.Time
+	LastError   string
+	LastErrorAt time.Time
+}
+
+var (
+	pluginDir string
+
+	// registeredPlugins key is plugin name.
+	registeredPlugins   = map[string]*registeredPlugin{}
+	registeredPluginsMu = &sync.RWMutex{}
+
+	// pluginStatus key is plugin path.
+	pluginStatus   = map[string]*PluginStatus{}
+	pluginStatusMu = &sync.RWMutex{}
+
+	// reloadMu makes sure only one reloading is running.
+	reloadMu = &sync.Mutex{}
+)
+
+func setPluginDir(dir string) {
+	reloadMu.Lock()
+	pluginDir = dir
+	reloadMu.Unlock()
+}
+
+// acquirePlugin returns the plugin registered by name and marks it in use. It is done under
+// the same lock which replaces the plugin, so a replaced plugin is never acquired after it
+// starts draining, the handler selected before the replacing uses the new plugin instead.
+func acquirePlugin(name string) (*registeredPlugin, error) {
+	registeredPluginsMu.RLock()
+	defer registeredPluginsMu.RUnlock()
+	p, ok := registeredPlugins[name]
+	if !ok {
+		return nil, fmt.Errorf("plugin %s is not registered", name)
+	}
+	p.acquire()
+	return p, nil
+}
+
+func getRegisteredPlugin(name string) (*registeredPlugin, bool) {
+	registeredPluginsMu.RLock()
+	defer registeredPluginsMu.RUnlock()
+	p, ok := registeredPlugins[name]
+	return p, ok
+}
+
+// registerPluginAuditDriver registers the audit driver of plugin. A registered driver can only be
+// replaced by the plugin which has the same binary path, the replaced plugin will be killed after
+// all drivers created by it are closed.
+func registerPluginAuditDriver(rp *registeredPlugin, h handler, rs []*Rule, ap params.Params, caps []Capability) error {
+	registeredPluginsMu.Lock()
+	defer registeredPluginsMu.Unlock()
+
+	old, replace := registeredPlugins[rp.name]
+	if replace && old.path != rp.path {
+		return fmt.Errorf("driver %s has been registered by plugin %s", rp.name, old.path)
+	}
+	if err := setAuditDriver(rp.name, h, rs, ap, caps, replace); err != nil {
+		return fmt.Errorf("register driver %s failed: %v", rp.name, err)
+	}
+	registeredPlugins[rp.name] = rp
+
+	if replace {
+		go drainPlugin(old)
+	}
+	return nil
+}
+
+// drainPlugin kills the plugin process after all drivers created by it are closed.
+func drainPlugin(p *registeredPlugin) {
+	l := log.NewEntry().WithFields(logrus.Fields{
+		"plugin_name": p.name,
+		"plugin_path": p.path,
+	})
+	timeout := time.After(pluginDrainTimeout)
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for p.inUseCount() > 0 {
+		select {
+		case <-timeout:
+			l.Warnf("there are still %d drivers in use after %v, kill the old plugin anyway",
+				p.inUseCount(), pluginDrainTimeout)
+			closePluginClient(p.client)
+			return
+		case <-tick.C:
+		}
+	}
+	closePluginClient(p.client)
+	l.Infoln("old plugin has been killed")
+}
+
+func setPluginLoaded(path, name string, modTime time.Time) {
+	pluginStatusMu.Lock()
+	defer pluginStatusMu.Unlock()
+	pluginStatus[path] = &PluginStatus{
+		Path:     path,
+		Name:     name,
+		ModTime:  modTime,
+		LoadedAt: time.Now(),
+	}
+}
+
+func setPluginLoadFailed(path string, modTime time.Time, err error) {
+	pluginStatusMu.Lock()
+	defer pluginStatusMu.Unlock()
+	status, ok := pluginStatus[path]
+	if !ok {
+		status = &PluginStatus{Path: path}
+		pluginStatus[path] = status
+	}
+	// keep name and loaded time of the running plugin, only the binary mod time is updated.
+	status.ModTime = modTime
+	status.LastError = err.Error()
+	status.LastErrorAt = time.Now()
+}
+
+// AllPluginStatus returns the load status of all plugin binaries in plugin directory.
+func AllPluginStatus() []PluginStatus {
+	pluginStatusMu.RLock()
+	defer pluginStatusMu.RUnlock()
+	ret := make([]PluginStatus, 0, len(pluginStatus))
+	for _, status := range pluginStatus {
+		ret = append(ret, *status)
+	}
+	return ret
+}
+
+func loadPlugin(binaryPath string) (PluginClient, error) {
+	var client PluginClient
+	client = newClientFromFile(binaryPath)
+	if !testConnClient(client) {
+		client = newOldClientFromFile(binaryPath)
+		if !testConnClient(client) {
+			return nil, fmt.Errorf("unable to load plugin: %v", binaryPath)
+		}
+	}
+	if err := RegisterDriverFromClient(client); err != nil {
+		closePluginClient(client)
+		return nil, err
+	}
+	return client, nil
+}
+
+func pluginNameByPath(path string) string {
+	registeredPluginsMu.RLock()
+	defer registeredPluginsMu.RUnlock()
+	for name, p := range registeredPlugins {
+		if p.path == path {
+			return name
+		}
+	}
+	return ""
+}
+
+// ReloadPlugins rescans plugin directory, the new plugin binaries and the modified plugin
+// binaries will be loaded, the drivers registered by the modified plugins are replaced.
+// A plugin binary failed to load is not retried until it is modified again.
+func ReloadPlugins() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	if pluginDir == "" {
+		return nil
+	}
+
+	plugins, err := listPluginFiles(pluginDir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range plugins {
+		binaryPath := filepath.Join(pluginDir, p.Name())
+
+		pluginStatusMu.RLock()
+		status, ok := pluginStatus[binaryPath]
+		pluginStatusMu.RUnlock()
+		if ok && status.ModTime.Equal(p.ModTime()) {
+			continue
+		}
+
+		l := log.NewEntry().WithField("plugin_path", binaryPath)
+		if _, err := loadPlugin(binaryPath); err != nil {
+			l.Errorf("reload plugin failed, error: %v", err)
+			setPluginLoadFailed(binaryPath, p.ModTime(), err)
+			errs = append(errs, err)
+			continue
+		}
+		setPluginLoaded(binaryPath, pluginNameByPath(binaryPath), p.ModTime())
+		l.Infoln("plugin reloaded")
+	}
+	return sqleErrors.Combine(errs...)
+}
+
+func listPluginFiles(dir string) ([]os.FileInfo, error) {
+	var plugins []os.FileInfo
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Wrap(err, "init plugin")
+		}
+
+		if info.IsDir() || info.Mode()&0111 == 0 {
+			return nil
+		}
+		plugins = append(plugins, info)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return plugins, nil
+}
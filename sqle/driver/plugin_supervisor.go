@@ -0,0 +1,350 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/log"
+
+	goPlugin "github.com/hashicorp/go-plugin"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PluginProcessState string
+
+const (
+	PluginProcessStateNotStarted   PluginProcessState = "not_started"
+	PluginProcessStateRunning      PluginProcessState = "running"
+	PluginProcessStateStopped      PluginProcessState = "stopped"
+	PluginProcessStateCrashed      PluginProcessState = "crashed"
+	PluginProcessStateStartFailed  PluginProcessState = "start_failed"
+	PluginProcessStateUnresponsive PluginProcessState = "unresponsive"
+)
+
+const (
+	pluginRestartBackoffBase = 500 * time.Millisecond
+	pluginRestartBackoffMax  = 30 * time.Second
+
+	pluginExitCheckInterval = time.Second
+
+	// pluginUnresponsiveTimeout is the duration of a RPC after which the plugin is regarded as hung.
+	pluginUnresponsiveTimeout = 5 * time.Minute
+)
+
+var errPluginExitedUnexpectedly = errors.New("plugin process exited unexpectedly")
+
+// PluginProcessStatus is the runtime status of a plugin process.
+type PluginProcessStatus struct {
+	Path      string
+	State     PluginProcessState
+	Pid       int
+	StartedAt time.Time
+
+	LastError   string
+	LastErrorAt time.Time
+
+	// RestartCount is the number of times the plugin process is started after crashed or failed to start.
+	RestartCount int
+
+	RPCCount       int64
+	LastRPCLatency time.Duration
+	AvgRPCLatency  time.Duration
+
+	InflightRPCCount   int
+	LongestInflightRPC time.Duration
+}
+
+// pluginSupervisor tracks the processes started by a plugin client. A plugin process
+// which crashed is restarted with backoff by the exit watcher, a plugin process which
+// failed to start will be restarted with backoff on the next call.
+type pluginSupervisor struct {
+	mu sync.Mutex
+
+	// restarter starts a new plugin process after the current one crashed.
+	restarter func() error
+	// closed is set when the plugin is unloaded, the crashed process is not restarted any more.
+	closed bool
+
+	path      string
+	state     PluginProcessState
+	current   *goPlugin.Client
+	pid       int
+	startedAt time.Time
+
+	// monitors key is the started process, value is closed when the process is killed by sqle.
+	monitors map[*goPlugin.Client]chan struct{}
+
+	lastError           string
+	lastErrorAt         time.Time
+	restartCount        int
+	consecutiveFailures int
+
+	rpcCount        int64
+	lastRPCLatency  time.Duration
+	totalRPCLatency time.Duration
+
+	inflightSeq uint64
+	inflight    map[uint64]time.Time
+}
+
+func newPluginSupervisor(path string) *pluginSupervisor {
+	return &pluginSupervisor{
+		path:     path,
+		state:    PluginProcessStateNotStarted,
+		monitors: map[*goPlugin.Client]chan struct{}{},
+		inflight: map[uint64]time.Time{},
+	}
+}
+
+func (s *pluginSupervisor) dialOptions() []grpc.DialOption {
+	return []grpc.DialOption{grpc.WithUnaryInterceptor(s.unaryInterceptor)}
+}
+
+// waitBackoff blocks before restarting a crashed or failed plugin process, the wait time
+// is doubled on every consecutive failure.
+func (s *pluginSupervisor) waitBackoff() {
+	s.mu.Lock()
+	failures := s.consecutiveFailures
+	s.mu.Unlock()
+	if failures == 0 {
+		return
+	}
+
+	backoff := pluginRestartBackoffBase
+	for i := 1; i < failures && backoff < pluginRestartBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > pluginRestartBackoffMax {
+		backoff = pluginRestartBackoffMax
+	}
+	log.NewEntry().WithFields(logrus.Fields{
+		"plugin_path": s.path,
+		"failures":    failures,
+	}).Warnf("plugin process is not healthy, restart after %v", backoff)
+	time.Sleep(backoff)
+}
+
+// started records the result of starting the plugin process c.
+func (s *pluginSupervisor) started(c *goPlugin.Client, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.consecutiveFailures > 0 {
+		s.restartCount++
+	}
+	s.current = c
+	if err != nil {
+		s.state = PluginProcessStateStartFailed
+		s.pid = 0
+		s.setErrorLocked(err)
+		s.consecutiveFailures++
+		return
+	}
+
+	s.state = PluginProcessStateRunning
+	s.consecutiveFailures = 0
+	s.startedAt = time.Now()
+	s.pid = 0
+	if rc := c.ReattachConfig(); rc != nil {
+		s.pid = rc.Pid
+	}
+
+	stopCh := make(chan struct{})
+	s.monitors[c] = stopCh
+	go s.monitor(c, stopCh)
+}
+
+// stopping is called before the plugin process c is killed by sqle.
+func (s *pluginSupervisor) stopping(c *goPlugin.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stopCh, ok := s.monitors[c]; ok {
+		close(stopCh)
+		delete(s.monitors, c)
+	}
+	if s.current == c && s.state == PluginProcessStateRunning {
+		s.state = PluginProcessStateStopped
+	}
+}
+
+func (s *pluginSupervisor) monitor(c *goPlugin.Client, stopCh <-chan struct{}) {
+	tick := time.NewTicker(pluginExitCheckInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-tick.C:
+			if c.Exited() {
+				s.crashed(c)
+				return
+			}
+		}
+	}
+}
+
+func (s *pluginSupervisor) crashed(c *goPlugin.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.monitors[c]; !ok {
+		// the process is killed by sqle.
+		return
+	}
+	delete(s.monitors, c)
+	log.NewEntry().WithField("plugin_path", s.path).Errorln(errPluginExitedUnexpectedly)
+	if s.current != c {
+		return
+	}
+	s.state = PluginProcessStateCrashed
+	s.setErrorLocked(errPluginExitedUnexpectedly)
+	s.consecutiveFailures++
+	if s.restarter != nil && !s.closed {
+		go s.restartCrashed()
+	}
+}
+
+// restartCrashed restarts the crashed plugin process with backoff until it is running again,
+// so the next call needn't wait for the restart.
+func (s *pluginSupervisor) restartCrashed() {
+	for {
+		s.waitBackoff()
+		if !s.needRestart() {
+			return
+		}
+		err := s.restarter()
+		if err == nil {
+			return
+		}
+		log.NewEntry().WithField("plugin_path", s.path).Errorf("restart crashed plugin process failed: %v", err)
+	}
+}
+
+// needRestart returns true if the current plugin process is crashed or failed to start.
+func (s *pluginSupervisor) needRestart() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	return s.state == PluginProcessStateCrashed || s.state == PluginProcessStateStartFailed
+}
+
+// close stops restarting the plugin process, it is called when the plugin is unloaded.
+func (s *pluginSupervisor) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+func (s *pluginSupervisor) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *pluginSupervisor) setErrorLocked(err error) {
+	s.lastError = err.Error()
+	s.lastErrorAt = time.Now()
+}
+
+func (s *pluginSupervisor) unaryInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	s.mu.Lock()
+	s.inflightSeq++
+	id := s.inflightSeq
+	s.inflight[id] = start
+	s.mu.Unlock()
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	latency := time.Since(start)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inflight, id)
+	s.rpcCount++
+	s.lastRPCLatency = latency
+	s.totalRPCLatency += latency
+	switch status.Code(err) {
+	case codes.OK, codes.Unimplemented, codes.Canceled:
+	default:
+		s.lastError = method + ": " + err.Error()
+		s.lastErrorAt = time.Now()
+	}
+	return err
+}
+
+func (s *pluginSupervisor) status() *PluginProcessStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ret := &PluginProcessStatus{
+		Path:             s.path,
+		State:            s.state,
+		Pid:              s.pid,
+		StartedAt:        s.startedAt,
+		LastError:        s.lastError,
+		LastErrorAt:      s.lastErrorAt,
+		RestartCount:     s.restartCount,
+		RPCCount:         s.rpcCount,
+		LastRPCLatency:   s.lastRPCLatency,
+		InflightRPCCount: len(s.inflight),
+	}
+	if s.rpcCount > 0 {
+		ret.AvgRPCLatency = s.totalRPCLatency / time.Duration(s.rpcCount)
+	}
+	for _, start := range s.inflight {
+		if d := time.Since(start); d > ret.LongestInflightRPC {
+			ret.LongestInflightRPC = d
+		}
+	}
+	if ret.State == PluginProcessStateRunning && ret.LongestInflightRPC > pluginUnresponsiveTimeout {
+		ret.State = PluginProcessStateUnresponsive
+	}
+	return ret
+}
+
+// supervisedPluginClient is a plugin client which process is tracked by pluginSupervisor.
+type supervisedPluginClient interface {
+	supervisor() *pluginSupervisor
+}
+
+// closePluginClient kills the plugin process and stops restarting it.
+func closePluginClient(c PluginClient) {
+	if sc, ok := c.(supervisedPluginClient); ok {
+		sc.supervisor().close()
+	}
+	c.Kill()
+}
+
+// DriverStatus is the status of a registered audit driver, Process is nil if the driver is not a plugin.
+type DriverStatus struct {
+	Name    string
+	Process *PluginProcessStatus
+}
+
+// AllDriverStatus returns the status of all registered audit drivers.
+func AllDriverStatus() []*DriverStatus {
+	names := AllDrivers()
+	sort.Strings(names)
+
+	ret := make([]*DriverStatus, 0, len(names))
+	for _, name := range names {
+		ds := &DriverStatus{Name: name}
+		if rp, ok := getRegisteredPlugin(name); ok {
+			if c, ok := rp.client.(supervisedPluginClient); ok {
+				ds.Process = c.supervisor().status()
+			}
+		}
+		ret = append(ret, ds)
+	}
+	return ret
+}
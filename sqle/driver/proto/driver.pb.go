@@ -10,6 +10,7 @@ It is generated from these files:
 It has these top-level messages:
 	DSN
 	Rule
+	RuleKnowledge
 	Param
 	InitRequest
 	Empty
@@ -23,7 +24,10 @@ It has these top-level messages:
 	ParseResponse
 	AuditRequest
 	AuditResult
+	SQLPosition
 	AuditResponse
+	AuditBatchRequest
+	AuditBatchResponse
 	GenRollbackSQLRequest
 	GenRollbackSQLResponse
 	MetasResponse
@@ -107,12 +111,13 @@ func (m *DSN) GetAdditionalParams() []*Param {
 }
 
 type Rule struct {
-	Name     string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Desc     string   `protobuf:"bytes,2,opt,name=desc" json:"desc,omitempty"`
-	Value    string   `protobuf:"bytes,3,opt,name=value" json:"value,omitempty"`
-	Level    string   `protobuf:"bytes,4,opt,name=level" json:"level,omitempty"`
-	Category string   `protobuf:"bytes,5,opt,name=category" json:"category,omitempty"`
-	Params   []*Param `protobuf:"bytes,6,rep,name=params" json:"params,omitempty"`
+	Name      string         `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Desc      string         `protobuf:"bytes,2,opt,name=desc" json:"desc,omitempty"`
+	Value     string         `protobuf:"bytes,3,opt,name=value" json:"value,omitempty"`
+	Level     string         `protobuf:"bytes,4,opt,name=level" json:"level,omitempty"`
+	Category  string         `protobuf:"bytes,5,opt,name=category" json:"category,omitempty"`
+	Params    []*Param       `protobuf:"bytes,6,rep,name=params" json:"params,omitempty"`
+	Knowledge *RuleKnowledge `protobuf:"bytes,7,opt,name=knowledge" json:"knowledge,omitempty"`
 }
 
 func (m *Rule) Reset()                    { *m = Rule{} }
@@ -162,6 +167,53 @@ func (m *Rule) GetParams() []*Param {
 	return nil
 }
 
+func (m *Rule) GetKnowledge() *RuleKnowledge {
+	if m != nil {
+		return m.Knowledge
+	}
+	return nil
+}
+
+type RuleKnowledge struct {
+	Rationale   string `protobuf:"bytes,1,opt,name=rationale" json:"rationale,omitempty"`
+	BadExample  string `protobuf:"bytes,2,opt,name=badExample" json:"badExample,omitempty"`
+	GoodExample string `protobuf:"bytes,3,opt,name=goodExample" json:"goodExample,omitempty"`
+	Remediation string `protobuf:"bytes,4,opt,name=remediation" json:"remediation,omitempty"`
+}
+
+func (m *RuleKnowledge) Reset()                    { *m = RuleKnowledge{} }
+func (m *RuleKnowledge) String() string            { return proto1.CompactTextString(m) }
+func (*RuleKnowledge) ProtoMessage()               {}
+func (*RuleKnowledge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *RuleKnowledge) GetRationale() string {
+	if m != nil {
+		return m.Rationale
+	}
+	return ""
+}
+
+func (m *RuleKnowledge) GetBadExample() string {
+	if m != nil {
+		return m.BadExample
+	}
+	return ""
+}
+
+func (m *RuleKnowledge) GetGoodExample() string {
+	if m != nil {
+		return m.GoodExample
+	}
+	return ""
+}
+
+func (m *RuleKnowledge) GetRemediation() string {
+	if m != nil {
+		return m.Remediation
+	}
+	return ""
+}
+
 type Param struct {
 	Key   string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
@@ -172,7 +224,7 @@ type Param struct {
 func (m *Param) Reset()                    { *m = Param{} }
 func (m *Param) String() string            { return proto1.CompactTextString(m) }
 func (*Param) ProtoMessage()               {}
-func (*Param) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*Param) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *Param) GetKey() string {
 	if m != nil {
@@ -210,7 +262,7 @@ type InitRequest struct {
 func (m *InitRequest) Reset()                    { *m = InitRequest{} }
 func (m *InitRequest) String() string            { return proto1.CompactTextString(m) }
 func (*InitRequest) ProtoMessage()               {}
-func (*InitRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*InitRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *InitRequest) GetDsn() *DSN {
 	if m != nil {
@@ -232,7 +284,7 @@ type Empty struct {
 func (m *Empty) Reset()                    { *m = Empty{} }
 func (m *Empty) String() string            { return proto1.CompactTextString(m) }
 func (*Empty) ProtoMessage()               {}
-func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 type ExecRequest struct {
 	Query string `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
@@ -241,7 +293,7 @@ type ExecRequest struct {
 func (m *ExecRequest) Reset()                    { *m = ExecRequest{} }
 func (m *ExecRequest) String() string            { return proto1.CompactTextString(m) }
 func (*ExecRequest) ProtoMessage()               {}
-func (*ExecRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*ExecRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *ExecRequest) GetQuery() string {
 	if m != nil {
@@ -260,7 +312,7 @@ type ExecResponse struct {
 func (m *ExecResponse) Reset()                    { *m = ExecResponse{} }
 func (m *ExecResponse) String() string            { return proto1.CompactTextString(m) }
 func (*ExecResponse) ProtoMessage()               {}
-func (*ExecResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*ExecResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func (m *ExecResponse) GetLastInsertId() int64 {
 	if m != nil {
@@ -297,7 +349,7 @@ type TxRequest struct {
 func (m *TxRequest) Reset()                    { *m = TxRequest{} }
 func (m *TxRequest) String() string            { return proto1.CompactTextString(m) }
 func (*TxRequest) ProtoMessage()               {}
-func (*TxRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*TxRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *TxRequest) GetQueries() []string {
 	if m != nil {
@@ -313,7 +365,7 @@ type TxResponse struct {
 func (m *TxResponse) Reset()                    { *m = TxResponse{} }
 func (m *TxResponse) String() string            { return proto1.CompactTextString(m) }
 func (*TxResponse) ProtoMessage()               {}
-func (*TxResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*TxResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *TxResponse) GetResults() []*ExecResponse {
 	if m != nil {
@@ -329,7 +381,7 @@ type DatabasesResponse struct {
 func (m *DatabasesResponse) Reset()                    { *m = DatabasesResponse{} }
 func (m *DatabasesResponse) String() string            { return proto1.CompactTextString(m) }
 func (*DatabasesResponse) ProtoMessage()               {}
-func (*DatabasesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*DatabasesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *DatabasesResponse) GetDatabases() []string {
 	if m != nil {
@@ -345,7 +397,7 @@ type ParseRequest struct {
 func (m *ParseRequest) Reset()                    { *m = ParseRequest{} }
 func (m *ParseRequest) String() string            { return proto1.CompactTextString(m) }
 func (*ParseRequest) ProtoMessage()               {}
-func (*ParseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*ParseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *ParseRequest) GetSqlText() string {
 	if m != nil {
@@ -363,7 +415,7 @@ type Node struct {
 func (m *Node) Reset()                    { *m = Node{} }
 func (m *Node) String() string            { return proto1.CompactTextString(m) }
 func (*Node) ProtoMessage()               {}
-func (*Node) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*Node) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *Node) GetText() string {
 	if m != nil {
@@ -393,7 +445,7 @@ type ParseResponse struct {
 func (m *ParseResponse) Reset()                    { *m = ParseResponse{} }
 func (m *ParseResponse) String() string            { return proto1.CompactTextString(m) }
 func (*ParseResponse) ProtoMessage()               {}
-func (*ParseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*ParseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *ParseResponse) GetNodes() []*Node {
 	if m != nil {
@@ -409,7 +461,7 @@ type AuditRequest struct {
 func (m *AuditRequest) Reset()                    { *m = AuditRequest{} }
 func (m *AuditRequest) String() string            { return proto1.CompactTextString(m) }
 func (*AuditRequest) ProtoMessage()               {}
-func (*AuditRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*AuditRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *AuditRequest) GetSql() string {
 	if m != nil {
@@ -419,14 +471,22 @@ func (m *AuditRequest) GetSql() string {
 }
 
 type AuditResult struct {
-	Message string `protobuf:"bytes,1,opt,name=message" json:"message,omitempty"`
-	Level   string `protobuf:"bytes,2,opt,name=level" json:"level,omitempty"`
+	Message  string       `protobuf:"bytes,1,opt,name=message" json:"message,omitempty"`
+	Level    string       `protobuf:"bytes,2,opt,name=level" json:"level,omitempty"`
+	RuleName string       `protobuf:"bytes,3,opt,name=ruleName" json:"ruleName,omitempty"`
+	Category string       `protobuf:"bytes,4,opt,name=category" json:"category,omitempty"`
+	Position *SQLPosition `protobuf:"bytes,5,opt,name=position" json:"position,omitempty"`
+	// suppressed is true if the result is suppressed by the hint in SQL comment.
+	Suppressed     bool   `protobuf:"varint,6,opt,name=suppressed" json:"suppressed,omitempty"`
+	SuppressReason string `protobuf:"bytes,7,opt,name=suppressReason" json:"suppressReason,omitempty"`
+	// fixable is true if the problem is fixed by the fixedSQL of AuditResponse.
+	Fixable bool `protobuf:"varint,8,opt,name=fixable" json:"fixable,omitempty"`
 }
 
 func (m *AuditResult) Reset()                    { *m = AuditResult{} }
 func (m *AuditResult) String() string            { return proto1.CompactTextString(m) }
 func (*AuditResult) ProtoMessage()               {}
-func (*AuditResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*AuditResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 func (m *AuditResult) GetMessage() string {
 	if m != nil {
@@ -442,14 +502,103 @@ func (m *AuditResult) GetLevel() string {
 	return ""
 }
 
+func (m *AuditResult) GetRuleName() string {
+	if m != nil {
+		return m.RuleName
+	}
+	return ""
+}
+
+func (m *AuditResult) GetCategory() string {
+	if m != nil {
+		return m.Category
+	}
+	return ""
+}
+
+func (m *AuditResult) GetPosition() *SQLPosition {
+	if m != nil {
+		return m.Position
+	}
+	return nil
+}
+
+func (m *AuditResult) GetSuppressed() bool {
+	if m != nil {
+		return m.Suppressed
+	}
+	return false
+}
+
+func (m *AuditResult) GetSuppressReason() string {
+	if m != nil {
+		return m.SuppressReason
+	}
+	return ""
+}
+
+func (m *AuditResult) GetFixable() bool {
+	if m != nil {
+		return m.Fixable
+	}
+	return false
+}
+
+// SQLPosition is the position of a SQL fragment in the audited SQL,
+// line and column start from 1.
+type SQLPosition struct {
+	StartLine   int32 `protobuf:"varint,1,opt,name=startLine" json:"startLine,omitempty"`
+	StartColumn int32 `protobuf:"varint,2,opt,name=startColumn" json:"startColumn,omitempty"`
+	EndLine     int32 `protobuf:"varint,3,opt,name=endLine" json:"endLine,omitempty"`
+	EndColumn   int32 `protobuf:"varint,4,opt,name=endColumn" json:"endColumn,omitempty"`
+}
+
+func (m *SQLPosition) Reset()                    { *m = SQLPosition{} }
+func (m *SQLPosition) String() string            { return proto1.CompactTextString(m) }
+func (*SQLPosition) ProtoMessage()               {}
+func (*SQLPosition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *SQLPosition) GetStartLine() int32 {
+	if m != nil {
+		return m.StartLine
+	}
+	return 0
+}
+
+func (m *SQLPosition) GetStartColumn() int32 {
+	if m != nil {
+		return m.StartColumn
+	}
+	return 0
+}
+
+func (m *SQLPosition) GetEndLine() int32 {
+	if m != nil {
+		return m.EndLine
+	}
+	return 0
+}
+
+func (m *SQLPosition) GetEndColumn() int32 {
+	if m != nil {
+		return m.EndColumn
+	}
+	return 0
+}
+
 type AuditResponse struct {
 	Results []*AuditResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	// fixedSQL is the SQL rewritten to fix the problems, it is empty if no problem can be fixed.
+	FixedSQL string `protobuf:"bytes,2,opt,name=fixedSQL" json:"fixedSQL,omitempty"`
+	// affectedRowsEstimated is true if the estimatedAffectedRows is set.
+	AffectedRowsEstimated bool  `protobuf:"varint,3,opt,name=affectedRowsEstimated" json:"affectedRowsEstimated,omitempty"`
+	EstimatedAffectedRows int64 `protobuf:"varint,4,opt,name=estimatedAffectedRows" json:"estimatedAffectedRows,omitempty"`
 }
 
 func (m *AuditResponse) Reset()                    { *m = AuditResponse{} }
 func (m *AuditResponse) String() string            { return proto1.CompactTextString(m) }
 func (*AuditResponse) ProtoMessage()               {}
-func (*AuditResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*AuditResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *AuditResponse) GetResults() []*AuditResult {
 	if m != nil {
@@ -458,6 +607,59 @@ func (m *AuditResponse) GetResults() []*AuditResult {
 	return nil
 }
 
+func (m *AuditResponse) GetFixedSQL() string {
+	if m != nil {
+		return m.FixedSQL
+	}
+	return ""
+}
+
+func (m *AuditResponse) GetAffectedRowsEstimated() bool {
+	if m != nil {
+		return m.AffectedRowsEstimated
+	}
+	return false
+}
+
+func (m *AuditResponse) GetEstimatedAffectedRows() int64 {
+	if m != nil {
+		return m.EstimatedAffectedRows
+	}
+	return 0
+}
+
+type AuditBatchRequest struct {
+	Sqls []string `protobuf:"bytes,1,rep,name=sqls" json:"sqls,omitempty"`
+}
+
+func (m *AuditBatchRequest) Reset()                    { *m = AuditBatchRequest{} }
+func (m *AuditBatchRequest) String() string            { return proto1.CompactTextString(m) }
+func (*AuditBatchRequest) ProtoMessage()               {}
+func (*AuditBatchRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *AuditBatchRequest) GetSqls() []string {
+	if m != nil {
+		return m.Sqls
+	}
+	return nil
+}
+
+type AuditBatchResponse struct {
+	Results []*AuditResponse `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *AuditBatchResponse) Reset()                    { *m = AuditBatchResponse{} }
+func (m *AuditBatchResponse) String() string            { return proto1.CompactTextString(m) }
+func (*AuditBatchResponse) ProtoMessage()               {}
+func (*AuditBatchResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *AuditBatchResponse) GetResults() []*AuditResponse {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
 type GenRollbackSQLRequest struct {
 	Sql string `protobuf:"bytes,1,opt,name=sql" json:"sql,omitempty"`
 }
@@ -465,7 +667,7 @@ type GenRollbackSQLRequest struct {
 func (m *GenRollbackSQLRequest) Reset()                    { *m = GenRollbackSQLRequest{} }
 func (m *GenRollbackSQLRequest) String() string            { return proto1.CompactTextString(m) }
 func (*GenRollbackSQLRequest) ProtoMessage()               {}
-func (*GenRollbackSQLRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*GenRollbackSQLRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *GenRollbackSQLRequest) GetSql() string {
 	if m != nil {
@@ -482,7 +684,7 @@ type GenRollbackSQLResponse struct {
 func (m *GenRollbackSQLResponse) Reset()                    { *m = GenRollbackSQLResponse{} }
 func (m *GenRollbackSQLResponse) String() string            { return proto1.CompactTextString(m) }
 func (*GenRollbackSQLResponse) ProtoMessage()               {}
-func (*GenRollbackSQLResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*GenRollbackSQLResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *GenRollbackSQLResponse) GetSql() string {
 	if m != nil {
@@ -502,12 +704,14 @@ type MetasResponse struct {
 	Name             string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Rules            []*Rule  `protobuf:"bytes,2,rep,name=rules" json:"rules,omitempty"`
 	AdditionalParams []*Param `protobuf:"bytes,3,rep,name=additionalParams" json:"additionalParams,omitempty"`
+	// capabilities is the features supported by the driver, see driver.Capability.
+	Capabilities []string `protobuf:"bytes,4,rep,name=capabilities" json:"capabilities,omitempty"`
 }
 
 func (m *MetasResponse) Reset()                    { *m = MetasResponse{} }
 func (m *MetasResponse) String() string            { return proto1.CompactTextString(m) }
 func (*MetasResponse) ProtoMessage()               {}
-func (*MetasResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*MetasResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 func (m *MetasResponse) GetName() string {
 	if m != nil {
@@ -530,9 +734,17 @@ func (m *MetasResponse) GetAdditionalParams() []*Param {
 	return nil
 }
 
+func (m *MetasResponse) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
 func init() {
 	proto1.RegisterType((*DSN)(nil), "proto.DSN")
 	proto1.RegisterType((*Rule)(nil), "proto.Rule")
+	proto1.RegisterType((*RuleKnowledge)(nil), "proto.RuleKnowledge")
 	proto1.RegisterType((*Param)(nil), "proto.Param")
 	proto1.RegisterType((*InitRequest)(nil), "proto.InitRequest")
 	proto1.RegisterType((*Empty)(nil), "proto.Empty")
@@ -546,7 +758,10 @@ func init() {
 	proto1.RegisterType((*ParseResponse)(nil), "proto.ParseResponse")
 	proto1.RegisterType((*AuditRequest)(nil), "proto.AuditRequest")
 	proto1.RegisterType((*AuditResult)(nil), "proto.AuditResult")
+	proto1.RegisterType((*SQLPosition)(nil), "proto.SQLPosition")
 	proto1.RegisterType((*AuditResponse)(nil), "proto.AuditResponse")
+	proto1.RegisterType((*AuditBatchRequest)(nil), "proto.AuditBatchRequest")
+	proto1.RegisterType((*AuditBatchResponse)(nil), "proto.AuditBatchResponse")
 	proto1.RegisterType((*GenRollbackSQLRequest)(nil), "proto.GenRollbackSQLRequest")
 	proto1.RegisterType((*GenRollbackSQLResponse)(nil), "proto.GenRollbackSQLResponse")
 	proto1.RegisterType((*MetasResponse)(nil), "proto.MetasResponse")
@@ -577,6 +792,9 @@ type DriverClient interface {
 	Databases(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DatabasesResponse, error)
 	Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
 	Audit(ctx context.Context, in *AuditRequest, opts ...grpc.CallOption) (*AuditResponse, error)
+	// AuditBatch audits multiple SQLs in one call within the same session
+	// context. Results are returned in the same order as the request SQLs.
+	AuditBatch(ctx context.Context, in *AuditBatchRequest, opts ...grpc.CallOption) (*AuditBatchResponse, error)
 	GenRollbackSQL(ctx context.Context, in *GenRollbackSQLRequest, opts ...grpc.CallOption) (*GenRollbackSQLResponse, error)
 }
 
@@ -669,6 +887,15 @@ func (c *driverClient) Audit(ctx context.Context, in *AuditRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *driverClient) AuditBatch(ctx context.Context, in *AuditBatchRequest, opts ...grpc.CallOption) (*AuditBatchResponse, error) {
+	out := new(AuditBatchResponse)
+	err := grpc.Invoke(ctx, "/proto.Driver/AuditBatch", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *driverClient) GenRollbackSQL(ctx context.Context, in *GenRollbackSQLRequest, opts ...grpc.CallOption) (*GenRollbackSQLResponse, error) {
 	out := new(GenRollbackSQLResponse)
 	err := grpc.Invoke(ctx, "/proto.Driver/GenRollbackSQL", in, out, c.cc, opts...)
@@ -695,6 +922,9 @@ type DriverServer interface {
 	Databases(context.Context, *Empty) (*DatabasesResponse, error)
 	Parse(context.Context, *ParseRequest) (*ParseResponse, error)
 	Audit(context.Context, *AuditRequest) (*AuditResponse, error)
+	// AuditBatch audits multiple SQLs in one call within the same session
+	// context. Results are returned in the same order as the request SQLs.
+	AuditBatch(context.Context, *AuditBatchRequest) (*AuditBatchResponse, error)
 	GenRollbackSQL(context.Context, *GenRollbackSQLRequest) (*GenRollbackSQLResponse, error)
 }
 
@@ -864,6 +1094,24 @@ func _Driver_Audit_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Driver_AuditBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).AuditBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Driver/AuditBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).AuditBatch(ctx, req.(*AuditBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Driver_GenRollbackSQL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GenRollbackSQLRequest)
 	if err := dec(in); err != nil {
@@ -922,6 +1170,10 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Audit",
 			Handler:    _Driver_Audit_Handler,
 		},
+		{
+			MethodName: "AuditBatch",
+			Handler:    _Driver_AuditBatch_Handler,
+		},
 		{
 			MethodName: "GenRollbackSQL",
 			Handler:    _Driver_GenRollbackSQL_Handler,
@@ -934,57 +1186,77 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 func init() { proto1.RegisterFile("driver.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 822 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xdb, 0x6a, 0xe4, 0x46,
-	0x10, 0x45, 0x23, 0x69, 0xbc, 0x53, 0x1a, 0x07, 0xbb, 0xd7, 0x59, 0xc4, 0xe0, 0xc0, 0x6c, 0x6f,
-	0x02, 0xb3, 0x64, 0xe3, 0x25, 0xb3, 0x2f, 0x81, 0x65, 0x1f, 0xd6, 0xb1, 0x09, 0x86, 0xac, 0x71,
-	0x64, 0x43, 0x20, 0x6f, 0xed, 0x51, 0xd9, 0x11, 0x2b, 0x4b, 0x9a, 0xee, 0x96, 0x2f, 0x6f, 0xf9,
-	0x94, 0x7c, 0x43, 0xf2, 0x63, 0xf9, 0x84, 0xd0, 0x37, 0x5d, 0xe6, 0x12, 0xf2, 0xa4, 0xaa, 0x53,
-	0xd5, 0xd5, 0xa7, 0x4b, 0x75, 0x0a, 0xc6, 0x29, 0xcf, 0xee, 0x91, 0x1f, 0x55, 0xbc, 0x94, 0x25,
-	0x09, 0xf5, 0x87, 0xfe, 0xed, 0x81, 0x7f, 0x72, 0x79, 0x4e, 0x08, 0x04, 0xbf, 0x97, 0x42, 0xc6,
-	0xde, 0xd4, 0x9b, 0x8d, 0x12, 0x6d, 0x2b, 0xac, 0x2a, 0xb9, 0x8c, 0x07, 0x06, 0x53, 0xb6, 0xc2,
-	0x6a, 0x81, 0x3c, 0xf6, 0x0d, 0xa6, 0x6c, 0x32, 0x81, 0x67, 0x15, 0x13, 0xe2, 0xa1, 0xe4, 0x69,
-	0x1c, 0x68, 0xbc, 0xf1, 0x55, 0x2c, 0x65, 0x92, 0x5d, 0x33, 0x81, 0x71, 0x68, 0x62, 0xce, 0x27,
-	0x3f, 0xc0, 0x1e, 0x4b, 0xd3, 0x4c, 0x66, 0x65, 0xc1, 0xf2, 0x0b, 0xc6, 0xd9, 0x9d, 0x88, 0x87,
-	0x53, 0x7f, 0x16, 0xcd, 0xc7, 0x86, 0xe4, 0x91, 0x06, 0x93, 0xb5, 0x2c, 0xfa, 0xa7, 0x07, 0x41,
-	0x52, 0xe7, 0xa8, 0xe8, 0x14, 0xec, 0x0e, 0x1d, 0x6d, 0x65, 0x2b, 0x2c, 0x45, 0xb1, 0x70, 0xb4,
-	0x95, 0x4d, 0x62, 0x08, 0xef, 0x59, 0x5e, 0xa3, 0xe1, 0x7d, 0x3c, 0x88, 0xbd, 0xc4, 0x00, 0xe4,
-	0x00, 0xc2, 0x1c, 0xef, 0x31, 0xb7, 0xcc, 0x8d, 0xa3, 0x68, 0x2f, 0x98, 0xc4, 0xdb, 0x92, 0x3f,
-	0x39, 0xda, 0xce, 0x27, 0x5f, 0xc3, 0xb0, 0xda, 0x4e, 0xd6, 0xc6, 0xe8, 0xaf, 0x10, 0x6a, 0x80,
-	0xec, 0x81, 0xff, 0x19, 0x9f, 0x2c, 0x43, 0x65, 0xaa, 0x2b, 0x0d, 0x19, 0xc3, 0xd0, 0x12, 0x71,
-	0xb4, 0xfd, 0x0e, 0x6d, 0x02, 0x81, 0x7c, 0xaa, 0xd0, 0x72, 0xd3, 0x36, 0x3d, 0x87, 0xe8, 0xac,
-	0xc8, 0x64, 0x82, 0xcb, 0x1a, 0x85, 0x24, 0x87, 0xe0, 0xa7, 0xa2, 0xd0, 0xe5, 0xa3, 0x39, 0x58,
-	0x2a, 0x27, 0x97, 0xe7, 0x89, 0x82, 0xc9, 0x4b, 0x08, 0x79, 0x9d, 0xa3, 0x88, 0x7d, 0x4d, 0x35,
-	0xb2, 0x71, 0xd5, 0xbb, 0xc4, 0x44, 0xe8, 0x0e, 0x84, 0xa7, 0x77, 0x95, 0x7c, 0xa2, 0xaf, 0x20,
-	0x3a, 0x7d, 0xc4, 0x85, 0x2b, 0x7c, 0x00, 0xe1, 0xb2, 0x46, 0xee, 0x98, 0x1b, 0x87, 0xfe, 0xe5,
-	0xc1, 0xd8, 0x64, 0x89, 0xaa, 0x2c, 0x04, 0x12, 0x0a, 0xe3, 0x9c, 0x09, 0x79, 0x56, 0x08, 0xe4,
-	0xf2, 0x2c, 0xd5, 0xd9, 0x7e, 0xd2, 0xc3, 0xc8, 0x1b, 0xd8, 0xef, 0xfa, 0xa7, 0x9c, 0x97, 0xdc,
-	0x3e, 0x7e, 0x3d, 0xa0, 0x2a, 0xf2, 0xf2, 0x41, 0x7c, 0xbc, 0xb9, 0xc1, 0x85, 0xc4, 0x54, 0x37,
-	0xc4, 0x4f, 0x7a, 0x98, 0xaa, 0xd8, 0xf5, 0x4d, 0x45, 0xd3, 0xa5, 0xf5, 0x00, 0xfd, 0x06, 0x46,
-	0x57, 0x8f, 0xee, 0x5d, 0x31, 0xec, 0xa8, 0xa7, 0x64, 0x28, 0x62, 0x6f, 0xea, 0xcf, 0x46, 0x89,
-	0x73, 0xe9, 0x7b, 0x00, 0x95, 0x66, 0x1f, 0xf6, 0x1d, 0xec, 0x70, 0x14, 0x75, 0x2e, 0x4d, 0x5e,
-	0x34, 0x7f, 0x6e, 0x9b, 0xd7, 0x7d, 0x7e, 0xe2, 0x72, 0xe8, 0xf7, 0xb0, 0x7f, 0x62, 0x07, 0x5b,
-	0x34, 0x35, 0x0e, 0x61, 0xe4, 0xa6, 0xdd, 0xdd, 0xd6, 0x02, 0x74, 0x06, 0xe3, 0x0b, 0xc6, 0x05,
-	0x76, 0x98, 0x89, 0x65, 0x7e, 0x85, 0x8f, 0x4e, 0x86, 0xce, 0xa5, 0x17, 0x10, 0x9c, 0x97, 0xa9,
-	0x9e, 0x11, 0xd9, 0x86, 0xb5, 0xdd, 0xcc, 0xc8, 0xa0, 0x9d, 0x11, 0x32, 0x85, 0xe8, 0x26, 0x2b,
-	0x6e, 0x91, 0x57, 0x3c, 0x2b, 0xa4, 0x1d, 0xa9, 0x2e, 0x44, 0xe7, 0xb0, 0x6b, 0xef, 0xb6, 0x54,
-	0x5f, 0x42, 0x58, 0x94, 0x29, 0xba, 0xc7, 0xba, 0x49, 0x51, 0xd7, 0x26, 0x26, 0x42, 0xa7, 0x30,
-	0xfe, 0x58, 0xa7, 0xed, 0xe8, 0xed, 0x81, 0x2f, 0x96, 0xb9, 0x9b, 0x6c, 0xb1, 0xcc, 0xe9, 0x07,
-	0x88, 0x6c, 0x86, 0x6a, 0x8a, 0x7a, 0xd0, 0x1d, 0x0a, 0xc1, 0x6e, 0x9d, 0x40, 0x9d, 0xdb, 0xaa,
-	0x6e, 0xd0, 0x51, 0x1d, 0xfd, 0x00, 0xbb, 0xee, 0xb8, 0x21, 0xf5, 0x66, 0xf5, 0x1f, 0x10, 0x4b,
-	0xab, 0x73, 0x4b, 0xfb, 0x0b, 0x5e, 0xc3, 0x97, 0x3f, 0x61, 0x91, 0x94, 0x79, 0x7e, 0xcd, 0x16,
-	0x9f, 0x2f, 0x7f, 0xf9, 0x79, 0x3b, 0xd1, 0x63, 0x78, 0xb1, 0x9a, 0x6a, 0xaf, 0x5c, 0xcb, 0x25,
-	0x2f, 0x60, 0xc8, 0x91, 0x89, 0xb2, 0xb0, 0x64, 0xad, 0x47, 0xff, 0xf0, 0x60, 0xf7, 0x13, 0x4a,
-	0xd6, 0xfe, 0xee, 0x4d, 0xdb, 0xa8, 0x51, 0xe0, 0x60, 0x9b, 0x02, 0x37, 0xee, 0x41, 0xff, 0xff,
-	0xec, 0xc1, 0xf9, 0x3f, 0x3e, 0x0c, 0x4f, 0xf4, 0x56, 0x27, 0xdf, 0x42, 0xa8, 0xc9, 0x10, 0x77,
-	0x46, 0x8b, 0x7a, 0x72, 0x60, 0xbd, 0x3e, 0xd1, 0x19, 0x04, 0x6a, 0x87, 0x10, 0xd7, 0xce, 0xce,
-	0x42, 0x99, 0xf4, 0xce, 0x93, 0x57, 0x10, 0xfe, 0x98, 0x97, 0x02, 0x57, 0xca, 0xf6, 0x93, 0x28,
-	0x04, 0x17, 0x59, 0x71, 0xfb, 0x9f, 0x39, 0x6f, 0x21, 0x50, 0xc2, 0x69, 0xae, 0xec, 0xac, 0x9a,
-	0xc9, 0x26, 0x65, 0x91, 0xd7, 0x30, 0xb8, 0x7a, 0x24, 0x7b, 0x36, 0xd4, 0xe8, 0x77, 0xb2, 0xdf,
-	0x41, 0x6c, 0xea, 0x3b, 0x18, 0x35, 0xda, 0x5b, 0x21, 0x11, 0xbb, 0x8d, 0xb8, 0xa6, 0xcd, 0xb9,
-	0x5e, 0xd0, 0x02, 0xc9, 0xf3, 0xb6, 0xc9, 0x8d, 0x16, 0x9b, 0xbe, 0xf5, 0x45, 0x32, 0x87, 0x50,
-	0x4f, 0x5e, 0x73, 0xa6, 0xab, 0x87, 0xe6, 0x4c, 0x7f, 0x86, 0x3f, 0xc1, 0x17, 0xfd, 0x51, 0x23,
-	0x87, 0x36, 0x6f, 0xe3, 0xb0, 0x4e, 0xbe, 0xda, 0x12, 0x35, 0xe5, 0x8e, 0xe1, 0xb7, 0x67, 0x47,
-	0x6f, 0xdf, 0xeb, 0x94, 0xeb, 0xa1, 0xfe, 0xbc, 0xfb, 0x37, 0x00, 0x00, 0xff, 0xff, 0x12, 0x6c,
-	0xb3, 0x02, 0xda, 0x07, 0x00, 0x00,
+	// 1150 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0x5d, 0x6e, 0xdb, 0x46,
+	0x10, 0x06, 0x45, 0xd2, 0x96, 0x46, 0x72, 0x60, 0x6f, 0x9c, 0x80, 0x15, 0xdc, 0x42, 0xd9, 0xf4,
+	0x47, 0x41, 0x53, 0x07, 0x55, 0xfa, 0x50, 0x20, 0x4f, 0x76, 0x6c, 0x14, 0x41, 0x1d, 0xc3, 0x59,
+	0x1b, 0x28, 0xd0, 0xb7, 0x95, 0x38, 0x56, 0x88, 0x50, 0x24, 0xb5, 0x4b, 0xc5, 0xf2, 0x01, 0x8a,
+	0x3e, 0xf7, 0x0e, 0x3d, 0x41, 0x73, 0x86, 0xde, 0xa0, 0x07, 0x2a, 0xf6, 0x8f, 0xa4, 0x64, 0xb9,
+	0xe8, 0x13, 0x77, 0xbe, 0xf9, 0x76, 0x76, 0x66, 0x76, 0x66, 0x96, 0xd0, 0x8b, 0x45, 0xf2, 0x11,
+	0xc5, 0x61, 0x21, 0xf2, 0x32, 0x27, 0xa1, 0xfe, 0xd0, 0x4f, 0x1e, 0xf8, 0x27, 0x97, 0xe7, 0x84,
+	0x40, 0xf0, 0x3e, 0x97, 0x65, 0xe4, 0x0d, 0xbc, 0x61, 0x87, 0xe9, 0xb5, 0xc2, 0x8a, 0x5c, 0x94,
+	0x51, 0xcb, 0x60, 0x6a, 0xad, 0xb0, 0x85, 0x44, 0x11, 0xf9, 0x06, 0x53, 0x6b, 0xd2, 0x87, 0x76,
+	0xc1, 0xa5, 0xbc, 0xc9, 0x45, 0x1c, 0x05, 0x1a, 0xaf, 0x64, 0xa5, 0x8b, 0x79, 0xc9, 0xc7, 0x5c,
+	0x62, 0x14, 0x1a, 0x9d, 0x93, 0xc9, 0x8f, 0xb0, 0xcb, 0xe3, 0x38, 0x29, 0x93, 0x3c, 0xe3, 0xe9,
+	0x05, 0x17, 0x7c, 0x26, 0xa3, 0xad, 0x81, 0x3f, 0xec, 0x8e, 0x7a, 0xc6, 0xc9, 0x43, 0x0d, 0xb2,
+	0x3b, 0x2c, 0xfa, 0x8f, 0x07, 0x01, 0x5b, 0xa4, 0xa8, 0xdc, 0xc9, 0xf8, 0x0c, 0x9d, 0xdb, 0x6a,
+	0xad, 0xb0, 0x18, 0xe5, 0xc4, 0xb9, 0xad, 0xd6, 0x24, 0x82, 0xf0, 0x23, 0x4f, 0x17, 0x68, 0xfc,
+	0x3e, 0x6e, 0x45, 0x1e, 0x33, 0x00, 0xd9, 0x87, 0x30, 0xc5, 0x8f, 0x98, 0x5a, 0xcf, 0x8d, 0xa0,
+	0xdc, 0x9e, 0xf0, 0x12, 0xa7, 0xb9, 0xb8, 0x75, 0x6e, 0x3b, 0x99, 0x7c, 0x09, 0x5b, 0xc5, 0xfd,
+	0xce, 0x5a, 0x1d, 0x19, 0x41, 0xe7, 0x43, 0x96, 0xdf, 0xa4, 0x18, 0x4f, 0x31, 0xda, 0x1e, 0x78,
+	0xc3, 0xee, 0x68, 0xdf, 0x12, 0x95, 0xe7, 0x3f, 0x3b, 0x1d, 0xab, 0x69, 0xf4, 0x0f, 0x0f, 0x76,
+	0x56, 0x94, 0xe4, 0x00, 0x3a, 0x82, 0x9b, 0xd0, 0x5d, 0x90, 0x35, 0x40, 0xbe, 0x00, 0x18, 0xf3,
+	0xf8, 0x74, 0xc9, 0x67, 0x45, 0x8a, 0x36, 0xde, 0x06, 0x42, 0x06, 0xd0, 0x9d, 0xe6, 0x79, 0x45,
+	0x30, 0x77, 0xd6, 0x84, 0x14, 0x43, 0xe0, 0x0c, 0xe3, 0x44, 0xdb, 0xb4, 0x39, 0x68, 0x42, 0xf4,
+	0x17, 0x08, 0x75, 0x60, 0x64, 0x17, 0xfc, 0x0f, 0x78, 0x6b, 0x9d, 0x50, 0x4b, 0x95, 0x3a, 0x93,
+	0x54, 0x73, 0xb2, 0x11, 0xaa, 0xf4, 0xfb, 0x8d, 0xf4, 0x13, 0x08, 0xca, 0xdb, 0x02, 0xad, 0x7d,
+	0xbd, 0xa6, 0xe7, 0xd0, 0x7d, 0x93, 0x25, 0x25, 0xc3, 0xf9, 0x02, 0x65, 0x49, 0x0e, 0xc0, 0x8f,
+	0x65, 0xa6, 0xcd, 0x77, 0x47, 0x60, 0x33, 0x75, 0x72, 0x79, 0xce, 0x14, 0x4c, 0x9e, 0x40, 0x28,
+	0x16, 0x29, 0xca, 0xc8, 0xd7, 0x29, 0xef, 0x36, 0x32, 0xc9, 0x8c, 0x86, 0x6e, 0x43, 0x78, 0x3a,
+	0x2b, 0xca, 0x5b, 0xfa, 0x14, 0xba, 0xa7, 0x4b, 0x9c, 0x38, 0xc3, 0xfb, 0x10, 0xce, 0x17, 0x28,
+	0x9c, 0xe7, 0x46, 0xa0, 0x7f, 0x79, 0xd0, 0x33, 0x2c, 0x59, 0xe4, 0x99, 0x44, 0x42, 0xa1, 0x97,
+	0x72, 0x59, 0xbe, 0xc9, 0x24, 0x8a, 0xf2, 0x4d, 0xac, 0xd9, 0x3e, 0x5b, 0xc1, 0xc8, 0x73, 0xd8,
+	0x6b, 0xca, 0xa7, 0x42, 0xe4, 0xc2, 0x06, 0x7f, 0x57, 0xa1, 0x2c, 0x8a, 0xfc, 0x46, 0x1e, 0x5d,
+	0x5f, 0xe3, 0xa4, 0xc4, 0x58, 0x27, 0xc4, 0x67, 0x2b, 0x98, 0xb2, 0xd8, 0x94, 0x8d, 0x45, 0x93,
+	0xa5, 0xbb, 0x0a, 0xfa, 0x15, 0x74, 0xae, 0x96, 0x2e, 0xae, 0x08, 0xb6, 0x55, 0x28, 0x09, 0xca,
+	0xc8, 0x1b, 0xf8, 0xc3, 0x0e, 0x73, 0x22, 0x7d, 0x05, 0x70, 0xb5, 0xac, 0x02, 0xfb, 0x0e, 0xb6,
+	0x05, 0xca, 0x45, 0x5a, 0x1a, 0x5e, 0x77, 0xf4, 0xd0, 0x26, 0xaf, 0x19, 0x3e, 0x73, 0x1c, 0xfa,
+	0x3d, 0xec, 0x9d, 0xd8, 0x06, 0x95, 0x95, 0x8d, 0x03, 0xe8, 0xb8, 0xae, 0x75, 0xa7, 0xd5, 0x00,
+	0x1d, 0x42, 0xef, 0x82, 0x0b, 0x89, 0x0d, 0xcf, 0xe4, 0x3c, 0xbd, 0xc2, 0xa5, 0x1b, 0x27, 0x4e,
+	0xa4, 0x17, 0x10, 0x9c, 0xe7, 0xb1, 0xae, 0x91, 0xb2, 0x56, 0xeb, 0x75, 0x55, 0x23, 0xad, 0xba,
+	0x46, 0x54, 0x79, 0x5e, 0x27, 0xd9, 0x14, 0x45, 0x21, 0x92, 0xac, 0x74, 0x05, 0xdc, 0x80, 0xe8,
+	0x08, 0x76, 0xec, 0xd9, 0xd6, 0xd5, 0x27, 0x10, 0x66, 0x79, 0x8c, 0x2e, 0x58, 0x57, 0x29, 0xea,
+	0x58, 0x66, 0x34, 0x74, 0x00, 0xbd, 0xa3, 0x45, 0x5c, 0x97, 0xde, 0x2e, 0xf8, 0x72, 0x9e, 0xba,
+	0xca, 0x96, 0xf3, 0x94, 0xfe, 0xde, 0x82, 0xae, 0xa5, 0xa8, 0xac, 0xa8, 0x88, 0x66, 0x28, 0x25,
+	0x9f, 0xba, 0x26, 0x74, 0x62, 0x3d, 0x3e, 0x5a, 0x6b, 0xe3, 0x43, 0x15, 0xe5, 0xb9, 0x1a, 0x4d,
+	0xc6, 0xe9, 0x4a, 0x5e, 0x19, 0x2d, 0xc1, 0xda, 0x68, 0x39, 0x84, 0x76, 0x91, 0x4b, 0x3d, 0xeb,
+	0xf4, 0xd8, 0xe9, 0x8e, 0x88, 0xf5, 0xff, 0xf2, 0xdd, 0xd9, 0x85, 0xd5, 0xb0, 0x8a, 0xa3, 0x06,
+	0x80, 0x5c, 0x14, 0x85, 0x40, 0x29, 0x31, 0x8e, 0xb6, 0x06, 0xde, 0xb0, 0xcd, 0x1a, 0x08, 0xf9,
+	0x1a, 0x1e, 0x38, 0x89, 0x21, 0x97, 0x79, 0xa6, 0x27, 0x51, 0x87, 0xad, 0xa1, 0x2a, 0xbe, 0xeb,
+	0x64, 0xc9, 0xc7, 0x29, 0x46, 0x6d, 0x6d, 0xc4, 0x89, 0xf4, 0x37, 0x0f, 0xba, 0x8d, 0xb3, 0x55,
+	0x25, 0xc8, 0x92, 0x8b, 0xf2, 0x2c, 0xc9, 0x4c, 0x2e, 0x42, 0x56, 0x03, 0xea, 0xbe, 0xb4, 0xf0,
+	0x3a, 0x4f, 0x17, 0xb3, 0x4c, 0xe7, 0x24, 0x64, 0x4d, 0x48, 0x9d, 0x84, 0x59, 0xac, 0x77, 0xfb,
+	0x5a, 0xeb, 0x44, 0x65, 0x19, 0xb3, 0xd8, 0xee, 0x0c, 0x8c, 0xe5, 0x0a, 0xa0, 0x7f, 0x7b, 0xb0,
+	0xe3, 0x6e, 0xc4, 0x5c, 0xf4, 0xf3, 0xf5, 0xba, 0x76, 0xa9, 0x6a, 0x5c, 0x5c, 0x55, 0xd6, 0x2a,
+	0xeb, 0xd7, 0xc9, 0x12, 0xe3, 0xcb, 0x77, 0x67, 0xf6, 0xaa, 0x2a, 0x99, 0xfc, 0x00, 0x8f, 0xb8,
+	0xed, 0x33, 0x96, 0xdf, 0xc8, 0x53, 0x59, 0x26, 0x33, 0xee, 0x3a, 0xb6, 0xcd, 0x36, 0x2b, 0xd5,
+	0x2e, 0x74, 0xc2, 0x51, 0x83, 0xa1, 0x7d, 0xf7, 0xd9, 0x66, 0x25, 0xfd, 0x06, 0xf6, 0xb4, 0x7f,
+	0xc7, 0xbc, 0x9c, 0xbc, 0x77, 0x05, 0x48, 0x20, 0x90, 0xf3, 0xd4, 0x75, 0x96, 0x5e, 0xd3, 0x13,
+	0x20, 0x4d, 0xa2, 0x0d, 0xfa, 0x70, 0x3d, 0xe8, 0xfd, 0xb5, 0xa0, 0xd7, 0xba, 0xf9, 0x19, 0x3c,
+	0xfa, 0x09, 0x33, 0x96, 0xa7, 0xe9, 0x98, 0x4f, 0x3e, 0x5c, 0xbe, 0x3b, 0xbb, 0xbf, 0xe6, 0x8f,
+	0xe1, 0xf1, 0x3a, 0xd5, 0x1e, 0x7a, 0x87, 0x4b, 0x1e, 0xc3, 0x96, 0x30, 0xf5, 0x64, 0x72, 0x69,
+	0x25, 0xfa, 0xa7, 0x07, 0x3b, 0x6f, 0xb1, 0xe4, 0xf5, 0xe4, 0xd8, 0xf4, 0x40, 0x57, 0xc3, 0xbc,
+	0x75, 0xdf, 0x30, 0xdf, 0xf8, 0x6b, 0xe0, 0xff, 0x9f, 0x5f, 0x03, 0x35, 0x75, 0x27, 0xbc, 0xe0,
+	0xe3, 0x24, 0x4d, 0x4a, 0x35, 0x1b, 0x03, 0x9d, 0xd3, 0x15, 0x6c, 0xf4, 0x29, 0x80, 0xad, 0x13,
+	0xfd, 0x33, 0x44, 0xbe, 0x85, 0x50, 0x3b, 0x4c, 0x9c, 0x5d, 0xfd, 0x86, 0xf4, 0x5d, 0x5a, 0x57,
+	0x83, 0x19, 0x42, 0xa0, 0x9e, 0x2c, 0xe2, 0x2a, 0xad, 0xf1, 0x7e, 0xf5, 0x57, 0xf6, 0x93, 0xa7,
+	0x10, 0xbe, 0x4e, 0x73, 0x89, 0x6b, 0x66, 0x57, 0x49, 0x14, 0x82, 0x8b, 0x24, 0x9b, 0xfe, 0x27,
+	0xe7, 0x05, 0x04, 0x6a, 0x4e, 0x57, 0x47, 0x36, 0x5e, 0xb6, 0xfe, 0xa6, 0x41, 0x4e, 0x9e, 0x41,
+	0xeb, 0x6a, 0x49, 0x76, 0xad, 0xaa, 0x7a, 0x2e, 0xfa, 0x7b, 0x0d, 0xc4, 0x52, 0x5f, 0x42, 0xa7,
+	0x1a, 0xf5, 0x6b, 0x4e, 0x44, 0xee, 0x01, 0xbe, 0xf3, 0x14, 0x8c, 0xf4, 0xff, 0x80, 0x44, 0xf2,
+	0xb0, 0xbe, 0x88, 0x6a, 0xf4, 0xf7, 0xf7, 0x57, 0xc1, 0x7a, 0x8f, 0xae, 0xcf, 0x6a, 0x4f, 0x73,
+	0xfc, 0xf6, 0x37, 0x96, 0x30, 0x39, 0x02, 0xa8, 0xeb, 0x9f, 0x44, 0x4d, 0x4e, 0xb3, 0x77, 0xfa,
+	0x9f, 0x6d, 0xd0, 0x58, 0x13, 0x6f, 0xe1, 0xc1, 0x6a, 0x45, 0x93, 0x03, 0x4b, 0xde, 0xd8, 0x13,
+	0xfd, 0xcf, 0xef, 0xd1, 0x1a, 0x73, 0xc7, 0xf0, 0x6b, 0xfb, 0xf0, 0xc5, 0x2b, 0x4d, 0x19, 0x6f,
+	0xe9, 0xcf, 0xcb, 0x7f, 0x07, 0x00, 0x43, 0xc2, 0x01, 0x65, 0x54, 0x0b, 0x00, 0x00,
 }
@@ -0,0 +1,77 @@
+package driver
+
+import (
+	"regexp"
+	"strings"
+)
+
+// suppressionHintRegex matches the hint which suppresses audit results in SQL comment, e.g.
+//
+//	/* sqle:ignore dml_check_with_limit,dml_check_where_is_invalid reason="..." */
+var suppressionHintRegex = regexp.MustCompile(
+	`/\*\s*sqle:ignore\s+([\w\-]+(?:\s*,\s*[\w\-]+)*)(?:\s+reason\s*=\s*"([^"]*)")?\s*\*/`)
+
+// Suppression is a hint in SQL comment which suppresses the results produced by the rules.
+type Suppression struct {
+	RuleNames []string
+	Reason    string
+}
+
+// ParseSuppressions returns the suppression hints in sql.
+func ParseSuppressions(sql string) []*Suppression {
+	if !strings.Contains(sql, "sqle:ignore") {
+		return nil
+	}
+	var suppressions []*Suppression
+	for _, match := range suppressionHintRegex.FindAllStringSubmatch(sql, -1) {
+		s := &Suppression{Reason: match[2]}
+		for _, name := range strings.Split(match[1], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				s.RuleNames = append(s.RuleNames, name)
+			}
+		}
+		suppressions = append(suppressions, s)
+	}
+	return suppressions
+}
+
+// ApplySuppressions marks the results which are suppressed by the hints in sql. The suppressed
+// results are kept in result, but they are not counted by Level.
+func (rs *AuditResult) ApplySuppressions(sql string) {
+	suppressions := ParseSuppressions(sql)
+	if len(suppressions) == 0 {
+		return
+	}
+	for _, item := range rs.results {
+		if item.RuleName == "" || item.Suppressed {
+			continue
+		}
+		for _, s := range suppressions {
+			if containsString(s.RuleNames, item.RuleName) {
+				item.Suppressed = true
+				item.SuppressReason = s.Reason
+				break
+			}
+		}
+	}
+}
+
+// HasSuppressed returns true if any result is suppressed.
+func (rs *AuditResult) HasSuppressed() bool {
+	for _, item := range rs.results {
+		if item.Suppressed {
+			return true
+		}
+	}
+	return false
+}
+
+// RevokeSuppressions revokes the suppression of the results which are not allowed to be suppressed.
+func (rs *AuditResult) RevokeSuppressions(allowed func(item *AuditResultItem) bool) {
+	for _, item := range rs.results {
+		if item.Suppressed && !allowed(item) {
+			item.Suppressed = false
+			item.SuppressReason = ""
+		}
+	}
+}
@@ -21,6 +21,9 @@ type AuditPlan struct {
 	InstanceDatabase string        `json:"instance_database"`
 	Type             string        `json:"type"`
 	Params           params.Params `json:"params" gorm:"type:varchar(1000)"`
+	// SchemaBaselineName is the schema baseline which the audit plan without instance is
+	// audited against.
+	SchemaBaselineName string `json:"schema_baseline_name"`
 
 	NotifyInterval      int    `json:"notify_interval" gorm:"default:10"`
 	NotifyLevel         string `json:"notify_level" gorm:"default:'warn'"`
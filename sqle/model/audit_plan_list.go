@@ -14,11 +14,13 @@ type AuditPlanListDetail struct {
 	InstanceDatabase string         `json:"instance_database"`
 	Type             sql.NullString `json:"type"`
 	Params           params.Params  `json:"params"`
+	SchemaBaseline   sql.NullString `json:"schema_baseline_name"`
 }
 
 var auditPlanQueryTpl = `
 SELECT audit_plans.name, audit_plans.cron_expression, audit_plans.db_type, audit_plans.token,
-audit_plans.instance_name, audit_plans.instance_database, audit_plans.type, audit_plans.params
+audit_plans.instance_name, audit_plans.instance_database, audit_plans.type, audit_plans.params,
+audit_plans.schema_baseline_name
 
 {{- template "body" . -}} 
 
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+// CustomRule is the audit rule defined by user. A rule with the same name is saved in
+// rules table, so that the custom rule can be added into rule template like a built-in rule.
+type CustomRule struct {
+	Model
+	RuleName  string  `json:"rule_name" gorm:"not null;unique_index"`
+	DBType    string  `json:"db_type" gorm:"not null"`
+	Desc      string  `json:"desc"`
+	Level     string  `json:"level"`
+	Typ       string  `json:"type" gorm:"column:type"`
+	StmtTypes RowList `json:"stmt_types" gorm:"type:varchar(255)"`
+	MatchType string  `json:"match_type" gorm:"not null"`
+	Matcher   string  `json:"matcher" gorm:"type:text"`
+}
+
+func (r *CustomRule) Rule() *Rule {
+	return &Rule{
+		Name:   r.RuleName,
+		DBType: r.DBType,
+		Desc:   r.Desc,
+		Level:  r.Level,
+		Typ:    r.Typ,
+	}
+}
+
+func (r *CustomRule) DriverMatcher() *driver.CustomRuleMatcher {
+	return &driver.CustomRuleMatcher{
+		MatchType: r.MatchType,
+		Matcher:   r.Matcher,
+		StmtTypes: r.StmtTypes,
+	}
+}
+
+func (s *Storage) GetCustomRuleByName(name string) (*CustomRule, bool, error) {
+	rule := &CustomRule{}
+	err := s.db.Where("rule_name = ?", name).First(rule).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return rule, true, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetCustomRules(dbType string) ([]*CustomRule, error) {
+	rules := []*CustomRule{}
+	db := s.db.Order("rule_name ASC")
+	if dbType != "" {
+		db = db.Where("db_type = ?", dbType)
+	}
+	err := db.Find(&rules).Error
+	return rules, errors.New(errors.ConnectStorageError, err)
+}
+
+// SaveCustomRule saves the custom rule and its rule in rules table.
+func (s *Storage) SaveCustomRule(rule *CustomRule) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		if err := txDB.Save(rule).Error; err != nil {
+			return err
+		}
+		return txDB.Save(rule.Rule()).Error
+	})
+}
+
+// DeleteCustomRule deletes the custom rule, its rule in rules table and the rule in rule templates.
+func (s *Storage) DeleteCustomRule(rule *CustomRule) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		if err := txDB.Unscoped().Delete(rule).Error; err != nil {
+			return err
+		}
+		if err := txDB.Exec("DELETE FROM rule_template_rule WHERE rule_name = ? AND rule_template_id IN "+
+			"(SELECT id FROM rule_templates WHERE db_type = ?)", rule.RuleName, rule.DBType).Error; err != nil {
+			return err
+		}
+		return txDB.Delete(rule.Rule()).Error
+	})
+}
+
+// FillCustomRuleMatcher sets the matcher params of the custom rules in rules, so that the
+// matcher is passed to driver. The params of custom rule can not be overridden by rule template.
+func (s *Storage) FillCustomRuleMatcher(dbType string, rules []*driver.Rule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	customRules, err := s.GetCustomRules(dbType)
+	if err != nil {
+		return err
+	}
+	if len(customRules) == 0 {
+		return nil
+	}
+	customRuleMap := make(map[string]*CustomRule, len(customRules))
+	for _, r := range customRules {
+		customRuleMap[r.RuleName] = r
+	}
+	for _, r := range rules {
+		if cr, ok := customRuleMap[r.Name]; ok {
+			r.Params = driver.NewCustomRuleParams(cr.DriverMatcher())
+		}
+	}
+	return nil
+}
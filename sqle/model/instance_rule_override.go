@@ -0,0 +1,92 @@
+package model
+
+import (
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	RuleValueOriginRuleTemplate = "rule_template"
+	RuleValueOriginInstance     = "instance"
+)
+
+// InstanceRuleOverride overrides the level and params of a rule in the rule template
+// of instance, so the instance needn't clone the whole rule template for a different
+// threshold. The empty level and the params not in RuleParams are inherited from the
+// rule template.
+type InstanceRuleOverride struct {
+	InstanceId uint          `json:"instance_id" gorm:"primary_key;auto_increment:false;"`
+	RuleName   string        `json:"name" gorm:"primary_key;"`
+	RuleLevel  string        `json:"level" gorm:"column:level;"`
+	RuleParams params.Params `json:"params" gorm:"column:rule_params;type:varchar(1000)"`
+}
+
+func (o InstanceRuleOverride) TableName() string {
+	return "instance_rule_overrides"
+}
+
+// IsParamOverridden reports whether the param of rule is overridden by instance.
+func (o *InstanceRuleOverride) IsParamOverridden(key string) bool {
+	return o.RuleParams.GetParam(key) != nil
+}
+
+// Apply returns a copy of rule which level and params are overridden.
+func (o *InstanceRuleOverride) Apply(rule *Rule) (*Rule, error) {
+	r := *rule
+	if o.RuleLevel != "" {
+		r.Level = o.RuleLevel
+	}
+	r.Params = rule.Params.Copy()
+	for _, p := range o.RuleParams {
+		if err := r.Params.SetParamValue(p.Key, p.Value); err != nil {
+			return nil, err
+		}
+	}
+	return &r, nil
+}
+
+// ApplyInstanceRuleOverrides overrides the rules of rule template by the overrides of
+// instance, the override which rule is not in the rule template is ignored.
+func ApplyInstanceRuleOverrides(rules []*Rule, overrides []*InstanceRuleOverride) ([]*Rule, error) {
+	overrideMap := make(map[string]*InstanceRuleOverride, len(overrides))
+	for _, o := range overrides {
+		overrideMap[o.RuleName] = o
+	}
+	ret := make([]*Rule, 0, len(rules))
+	for _, rule := range rules {
+		o, ok := overrideMap[rule.Name]
+		if !ok {
+			ret = append(ret, rule)
+			continue
+		}
+		r, err := o.Apply(rule)
+		if err != nil {
+			return nil, errors.New(errors.DataInvalid, err)
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
+
+func (s *Storage) GetInstanceRuleOverrides(instanceId uint) ([]*InstanceRuleOverride, error) {
+	overrides := []*InstanceRuleOverride{}
+	err := s.db.Where("instance_id = ?", instanceId).Order("rule_name ASC").Find(&overrides).Error
+	return overrides, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) UpdateInstanceRuleOverrides(instance *Instance, overrides ...*InstanceRuleOverride) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		if err := txDB.Where("instance_id = ?", instance.ID).Delete(&InstanceRuleOverride{}).Error; err != nil {
+			return err
+		}
+		for _, o := range overrides {
+			o.InstanceId = instance.ID
+			if err := txDB.Save(o).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyInstanceRuleOverrides(t *testing.T) {
+	newRules := func() []*Rule {
+		return []*Rule{
+			{Name: "rule_1", Level: "error", Params: params.Params{
+				{Key: "max_length", Value: "64", Type: params.ParamTypeInt},
+				{Key: "min_length", Value: "1", Type: params.ParamTypeInt},
+			}},
+			{Name: "rule_2", Level: "warn"},
+		}
+	}
+	rules := newRules()
+
+	overridden, err := ApplyInstanceRuleOverrides(rules, []*InstanceRuleOverride{
+		{RuleName: "rule_1", RuleParams: params.Params{{Key: "max_length", Value: "32"}}},
+		{RuleName: "rule_2", RuleLevel: "notice"},
+		{RuleName: "rule_not_in_template", RuleLevel: "error"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, overridden, 2)
+	assert.Equal(t, "error", overridden[0].Level)
+	assert.Equal(t, "32", overridden[0].Params.GetParam("max_length").Value)
+	assert.Equal(t, "1", overridden[0].Params.GetParam("min_length").Value)
+	assert.Equal(t, "notice", overridden[1].Level)
+
+	// the rules of rule template are not changed.
+	assert.Equal(t, newRules(), rules)
+
+	_, err = ApplyInstanceRuleOverrides(rules, []*InstanceRuleOverride{
+		{RuleName: "rule_1", RuleParams: params.Params{{Key: "max_length", Value: "a"}}},
+	})
+	assert.Error(t, err)
+}
@@ -17,6 +17,27 @@ type RuleTemplate struct {
 	DBType    string             `json:"db_type"`
 	Instances []Instance         `json:"instance_list" gorm:"many2many:instance_rule_template"`
 	RuleList  []RuleTemplateRule `json:"rule_list" gorm:"foreignkey:rule_template_id;association_foreignkey:id"`
+
+	// AllowSuppression controls whether the audit results can be suppressed by the hint in SQL comment.
+	AllowSuppression bool `json:"allow_suppression" gorm:"not null;default:false"`
+	// SuppressibleLevels are the levels of the results which can be suppressed, empty means all levels.
+	SuppressibleLevels RowList `json:"suppressible_levels" gorm:"type:varchar(255)"`
+}
+
+// CanSuppress returns true if the audit result of level can be suppressed by the hint in SQL comment.
+func (t *RuleTemplate) CanSuppress(level string) bool {
+	if !t.AllowSuppression {
+		return false
+	}
+	if len(t.SuppressibleLevels) == 0 {
+		return true
+	}
+	for _, l := range t.SuppressibleLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
 }
 
 func GenerateRuleByDriverRule(dr *driver.Rule, dbType string) *Rule {
@@ -27,6 +48,12 @@ func GenerateRuleByDriverRule(dr *driver.Rule, dbType string) *Rule {
 		Typ:    dr.Category,
 		DBType: dbType,
 		Params: dr.Params,
+		RuleKnowledge: RuleKnowledge{
+			Rationale:   dr.Knowledge.Rationale,
+			BadExample:  dr.Knowledge.BadExample,
+			GoodExample: dr.Knowledge.GoodExample,
+			Remediation: dr.Knowledge.Remediation,
+		},
 	}
 }
 
@@ -37,6 +64,12 @@ func ConvertRuleToDriverRule(r *Rule) *driver.Rule {
 		Category: r.Typ,
 		Level:    driver.RuleLevel(r.Level),
 		Params:   r.Params,
+		Knowledge: driver.RuleKnowledge{
+			Rationale:   r.Rationale,
+			BadExample:  r.BadExample,
+			GoodExample: r.GoodExample,
+			Remediation: r.Remediation,
+		},
 	}
 }
 
@@ -47,6 +80,15 @@ type Rule struct {
 	Level  string        `json:"level" example:"error"` // notice, warn, error
 	Typ    string        `json:"type" gorm:"column:type; not null"`
 	Params params.Params `json:"params" gorm:"type:varchar(1000)"`
+	RuleKnowledge
+}
+
+// RuleKnowledge is the knowledge base of rule, see driver.RuleKnowledge.
+type RuleKnowledge struct {
+	Rationale   string `json:"rationale" gorm:"type:text"`
+	BadExample  string `json:"bad_example" gorm:"type:text"`
+	GoodExample string `json:"good_example" gorm:"type:text"`
+	Remediation string `json:"remediation" gorm:"type:text"`
 }
 
 func (r Rule) TableName() string {
@@ -108,6 +150,8 @@ func (s *Storage) GetRulesFromRuleTemplateByName(name string) ([]*Rule, error) {
 	return rules, nil
 }
 
+// GetRulesByInstanceId returns the rules of the rule template of instance, which are
+// overridden by the rule overrides of instance.
 func (s *Storage) GetRulesByInstanceId(instanceId string) ([]*Rule, error) {
 	instance, _, err := s.GetInstanceById(instanceId)
 	if err != nil {
@@ -118,7 +162,31 @@ func (s *Storage) GetRulesByInstanceId(instanceId string) ([]*Rule, error) {
 		return nil, nil
 	}
 	tplName := templates[0].Name
-	return s.GetRulesFromRuleTemplateByName(tplName)
+	rules, err := s.GetRulesFromRuleTemplateByName(tplName)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := s.GetInstanceRuleOverrides(instance.ID)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyInstanceRuleOverrides(rules, overrides)
+}
+
+// GetAuditRuleTemplate returns the rule template which is used to audit the SQL of instance,
+// the default rule template of dbType is returned if instance is nil.
+func (s *Storage) GetAuditRuleTemplate(inst *Instance, dbType string) (*RuleTemplate, bool, error) {
+	if inst == nil {
+		return s.GetRuleTemplateByName(s.GetDefaultRuleTemplateName(dbType))
+	}
+	templates, err := s.GetRuleTemplatesByInstance(inst)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(templates) == 0 {
+		return nil, false, nil
+	}
+	return &templates[0], true, nil
 }
 
 func (s *Storage) GetRuleTemplateByName(name string) (*RuleTemplate, bool, error) {
@@ -190,6 +258,17 @@ func (s *Storage) GetRule(name, dbType string) (*Rule, bool, error) {
 	return &rule, true, errors.New(errors.ConnectStorageError, err)
 }
 
+func (s *Storage) UpdateRuleKnowledge(rule *Rule, knowledge RuleKnowledge) error {
+	err := s.db.Model(&Rule{}).Where("name = ? AND db_type = ?", rule.Name, rule.DBType).
+		Updates(map[string]interface{}{
+			"rationale":    knowledge.Rationale,
+			"bad_example":  knowledge.BadExample,
+			"good_example": knowledge.GoodExample,
+			"remediation":  knowledge.Remediation,
+		}).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) GetAllRule() ([]*Rule, error) {
 	rules := []*Rule{}
 	err := s.db.Find(&rules).Error
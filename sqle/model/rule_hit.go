@@ -0,0 +1,174 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/errors"
+)
+
+const (
+	RuleHitSourceTask      = "task"
+	RuleHitSourceAuditPlan = "audit_plan"
+
+	// RuleHitWhitelist is the rule name of the hits which SQL matches the whitelist.
+	RuleHitWhitelist = "sql_whitelist"
+)
+
+// RuleHit records the number of times that a rule is triggered at a level in one audit
+// of task or audit plan, it is used to analyze the effectiveness of rules.
+type RuleHit struct {
+	Model
+	RuleName         string `json:"rule_name" gorm:"index;not null"`
+	DBType           string `json:"db_type"`
+	InstanceName     string `json:"instance_name"`
+	RuleTemplateId   uint   `json:"rule_template_id"`
+	RuleTemplateName string `json:"rule_template_name"`
+	Level            string `json:"level"`
+	// Source is the source of audit, it is "task" or "audit_plan", and SourceId is the
+	// id of task or audit plan report.
+	Source   string `json:"source"`
+	SourceId uint   `json:"source_id"`
+	// HitCount is the number of SQLs which the rule is triggered on, SuppressedCount is
+	// the number of them which the result is suppressed, and BlockedCount is the number
+	// of them which the result blocks the task from being submitted as workflow.
+	HitCount        uint `json:"hit_count"`
+	SuppressedCount uint `json:"suppressed_count"`
+	BlockedCount    uint `json:"blocked_count"`
+}
+
+// NewRuleHits counts the rule hits from the audit results of task SQLs, the SQLs match
+// the whitelist are counted as the hits of RuleHitWhitelist, and the other results without
+// rule are ignored. The results which level is higher than blockLevel block the workflow,
+// blockLevel is empty if the task is not submitted as workflow, e.g. the audit plan.
+func NewRuleHits(task *Task, source string, sourceId uint, blockLevel string) []*RuleHit {
+	type hitKey struct {
+		ruleName string
+		level    string
+	}
+	hits := map[hitKey]*RuleHit{}
+	for _, executeSQL := range task.ExecuteSQLs {
+		for _, result := range executeSQL.AuditResults {
+			ruleName := result.RuleName
+			if ruleName == "" {
+				if result.Message != SQLWhitelistAuditResult {
+					continue
+				}
+				ruleName = RuleHitWhitelist
+			}
+			key := hitKey{ruleName: ruleName, level: result.Level}
+			hit, ok := hits[key]
+			if !ok {
+				hit = &RuleHit{
+					RuleName:         ruleName,
+					DBType:           task.DBType,
+					InstanceName:     task.InstanceName(),
+					RuleTemplateId:   task.RuleTemplateId,
+					RuleTemplateName: task.RuleTemplateName,
+					Level:            result.Level,
+					Source:           source,
+					SourceId:         sourceId,
+				}
+				hits[key] = hit
+			}
+			hit.HitCount++
+			if result.Suppressed {
+				hit.SuppressedCount++
+			} else if blockLevel != "" && driver.RuleLevel(result.Level).More(driver.RuleLevel(blockLevel)) {
+				hit.BlockedCount++
+			}
+		}
+	}
+
+	ruleHits := make([]*RuleHit, 0, len(hits))
+	for _, hit := range hits {
+		ruleHits = append(ruleHits, hit)
+	}
+	sort.Slice(ruleHits, func(i, j int) bool {
+		if ruleHits[i].RuleName != ruleHits[j].RuleName {
+			return ruleHits[i].RuleName < ruleHits[j].RuleName
+		}
+		return ruleHits[i].Level < ruleHits[j].Level
+	})
+	return ruleHits
+}
+
+func (s *Storage) SaveRuleHits(hits []*RuleHit) error {
+	tx := s.db.Begin()
+	for _, hit := range hits {
+		if err := tx.Save(hit).Error; err != nil {
+			tx.Rollback()
+			return errors.New(errors.ConnectStorageError, err)
+		}
+	}
+	return errors.New(errors.ConnectStorageError, tx.Commit().Error)
+}
+
+type RuleHitStatistics struct {
+	RuleName         string `json:"rule_name"`
+	DBType           string `json:"db_type"`
+	InstanceName     string `json:"instance_name"`
+	RuleTemplateName string `json:"rule_template_name"`
+	Level            string `json:"level"`
+	HitCount         uint64 `json:"hit_count"`
+	SuppressedCount  uint64 `json:"suppressed_count"`
+	BlockedCount     uint64 `json:"blocked_count"`
+	AuditCount       uint64 `json:"audit_count"`
+}
+
+var ruleHitStatisticsQueryTpl = `
+SELECT rule_hits.rule_name, rule_hits.db_type, rule_hits.instance_name, rule_hits.rule_template_name, rule_hits.level,
+SUM(rule_hits.hit_count) AS hit_count, SUM(rule_hits.suppressed_count) AS suppressed_count,
+SUM(rule_hits.blocked_count) AS blocked_count,
+COUNT(DISTINCT rule_hits.source, rule_hits.source_id) AS audit_count
+
+{{- template "body" . -}}
+
+ORDER BY hit_count DESC, rule_hits.rule_name ASC
+`
+
+var ruleHitStatisticsBodyTpl = `
+{{ define "body" }}
+
+FROM rule_hits
+
+WHERE rule_hits.deleted_at IS NULL
+
+{{- if .filter_create_time_from }}
+AND rule_hits.created_at > :filter_create_time_from
+{{- end }}
+
+{{- if .filter_create_time_to }}
+AND rule_hits.created_at < :filter_create_time_to
+{{- end }}
+
+{{- if .filter_db_type }}
+AND rule_hits.db_type = :filter_db_type
+{{- end }}
+
+{{- if .filter_instance_name }}
+AND rule_hits.instance_name = :filter_instance_name
+{{- end }}
+
+{{- if .filter_rule_template_name }}
+AND rule_hits.rule_template_name = :filter_rule_template_name
+{{- end }}
+
+{{- if .filter_level }}
+AND rule_hits.level = :filter_level
+{{- end }}
+
+{{- if .filter_source }}
+AND rule_hits.source = :filter_source
+{{- end }}
+
+GROUP BY rule_hits.rule_name, rule_hits.db_type, rule_hits.instance_name, rule_hits.rule_template_name, rule_hits.level
+
+{{ end }}
+`
+
+// GetRuleHitStatisticsByReq sums the rule hits by rule, instance, rule template and level.
+func (s *Storage) GetRuleHitStatisticsByReq(data map[string]interface{}) (list []*RuleHitStatistics, err error) {
+	err = s.getListResult(ruleHitStatisticsBodyTpl, ruleHitStatisticsQueryTpl, data, &list)
+	return list, errors.New(errors.ConnectStorageError, err)
+}
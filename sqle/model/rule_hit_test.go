@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRuleHits(t *testing.T) {
+	task := &Task{
+		DBType:           "mysql",
+		Instance:         &Instance{Name: "inst_1"},
+		RuleTemplateId:   1,
+		RuleTemplateName: "tpl_1",
+		ExecuteSQLs: []*ExecuteSQL{
+			{AuditResults: AuditResults{
+				{Level: "error", RuleName: "rule_1"},
+				{Level: "warn", RuleName: "rule_2"},
+				{Level: "notice", Message: "[osc]pt-online-schema-change"},
+			}},
+			{AuditResults: AuditResults{
+				{Level: "error", RuleName: "rule_1", Suppressed: true},
+				{Level: "normal", Message: "白名单"},
+			}},
+		},
+	}
+	hits := NewRuleHits(task, RuleHitSourceTask, 1, "warn")
+	assert.Equal(t, []*RuleHit{
+		{RuleName: "rule_1", DBType: "mysql", InstanceName: "inst_1", RuleTemplateId: 1, RuleTemplateName: "tpl_1",
+			Level: "error", Source: RuleHitSourceTask, SourceId: 1, HitCount: 2, SuppressedCount: 1, BlockedCount: 1},
+		{RuleName: "rule_2", DBType: "mysql", InstanceName: "inst_1", RuleTemplateId: 1, RuleTemplateName: "tpl_1",
+			Level: "warn", Source: RuleHitSourceTask, SourceId: 1, HitCount: 1},
+		{RuleName: RuleHitWhitelist, DBType: "mysql", InstanceName: "inst_1", RuleTemplateId: 1, RuleTemplateName: "tpl_1",
+			Level: "normal", Source: RuleHitSourceTask, SourceId: 1, HitCount: 1},
+	}, hits)
+
+	// the audit plan does not block workflow.
+	hits = NewRuleHits(task, RuleHitSourceAuditPlan, 1, "")
+	assert.Equal(t, uint(0), hits[0].BlockedCount)
+}
+
+func TestStorage_GetRuleHitStatisticsByReq(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+
+	mock.ExpectPrepare(`(?s)FROM rule_hits.*AND rule_hits.created_at > \?.*AND rule_hits.instance_name = \?.*GROUP BY`).
+		ExpectQuery().WithArgs("2022-01-01", "inst_1").
+		WillReturnRows(sqlmock.NewRows([]string{"rule_name", "db_type", "instance_name", "rule_template_name",
+			"level", "hit_count", "suppressed_count", "blocked_count", "audit_count"}).
+			AddRow("rule_1", "mysql", "inst_1", "tpl_1", "error", 3, 1, 1, 2))
+	list, err := GetStorage().GetRuleHitStatisticsByReq(map[string]interface{}{
+		"filter_create_time_from": "2022-01-01",
+		"filter_instance_name":    "inst_1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*RuleHitStatistics{{
+		RuleName: "rule_1", DBType: "mysql", InstanceName: "inst_1", RuleTemplateName: "tpl_1",
+		Level: "error", HitCount: 3, SuppressedCount: 1, BlockedCount: 1, AuditCount: 2,
+	}}, list)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
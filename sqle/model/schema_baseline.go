@@ -0,0 +1,60 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+// SchemaBaseline is an uploaded schema dump, the offline audit of task and audit plan
+// which is attached to it is against the tables in dump instead of an empty database.
+type SchemaBaseline struct {
+	Model
+	Name          string `json:"name" gorm:"not null;index"`
+	DBType        string `json:"db_type" gorm:"not null"`
+	DefaultSchema string `json:"default_schema"`
+	Desc          string `json:"desc"`
+	Content       string `json:"content" gorm:"type:longtext"`
+}
+
+// NewSchemaBaselineContent joins the CREATE statements to the content of schema baseline.
+func NewSchemaBaselineContent(sqls []string) string {
+	stmts := make([]string, 0, len(sqls))
+	for _, sql := range sqls {
+		sql = strings.TrimRight(strings.TrimSpace(sql), ";")
+		if sql != "" {
+			stmts = append(stmts, sql+";")
+		}
+	}
+	return strings.Join(stmts, "\n")
+}
+
+func (s *Storage) GetSchemaBaselineByName(name string) (*SchemaBaseline, bool, error) {
+	b := &SchemaBaseline{}
+	err := s.db.Where("name = ?", name).First(b).Error
+	if err == gorm.ErrRecordNotFound {
+		return b, false, nil
+	}
+	return b, true, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetAuditPlanNamesBySchemaBaseline returns the names of audit plans which are attached to
+// the schema baseline.
+func (s *Storage) GetAuditPlanNamesBySchemaBaseline(name string) ([]string, error) {
+	names := []string{}
+	err := s.db.Model(&AuditPlan{}).Where("schema_baseline_name = ?", name).Pluck("name", &names).Error
+	return names, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetSchemaBaselineTips returns the schema baselines without content.
+func (s *Storage) GetSchemaBaselineTips(dbType string) ([]*SchemaBaseline, error) {
+	baselines := []*SchemaBaseline{}
+	db := s.db.Select("id, created_at, name, db_type, default_schema, `desc`")
+	if dbType != "" {
+		db = db.Where("db_type = ?", dbType)
+	}
+	err := db.Order("name ASC").Find(&baselines).Error
+	return baselines, errors.New(errors.ConnectStorageError, err)
+}
@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+
+	"github.com/jinzhu/gorm"
 )
 
 type SqlQueryConfig struct {
@@ -58,3 +62,32 @@ type SqlQueryExecutionSql struct {
 	ExecEndAt         *time.Time `json:"exec_end_at"`
 	ExecResult        string     `json:"exec_result" gorm:"type:text"`
 }
+
+func (s *Storage) GetSqlQueryExecutionSqlById(id string) (*SqlQueryExecutionSql, bool, error) {
+	executionSql := &SqlQueryExecutionSql{}
+	err := s.db.Where("id = ?", id).First(executionSql).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return executionSql, true, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetSqlQueryHistoryById(id uint) (*SqlQueryHistory, bool, error) {
+	history := &SqlQueryHistory{}
+	err := s.db.Where("id = ?", id).First(history).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return history, true, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetSqlQueryHistories returns the SQL query histories of user on instance, the latest first.
+func (s *Storage) GetSqlQueryHistories(userId, instanceId uint, fuzzySearch string, pageIndex, pageSize uint32) ([]*SqlQueryHistory, error) {
+	histories := []*SqlQueryHistory{}
+	query := s.db.Where("create_user_id = ? AND instance_id = ?", userId, instanceId)
+	if fuzzySearch != "" {
+		query = query.Where("raw_sql LIKE ?", fmt.Sprintf("%%%s%%", fuzzySearch))
+	}
+	err := query.Offset((pageIndex - 1) * pageSize).Limit(pageSize).Order("id desc").Find(&histories).Error
+	return histories, errors.New(errors.ConnectStorageError, err)
+}
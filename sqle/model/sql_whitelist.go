@@ -11,6 +11,9 @@ import (
 const (
 	SQLWhitelistExactMatch = "exact_match"
 	SQLWhitelistFPMatch    = "fp_match"
+
+	// SQLWhitelistAuditResult is the audit result of the SQL which matches the whitelist.
+	SQLWhitelistAuditResult = "白名单"
 )
 
 type SqlWhitelist struct {
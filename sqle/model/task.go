@@ -3,21 +3,27 @@ package model
 import (
 	"bytes"
 	"database/sql"
+	sqlDriver "database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/errors"
 
 	"github.com/jinzhu/gorm"
 )
 
 const (
-	TaskStatusInit             = "initialized"
-	TaskStatusAudited          = "audited"
-	TaskStatusExecuting        = "executing"
-	TaskStatusExecuteSucceeded = "exec_succeeded"
-	TaskStatusExecuteFailed    = "exec_failed"
+	TaskStatusInit              = "initialized"
+	TaskStatusAudited           = "audited"
+	TaskStatusExecuting         = "executing"
+	TaskStatusExecuteSucceeded  = "exec_succeeded"
+	TaskStatusExecuteFailed     = "exec_failed"
+	TaskStatusRollingBack       = "rolling_back"
+	TaskStatusRollbackSucceeded = "rollback_succeeded"
+	TaskStatusRollbackFailed    = "rollback_failed"
 )
 
 const (
@@ -42,11 +48,21 @@ type Task struct {
 	CreateUserId uint
 	ExecStartAt  *time.Time
 	ExecEndAt    *time.Time
-
-	CreateUser   *User          `gorm:"foreignkey:CreateUserId"`
-	Instance     *Instance      `json:"-" gorm:"foreignkey:InstanceId"`
-	ExecuteSQLs  []*ExecuteSQL  `json:"-" gorm:"foreignkey:TaskId"`
-	RollbackSQLs []*RollbackSQL `json:"-" gorm:"foreignkey:TaskId"`
+	// SourceTaskId is the task which this task is revised from, it is 0 if the task is not a revision.
+	SourceTaskId uint `json:"source_task_id"`
+	// SchemaBaselineId is the schema baseline which the task is audited against when the
+	// task has no instance.
+	SchemaBaselineId uint `json:"schema_baseline_id"`
+	// RuleTemplateId and RuleTemplateName are the rule template which the task is audited
+	// by, they are kept as of the audit even if the rule template of instance is changed.
+	RuleTemplateId   uint   `json:"rule_template_id"`
+	RuleTemplateName string `json:"rule_template_name"`
+
+	CreateUser     *User           `gorm:"foreignkey:CreateUserId"`
+	Instance       *Instance       `json:"-" gorm:"foreignkey:InstanceId"`
+	SchemaBaseline *SchemaBaseline `json:"-" gorm:"foreignkey:SchemaBaselineId"`
+	ExecuteSQLs    []*ExecuteSQL   `json:"-" gorm:"foreignkey:TaskId"`
+	RollbackSQLs   []*RollbackSQL  `json:"-" gorm:"foreignkey:TaskId"`
 }
 
 func (t *Task) InstanceName() string {
@@ -69,6 +85,12 @@ const (
 	SQLExecuteStatusSucceeded   = "succeeded"
 )
 
+const (
+	SQLDryRunStatusSucceeded = "succeeded"
+	SQLDryRunStatusFailed    = "failed"
+	SQLDryRunStatusSkipped   = "skipped"
+)
+
 type BaseSQL struct {
 	Model
 	TaskId uint `json:"-" gorm:"index"`
@@ -108,11 +130,23 @@ type ExecuteSQL struct {
 	BaseSQL
 	AuditStatus string `json:"audit_status" gorm:"default:\"initialized\""`
 	AuditResult string `json:"audit_result" gorm:"type:text"`
+	// AuditResults is the structured AuditResult, it keeps the rule and the
+	// position of each result.
+	AuditResults AuditResults `json:"audit_results" gorm:"type:json"`
 	// AuditFingerprint generate from SQL and SQL audit result use MD5 hash algorithm,
 	// it used for deduplication in one audit task.
 	AuditFingerprint string `json:"audit_fingerprint" gorm:"index;type:char(32)"`
 	// AuditLevel has four level: error, warn, notice, normal.
 	AuditLevel string `json:"audit_level"`
+	// FixedSQL is the SQL suggested by the rules to fix the problems in audit results.
+	FixedSQL string `json:"fixed_sql" gorm:"type:longtext"`
+	// EstimatedAffectedRows is the rows affected by the DML estimated in audit,
+	// it is null if the rows is not estimated.
+	EstimatedAffectedRows sql.NullInt64 `json:"estimated_affected_rows"`
+	// DryRunStatus and DryRunResult are the outcome of the SQL executed against the shadow
+	// tables in dry run, DryRunStatus is empty if the SQL has not been dry run.
+	DryRunStatus string `json:"dry_run_status"`
+	DryRunResult string `json:"dry_run_result" gorm:"type:text"`
 }
 
 func (s ExecuteSQL) TableName() string {
@@ -139,15 +173,113 @@ func (s *ExecuteSQL) GetAuditResultDesc() string {
 	return s.AuditResult
 }
 
+type AuditResults []*AuditResult
+
+type AuditResult struct {
+	Level    string       `json:"level"`
+	Message  string       `json:"message"`
+	RuleName string       `json:"rule_name"`
+	Category string       `json:"category"`
+	Position *SQLPosition `json:"position,omitempty"`
+
+	Suppressed     bool   `json:"suppressed,omitempty"`
+	SuppressReason string `json:"suppress_reason,omitempty"`
+
+	Fixable bool `json:"fixable,omitempty"`
+}
+
+type SQLPosition struct {
+	StartLine   int `json:"start_line"`
+	StartColumn int `json:"start_column"`
+	EndLine     int `json:"end_line"`
+	EndColumn   int `json:"end_column"`
+}
+
+func GenerateAuditResultsByDriverResult(result *driver.AuditResult) AuditResults {
+	items := result.Items()
+	results := make(AuditResults, 0, len(items))
+	for _, item := range items {
+		r := &AuditResult{
+			Level:    string(item.Level),
+			Message:  item.Message,
+			RuleName: item.RuleName,
+			Category: item.Category,
+
+			Suppressed:     item.Suppressed,
+			SuppressReason: item.SuppressReason,
+			Fixable:        item.Fixable,
+		}
+		if item.Position != nil {
+			r.Position = &SQLPosition{
+				StartLine:   item.Position.StartLine,
+				StartColumn: item.Position.StartColumn,
+				EndLine:     item.Position.EndLine,
+				EndColumn:   item.Position.EndColumn,
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// Scan impl sql.Scanner interface
+func (r *AuditResults) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal json value: %v", value)
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	result := AuditResults{}
+	err := json.Unmarshal(bytes, &result)
+	*r = result
+	return err
+}
+
+// Value impl sql.driver.Valuer interface
+func (r AuditResults) Value() (sqlDriver.Value, error) {
+	if len(r) == 0 {
+		return nil, nil
+	}
+	v, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json value: %v", v)
+	}
+	return string(v), err
+}
+
 type RollbackSQL struct {
 	BaseSQL
 	ExecuteSQLId uint `gorm:"index;column:execute_sql_id"`
+
+	Statements []*RollbackSQLStatement `json:"-" gorm:"foreignkey:RollbackSQLId"`
 }
 
 func (s RollbackSQL) TableName() string {
 	return "rollback_sql_detail"
 }
 
+// RollbackSQLStatement is a single statement split from the content of RollbackSQL,
+// it records the outcome of the statement when the rollback SQL is executed.
+type RollbackSQLStatement struct {
+	Model
+	RollbackSQLId uint   `gorm:"index;column:rollback_sql_id"`
+	Number        uint   `json:"number"`
+	Content       string `json:"sql" gorm:"type:longtext"`
+	RowAffects    int64  `json:"row_affects"`
+	ExecStatus    string `json:"exec_status" gorm:"default:\"initialized\""`
+	ExecResult    string `json:"exec_result" gorm:"type:text"`
+}
+
 func (t *Task) HasDoingAudit() bool {
 	if t.ExecuteSQLs != nil {
 		for _, commitSQL := range t.ExecuteSQLs {
@@ -194,7 +326,7 @@ func (t *Task) HasDoingRollback() bool {
 
 func (s *Storage) GetTaskById(taskId string) (*Task, bool, error) {
 	task := &Task{}
-	err := s.db.Where("id = ?", taskId).Preload("Instance").First(task).Error
+	err := s.db.Where("id = ?", taskId).Preload("Instance").Preload("SchemaBaseline").First(task).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, false, nil
 	}
@@ -203,8 +335,8 @@ func (s *Storage) GetTaskById(taskId string) (*Task, bool, error) {
 
 func (s *Storage) GetTaskDetailById(taskId string) (*Task, bool, error) {
 	task := &Task{}
-	err := s.db.Where("id = ?", taskId).Preload("Instance").
-		Preload("ExecuteSQLs").Preload("RollbackSQLs").First(task).Error
+	err := s.db.Where("id = ?", taskId).Preload("Instance").Preload("SchemaBaseline").
+		Preload("ExecuteSQLs").Preload("RollbackSQLs").Preload("RollbackSQLs.Statements").First(task).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, false, nil
 	}
@@ -335,19 +467,27 @@ func (s *Storage) GetTaskByInstanceId(instanceId uint) ([]Task, error) {
 }
 
 type TaskSQLDetail struct {
-	Number      uint           `json:"number"`
-	Description string         `json:"description"`
-	ExecSQL     string         `json:"exec_sql"`
-	AuditResult string         `json:"audit_result"`
-	AuditLevel  string         `json:"audit_level"`
-	AuditStatus string         `json:"audit_status"`
-	ExecResult  string         `json:"exec_result"`
-	ExecStatus  string         `json:"exec_status"`
-	RollbackSQL sql.NullString `json:"rollback_sql"`
+	Number       uint           `json:"number"`
+	Description  string         `json:"description"`
+	ExecSQL      string         `json:"exec_sql"`
+	AuditResult  string         `json:"audit_result"`
+	AuditResults AuditResults   `json:"audit_results"`
+	AuditLevel   string         `json:"audit_level"`
+	AuditStatus  string         `json:"audit_status"`
+	ExecResult   string         `json:"exec_result"`
+	ExecStatus   string         `json:"exec_status"`
+	RollbackSQL  sql.NullString `json:"rollback_sql"`
+	FixedSQL     sql.NullString `json:"fixed_sql"`
+
+	EstimatedAffectedRows sql.NullInt64 `json:"estimated_affected_rows"`
+
+	DryRunStatus sql.NullString `json:"dry_run_status"`
+	DryRunResult sql.NullString `json:"dry_run_result"`
 }
 
 var taskSQLsQueryTpl = `SELECT e_sql.number, e_sql.description, e_sql.content AS exec_sql, r_sql.content AS rollback_sql,
-e_sql.audit_result, e_sql.audit_level, e_sql.audit_status, e_sql.exec_result, e_sql.exec_status
+e_sql.audit_result, e_sql.audit_results, e_sql.audit_level, e_sql.audit_status, e_sql.exec_result, e_sql.exec_status,
+e_sql.fixed_sql, e_sql.estimated_affected_rows, e_sql.dry_run_status, e_sql.dry_run_result
 
 {{- template "body" . -}}
 
@@ -110,6 +110,7 @@ func (s *Storage) AutoMigrate() error {
 		&AuditPlan{},
 		&ExecuteSQL{},
 		&Instance{},
+		&InstanceRuleOverride{},
 		&WeChatConfiguration{},
 		&LDAPConfiguration{},
 		&Oauth2Configuration{},
@@ -117,9 +118,13 @@ func (s *Storage) AutoMigrate() error {
 		&RoleOperation{},
 		&Role{},
 		&RollbackSQL{},
+		&RollbackSQLStatement{},
 		&RuleTemplateRule{},
 		&RuleTemplate{},
 		&Rule{},
+		&RuleHit{},
+		&CustomRule{},
+		&SchemaBaseline{},
 		&SMTPConfiguration{},
 		&SqlWhitelist{},
 		&SystemVariable{},
@@ -183,6 +188,15 @@ func (s *Storage) CreateRulesIfNotExist(rules map[string][]*driver.Rule) error {
 					if err != nil {
 						return err
 					}
+					continue
+				}
+				// 3. rule knowledge in db is different from the code, only the knowledge is updated.
+				knowledge := GenerateRuleByDriverRule(rule, dbType).RuleKnowledge
+				if existedRule.RuleKnowledge != knowledge {
+					err := s.UpdateRuleKnowledge(existedRule, knowledge)
+					if err != nil {
+						return err
+					}
 				}
 			}
 		}
@@ -157,13 +157,16 @@ type Workflow struct {
 }
 
 const (
-	WorkflowStatusRunning       = "on_process"
-	WorkflowStatusReject        = "rejected"
-	WorkflowStatusCancel        = "canceled"
-	WorkflowStatusExecScheduled = "exec_scheduled"
-	WorkflowStatusExecuting     = "executing"
-	WorkflowStatusExecFailed    = "exec_failed"
-	WorkflowStatusFinish        = "finished"
+	WorkflowStatusRunning        = "on_process"
+	WorkflowStatusReject         = "rejected"
+	WorkflowStatusCancel         = "canceled"
+	WorkflowStatusExecScheduled  = "exec_scheduled"
+	WorkflowStatusExecuting      = "executing"
+	WorkflowStatusExecFailed     = "exec_failed"
+	WorkflowStatusFinish         = "finished"
+	WorkflowStatusRollingBack    = "rolling_back"
+	WorkflowStatusRollbackFailed = "rollback_failed"
+	WorkflowStatusRolledBack     = "rolled_back"
 )
 
 type WorkflowRecord struct {
@@ -275,6 +278,16 @@ func (w *Workflow) IsOperationUser(user *User) bool {
 	return false
 }
 
+// IsFinalStepUser check the user is the assignee of the final step which executes the SQL.
+func (w *Workflow) IsFinalStepUser(user *User) bool {
+	for _, assUser := range w.FinalStep().Assignees {
+		if user.ID == assUser.ID {
+			return true
+		}
+	}
+	return false
+}
+
 // IsFirstRecord check the record is the first record in workflow;
 // you must load record history first and then use it.
 func (w *Workflow) IsFirstRecord(record *WorkflowRecord) bool {
@@ -36,7 +36,8 @@ type AuditAdaptor struct {
 }
 
 type adaptorOptions struct {
-	sqlParser func(string) (interface{}, error)
+	sqlParser    func(string) (interface{}, error)
+	capabilities []driver.Capability
 }
 
 type rawSQLRuleHandler func(ctx context.Context, rule *driver.Rule, rawSQL string) (string, error)
@@ -101,11 +102,7 @@ func (a *AuditAdaptor) GeneratePlugin(opts ...AdaptorOption) goPlugin.Plugin {
 		panic("Add rule by AddRuleWithSQLParser(), but no SQL parser provided.")
 	}
 
-	r := &auditRegistererImpl{
-		dt:               a.dt,
-		rules:            a.rules,
-		additionalParams: a.additionalParams,
-	}
+	r := a.newRegisterer()
 
 	newDriver := func(cfg *driver.Config) driver.Driver {
 		a.cfg = cfg
@@ -137,6 +134,15 @@ func (a *AuditAdaptor) GeneratePlugin(opts ...AdaptorOption) goPlugin.Plugin {
 	return driver.NewAuditDriverPlugin(r, newDriver)
 }
 
+func (a *AuditAdaptor) newRegisterer() *auditRegistererImpl {
+	return &auditRegistererImpl{
+		dt:               a.dt,
+		rules:            a.rules,
+		additionalParams: a.additionalParams,
+		capabilities:     a.ao.capabilities,
+	}
+}
+
 // AdaptorOption store some custom options for the driver adaptor.
 type AdaptorOption interface {
 	apply(*adaptorOptions)
@@ -165,13 +171,24 @@ func WithSQLParser(parser func(sql string) (ast interface{}, err error)) Adaptor
 	})
 }
 
+// WithCapabilities declare the features supported by the plugin, such as
+// driver.CapabilityOfflineAudit. Query and analysis are detected by the host
+// process, so they are not required here.
+func WithCapabilities(caps ...driver.Capability) AdaptorOption {
+	return newOptionFunc(func(a *adaptorOptions) {
+		a.capabilities = append(a.capabilities, caps...)
+	})
+}
+
 var _ driver.Driver = (*auditDriverImpl)(nil)
 var _ driver.Registerer = (*auditRegistererImpl)(nil)
+var _ driver.CapabilityRegisterer = (*auditRegistererImpl)(nil)
 
 type auditRegistererImpl struct {
 	dt               Dialector
 	rules            []*driver.Rule
 	additionalParams params.Params
+	capabilities     []driver.Capability
 }
 
 func (r *auditRegistererImpl) Name() string {
@@ -186,6 +203,10 @@ func (r *auditRegistererImpl) AdditionalParams() params.Params {
 	return r.additionalParams
 }
 
+func (r *auditRegistererImpl) Capabilities() []driver.Capability {
+	return r.capabilities
+}
+
 type auditDriverImpl struct {
 	a    *AuditAdaptor
 	db   *sql.DB
@@ -326,7 +347,7 @@ func (d *auditDriverImpl) Audit(ctx context.Context, sql string) (*driver.AuditR
 			if err != nil {
 				return nil, errors.Wrapf(err, "audit SQL %s in driver adaptor", sql)
 			}
-			result.Add(rule.Level, msg)
+			result.AddWithRule(*rule, msg)
 		} else {
 			handler, ok := d.a.ruleToASTHandler[rule.Name]
 			if ok {
@@ -334,10 +355,11 @@ func (d *auditDriverImpl) Audit(ctx context.Context, sql string) (*driver.AuditR
 				if err != nil {
 					return nil, errors.Wrapf(err, "audit SQL %s in driver adaptor", sql)
 				}
-				result.Add(rule.Level, msg)
+				result.AddWithRule(*rule, msg)
 			}
 		}
 	}
+	result.ApplySuppressions(sql)
 
 	return result, nil
 }
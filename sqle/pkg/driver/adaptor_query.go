@@ -86,6 +86,15 @@ type queryDriverImpl struct {
 	conn *sql.Conn
 }
 
+func (q *queryDriverImpl) Close(ctx context.Context) {
+	if q.conn != nil {
+		q.conn.Close()
+	}
+	if q.db != nil {
+		q.db.Close()
+	}
+}
+
 func (q *queryDriverImpl) QueryPrepare(ctx context.Context, sql string, conf *driver.QueryPrepareConf) (*driver.QueryPrepareResult, error) {
 	if q.q.queryPrepare != nil {
 		return q.q.queryPrepare(ctx, sql, conf)
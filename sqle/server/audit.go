@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"math"
 	"strings"
@@ -14,7 +15,7 @@ import (
 )
 
 func Audit(l *logrus.Entry, task *model.Task) (err error) {
-	d, err := newDriverWithAudit(l, task.Instance, task.Schema, task.DBType)
+	d, err := newDriverWithAudit(l, task.Instance, task.Schema, task.DBType, task.SchemaBaseline, task.ID)
 	if err != nil {
 		return err
 	}
@@ -30,6 +31,10 @@ func audit(l *logrus.Entry, task *model.Task, d driver.Driver) (err error) {
 	if err != nil {
 		return err
 	}
+	// auditSQLs are the SQLs which not match the whitelist, they will be audited
+	// in one batch to reduce the round trips between SQLE and plugin.
+	auditSQLs := make([]*model.ExecuteSQL, 0, len(task.ExecuteSQLs))
+	fingerprints := make(map[*model.ExecuteSQL]string, len(task.ExecuteSQLs))
 	for _, executeSQL := range task.ExecuteSQLs {
 		// We always trust the ExecuteSQL.Content is single SQL.
 		//
@@ -60,24 +65,37 @@ func audit(l *logrus.Entry, task *model.Task, d driver.Driver) (err error) {
 				}
 			}
 		}
-		result := driver.NewInspectResults()
+		fingerprints[executeSQL] = node.Fingerprint
 		if whitelistMatch {
-			result.Add(driver.RuleLevelNormal, "白名单")
+			result := driver.NewInspectResults()
+			result.Add(driver.RuleLevelNormal, model.SQLWhitelistAuditResult)
+			setAuditResult(l, executeSQL, result, node.Fingerprint)
 		} else {
-			result, err = d.Audit(context.TODO(), executeSQL.Content)
-			if err != nil {
-				return err
-			}
+			auditSQLs = append(auditSQLs, executeSQL)
 		}
-		executeSQL.AuditStatus = model.SQLAuditStatusFinished
-		executeSQL.AuditLevel = string(result.Level())
-		executeSQL.AuditResult = result.Message()
-		executeSQL.AuditFingerprint = utils.Md5String(string(append([]byte(result.Message()), []byte(node.Fingerprint)...)))
+	}
 
-		l.WithFields(logrus.Fields{
-			"SQL":    executeSQL.Content,
-			"level":  executeSQL.AuditLevel,
-			"result": executeSQL.AuditResult}).Info("audit finished")
+	sqls := make([]string, 0, len(auditSQLs))
+	for _, executeSQL := range auditSQLs {
+		sqls = append(sqls, executeSQL.Content)
+	}
+	// the rule template is kept in task as of the audit for the rule hit statistics.
+	template, exist, err := st.GetAuditRuleTemplate(task.Instance, task.DBType)
+	if err != nil {
+		return err
+	}
+	if exist {
+		task.RuleTemplateId = template.ID
+		task.RuleTemplateName = template.Name
+	}
+	results, err := auditSQLsWithPolicy(d, sqls, func() (*model.RuleTemplate, bool, error) {
+		return template, exist, nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, executeSQL := range auditSQLs {
+		setAuditResult(l, executeSQL, results[i], fingerprints[executeSQL])
 	}
 
 	replenishTaskStatistics(task)
@@ -85,6 +103,92 @@ func audit(l *logrus.Entry, task *model.Task, d driver.Driver) (err error) {
 	return nil
 }
 
+// auditSQLsWithPolicy audits the SQLs and revokes the suppressions which are not allowed
+// by the rule template, every audit path should call it instead of driver.AuditSQLs. The
+// template is only fetched when there is suppressed result.
+func auditSQLsWithPolicy(d driver.Driver, sqls []string, getTemplate func() (*model.RuleTemplate, bool, error)) ([]*driver.AuditResult, error) {
+	results, err := driver.AuditSQLs(context.TODO(), d, sqls)
+	if err != nil {
+		return nil, err
+	}
+
+	var hasSuppressed bool
+	for _, result := range results {
+		if result.HasSuppressed() {
+			hasSuppressed = true
+			break
+		}
+	}
+	if !hasSuppressed {
+		return results, nil
+	}
+
+	template, exist, err := getTemplate()
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		result.RevokeSuppressions(func(item *driver.AuditResultItem) bool {
+			return exist && template.CanSuppress(string(item.Level))
+		})
+	}
+	return results, nil
+}
+
+func setAuditResult(l *logrus.Entry, executeSQL *model.ExecuteSQL, result *driver.AuditResult, fingerprint string) {
+	executeSQL.AuditStatus = model.SQLAuditStatusFinished
+	executeSQL.AuditLevel = string(result.Level())
+	executeSQL.AuditResult = result.Message()
+	executeSQL.AuditResults = model.GenerateAuditResultsByDriverResult(result)
+	executeSQL.FixedSQL = result.FixedSQL()
+	if rows, ok := result.EstimatedAffectedRows(); ok {
+		executeSQL.EstimatedAffectedRows = sql.NullInt64{Int64: rows, Valid: true}
+	}
+	executeSQL.AuditFingerprint = utils.Md5String(string(append([]byte(result.Message()), []byte(fingerprint)...)))
+
+	l.WithFields(logrus.Fields{
+		"SQL":    executeSQL.Content,
+		"level":  executeSQL.AuditLevel,
+		"result": executeSQL.AuditResult}).Info("audit finished")
+}
+
+// RecordRuleHits records the rule hits of the audited task for the rule effectiveness
+// statistics, the error is only logged because the statistics should not break the audit.
+func RecordRuleHits(l *logrus.Entry, task *model.Task, source string, sourceId uint) {
+	if err := recordRuleHits(task, source, sourceId); err != nil {
+		l.Errorf("record rule hits error: %v", err)
+	}
+}
+
+func recordRuleHits(task *model.Task, source string, sourceId uint) error {
+	st := model.GetStorage()
+	// only the task of instance can be submitted as workflow.
+	var blockLevel string
+	if source == model.RuleHitSourceTask && task.Instance != nil {
+		template, exist, err := st.GetWorkflowTemplateById(task.Instance.WorkflowTemplateId)
+		if err != nil {
+			return err
+		}
+		if exist {
+			blockLevel = template.AllowSubmitWhenLessAuditLevel
+			if blockLevel == "" {
+				blockLevel = string(driver.RuleLevelError)
+			}
+		}
+	}
+	hits := model.NewRuleHits(task, source, sourceId, blockLevel)
+	if len(hits) == 0 {
+		return nil
+	}
+	return st.SaveRuleHits(hits)
+}
+
+// ReplenishTaskStatistics sets the pass rate, score and audit level of the task by the
+// audit level of its SQLs, it is used by the task which SQLs are not audited by Audit.
+func ReplenishTaskStatistics(task *model.Task) {
+	replenishTaskStatistics(task)
+}
+
 func replenishTaskStatistics(task *model.Task) {
 	var normalCount float64
 	maxAuditLevel := driver.RuleLevelNull
@@ -192,19 +296,30 @@ func parse(l *logrus.Entry, d driver.Driver, sql string) (node driver.Node, err
 	return nodes[0], nil
 }
 
+// addAuditNotice adds the message to the audit result of SQL as a notice.
+func addAuditNotice(executeSQL *model.ExecuteSQL, message string) {
+	result := driver.NewInspectResults()
+	result.Add(driver.RuleLevel(executeSQL.AuditLevel), executeSQL.AuditResult)
+	result.Add(driver.RuleLevelNotice, message)
+	executeSQL.AuditLevel = string(result.Level())
+	executeSQL.AuditResult = result.Message()
+	if message != "" {
+		executeSQL.AuditResults = append(executeSQL.AuditResults, &model.AuditResult{
+			Level:   string(driver.RuleLevelNotice),
+			Message: message,
+		})
+	}
+}
+
 func genRollbackSQL(l *logrus.Entry, task *model.Task, d driver.Driver) ([]*model.RollbackSQL, error) {
 	rollbackSQLs := make([]*model.RollbackSQL, 0, len(task.ExecuteSQLs))
 	for _, executeSQL := range task.ExecuteSQLs {
-		rollbackSQL, reason, err := d.GenRollbackSQL(context.TODO(), executeSQL.Content)
+		rollbackSQL, reason, err := d.GenRollbackSQL(driver.WithExecuteSQLNumber(context.TODO(), executeSQL.Number), executeSQL.Content)
 		if err != nil {
 			l.Errorf("gen rollback sql error, %v", err)
 			return nil, err
 		}
-		result := driver.NewInspectResults()
-		result.Add(driver.RuleLevel(executeSQL.AuditLevel), executeSQL.AuditResult)
-		result.Add(driver.RuleLevelNotice, reason)
-		executeSQL.AuditLevel = string(result.Level())
-		executeSQL.AuditResult = result.Message()
+		addAuditNotice(executeSQL, reason)
 
 		rollbackSQLs = append(rollbackSQLs, &model.RollbackSQL{
 			BaseSQL: model.BaseSQL{
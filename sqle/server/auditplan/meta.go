@@ -15,12 +15,13 @@ type Meta struct {
 }
 
 const (
-	TypeDefault         = "default"
-	TypeMySQLSlowLog    = "mysql_slow_log"
-	TypeMySQLMybatis    = "mysql_mybatis"
-	TypeMySQLSchemaMeta = "mysql_schema_meta"
-	TypeOracleTopSQL    = "oracle_top_sql"
-	TypeAllAppExtract   = "all_app_extract"
+	TypeDefault           = "default"
+	TypeMySQLSlowLog      = "mysql_slow_log"
+	TypeMySQLMybatis      = "mysql_mybatis"
+	TypeMySQLSchemaMeta   = "mysql_schema_meta"
+	TypeMySQLSchemaDesign = "mysql_schema_design"
+	TypeOracleTopSQL      = "oracle_top_sql"
+	TypeAllAppExtract     = "all_app_extract"
 )
 
 const (
@@ -68,6 +69,19 @@ var Metas = []Meta{
 			},
 		},
 	},
+	{
+		Type:         TypeMySQLSchemaDesign,
+		Desc:         "库表设计审核",
+		InstanceType: InstanceTypeMySQL,
+		Params: []*params.Param{
+			&params.Param{
+				Key:   paramKeyCollectIntervalMinute,
+				Desc:  "采集周期（分钟）",
+				Value: "1440",
+				Type:  params.ParamTypeInt,
+			},
+		},
+	},
 	{
 		Type:         TypeOracleTopSQL,
 		Desc:         "Oracle TOP SQL",
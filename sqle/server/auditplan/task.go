@@ -10,6 +10,9 @@ import (
 
 	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
@@ -48,6 +51,8 @@ func NewTask(entry *logrus.Entry, ap *model.AuditPlan) Task {
 	switch ap.Type {
 	case TypeMySQLSchemaMeta:
 		return NewSchemaMetaTask(entry, ap)
+	case TypeMySQLSchemaDesign:
+		return NewSchemaDesignTask(entry, ap)
 	case TypeOracleTopSQL:
 		return NewOracleTopSQLTask(entry, ap)
 	default:
@@ -89,6 +94,19 @@ func (at *baseTask) audit(task *model.Task) (*model.AuditPlanReportV2, error) {
 		return nil, errNoSQLInAuditPlan
 	}
 
+	if task.Instance == nil && at.ap.SchemaBaselineName != "" {
+		baseline, exist, err := at.persist.GetSchemaBaselineByName(at.ap.SchemaBaselineName)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			task.SchemaBaselineId = baseline.ID
+			task.SchemaBaseline = baseline
+		} else {
+			at.logger.Warnf("schema baseline %s not exist, audit offline without it", at.ap.SchemaBaselineName)
+		}
+	}
+
 	for i, sql := range auditPlanSQLs {
 		task.ExecuteSQLs = append(task.ExecuteSQLs, &model.ExecuteSQL{
 			BaseSQL: model.BaseSQL{
@@ -120,6 +138,7 @@ func (at *baseTask) audit(task *model.Task) (*model.AuditPlanReportV2, error) {
 	if err != nil {
 		return nil, err
 	}
+	server.RecordRuleHits(at.logger, task, model.RuleHitSourceAuditPlan, auditPlanReport.ID)
 	return auditPlanReport, nil
 }
 
@@ -329,21 +348,22 @@ func (at *SchemaMetaTask) collectorDo() {
 	if err != nil {
 		return
 	}
-	db, err := executor.NewExecutor(at.logger, &driver.DSN{
+	dsn := &driver.DSN{
 		Host:             instance.Host,
 		Port:             instance.Port,
 		User:             instance.User,
 		Password:         instance.Password,
 		AdditionalParams: instance.AdditionalParams,
 		DatabaseName:     at.ap.InstanceDatabase,
-	},
-		at.ap.InstanceDatabase)
+	}
+	db, err := executor.NewExecutor(at.logger, dsn, at.ap.InstanceDatabase)
 	if err != nil {
 		at.logger.Errorf("connect to instance fail, error: %v", err)
 		return
 	}
 	defer db.Db.Close()
 
+	metaCacheGeneration := session.DefaultMetaCache.Generation(session.MetaCacheKey(dsn))
 	tables, err := db.ShowSchemaTables(at.ap.InstanceDatabase)
 	if err != nil {
 		at.logger.Errorf("get schema table fail, error: %v", err)
@@ -358,6 +378,7 @@ func (at *SchemaMetaTask) collectorDo() {
 		}
 	}
 	sqls := make([]string, 0, len(tables)+len(views))
+	createTableSQLs := make(map[string]string, len(tables))
 	for _, table := range tables {
 		sql, err := db.ShowCreateTable("", utils.SupplementalQuotationMarks(table))
 		if err != nil {
@@ -365,7 +386,11 @@ func (at *SchemaMetaTask) collectorDo() {
 			return
 		}
 		sqls = append(sqls, sql)
+		createTableSQLs[table] = sql
 	}
+	// the audit of tasks on the instance reuses the collected tables, and the tables
+	// changed outside SQLE are refreshed in cache.
+	session.DefaultMetaCache.UpdateSchema(session.MetaCacheKey(dsn), metaCacheGeneration, at.ap.InstanceDatabase, createTableSQLs)
 	for _, view := range views {
 		sql, err := db.ShowCreateView(utils.SupplementalQuotationMarks(view))
 		if err != nil {
@@ -410,6 +435,229 @@ func (at *SchemaMetaTask) GetSQLs(args map[string]interface{}) ([]Head, []map[st
 	return head, rows, count, nil
 }
 
+// SchemaDesignTask implement the Task interface.
+//
+// SchemaDesignTask is a loop task which collects the tables of the schema, the design of
+// the schema is audited as a unit instead of auditing the CREATE TABLE one by one.
+type SchemaDesignTask struct {
+	*sqlCollector
+}
+
+func NewSchemaDesignTask(entry *logrus.Entry, ap *model.AuditPlan) *SchemaDesignTask {
+	task := &SchemaDesignTask{
+		sqlCollector: newSQLCollector(entry, ap),
+	}
+	task.sqlCollector.do = task.collectorDo
+	return task
+}
+
+const schemaTableInfoKeySizeMB = "size_mb"
+
+func (at *SchemaDesignTask) collectorDo() {
+	if at.ap.InstanceName == "" {
+		at.logger.Warnf("instance is not configured")
+		return
+	}
+	if at.ap.InstanceDatabase == "" {
+		at.logger.Warnf("instance schema is not configured")
+		return
+	}
+	instance, _, err := at.persist.GetInstanceByName(at.ap.InstanceName)
+	if err != nil {
+		return
+	}
+	sqls, err := collectSchemaTables(at.logger, instance, at.ap.InstanceDatabase)
+	if err != nil {
+		at.logger.Errorf("collect schema tables fail, error: %v", err)
+		return
+	}
+	if len(sqls) > 0 {
+		err = at.persist.OverrideAuditPlanSQLs(at.ap.Name, convertSQLsToModelSQLs(sqls))
+		if err != nil {
+			at.logger.Errorf("save schema tables to storage fail, error: %v", err)
+		}
+	}
+}
+
+// collectSchemaTables collects the CREATE TABLE statements and the sizes of the tables in schema.
+func collectSchemaTables(l *logrus.Entry, instance *model.Instance, schema string) ([]*SQL, error) {
+	db, err := executor.NewExecutor(l, &driver.DSN{
+		Host:             instance.Host,
+		Port:             instance.Port,
+		User:             instance.User,
+		Password:         instance.Password,
+		AdditionalParams: instance.AdditionalParams,
+		DatabaseName:     schema,
+	},
+		schema)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Db.Close()
+
+	tables, err := db.ShowSchemaTables(schema)
+	if err != nil {
+		return nil, err
+	}
+	sizes, err := db.ShowSchemaTablesSizeMB(schema)
+	if err != nil {
+		return nil, err
+	}
+	sqls := make([]*SQL, 0, len(tables))
+	for _, table := range tables {
+		sql, err := db.ShowCreateTable("", utils.SupplementalQuotationMarks(table))
+		if err != nil {
+			return nil, err
+		}
+		sqls = append(sqls, &SQL{
+			SQLContent:  sql,
+			Fingerprint: sql,
+			Info: map[string]interface{}{
+				schemaTableInfoKeySizeMB: sizes[table],
+			},
+		})
+	}
+	return sqls, nil
+}
+
+func (at *SchemaDesignTask) Audit() (*model.AuditPlanReportV2, error) {
+	auditPlanSQLs, err := at.persist.GetAuditPlanSQLs(at.ap.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(auditPlanSQLs) == 0 {
+		return nil, errNoSQLInAuditPlan
+	}
+	sqls := make([]*SQL, 0, len(auditPlanSQLs))
+	for _, auditPlanSQL := range auditPlanSQLs {
+		info := map[string]interface{}{}
+		if err := json.Unmarshal(auditPlanSQL.Info, &info); err != nil {
+			return nil, err
+		}
+		sqls = append(sqls, &SQL{
+			SQLContent:  auditPlanSQL.SQLContent,
+			Fingerprint: auditPlanSQL.Fingerprint,
+			Info:        info,
+		})
+	}
+	instance, exist, err := at.persist.GetInstanceByName(at.ap.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("instance %s not exist", at.ap.InstanceName))
+	}
+	rules, err := getSchemaDesignRules(at.persist, instance)
+	if err != nil {
+		return nil, err
+	}
+	report, err := auditSchemaDesign(sqls, rules)
+	if err != nil {
+		return nil, err
+	}
+	report.AuditPlanID = at.ap.ID
+	err = at.persist.Save(report)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// AuditSchemaDesign audits the design of the schema in the instance on demand, the report
+// is not saved.
+func AuditSchemaDesign(l *logrus.Entry, instance *model.Instance, schema string) (*model.AuditPlanReportV2, error) {
+	rules, err := getSchemaDesignRules(model.GetStorage(), instance)
+	if err != nil {
+		return nil, err
+	}
+	sqls, err := collectSchemaTables(l, instance, schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(sqls) == 0 {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("there is no table in schema %s", schema))
+	}
+	return auditSchemaDesign(sqls, rules)
+}
+
+// getSchemaDesignRules returns the rules of the rule template of instance, the schema design
+// checks are enabled and configured by the schema design rules in it.
+func getSchemaDesignRules(s *model.Storage, instance *model.Instance) ([]*driver.Rule, error) {
+	modelRules, err := s.GetRulesByInstanceId(fmt.Sprintf("%v", instance.ID))
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*driver.Rule, 0, len(modelRules))
+	for _, rule := range modelRules {
+		rules = append(rules, model.ConvertRuleToDriverRule(rule))
+	}
+	return rules, nil
+}
+
+func auditSchemaDesign(sqls []*SQL, rules []*driver.Rule) (*model.AuditPlanReportV2, error) {
+	tables := make([]*rulepkg.SchemaTable, 0, len(sqls))
+	for _, sql := range sqls {
+		stmt, err := util.ParseCreateTableStmt(sql.SQLContent)
+		if err != nil {
+			return nil, err
+		}
+		size, _ := sql.Info[schemaTableInfoKeySizeMB].(float64)
+		tables = append(tables, &rulepkg.SchemaTable{Stmt: stmt, SizeMB: size})
+	}
+	results := rulepkg.AuditSchemaDesign(tables, rules)
+
+	task := &model.Task{}
+	report := &model.AuditPlanReportV2{}
+	for i, result := range results {
+		task.ExecuteSQLs = append(task.ExecuteSQLs, &model.ExecuteSQL{
+			AuditLevel:  string(result.Level()),
+			AuditResult: result.Message(),
+		})
+		report.AuditPlanReportSQLs = append(report.AuditPlanReportSQLs, &model.AuditPlanReportSQLV2{
+			SQL:         sqls[i].SQLContent,
+			Number:      uint(i + 1),
+			AuditResult: result.Message(),
+		})
+	}
+	server.ReplenishTaskStatistics(task)
+	report.PassRate = task.PassRate
+	report.Score = task.Score
+	report.AuditLevel = task.AuditLevel
+	return report, nil
+}
+
+func (at *SchemaDesignTask) GetSQLs(args map[string]interface{}) ([]Head, []map[string] /* head name */ string, uint64, error) {
+	auditPlanSQLs, count, err := at.persist.GetAuditPlanSQLsByReq(args)
+	if err != nil {
+		return nil, nil, count, err
+	}
+	head := []Head{
+		{
+			Name: "sql",
+			Desc: "SQL语句",
+			Type: "sql",
+		},
+		{
+			Name: schemaTableInfoKeySizeMB,
+			Desc: "表大小(MB)",
+		},
+	}
+	rows := make([]map[string]string, 0, len(auditPlanSQLs))
+	for _, sql := range auditPlanSQLs {
+		var info = struct {
+			SizeMB float64 `json:"size_mb"`
+		}{}
+		if err := json.Unmarshal(sql.Info, &info); err != nil {
+			return nil, nil, 0, err
+		}
+		rows = append(rows, map[string]string{
+			"sql":                    sql.SQLContent,
+			schemaTableInfoKeySizeMB: fmt.Sprintf("%v", utils.Round(info.SizeMB, 2)),
+		})
+	}
+	return head, rows, count, nil
+}
+
 // OracleTopSQLTask implement the Task interface.
 //
 // OracleTopSQLTask is a loop task which collect Top SQL from oracle instance.
@@ -0,0 +1,40 @@
+package auditplan
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditSchemaDesign(t *testing.T) {
+	sqls := []*SQL{
+		{
+			SQLContent: "CREATE TABLE `users` (`id` bigint NOT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+			Info:       map[string]interface{}{schemaTableInfoKeySizeMB: float64(1)},
+		},
+		{
+			SQLContent: "CREATE TABLE `logs` (`content` text) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+			Info:       map[string]interface{}{schemaTableInfoKeySizeMB: float64(2048)},
+		},
+	}
+	sizeRule := rulepkg.RuleHandlerMap[rulepkg.SchemaDesignCheckTableSize].Rule
+	sizeRule.Params = sizeRule.Params.Copy()
+	sizeRule.Params.SetParamValue(rulepkg.DefaultSingleParamKeyName, "1024")
+	pkRule := rulepkg.RuleHandlerMap[rulepkg.SchemaDesignCheckPKExist].Rule
+	rules := []*driver.Rule{&sizeRule, &pkRule}
+
+	report, err := auditSchemaDesign(sqls, rules)
+	assert.NoError(t, err)
+	assert.Equal(t, string(driver.RuleLevelError), report.AuditLevel)
+	assert.Equal(t, 0.5, report.PassRate)
+	assert.Len(t, report.AuditPlanReportSQLs, 2)
+	assert.Equal(t, "", report.AuditPlanReportSQLs[0].AuditResult)
+	assert.Equal(t, uint(2), report.AuditPlanReportSQLs[1].Number)
+	assert.Equal(t, "[error]表没有主键\n[warn]表大小为2048MB, 超过了1024MB, 建议归档或拆分", report.AuditPlanReportSQLs[1].AuditResult)
+
+	_, err = auditSchemaDesign([]*SQL{{SQLContent: "select 1"}}, rules)
+	assert.Error(t, err)
+}
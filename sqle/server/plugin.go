@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+const pluginReloadInterval = 30 * time.Second
+
+// ReloadPlugins reloads the new or modified plugins in plugin directory, and creates
+// the rules of the reloaded drivers if not exist.
+func ReloadPlugins() error {
+	reloadErr := driver.ReloadPlugins()
+	// the rules of the successfully reloaded plugins should be created even if some plugins failed.
+	if err := model.GetStorage().CreateRulesIfNotExist(driver.AllRules()); err != nil {
+		return fmt.Errorf("create rules failed after reloading plugins: %v", err)
+	}
+	return reloadErr
+}
+
+// StartPluginReloadLoop watches plugin directory and reloads the new or modified plugins.
+func (s *Sqled) StartPluginReloadLoop() {
+	go s.pluginReloadLoop()
+}
+
+func (s *Sqled) pluginReloadLoop() {
+	tick := time.NewTicker(pluginReloadInterval)
+	defer tick.Stop()
+	entry := log.NewEntry().WithField("type", "plugin_reload")
+	for {
+		select {
+		case <-s.exit:
+			return
+		case <-tick.C:
+			if err := ReloadPlugins(); err != nil {
+				entry.Errorf("reload plugins error: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"context"
+	_driver "database/sql/driver"
+	_errors "errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+// rollbackStatement is a single statement split from the rollback SQL of task.
+type rollbackStatement struct {
+	rollbackSQL      *model.RollbackSQL
+	executeSQLNumber uint
+	typ              string
+	statement        *model.RollbackSQLStatement
+}
+
+// splitRollbackSQLs splits the rollback SQLs of task to statements in the order of rollback,
+// the SQLs are rolled back in the reverse order of the execution.
+func splitRollbackSQLs(ctx context.Context, d driver.Driver, task *model.Task) ([]*rollbackStatement, error) {
+	numbers := make(map[uint]uint, len(task.ExecuteSQLs))
+	for _, executeSQL := range task.ExecuteSQLs {
+		numbers[executeSQL.ID] = executeSQL.Number
+	}
+
+	rollbackSQLs := make([]*model.RollbackSQL, 0, len(task.RollbackSQLs))
+	for _, rollbackSQL := range task.RollbackSQLs {
+		if rollbackSQL.Content != "" {
+			rollbackSQLs = append(rollbackSQLs, rollbackSQL)
+		}
+	}
+	sort.SliceStable(rollbackSQLs, func(i, j int) bool {
+		return numbers[rollbackSQLs[i].ExecuteSQLId] > numbers[rollbackSQLs[j].ExecuteSQLId]
+	})
+
+	stmts := []*rollbackStatement{}
+	for _, rollbackSQL := range rollbackSQLs {
+		nodes, err := d.Parse(ctx, rollbackSQL.Content)
+		if err != nil {
+			return nil, err
+		}
+		for idx, node := range nodes {
+			stmts = append(stmts, &rollbackStatement{
+				rollbackSQL:      rollbackSQL,
+				executeSQLNumber: numbers[rollbackSQL.ExecuteSQLId],
+				typ:              node.Type,
+				statement: &model.RollbackSQLStatement{
+					RollbackSQLId: rollbackSQL.ID,
+					Number:        uint(idx + 1),
+					Content:       node.Text,
+					ExecStatus:    model.SQLExecuteStatusInitialized,
+				},
+			})
+		}
+	}
+	return stmts, nil
+}
+
+// groupRollbackStatements groups the adjacent DMLs to be executed in one transaction,
+// each of the other statements is in a group by itself.
+func groupRollbackStatements(stmts []*rollbackStatement) [][]*rollbackStatement {
+	groups := [][]*rollbackStatement{}
+	for idx, stmt := range stmts {
+		if idx > 0 && stmt.typ == driver.SQLTypeDML && stmts[idx-1].typ == driver.SQLTypeDML {
+			groups[len(groups)-1] = append(groups[len(groups)-1], stmt)
+			continue
+		}
+		groups = append(groups, []*rollbackStatement{stmt})
+	}
+	return groups
+}
+
+// RollbackPreview is a statement which will be executed when the task is rolled back.
+type RollbackPreview struct {
+	ExecuteSQLNumber uint
+	SQL              string
+	// Group is the order of the group which the statement belongs to, the statements in
+	// the same group are executed in one transaction if InTransaction is true.
+	Group         int
+	InTransaction bool
+	// MatchedRows is nil if the statement is not a DML or the rows can not be counted.
+	MatchedRows *int64
+}
+
+// rollbackPreviewCountTimeout is the time limit to count the rows matched by each DML
+// when previewing rollback, the rows is omitted if it is exceeded.
+const rollbackPreviewCountTimeout = 10 * time.Second
+
+// PreviewRollback returns the statements which will be executed in order when the task is
+// rolled back, and the rows matched by each DML. Nothing is changed on the instance.
+func PreviewRollback(ctx context.Context, task *model.Task) ([]*RollbackPreview, error) {
+	if task.Instance == nil {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("instance is not exist"))
+	}
+	entry := log.NewEntry().WithField("task_id", task.ID)
+	d, err := newDriverWithAudit(entry, task.Instance, task.Schema, task.DBType, nil, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close(context.TODO())
+
+	stmts, err := splitRollbackSQLs(ctx, d, task)
+	if err != nil {
+		return nil, err
+	}
+	counter, canCount := d.(driver.RowsCounter)
+
+	previews := make([]*RollbackPreview, 0, len(stmts))
+	for idx, group := range groupRollbackStatements(stmts) {
+		for _, stmt := range group {
+			preview := &RollbackPreview{
+				ExecuteSQLNumber: stmt.executeSQLNumber,
+				SQL:              stmt.statement.Content,
+				Group:            idx + 1,
+				InTransaction:    stmt.typ == driver.SQLTypeDML,
+			}
+			if canCount && stmt.typ == driver.SQLTypeDML {
+				countCtx, cancel := context.WithTimeout(ctx, rollbackPreviewCountTimeout)
+				rows, ok, err := counter.CountMatchedRows(countCtx, stmt.statement.Content)
+				cancel()
+				if err != nil {
+					entry.Warnf("count matched rows failed, sql: %v, error: %v", stmt.statement.Content, err)
+				} else if ok {
+					preview.MatchedRows = &rows
+				}
+			}
+			previews = append(previews, preview)
+		}
+	}
+	return previews, nil
+}
+
+// execRollbackStatements executes the DMLs in one transaction or the other statement alone,
+// and records the outcome of each statement.
+func (a *action) execRollbackStatements(stmts []*rollbackStatement) error {
+	qs := make([]string, 0, len(stmts))
+	for _, stmt := range stmts {
+		stmt.statement.ExecStatus = model.SQLExecuteStatusDoing
+		qs = append(qs, stmt.statement.Content)
+	}
+
+	var results []_driver.Result
+	var err error
+	if stmts[0].typ == driver.SQLTypeDML {
+		results, err = a.driver.Tx(context.TODO(), qs...)
+	} else {
+		var result _driver.Result
+		result, err = a.driver.Exec(context.TODO(), qs[0])
+		results = []_driver.Result{result}
+	}
+
+	// the statement fails alone if the driver doesn't return driver.TxError.
+	failedIdx := -1
+	var txErr *driver.TxError
+	if _errors.As(err, &txErr) && txErr.Index < len(stmts) {
+		failedIdx = txErr.Index
+	}
+	for idx, stmt := range stmts {
+		switch {
+		case err == nil:
+			if idx < len(results) && results[idx] != nil {
+				stmt.statement.RowAffects, _ = results[idx].RowsAffected()
+			}
+			stmt.statement.ExecStatus = model.SQLExecuteStatusSucceeded
+			stmt.statement.ExecResult = model.TaskExecResultOK
+		case failedIdx < 0 || idx == failedIdx:
+			stmt.statement.ExecStatus = model.SQLExecuteStatusFailed
+			stmt.statement.ExecResult = err.Error()
+		case idx < failedIdx:
+			stmt.statement.ExecStatus = model.SQLExecuteStatusFailed
+			stmt.statement.ExecResult = fmt.Sprintf("rolled back because of the failure of \"%s\"",
+				stmts[failedIdx].statement.Content)
+		default:
+			stmt.statement.ExecStatus = model.SQLExecuteStatusInitialized
+			stmt.statement.ExecResult = rollbackNotExecutedResult
+		}
+	}
+	return err
+}
+
+const rollbackNotExecutedResult = "not executed because of the previous failure"
+
+// summarizeRollbackSQL sets the outcome of the rollback SQL by its statements, it is
+// succeeded only if all the statements are succeeded.
+func summarizeRollbackSQL(rollbackSQL *model.RollbackSQL) {
+	rollbackSQL.RowAffects = 0
+	rollbackSQL.ExecStatus = model.SQLExecuteStatusSucceeded
+	rollbackSQL.ExecResult = model.TaskExecResultOK
+	executed := false
+	for _, stmt := range rollbackSQL.Statements {
+		rollbackSQL.RowAffects += stmt.RowAffects
+		switch stmt.ExecStatus {
+		case model.SQLExecuteStatusFailed:
+			rollbackSQL.ExecStatus = model.SQLExecuteStatusFailed
+			rollbackSQL.ExecResult = stmt.ExecResult
+			return
+		case model.SQLExecuteStatusSucceeded:
+			executed = true
+		default:
+			rollbackSQL.ExecStatus = model.SQLExecuteStatusFailed
+			rollbackSQL.ExecResult = rollbackNotExecutedResult
+		}
+	}
+	if !executed {
+		rollbackSQL.ExecStatus = model.SQLExecuteStatusInitialized
+		rollbackSQL.ExecResult = ""
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/sirupsen/logrus"
+)
+
+// SandboxSQL is an audited SQL of the sandbox audit.
+type SandboxSQL struct {
+	SQL    string
+	Result *driver.AuditResult
+}
+
+// AuditSandbox audits the SQL with the rules, no instance is connected. The setup SQL
+// is audited before the SQL to build the mock database, e.g. the tables which the SQL
+// depends on, and its audit results are dropped. The suppressions are allowed by the rule
+// template, they are all revoked if no rule template is given.
+func AuditSandbox(l *logrus.Entry, dbType, ruleTemplateName string, rules []*driver.Rule, setupSQL, sql string) ([]*SandboxSQL, error) {
+	sandbox := driver.HasCapability(dbType, driver.CapabilitySandbox)
+	if !sandbox && !driver.HasCapability(dbType, driver.CapabilityOfflineAudit) {
+		return nil, fmt.Errorf("db type %s does not support sandbox audit", dbType)
+	}
+	if err := model.GetStorage().FillCustomRuleMatcher(dbType, rules); err != nil {
+		return nil, fmt.Errorf("get custom rules error: %v", err)
+	}
+
+	d, err := driver.NewDriver(l, dbType, &driver.Config{Rules: rules, Sandbox: sandbox})
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close(context.TODO())
+
+	setupSQLs, err := splitSandboxSQL(d, setupSQL)
+	if err != nil {
+		return nil, fmt.Errorf("parse setup sql error: %v", err)
+	}
+	sqls, err := splitSandboxSQL(d, sql)
+	if err != nil {
+		return nil, fmt.Errorf("parse sql error: %v", err)
+	}
+	if len(sqls) == 0 {
+		return nil, fmt.Errorf("sql is empty")
+	}
+
+	results, err := auditSQLsWithPolicy(d, append(setupSQLs, sqls...), func() (*model.RuleTemplate, bool, error) {
+		if ruleTemplateName == "" {
+			return nil, false, nil
+		}
+		return model.GetStorage().GetRuleTemplateByName(ruleTemplateName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sandboxSQLs := make([]*SandboxSQL, 0, len(sqls))
+	for i, sql := range sqls {
+		sandboxSQLs = append(sandboxSQLs, &SandboxSQL{
+			SQL:    sql,
+			Result: results[len(setupSQLs)+i],
+		})
+	}
+	return sandboxSQLs, nil
+}
+
+func splitSandboxSQL(d driver.Driver, sql string) ([]string, error) {
+	if sql == "" {
+		return nil, nil
+	}
+	nodes, err := d.Parse(context.TODO(), sql)
+	if err != nil {
+		return nil, err
+	}
+	sqls := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		sqls = append(sqls, node.Text)
+	}
+	return sqls, nil
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	_driver "database/sql/driver"
 	_errors "errors"
 	"fmt"
 	"sync"
@@ -24,7 +25,7 @@ func GetSqled() *Sqled {
 }
 
 // Sqled is an async task scheduling service.
-// receive tasks from queue, the tasks include inspect, execute, rollback;
+// receive tasks from queue, the tasks include inspect, execute, rollback, dry run;
 // and the task will only be executed once.
 type Sqled struct {
 	sync.Mutex
@@ -55,14 +56,15 @@ func (s *Sqled) HasTask(taskId string) bool {
 
 // addTask receive taskId and action type, using taskId and typ to create an action;
 // action will be validated, and sent to Sqled.queue.
-func (s *Sqled) addTask(taskId string, typ int) (*action, error) {
+func (s *Sqled) addTask(taskId string, typ int, dryRunOpts *driver.DryRunOptions) (*action, error) {
 	var err error
 	var d driver.Driver
 	entry := log.NewEntry().WithField("task_id", taskId)
 	action := &action{
-		typ:   typ,
-		entry: entry,
-		done:  make(chan struct{}),
+		typ:        typ,
+		entry:      entry,
+		done:       make(chan struct{}),
+		dryRunOpts: dryRunOpts,
 	}
 
 	s.Lock()
@@ -90,7 +92,7 @@ func (s *Sqled) addTask(taskId string, typ int) (*action, error) {
 	action.task = task
 
 	// d will be closed in Sqled.do().
-	if d, err = newDriverWithAudit(entry, task.Instance, task.Schema, task.DBType); err != nil {
+	if d, err = newDriverWithAudit(entry, task.Instance, task.Schema, task.DBType, task.SchemaBaseline, task.ID); err != nil {
 		goto Error
 	}
 	action.driver = d
@@ -106,12 +108,33 @@ Error:
 }
 
 func (s *Sqled) AddTask(taskId string, typ int) error {
-	_, err := s.addTask(taskId, typ)
+	_, err := s.addTask(taskId, typ, nil)
 	return err
 }
 
 func (s *Sqled) AddTaskWaitResult(taskId string, typ int) (*model.Task, error) {
-	action, err := s.addTask(taskId, typ)
+	action, err := s.addTask(taskId, typ, nil)
+	if err != nil {
+		return nil, err
+	}
+	<-action.done
+	return action.task, action.err
+}
+
+// AddDryRunTaskWaitResult dry runs the task against the shadow tables, the shadow is created on
+// the staging instance if it is not nil, otherwise on the instance of task.
+func (s *Sqled) AddDryRunTaskWaitResult(taskId string, staging *model.Instance, sampleRows int64) (*model.Task, error) {
+	opts := &driver.DryRunOptions{SampleRows: sampleRows}
+	if staging != nil {
+		opts.DSN = &driver.DSN{
+			Host:             staging.Host,
+			Port:             staging.Port,
+			User:             staging.User,
+			Password:         staging.Password,
+			AdditionalParams: staging.AdditionalParams,
+		}
+	}
+	action, err := s.addTask(taskId, ActionTypeDryRun, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -150,6 +173,8 @@ func (s *Sqled) do(action *action) error {
 		err = action.execute()
 	case ActionTypeRollback:
 		err = action.rollback()
+	case ActionTypeDryRun:
+		err = action.dryRun()
 	}
 	if err != nil {
 		action.err = err
@@ -169,10 +194,13 @@ func (s *Sqled) do(action *action) error {
 	return err
 }
 
+const flashbackFailedNotice = "未能通过 binlog 生成回滚语句（%s），回滚语句为审核时生成的语句"
+
 const (
 	ActionTypeAudit = iota + 1
 	ActionTypeExecute
 	ActionTypeRollback
+	ActionTypeDryRun
 )
 
 // Action is an action for the task;
@@ -190,6 +218,9 @@ type action struct {
 	typ  int
 	err  error
 	done chan struct{}
+
+	// dryRunOpts is the options of dry run, it is used when typ is ActionTypeDryRun.
+	dryRunOpts *driver.DryRunOptions
 }
 
 var (
@@ -198,6 +229,8 @@ var (
 	ErrActionRollbackOnRollbackedTask    = _errors.New("task has been rollbacked, can not do rollback on it")
 	ErrActionRollbackOnExecuteFailedTask = _errors.New("task has been executed failed, can not do rollback on it")
 	ErrActionRollbackOnNonExecutedTask   = _errors.New("task has not been executed, can not do rollback on it")
+	ErrActionDryRunOnNonAuditedTask      = _errors.New("task has not been audited, can not do dry run on it")
+	ErrActionDryRunOnExecutedTask        = _errors.New("task has been executed, can not do dry run on it")
 )
 
 // validation validate whether task can do action type(a.typ) or not.
@@ -223,6 +256,13 @@ func (a *action) validation(task *model.Task) error {
 		if !task.HasDoingExecute() {
 			return errors.New(errors.TaskActionInvalid, ErrActionRollbackOnNonExecutedTask)
 		}
+	case ActionTypeDryRun:
+		if task.HasDoingExecute() {
+			return errors.New(errors.TaskActionDone, ErrActionDryRunOnExecutedTask)
+		}
+		if !task.HasDoingAudit() {
+			return errors.New(errors.TaskActionInvalid, ErrActionDryRunOnNonAuditedTask)
+		}
 	}
 	return nil
 }
@@ -239,7 +279,7 @@ func (a *action) audit() (err error) {
 	if a.task.SQLSource == model.TaskSQLSourceFromMyBatisXMLFile || a.task.InstanceId == 0 {
 		a.entry.Warn("skip generate rollback SQLs")
 	} else {
-		d, err := newDriverWithAudit(a.entry, a.task.Instance, a.task.Schema, a.task.DBType)
+		d, err := newDriverWithAudit(a.entry, a.task.Instance, a.task.Schema, a.task.DBType, a.task.SchemaBaseline, a.task.ID)
 		if err != nil {
 			return xerrors.Wrap(err, "new driver for generate rollback SQL")
 		}
@@ -262,14 +302,18 @@ func (a *action) audit() (err error) {
 	}
 
 	if err = st.UpdateTask(a.task, map[string]interface{}{
-		"pass_rate":   a.task.PassRate,
-		"audit_level": a.task.AuditLevel,
-		"status":      a.task.Status,
-		"score":       a.task.Score,
+		"pass_rate":          a.task.PassRate,
+		"audit_level":        a.task.AuditLevel,
+		"status":             a.task.Status,
+		"score":              a.task.Score,
+		"rule_template_id":   a.task.RuleTemplateId,
+		"rule_template_name": a.task.RuleTemplateName,
 	}); err != nil {
 		a.entry.Errorf("update task error:%v", err)
 		return err
 	}
+
+	RecordRuleHits(a.entry, a.task, model.RuleHitSourceTask, a.task.ID)
 	return nil
 }
 
@@ -355,7 +399,7 @@ func (a *action) execSQL(executeSQL *model.ExecuteSQL) error {
 		return err
 	}
 
-	_, err := a.driver.Exec(context.TODO(), executeSQL.Content)
+	_, err := a.driver.Exec(driver.WithExecuteSQLNumber(context.TODO(), executeSQL.Number), executeSQL.Content)
 	if err != nil {
 		executeSQL.ExecStatus = model.SQLExecuteStatusFailed
 		executeSQL.ExecResult = err.Error()
@@ -385,7 +429,14 @@ func (a *action) execSQLs(executeSQLs []*model.ExecuteSQL) error {
 		qs = append(qs, executeSQL.Content)
 	}
 
-	results, txErr := a.driver.Tx(context.TODO(), qs...)
+	var results []_driver.Result
+	var flashback *driver.Flashback
+	var txErr error
+	if fe, ok := a.driver.(driver.FlashbackExecutor); ok {
+		results, flashback, txErr = fe.TxWithFlashback(context.TODO(), qs...)
+	} else {
+		results, txErr = a.driver.Tx(context.TODO(), qs...)
+	}
 	for idx, executeSQL := range executeSQLs {
 		if txErr != nil {
 			executeSQL.ExecStatus = model.SQLExecuteStatusFailed
@@ -396,59 +447,176 @@ func (a *action) execSQLs(executeSQLs []*model.ExecuteSQL) error {
 		executeSQL.RowAffects = rowAffects
 		executeSQL.ExecStatus = model.SQLExecuteStatusSucceeded
 		executeSQL.ExecResult = model.TaskExecResultOK
+		if flashback != nil {
+			executeSQL.StartBinlogFile = flashback.StartBinlogFile
+			executeSQL.StartBinlogPos = flashback.StartBinlogPos
+			executeSQL.EndBinlogFile = flashback.EndBinlogFile
+			executeSQL.EndBinlogPos = flashback.EndBinlogPos
+			if flashback.RollbackSQLs == nil && flashback.FailedReason != "" {
+				// the rollback SQL generated in audit is kept.
+				addAuditNotice(executeSQL, fmt.Sprintf(flashbackFailedNotice, flashback.FailedReason))
+			}
+		}
 	}
 
-	return st.UpdateExecuteSQLs(executeSQLs)
+	if err := st.UpdateExecuteSQLs(executeSQLs); err != nil {
+		return err
+	}
+	if txErr != nil || flashback == nil || flashback.RollbackSQLs == nil {
+		return nil
+	}
+	return a.saveFlashbackRollbackSQLs(executeSQLs, flashback.RollbackSQLs)
 }
 
-func (a *action) rollback() (err error) {
-	task := a.task
-	a.entry.Info("start rollback SQL")
+// saveFlashbackRollbackSQLs replaces the rollback SQLs generated in audit with the ones generated
+// from the changes actually made by the execution.
+func (a *action) saveFlashbackRollbackSQLs(executeSQLs []*model.ExecuteSQL, sqls []string) error {
+	existRollbackSQLs := map[uint]*model.RollbackSQL{}
+	for _, rollbackSQL := range a.task.RollbackSQLs {
+		existRollbackSQLs[rollbackSQL.ExecuteSQLId] = rollbackSQL
+	}
 
-	var execErr error
-	st := model.GetStorage()
-ExecSQLs:
-	for _, rollbackSQL := range task.RollbackSQLs {
-		if rollbackSQL.Content == "" {
+	rollbackSQLs := []*model.RollbackSQL{}
+	for idx, executeSQL := range executeSQLs {
+		if idx >= len(sqls) {
 			continue
 		}
-		if err = st.UpdateRollbackSqlStatus(&rollbackSQL.BaseSQL, model.SQLExecuteStatusDoing, ""); err != nil {
-			return err
+		rollbackSQL, ok := existRollbackSQLs[executeSQL.ID]
+		if sqls[idx] == "" {
+			// the SQL changed no rows in execution, the rollback SQL generated from the rows
+			// selected in audit would change the rows which are not changed by the task.
+			if ok && rollbackSQL.Content != "" {
+				rollbackSQL.Content = ""
+				rollbackSQLs = append(rollbackSQLs, rollbackSQL)
+			}
+			continue
+		}
+		if !ok {
+			rollbackSQL = &model.RollbackSQL{
+				BaseSQL: model.BaseSQL{
+					TaskId: executeSQL.TaskId,
+					Number: executeSQL.Number,
+				},
+				ExecuteSQLId: executeSQL.ID,
+			}
+			a.task.RollbackSQLs = append(a.task.RollbackSQLs, rollbackSQL)
 		}
+		rollbackSQL.Content = sqls[idx]
+		rollbackSQL.StartBinlogFile = executeSQL.StartBinlogFile
+		rollbackSQL.StartBinlogPos = executeSQL.StartBinlogPos
+		rollbackSQL.EndBinlogFile = executeSQL.EndBinlogFile
+		rollbackSQL.EndBinlogPos = executeSQL.EndBinlogPos
+		rollbackSQLs = append(rollbackSQLs, rollbackSQL)
+	}
+	return model.GetStorage().UpdateRollbackSQLs(rollbackSQLs)
+}
 
-		nodes, err := a.driver.Parse(context.TODO(), rollbackSQL.Content)
-		if err != nil {
-			return err
+func (a *action) rollback() (err error) {
+	st := model.GetStorage()
+	task := a.task
+	a.entry.Info("start rollback SQL")
+
+	stmts, err := splitRollbackSQLs(context.TODO(), a.driver, task)
+	if err != nil {
+		return err
+	}
+
+	if err = st.UpdateTask(task, map[string]interface{}{
+		"status": model.TaskStatusRollingBack,
+	}); err != nil {
+		return err
+	}
+
+	rollbackSQLs := []*model.RollbackSQL{}
+	for _, stmt := range stmts {
+		rollbackSQL := stmt.rollbackSQL
+		if len(rollbackSQLs) == 0 || rollbackSQLs[len(rollbackSQLs)-1] != rollbackSQL {
+			rollbackSQL.ExecStatus = model.SQLExecuteStatusDoing
+			rollbackSQL.Statements = nil
+			rollbackSQLs = append(rollbackSQLs, rollbackSQL)
 		}
-		// todo: execute in transaction
-		for _, node := range nodes {
-			currentSQL := model.RollbackSQL{BaseSQL: model.BaseSQL{
-				TaskId:  rollbackSQL.TaskId,
-				Content: node.Text,
-			}, ExecuteSQLId: rollbackSQL.ExecuteSQLId}
-			_, execErr := a.driver.Exec(context.TODO(), node.Text)
-			if execErr != nil {
-				currentSQL.ExecStatus = model.SQLExecuteStatusFailed
-				currentSQL.ExecResult = execErr.Error()
-			} else {
-				currentSQL.ExecStatus = model.SQLExecuteStatusSucceeded
-				currentSQL.ExecResult = model.TaskExecResultOK
-			}
-			if execErr := st.Save(currentSQL); execErr != nil {
-				break ExecSQLs
-			}
+		rollbackSQL.Statements = append(rollbackSQL.Statements, stmt.statement)
+	}
+	if err = st.UpdateRollbackSQLs(rollbackSQLs); err != nil {
+		return err
+	}
+
+	// the DMLs are rolled back in one transaction, but the DDL is committed implicitly, so
+	// the rollback stops at the first failure to avoid going on with an inconsistent state.
+	var execErr error
+	for _, group := range groupRollbackStatements(stmts) {
+		if execErr = a.execRollbackStatements(group); execErr != nil {
+			break
 		}
 	}
+	for _, rollbackSQL := range rollbackSQLs {
+		summarizeRollbackSQL(rollbackSQL)
+	}
+	if err = st.UpdateRollbackSQLs(rollbackSQLs); err != nil {
+		a.entry.Errorf("save rollback SQLs error:%v", err)
+		return err
+	}
 
+	taskStatus := model.TaskStatusRollbackSucceeded
 	if execErr != nil {
-		a.entry.Errorf("rollback SQL error:%v", execErr)
-	} else {
-		a.entry.Error("rollback SQL finished")
+		taskStatus = model.TaskStatusRollbackFailed
+	}
+	task.Status = taskStatus
+	a.entry.WithField("task_status", taskStatus).Infof("rollback is completed, err:%v", execErr)
+
+	if err = st.UpdateTask(task, map[string]interface{}{
+		"status": taskStatus,
+	}); err != nil {
+		return err
 	}
 	return execErr
 }
 
-func newDriverWithAudit(l *logrus.Entry, inst *model.Instance, database string, dbType string) (driver.Driver, error) {
+// dryRun executes the SQLs of task against the shadow tables, and saves the outcome of each
+// SQL. The task status is not changed, the SQLs are not executed on the tables actually.
+func (a *action) dryRun() error {
+	runner, ok := a.driver.(driver.DryRunner)
+	if !ok {
+		return errors.New(errors.FeatureNotImplemented, fmt.Errorf("dry run is not supported by %v", a.task.DBType))
+	}
+	a.entry.Info("start dry run")
+
+	queries := make([]string, 0, len(a.task.ExecuteSQLs))
+	for _, executeSQL := range a.task.ExecuteSQLs {
+		queries = append(queries, executeSQL.Content)
+	}
+	results, err := runner.DryRun(context.TODO(), a.dryRunOpts, queries...)
+	if err != nil {
+		a.entry.Errorf("dry run error:%v", err)
+		return err
+	}
+	if len(results) != len(a.task.ExecuteSQLs) {
+		return fmt.Errorf("the count of dry run results %d is not equal to the count of SQLs %d",
+			len(results), len(a.task.ExecuteSQLs))
+	}
+
+	for idx, executeSQL := range a.task.ExecuteSQLs {
+		result := results[idx]
+		switch {
+		case result.Skipped:
+			executeSQL.DryRunStatus = model.SQLDryRunStatusSkipped
+			executeSQL.DryRunResult = result.Reason
+		case result.Err != nil:
+			executeSQL.DryRunStatus = model.SQLDryRunStatusFailed
+			executeSQL.DryRunResult = result.Err.Error()
+		default:
+			executeSQL.DryRunStatus = model.SQLDryRunStatusSucceeded
+			executeSQL.DryRunResult = model.TaskExecResultOK
+		}
+	}
+	a.entry.Info("dry run is completed")
+	return model.GetStorage().UpdateExecuteSQLs(a.task.ExecuteSQLs)
+}
+
+// newDriverWithAudit creates a driver to audit SQL of the task, the SQL is audited offline if inst
+// is nil, and it is against the schema baseline if baseline is not nil and supported by driver.
+func newDriverWithAudit(l *logrus.Entry, inst *model.Instance, database string, dbType string,
+	baseline *model.SchemaBaseline, taskId uint) (driver.Driver, error) {
 	if inst == nil && dbType == "" {
 		return nil, xerrors.Errorf("instance is nil and dbType is nil")
 	}
@@ -488,11 +656,27 @@ func newDriverWithAudit(l *logrus.Entry, inst *model.Instance, database string,
 	for i, rule := range modelRules {
 		rules[i] = model.ConvertRuleToDriverRule(rule)
 	}
+	if err := st.FillCustomRuleMatcher(dbType, rules); err != nil {
+		return nil, xerrors.Errorf("get custom rules error: %v", err)
+	}
 
 	cfg, err := driver.NewConfig(dsn, rules)
 	if err != nil {
 		return nil, xerrors.Wrap(err, "new driver with audit")
 	}
+	if taskId != 0 {
+		cfg.TaskId = fmt.Sprintf("%v", taskId)
+	}
+	if inst == nil && baseline != nil {
+		if driver.HasCapability(dbType, driver.CapabilitySchemaBaseline) {
+			cfg.SchemaBaseline = &driver.SchemaBaseline{
+				DefaultSchema: baseline.DefaultSchema,
+				SQL:           baseline.Content,
+			}
+		} else {
+			l.Warnf("driver %s does not support schema baseline, ignore schema baseline %s", dbType, baseline.Name)
+		}
+	}
 
 	return driver.NewDriver(l, dbType, cfg)
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -128,6 +129,11 @@ func TestAction_validation(t *testing.T) {
 		{BaseSQL: model.BaseSQL{ExecStatus: model.SQLExecuteStatusInitialized}, AuditStatus: model.SQLAuditStatusFinished},
 	}}
 	assert.EqualError(t, actions[ActionTypeRollback].validation(noExecutedTask), ErrActionRollbackOnNonExecutedTask.Error())
+
+	dryRunAction := &action{typ: ActionTypeDryRun}
+	assert.Nil(t, dryRunAction.validation(noExecutedTask))
+	assert.EqualError(t, dryRunAction.validation(executingTask), ErrActionDryRunOnExecutedTask.Error())
+	assert.EqualError(t, dryRunAction.validation(noAuditedTask), ErrActionDryRunOnNonAuditedTask.Error())
 }
 
 func Test_action_audit_UpdateTask(t *testing.T) {
@@ -145,16 +151,19 @@ func Test_action_audit_UpdateTask(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"value", "match_type"}).AddRow(whitelist.Value, whitelist.MatchType))
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `sql_whitelist`")).
 		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow("1"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `rule_templates`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "default_mysql"))
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `execute_sql_detail`")).
-		WithArgs(model.MockTime, model.MockTime, nil, 0, 0, act.task.ExecuteSQLs[0].Content, "", "", 0, "", 0, 0, "", model.SQLAuditStatusFinished, "[normal]白名单", "2882fdbb7d5bcda7b49ea0803493467e", "normal").
+		WithArgs(model.MockTime, model.MockTime, nil, 0, 0, act.task.ExecuteSQLs[0].Content, "", "", 0, "", 0, 0, "", model.SQLAuditStatusFinished, "[normal]白名单",
+			`[{"level":"normal","message":"白名单","rule_name":"","category":""}]`, "2882fdbb7d5bcda7b49ea0803493467e", "normal", "", nil, "", "").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks`")).
-		WithArgs(driver.RuleLevelNormal, float64(1), 100, model.TaskStatusAudited, act.task.ID).
+		WithArgs(driver.RuleLevelNormal, float64(1), 1, "default_mysql", 100, model.TaskStatusAudited, act.task.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
@@ -162,6 +171,107 @@ func Test_action_audit_UpdateTask(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, model.TaskStatusAudited, act.task.Status)
 	assert.Equal(t, float64(1), act.task.PassRate)
+	assert.Equal(t, uint(1), act.task.RuleTemplateId)
+	assert.Equal(t, "default_mysql", act.task.RuleTemplateName)
+}
+
+type mockBatchDriver struct {
+	mockDriver
+	auditCalled      int
+	auditBatchCalled int
+}
+
+func (d *mockBatchDriver) Audit(ctx context.Context, sql string) (*driver.AuditResult, error) {
+	d.auditCalled++
+	return driver.NewInspectResults(), nil
+}
+
+func (d *mockBatchDriver) AuditBatch(ctx context.Context, sqls []string) ([]*driver.AuditResult, error) {
+	d.auditBatchCalled++
+	results := make([]*driver.AuditResult, len(sqls))
+	for i, sql := range sqls {
+		results[i] = driver.NewInspectResults()
+		results[i].Add(driver.RuleLevelWarn, "audited: %s", sql)
+	}
+	return results, nil
+}
+
+func Test_audit_WithBatchAuditor(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	model.InitMockStorage(mockDB)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sql_whitelist`")).
+		WillReturnRows(sqlmock.NewRows([]string{"value", "match_type"}).AddRow("select * from t1", model.SQLWhitelistExactMatch))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `sql_whitelist`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow("1"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `rule_templates`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "default_mysql"))
+
+	d := &mockBatchDriver{}
+	act := getAction([]string{"select * from t2", "select * from t1", "select * from t3"}, ActionTypeAudit, d)
+	err = audit(act.entry, act.task, d)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, 1, d.auditBatchCalled)
+	assert.Equal(t, 0, d.auditCalled)
+	assert.Equal(t, "[warn]audited: select * from t2", act.task.ExecuteSQLs[0].AuditResult)
+	assert.Equal(t, "[normal]白名单", act.task.ExecuteSQLs[1].AuditResult)
+	assert.Equal(t, "[warn]audited: select * from t3", act.task.ExecuteSQLs[2].AuditResult)
+	assert.Equal(t, string(driver.RuleLevelWarn), act.task.AuditLevel)
+}
+
+type mockSuppressDriver struct {
+	mockDriver
+}
+
+func (d *mockSuppressDriver) Audit(ctx context.Context, sql string) (*driver.AuditResult, error) {
+	result := driver.NewInspectResults()
+	result.AddWithRule(driver.Rule{Name: "rule_warn", Level: driver.RuleLevelWarn}, "warn")
+	result.AddWithRule(driver.Rule{Name: "rule_error", Level: driver.RuleLevelError}, "error")
+	result.ApplySuppressions(sql)
+	return result, nil
+}
+
+func Test_audit_WithSuppression(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	model.InitMockStorage(mockDB)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sql_whitelist`")).
+		WillReturnRows(sqlmock.NewRows([]string{"value", "match_type"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `sql_whitelist`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow("0"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `rule_templates`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "allow_suppression", "suppressible_levels"}).
+			AddRow(1, "default_mysql", true, "warn"))
+
+	d := &mockSuppressDriver{}
+	act := getAction([]string{`select 1 /* sqle:ignore rule_warn,rule_error reason="test" */`}, ActionTypeAudit, d)
+	act.task.DBType = driver.DriverTypeMySQL
+	err = audit(act.entry, act.task, d)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	executeSQL := act.task.ExecuteSQLs[0]
+	assert.Equal(t, string(driver.RuleLevelError), executeSQL.AuditLevel)
+	assert.Len(t, executeSQL.AuditResults, 2)
+	assert.False(t, executeSQL.AuditResults[0].Suppressed)
+	assert.True(t, executeSQL.AuditResults[1].Suppressed)
+	assert.Equal(t, "test", executeSQL.AuditResults[1].SuppressReason)
+}
+
+func Test_auditSQLsWithPolicy_WithoutTemplate(t *testing.T) {
+	d := &mockSuppressDriver{}
+	results, err := auditSQLsWithPolicy(d, []string{`select 1 /* sqle:ignore rule_warn reason="test" */`},
+		func() (*model.RuleTemplate, bool, error) {
+			return nil, false, nil
+		})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.False(t, results[0].HasSuppressed())
+	assert.Equal(t, driver.RuleLevelError, results[0].Level())
 }
 
 func Test_action_execute(t *testing.T) {
@@ -215,7 +325,7 @@ func Test_action_execute(t *testing.T) {
 					return errors.New("mock error: Storage.UpdateExecuteSQLs")
 				})
 
-				return newDriverWithAudit(log.NewEntry(), nil, "", driver.DriverTypeMySQL)
+				return newDriverWithAudit(log.NewEntry(), nil, "", driver.DriverTypeMySQL, nil, 0)
 			},
 			sqls:    []string{"select * from t1"},
 			wantErr: false,
@@ -230,7 +340,7 @@ func Test_action_execute(t *testing.T) {
 					return errors.New("mock error: Storage.UpdateExecuteSqlStatus")
 				})
 
-				return newDriverWithAudit(log.NewEntry(), nil, "", driver.DriverTypeMySQL)
+				return newDriverWithAudit(log.NewEntry(), nil, "", driver.DriverTypeMySQL, nil, 0)
 			},
 			sqls:    []string{"create table t1(id int)"},
 			wantErr: false,
@@ -245,7 +355,7 @@ func Test_action_execute(t *testing.T) {
 					return errors.New("mock error: Storage.UpdateExecuteSQLs")
 				})
 
-				return newDriverWithAudit(log.NewEntry(), nil, "", driver.DriverTypeMySQL)
+				return newDriverWithAudit(log.NewEntry(), nil, "", driver.DriverTypeMySQL, nil, 0)
 			},
 			sqls:    []string{"select * from t1", "create table t1(id int)"},
 			wantErr: false,
@@ -265,6 +375,65 @@ func Test_action_execute(t *testing.T) {
 	}
 }
 
+type flashbackMockDriver struct {
+	mockDriver
+	flashback *driver.Flashback
+}
+
+func (d *flashbackMockDriver) TxWithFlashback(ctx context.Context, queries ...string) ([]_driver.Result, *driver.Flashback, error) {
+	results := make([]_driver.Result, 0, len(queries))
+	for range queries {
+		results = append(results, _driver.RowsAffected(1))
+	}
+	return results, d.flashback, nil
+}
+
+func Test_action_execSQLs_WithFlashback(t *testing.T) {
+	var updatedRollbackSQLs []*model.RollbackSQL
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(&model.Storage{}), "UpdateExecuteSQLs", func(_ *model.Storage, _ []*model.ExecuteSQL) error {
+		return nil
+	})
+	defer patches.Reset()
+	patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "UpdateRollbackSQLs", func(_ *model.Storage, sqls []*model.RollbackSQL) error {
+		updatedRollbackSQLs = sqls
+		return nil
+	})
+
+	// the rollback SQLs generated from binlog replace the ones generated in audit.
+	a := getAction([]string{"DELETE FROM t1 WHERE id = 1"}, ActionTypeExecute, &flashbackMockDriver{
+		flashback: &driver.Flashback{RollbackSQLs: []string{"INSERT INTO t1 VALUES (1);"}},
+	})
+	a.task.RollbackSQLs = []*model.RollbackSQL{{BaseSQL: model.BaseSQL{Content: "INSERT INTO t1 (id) VALUES (1);"}}}
+	assert.NoError(t, a.execSQLs(a.task.ExecuteSQLs))
+	assert.Len(t, updatedRollbackSQLs, 1)
+	assert.Equal(t, "INSERT INTO t1 VALUES (1);", a.task.RollbackSQLs[0].Content)
+	assert.Empty(t, a.task.ExecuteSQLs[0].AuditResults)
+
+	// the rollback SQL generated in audit is cleared if the SQL changed no rows in execution.
+	updatedRollbackSQLs = nil
+	a = getAction([]string{"DELETE FROM t1 WHERE id = 1"}, ActionTypeExecute, &flashbackMockDriver{
+		flashback: &driver.Flashback{RollbackSQLs: []string{""}},
+	})
+	a.task.RollbackSQLs = []*model.RollbackSQL{{BaseSQL: model.BaseSQL{Content: "INSERT INTO t1 (id) VALUES (1);"}}}
+	assert.NoError(t, a.execSQLs(a.task.ExecuteSQLs))
+	assert.Len(t, updatedRollbackSQLs, 1)
+	assert.Equal(t, "", a.task.RollbackSQLs[0].Content)
+
+	// the rollback SQLs generated in audit are kept if the generation from binlog is failed.
+	updatedRollbackSQLs = nil
+	a = getAction([]string{"DELETE FROM t1 WHERE id = 1"}, ActionTypeExecute, &flashbackMockDriver{
+		flashback: &driver.Flashback{FailedReason: "binlog_format is STATEMENT, not ROW"},
+	})
+	a.task.RollbackSQLs = []*model.RollbackSQL{{BaseSQL: model.BaseSQL{Content: "INSERT INTO t1 (id) VALUES (1);"}}}
+	assert.NoError(t, a.execSQLs(a.task.ExecuteSQLs))
+	assert.Nil(t, updatedRollbackSQLs)
+	assert.Equal(t, "INSERT INTO t1 (id) VALUES (1);", a.task.RollbackSQLs[0].Content)
+	assert.Equal(t, model.SQLExecuteStatusSucceeded, a.task.ExecuteSQLs[0].ExecStatus)
+	assert.Len(t, a.task.ExecuteSQLs[0].AuditResults, 1)
+	assert.Contains(t, a.task.ExecuteSQLs[0].AuditResults[0].Message, "binlog_format is STATEMENT, not ROW")
+	assert.Equal(t, string(driver.RuleLevelNotice), a.task.ExecuteSQLs[0].AuditLevel)
+}
+
 func TestScoreTask(t *testing.T) {
 	task := &model.Task{
 		PassRate: 0.5,
@@ -302,3 +471,166 @@ func TestScoreTask(t *testing.T) {
 
 	assert.Equal(t, int32(45), score)
 }
+
+type rollbackMockDriver struct {
+	mockDriver
+	execErr  error
+	txErr    *driver.TxError
+	executed [][]string
+}
+
+func (d *rollbackMockDriver) Parse(ctx context.Context, sqlText string) ([]driver.Node, error) {
+	nodes := []driver.Node{}
+	for _, sql := range strings.Split(sqlText, ";") {
+		sql = strings.TrimSpace(sql)
+		if sql == "" {
+			continue
+		}
+		node := driver.Node{Text: sql, Type: driver.SQLTypeDDL}
+		if strings.HasPrefix(sql, "INSERT") || strings.HasPrefix(sql, "UPDATE") {
+			node.Type = driver.SQLTypeDML
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func (d *rollbackMockDriver) Exec(ctx context.Context, query string) (_driver.Result, error) {
+	d.executed = append(d.executed, []string{query})
+	if d.execErr != nil {
+		return nil, d.execErr
+	}
+	return _driver.ResultNoRows, nil
+}
+
+func (d *rollbackMockDriver) Tx(ctx context.Context, queries ...string) ([]_driver.Result, error) {
+	d.executed = append(d.executed, queries)
+	results := make([]_driver.Result, 0, len(queries))
+	for idx := range queries {
+		if d.txErr != nil && idx == d.txErr.Index {
+			return results, d.txErr
+		}
+		results = append(results, _driver.RowsAffected(1))
+	}
+	return results, nil
+}
+
+func Test_action_rollback(t *testing.T) {
+	newRollbackAction := func(d driver.Driver) *action {
+		a := getAction([]string{
+			"DELETE FROM t1 WHERE id IN (1, 2)",
+			"ALTER TABLE t1 ADD COLUMN c1 INT",
+			"UPDATE t1 SET c2 = 2 WHERE id = 1",
+		}, ActionTypeRollback, d)
+		for idx, executeSQL := range a.task.ExecuteSQLs {
+			executeSQL.ID = uint(idx + 1)
+			executeSQL.Number = uint(idx + 1)
+		}
+		a.task.RollbackSQLs = []*model.RollbackSQL{
+			{BaseSQL: model.BaseSQL{Content: "INSERT INTO t1 VALUES (1);INSERT INTO t1 VALUES (2);"}, ExecuteSQLId: 1},
+			{BaseSQL: model.BaseSQL{Content: "ALTER TABLE t1 DROP COLUMN c1;"}, ExecuteSQLId: 2},
+			{BaseSQL: model.BaseSQL{Content: "UPDATE t1 SET c2 = 1 WHERE id = 1;"}, ExecuteSQLId: 3},
+			{BaseSQL: model.BaseSQL{Content: ""}, ExecuteSQLId: 4},
+		}
+		return a
+	}
+
+	var taskStatus string
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(&model.Storage{}), "UpdateTask", func(_ *model.Storage, _ *model.Task, attr ...interface{}) error {
+		taskStatus = attr[0].(map[string]interface{})["status"].(string)
+		return nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "UpdateRollbackSQLs", func(_ *model.Storage, _ []*model.RollbackSQL) error {
+		return nil
+	})
+	defer patches.Reset()
+
+	// the rollback SQLs are executed in the reverse order, and the adjacent DMLs are in one transaction.
+	d := &rollbackMockDriver{}
+	a := newRollbackAction(d)
+	assert.NoError(t, a.rollback())
+	assert.Equal(t, [][]string{
+		{"UPDATE t1 SET c2 = 1 WHERE id = 1"},
+		{"ALTER TABLE t1 DROP COLUMN c1"},
+		{"INSERT INTO t1 VALUES (1)", "INSERT INTO t1 VALUES (2)"},
+	}, d.executed)
+	assert.Equal(t, model.TaskStatusRollbackSucceeded, taskStatus)
+	for _, rollbackSQL := range a.task.RollbackSQLs[:3] {
+		assert.Equal(t, model.SQLExecuteStatusSucceeded, rollbackSQL.ExecStatus)
+	}
+	assert.Len(t, a.task.RollbackSQLs[0].Statements, 2)
+	assert.Equal(t, int64(2), a.task.RollbackSQLs[0].RowAffects)
+	assert.Empty(t, a.task.RollbackSQLs[3].Statements)
+
+	// the rollback stops at the first failure.
+	d = &rollbackMockDriver{execErr: errors.New("mock error: rollbackMockDriver.Exec")}
+	a = newRollbackAction(d)
+	assert.Error(t, a.rollback())
+	assert.Len(t, d.executed, 2)
+	assert.Equal(t, model.TaskStatusRollbackFailed, taskStatus)
+	assert.Equal(t, model.SQLExecuteStatusInitialized, a.task.RollbackSQLs[0].ExecStatus)
+	assert.Equal(t, model.SQLExecuteStatusFailed, a.task.RollbackSQLs[1].ExecStatus)
+	assert.Equal(t, "mock error: rollbackMockDriver.Exec", a.task.RollbackSQLs[1].ExecResult)
+	assert.Equal(t, model.SQLExecuteStatusSucceeded, a.task.RollbackSQLs[2].ExecStatus)
+
+	// the failed statement in transaction is recorded, the statements before it are rolled
+	// back and the statements after it are not executed.
+	d = &rollbackMockDriver{txErr: &driver.TxError{Index: 1, Err: errors.New("mock error: Duplicate entry")}}
+	a = newRollbackAction(d)
+	a.task.RollbackSQLs[0].Content = "INSERT INTO t1 VALUES (1);INSERT INTO t1 VALUES (2);INSERT INTO t1 VALUES (3);"
+	assert.Error(t, a.rollback())
+	assert.Equal(t, model.TaskStatusRollbackFailed, taskStatus)
+	stmts := a.task.RollbackSQLs[0].Statements
+	assert.Len(t, stmts, 3)
+	assert.Equal(t, model.SQLExecuteStatusFailed, stmts[0].ExecStatus)
+	assert.Equal(t, "rolled back because of the failure of \"INSERT INTO t1 VALUES (2)\"", stmts[0].ExecResult)
+	assert.Equal(t, model.SQLExecuteStatusFailed, stmts[1].ExecStatus)
+	assert.Equal(t, "mock error: Duplicate entry", stmts[1].ExecResult)
+	assert.Equal(t, model.SQLExecuteStatusInitialized, stmts[2].ExecStatus)
+	assert.Equal(t, rollbackNotExecutedResult, stmts[2].ExecResult)
+	assert.Equal(t, model.SQLExecuteStatusFailed, a.task.RollbackSQLs[0].ExecStatus)
+}
+
+type dryRunMockDriver struct {
+	mockDriver
+	opts *driver.DryRunOptions
+}
+
+func (d *dryRunMockDriver) DryRun(ctx context.Context, opts *driver.DryRunOptions, queries ...string) ([]*driver.DryRunResult, error) {
+	d.opts = opts
+	return []*driver.DryRunResult{
+		{},
+		{Err: errors.New("mock error: Duplicate entry")},
+		{Skipped: true, Reason: "the statement is not supported in dry run"},
+	}, nil
+}
+
+func Test_action_dryRun(t *testing.T) {
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(&model.Storage{}), "UpdateExecuteSQLs", func(_ *model.Storage, _ []*model.ExecuteSQL) error {
+		return nil
+	})
+	defer patches.Reset()
+
+	d := &dryRunMockDriver{}
+	a := getAction([]string{
+		"ALTER TABLE t1 ADD COLUMN c1 INT",
+		"ALTER TABLE t1 ADD UNIQUE INDEX uk_c2(c2)",
+		"CREATE DATABASE db1",
+	}, ActionTypeDryRun, d)
+	a.dryRunOpts = &driver.DryRunOptions{SampleRows: 10}
+	assert.NoError(t, a.dryRun())
+	assert.Equal(t, a.dryRunOpts, d.opts)
+
+	sqls := a.task.ExecuteSQLs
+	assert.Equal(t, model.SQLDryRunStatusSucceeded, sqls[0].DryRunStatus)
+	assert.Equal(t, model.SQLDryRunStatusFailed, sqls[1].DryRunStatus)
+	assert.Equal(t, "mock error: Duplicate entry", sqls[1].DryRunResult)
+	assert.Equal(t, model.SQLDryRunStatusSkipped, sqls[2].DryRunStatus)
+	for _, sql := range sqls {
+		assert.Empty(t, sql.ExecStatus)
+	}
+
+	// the driver which doesn't implement driver.DryRunner.
+	a = getAction([]string{"ALTER TABLE t1 ADD COLUMN c1 INT"}, ActionTypeDryRun, &mockDriver{})
+	assert.Error(t, a.dryRun())
+}
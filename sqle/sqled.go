@@ -5,12 +5,14 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/utils"
 
 	"github.com/actiontech/sqle/sqle/api"
 	"github.com/actiontech/sqle/sqle/config"
 	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/actiontech/sqle/sqle/server"
@@ -37,6 +39,10 @@ func Run(config *config.Config) error {
 		return fmt.Errorf("init plugins error: %v", err)
 	}
 
+	if ttl := config.Server.SqleCnf.MysqlMetaCacheTTL; ttl > 0 {
+		session.DefaultMetaCache.SetTTL(time.Duration(ttl) * time.Second)
+	}
+
 	dbConfig := config.Server.DBCnf.MysqlCnf
 
 	dbPassword := dbConfig.Password
@@ -77,6 +83,9 @@ func Run(config *config.Config) error {
 	}
 	exitChan := make(chan struct{})
 	server.InitSqled(exitChan)
+	if config.Server.SqleCnf.AutoReloadPlugins {
+		server.GetSqled().StartPluginReloadLoop()
+	}
 	auditPlanMgrQuitCh := auditplan.InitManager(model.GetStorage())
 
 	net := &gracenet.Net{}